@@ -0,0 +1,186 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+// ./cmd/allan/main.go
+//
+// Captures a long static dataset from one IMU and writes the Allan deviation
+// of every gyro (°/s) and accel (g) axis as CSV, to characterize sensor noise
+// (random walk, bias instability).
+//
+// The device must stay still and at a steady temperature for the whole
+// capture. Only taus up to about a tenth of the capture are meaningful, and
+// MEMS gyro bias instability shows up between roughly 10 and 1000 s, so
+// capture at least 1 hour (3 hours or more for a reliable bias instability
+// and rate random walk). Stop imu_producer first: this reads the sensor
+// directly.
+//
+// Run:
+//
+//	go run ./cmd/allan -imu left -duration 3h -out allan_left.csv
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/relabs-tech/inertial_computer/internal/calibration"
+	"github.com/relabs-tech/inertial_computer/internal/config"
+	"github.com/relabs-tech/inertial_computer/internal/imu"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
+	"github.com/relabs-tech/inertial_computer/internal/sensors"
+)
+
+var axes = []string{"gx", "gy", "gz", "ax", "ay", "az"}
+
+func main() {
+	configPath := flag.String("config", "inertial_config.txt", "Path to configuration file")
+	imuFlag := flag.String("imu", "left", "IMU to capture: left or right")
+	duration := flag.Duration("duration", time.Hour, "Capture length (at least 1h; 3h+ for bias instability)")
+	rate := flag.Int("rate", 100, "Samples per second")
+	out := flag.String("out", "", "CSV output file (default: stdout)")
+	flag.Parse()
+
+	if *imuFlag != "left" && *imuFlag != "right" {
+		log.Fatalf("invalid -imu %q (must be left or right)", *imuFlag)
+	}
+	if *rate <= 0 || *duration <= 0 {
+		log.Fatalf("-rate and -duration must be positive")
+	}
+	if *duration < time.Hour {
+		log.Printf("warning: captures shorter than 1h do not reach the bias instability region")
+	}
+
+	if err := config.InitGlobal(*configPath); err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+	if err := logging.Setup(config.Get().LogLevel, config.Get().LogFormat); err != nil {
+		log.Fatalf("failed to set up logging: %v", err)
+	}
+	cfg := config.Get()
+
+	mgr := sensors.GetIMUManager()
+	if err := mgr.Init(); err != nil {
+		log.Fatalf("failed to initialize IMU manager: %v", err)
+	}
+
+	log.Printf("capturing %s IMU at %d Hz for %v; keep the device still (Ctrl+C ends early)", *imuFlag, *rate, *duration)
+	samples, dt, err := capture(mgr, *imuFlag, *rate, *duration, cfg.IMUGyroRange, cfg.IMUAccelRange)
+	if err != nil {
+		log.Fatalf("capture failed: %v", err)
+	}
+	log.Printf("captured %d samples over %.0fs", len(samples[0]), float64(len(samples[0]))*dt)
+
+	w := io.Writer(os.Stdout)
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("failed to create %s: %v", *out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := writeAllan(w, samples, dt); err != nil {
+		log.Fatalf("failed to write CSV: %v", err)
+	}
+	if *out != "" {
+		log.Printf("wrote %s", *out)
+	}
+}
+
+// capture reads the IMU on a ticker until duration or a signal, returning one
+// series per axis (gyro in °/s, accel in g) and the mean sample interval.
+func capture(mgr *sensors.IMUManager, imuID string, rate int, duration time.Duration, gyroFs, accelFs byte) ([][]float64, float64, error) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(time.Second / time.Duration(rate))
+	defer ticker.Stop()
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	progress := time.NewTicker(time.Minute)
+	defer progress.Stop()
+
+	samples := make([][]float64, len(axes))
+	start := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("interrupted, using the samples captured so far")
+		case <-timer.C:
+		case <-progress.C:
+			log.Printf("  %v / %v", time.Since(start).Round(time.Second), duration)
+			continue
+		case <-ticker.C:
+			r, err := mgr.ReadIMUUncalibrated(imuID)
+			if err != nil {
+				return nil, 0, err
+			}
+			for i, v := range []float64{
+				imu.GyroDPS(r.Gx, gyroFs), imu.GyroDPS(r.Gy, gyroFs), imu.GyroDPS(r.Gz, gyroFs),
+				imu.AccelG(r.Ax, accelFs), imu.AccelG(r.Ay, accelFs), imu.AccelG(r.Az, accelFs),
+			} {
+				samples[i] = append(samples[i], v)
+			}
+			continue
+		}
+		break
+	}
+
+	n := len(samples[0])
+	if n < 3 {
+		return nil, 0, fmt.Errorf("only %d samples captured", n)
+	}
+	return samples, time.Since(start).Seconds() / float64(n), nil
+}
+
+// writeAllan writes one row per tau with the Allan deviation of every axis
+// and logs the bias instability estimate (min adev / 0.664) per axis.
+func writeAllan(w io.Writer, samples [][]float64, dt float64) error {
+	var taus []float64
+	adevs := make([][]float64, len(axes))
+	for i := range axes {
+		taus, adevs[i] = calibration.AllanDeviation(samples[i], dt)
+
+		best := 0
+		for j, v := range adevs[i] {
+			if v < adevs[i][best] {
+				best = j
+			}
+		}
+		unit := "°/s"
+		if axes[i][0] == 'a' {
+			unit = "g"
+		}
+		log.Printf("%s: bias instability ≈ %.3g %s at tau %.0fs", axes[i], adevs[i][best]/0.664, unit, taus[best])
+	}
+
+	cw := csv.NewWriter(w)
+	header := []string{"tau_s"}
+	for _, a := range axes {
+		header = append(header, a)
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for j, tau := range taus {
+		row := []string{strconv.FormatFloat(tau, 'f', -1, 64)}
+		for i := range axes {
+			row = append(row, strconv.FormatFloat(adevs[i][j], 'g', 6, 64))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -7,10 +7,29 @@
 //
 // Guided calibration for MPU-9250 class IMUs in this project.
 // Calibrates:
+//  0. Warmup: discards samples for -warmup (default 30s) and, with -warmup-std, waits until
+//     the gyro noise settles, since MEMS gyro bias drifts right after power-on
 //  1. Gyro: static bias (still) + dynamic refinement via guided rotations (X/Y/Z)
-//  2. Accel: 6-point (±X, ±Y, ±Z) static poses to estimate bias + per-axis scale
+//  2. Accel: 6-point (±X, ±Y, ±Z) static poses to estimate bias + per-axis scale; with -accel-matrix,
+//     optional extra poses and a least-squares 3x3 scale/misalignment matrix
 //  3. Mag: guided 3D rotation to estimate hard-iron offset + per-axis soft-iron scale (min/max method)
 //
+// With -only gyro|accel|mag just that sensor is calibrated and merged into an existing calibration
+// (-base, default: the latest file for the IMU); the other sensors keep their values.
+//
+// For scripts and CI:
+//   - -json writes newline-delimited JSON events to stdout (start, prompt, countdown, phase_start, stats,
+//     confidence, warning, result, error; see calEvent) and the human-readable text to stderr.
+//     Steps are still gated by ENTER on stdin: send a newline after each "prompt" event.
+//   - -auto never prompts: each step counts down -auto-delay for the device (or a rig) to get into
+//     position, rotations and the mag capture run for a fixed time, no extra accel matrix poses
+//     are offered, and a result below -min-confidence is discarded with exit status 1. Use -imu to
+//     pick the IMU (default left).
+//
+// The accel poses can be done in any order: before each capture the axis pointing up is detected
+// from the live accel vector and crossed off a checklist. Tilted placements (no axis within 25°
+// of vertical) and poses already captured are rejected with a warning.
+//
 // Output:
 //
 //	Writes a JSON file under ./calibration/ including calibration date/time and quality/confidence.
@@ -21,7 +40,8 @@
 //
 // Notes / assumptions:
 //   - Reads raw samples via internal/sensors IMUManager (left/right) returning internal/imu.IMURaw.
-//   - Stores calibration in RAW UNITS (counts). Applying this calibration later requires consistent units.
+//   - Stores gyro/accel calibration in RAW UNITS (counts). Mag calibration is stored in µT (schema v2),
+//     following the internal/imu mag convention; apply it with imu.MagCalibration.
 //   - Mag calibration here uses a practical min/max ellipsoid approximation (offset + diagonal scale). It is
 //     robust and easy, though not as accurate as a full 3x3 ellipsoid fit.
 package main
@@ -32,11 +52,14 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"math"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/relabs-tech/inertial_computer/internal/calibration"
 	"github.com/relabs-tech/inertial_computer/internal/config"
 	"github.com/relabs-tech/inertial_computer/internal/imu"
 	"github.com/relabs-tech/inertial_computer/internal/sensors"
@@ -45,28 +68,30 @@ import (
 const (
 	sampleHz = 100 // target loop frequency (best-effort)
 
+	// Warmup
+	warmupDefault     = 30 * time.Second
+	warmupWindow      = 2 * time.Second // stability is judged per window
+	warmupMaxSettling = 2 * time.Minute // give up waiting for stability after this
+
 	// Gyro
 	gyroStaticDuration = 10 * time.Second
-	gyroRotMinDur      = 8 * time.Second
+	gyroRotMinDur      = calibration.RotationMinDuration
 	gyroRotMaxDur      = 30 * time.Second
 
 	// Accel 6-point
 	accelPoseDuration = 6 * time.Second
+	poseProbeDuration = 1 * time.Second // live pose detection before each capture
+	poseMaxTiltDeg    = 25.0            // max angle between the detected "up" axis and gravity
 
 	// Mag
 	magDurationDefault = 60 * time.Second
 
-	// Generic quality heuristics (in raw counts; tune as needed)
-	stillStdGood = 3.0  // "good" standard deviation threshold for stillness
-	stillStdBad  = 12.0 // "bad" threshold; above this confidence drops steeply
+	// -auto: fixed capture of each guided rotation
+	gyroRotAutoDur = 15 * time.Second
 
-	dominanceGood = 0.70 // dominant-axis ratio for guided single-axis rotations
-	dominanceBad  = 0.45
-
-	minMeanAbsRate = 20.0 // minimal mean abs gyro rate (counts) to consider "real rotation"
-
-	// Confidence floor (we never want hard zero unless we error out)
-	confFloor = 0.05
+	// Confidence floor (we never want hard zero unless we error out); the
+	// heuristics are in internal/calibration, shared with the web UI
+	confFloor = calibration.ConfidenceFloor
 )
 
 // ---------- Data model (JSON output) ----------
@@ -77,6 +102,10 @@ type Vec3 struct {
 	Z float64 `json:"z"`
 }
 
+func (v Vec3) array() [3]float64 {
+	return [3]float64{v.X, v.Y, v.Z}
+}
+
 type PhaseStats struct {
 	Samples       int      `json:"samples"`
 	DurationSec   float64  `json:"duration_sec"`
@@ -97,11 +126,23 @@ type AccelPoseStats struct {
 	Confidence  float64 `json:"confidence"`
 }
 
+// WarmupStats describes the warmup before the static gyro capture.
+type WarmupStats struct {
+	DurationSec  float64 `json:"duration_sec"`  // total, including waiting for stability
+	Samples      int     `json:"samples"`       // discarded
+	StdThreshold float64 `json:"std_threshold"` // mean gyro std (counts) required; 0 = not monitored
+	FinalStdDev  Vec3    `json:"final_stddev"`  // gyro std of the last window (counts)
+	FinalDrift   Vec3    `json:"final_drift"`   // gyro mean change over the last window (counts)
+	Stable       bool    `json:"stable"`        // threshold reached (always true when not monitored)
+}
+
 type CalibrationResult struct {
 	SchemaVersion int    `json:"schema_version"`
 	CalibrationAt string `json:"calibration_at"` // RFC3339
 	IMU           string `json:"imu"`            // "left" or "right"
 
+	Warmup WarmupStats `json:"warmup"`
+
 	// Gyro bias (counts)
 	GyroBiasStatic Vec3 `json:"gyro_bias_static"`
 	GyroBiasDyn    Vec3 `json:"gyro_bias_dynamic"`
@@ -112,8 +153,23 @@ type CalibrationResult struct {
 	AccelBias  Vec3 `json:"accel_bias"`
 	AccelScale Vec3 `json:"accel_scale"`
 
-	// Mag hard/soft iron approximation (counts)
-	// CorrectedMagAxis = (raw - offset) / scale
+	// Optional scale/misalignment matrix (-accel-matrix, see imu.FitAccelMatrix).
+	// When present it replaces accel_scale and accel_bias is the fitted bias:
+	// CorrectedAccel (counts) = accel_matrix · (raw - bias)
+	AccelMatrix       *[3][3]float64 `json:"accel_matrix,omitempty"`
+	AccelMatrixRMSErr float64        `json:"accel_matrix_rms_err_g,omitempty"` // fit residual (g)
+
+	// Mean die temperature during the accel capture (°C, 0 if not read)
+	AccelTempC float64 `json:"accel_temp_c,omitempty"`
+
+	// Optional accel scale temperature compensation, written by -fit-accel-temp
+	// (see imu.FitAccelScaleTemp):
+	// scale(T) = accel_scale * (1 + coeff * (T - accel_scale_ref_temp_c))
+	AccelScaleTempCoeff *Vec3   `json:"accel_scale_temp_coeff,omitempty"`
+	AccelScaleRefTempC  float64 `json:"accel_scale_ref_temp_c,omitempty"`
+
+	// Mag hard/soft iron approximation (µT, see imu.MagCalibration)
+	// CorrectedMagAxis (µT) = (imu.MagUT(raw) - offset) * scale
 	MagOffset Vec3 `json:"mag_offset"`
 	MagScale  Vec3 `json:"mag_scale"`
 
@@ -134,9 +190,53 @@ type CalibrationResult struct {
 
 	MagStats PhaseStats `json:"mag_stats"`
 
+	// Calibration the untouched sensors were taken from (-only)
+	BaseFile string `json:"base_file,omitempty"`
+
 	Notes []string `json:"notes,omitempty"`
 }
 
+// ---------- Console and events ----------
+
+var (
+	// console receives the human-readable text: stdout, or stderr with -json
+	console io.Writer = os.Stdout
+
+	// events writes the -json events to stdout (nil = off)
+	events *json.Encoder
+
+	// autoMode (-auto) replaces ENTER gating with autoDelay and fixed durations
+	autoMode  bool
+	autoDelay time.Duration
+)
+
+// calEvent is one -json event line.
+type calEvent struct {
+	Event      string             `json:"event"` // start, prompt, countdown, phase_start, stats, confidence, warning, result, error
+	Time       string             `json:"time"`  // RFC3339
+	IMU        string             `json:"imu,omitempty"`
+	Phase      string             `json:"phase,omitempty"`   // e.g. warmup, gyro_static, gyro_rotation_x, accel_pose_+X, mag, overall
+	Message    string             `json:"message,omitempty"` // prompt or warning text
+	Stats      any                `json:"stats,omitempty"`   // PhaseStats, AccelPoseStats or WarmupStats
+	Confidence *float64           `json:"confidence,omitempty"`
+	File       string             `json:"file,omitempty"`
+	Result     *CalibrationResult `json:"result,omitempty"`
+}
+
+// emit writes e with -json; it is a no-op otherwise.
+func emit(e calEvent) {
+	if events == nil {
+		return
+	}
+	e.Time = time.Now().Format(time.RFC3339)
+	_ = events.Encode(e)
+}
+
+// emitConfidence emits a confidence event for phase.
+func emitConfidence(phase string, c float64) {
+	emit(calEvent{Event: "confidence", Phase: phase, Confidence: &c})
+}
+
 // ---------- Main ----------
 
 func main() {
@@ -144,47 +244,233 @@ func main() {
 
 	// Parse command-line flags
 	configPath := flag.String("config", "inertial_config.txt", "Path to configuration file")
+	warmup := flag.Duration("warmup", warmupDefault, "Warmup before the static gyro capture; samples are discarded (0 = none)")
+	warmupStd := flag.Float64("warmup-std", 0, "After -warmup, keep waiting (up to 2m) until the mean gyro std dev over 2s windows is at or below this many counts (0 = don't wait)")
+	minConfidence := flag.Float64("min-confidence", 0.5, "Overall confidence (0-1) below which the tool offers to retry before saving")
+	accelMatrix := flag.Bool("accel-matrix", false, "Also fit a full 3x3 accel scale/misalignment matrix (offers extra poses after the 6-point capture)")
+	only := flag.String("only", "", "Calibrate only gyro, accel or mag and merge into an existing calibration (see -base)")
+	basePath := flag.String("base", "", "Calibration file -only merges into (default: latest *_inertial_calibration.json for the IMU)")
+	fitAccelTemp := flag.String("fit-accel-temp", "", "Comma-separated calibration files of one IMU taken at different temperatures: fit the accel scale temperature coefficients, write a new calibration and exit")
+	jsonOut := flag.Bool("json", false, "Write newline-delimited JSON events to stdout and the human-readable text to stderr")
+	flag.BoolVar(&autoMode, "auto", false, "No prompts: fixed timings, no extra accel poses, discard (exit 1) below -min-confidence")
+	flag.DurationVar(&autoDelay, "auto-delay", 5*time.Second, "With -auto, time to get into position before each capture")
+	imuFlag := flag.String("imu", "", "IMU to calibrate: left or right (default: ask, or left with -auto)")
 	flag.Parse()
 
-	fmt.Println("=== Guided Calibration (Accel + Gyro + Mag) ===")
-	fmt.Println("This workflow will prompt you in the console and store results in ./inertial_calibration.json")
-	fmt.Println()
+	if *jsonOut {
+		console = os.Stderr
+		events = json.NewEncoder(os.Stdout)
+		// Keep the event stream clean of what other packages print (e.g.
+		// the IMU init messages)
+		os.Stdout = os.Stderr
+	}
+	switch *imuFlag {
+	case "", "left", "right":
+	default:
+		fatal(fmt.Errorf("-imu must be left or right, got %q", *imuFlag))
+	}
+	if autoDelay < 0 {
+		fatal(fmt.Errorf("-auto-delay must be >= 0, got %v", autoDelay))
+	}
+
+	if *minConfidence < 0 || *minConfidence > 1 {
+		fatal(fmt.Errorf("-min-confidence must be between 0 and 1, got %v", *minConfidence))
+	}
+	switch *only {
+	case "", "gyro", "accel", "mag":
+	default:
+		fatal(fmt.Errorf("-only must be gyro, accel or mag, got %q", *only))
+	}
+
+	if *fitAccelTemp != "" {
+		if err := fitAccelScaleTemp(strings.Split(*fitAccelTemp, ",")); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	fmt.Fprintln(console, "=== Guided Calibration (Accel + Gyro + Mag) ===")
+	fmt.Fprintln(console, "This workflow will prompt you in the console and store results in ./inertial_calibration.json")
+	fmt.Fprintln(console)
 
 	// Initialize configuration
 	if err := config.InitGlobal(*configPath); err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR: Failed to load config from %s: %v\n", *configPath, err)
-		os.Exit(1)
+		fatal(fmt.Errorf("failed to load config from %s: %w", *configPath, err))
 	}
 
 	// Init IMUs
 	mgr := sensors.GetIMUManager()
 	if err := mgr.Init(); err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR: IMU init failed: %v\n", err)
-		os.Exit(1)
+		fatal(fmt.Errorf("IMU init failed: %w", err))
 	}
 
 	leftOK := mgr.IsLeftIMUAvailable()
 	rightOK := mgr.IsRightIMUAvailable()
 	if !leftOK && !rightOK {
-		fmt.Fprintln(os.Stderr, "ERROR: No IMU available (left and right both unavailable).")
-		os.Exit(1)
+		fatal(errors.New("no IMU available (left and right both unavailable)"))
 	}
 
-	imuName, readFn := pickIMU(in, leftOK, rightOK, mgr)
+	imuName, readFn, err := pickIMU(in, *imuFlag, leftOK, rightOK, mgr)
+	if err != nil {
+		fatal(err)
+	}
 
-	fmt.Printf("\nSelected IMU: %s\n\n", imuName)
+	fmt.Fprintf(console, "\nSelected IMU: %s\n\n", imuName)
+	emit(calEvent{Event: "start", IMU: imuName})
 
-	res := CalibrationResult{
-		SchemaVersion: 1,
-		CalibrationAt: time.Now().Format(time.RFC3339),
-		IMU:           imuName,
-		GyroRotStats:  map[string]PhaseStats{},
+	base := CalibrationResult{SchemaVersion: imu.CLISchemaVersion, IMU: imuName}
+	if *only != "" {
+		var err error
+		if base, err = loadBaseResult(*basePath, imuName); err != nil {
+			fatal(err)
+		}
+		fmt.Fprintf(console, "Recalibrating %s only; other sensors are kept from %s\n\n", *only, base.BaseFile)
+		if base.SchemaVersion != imu.CLISchemaVersion {
+			if *only == "mag" {
+				// The new mag calibration is in the accel/gyro frame; gyro
+				// and accel are unaffected by the mag axis change
+				base.SchemaVersion = imu.CLISchemaVersion
+			} else {
+				fmt.Fprintf(console, "Note: %s has a magnetometer calibration in the old AK8963 frame, which is not applied; recalibrate with -only mag\n\n", base.BaseFile)
+			}
+		}
+		base.Notes = append(base.Notes, fmt.Sprintf("%s recalibrated on %s, other sensors from %s", *only, time.Now().Format(time.RFC3339), base.BaseFile))
 	}
 
+	opts := calibrateOptions{warmup: *warmup, warmupStd: *warmupStd, accelMatrix: *accelMatrix, only: *only}
+	var res CalibrationResult
+	for {
+		res = calibrate(in, readFn, base, opts)
+		if res.Confidence.Overall >= *minConfidence {
+			break
+		}
+		msg := fmt.Sprintf("overall confidence %.2f is below -min-confidence %.2f; the calibration may be useless.", res.Confidence.Overall, *minConfidence)
+		fmt.Fprintf(console, "\nWARNING: %s\n", msg)
+		emit(calEvent{Event: "warning", Phase: "overall", Message: msg})
+		if autoMode {
+			fatal(errors.New("calibration discarded (-auto does not retry)"))
+		}
+		if !retryOrSave(in) {
+			res.Notes = append(res.Notes, fmt.Sprintf("saved_below_min_confidence: %.2f < %.2f", res.Confidence.Overall, *minConfidence))
+			break
+		}
+		// The IMU is warm by now
+		opts.warmup, opts.warmupStd = 0, 0
+		fmt.Fprintln(console)
+	}
+
+	name, err := writeResult(res)
+	if err != nil {
+		fatal(err)
+	}
+	emit(calEvent{Event: "result", IMU: res.IMU, File: name, Confidence: &res.Confidence.Overall, Result: &res})
+
+	fmt.Fprintln(console, "\nCalibration complete.")
+	fmt.Fprintf(console, "Overall confidence: %.2f\n", res.Confidence.Overall)
+	fmt.Fprintln(console, "Saved to ./inertial_calibration.json")
+}
+
+// calibrateOptions are the flags that shape one calibration run.
+type calibrateOptions struct {
+	warmup      time.Duration
+	warmupStd   float64
+	accelMatrix bool
+	only        string // "gyro", "accel" or "mag" ("" = all)
+}
+
+// loadBaseResult reads the calibration that -only merges into: path, or the
+// most recently modified calibration file for imuName in the working
+// directory. It must be a schema v2 file for the same IMU.
+func loadBaseResult(path, imuName string) (CalibrationResult, error) {
+	if path == "" {
+		matches, err := filepath.Glob(imuName + "_*_inertial_calibration.json")
+		if err != nil {
+			return CalibrationResult{}, err
+		}
+		var latestMod time.Time
+		for _, m := range matches {
+			info, err := os.Stat(m)
+			if err != nil || info.IsDir() {
+				continue
+			}
+			if path == "" || info.ModTime().After(latestMod) {
+				path, latestMod = m, info.ModTime()
+			}
+		}
+		if path == "" {
+			return CalibrationResult{}, fmt.Errorf("no %s calibration to merge into; run a full calibration or pass -base", imuName)
+		}
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return CalibrationResult{}, err
+	}
+	var r CalibrationResult
+	if err := json.Unmarshal(b, &r); err != nil {
+		return CalibrationResult{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if r.SchemaVersion != imu.CLISchemaVersion && r.SchemaVersion != 2 {
+		return CalibrationResult{}, fmt.Errorf("%s: not a cmd/calibration file (schema_version %d)", path, r.SchemaVersion)
+	}
+	if r.IMU != imuName {
+		return CalibrationResult{}, fmt.Errorf("%s is for the %s IMU, not %s", path, r.IMU, imuName)
+	}
+	r.BaseFile = path
+	return r, nil
+}
+
+// calibrate runs the guided gyro, accel and mag steps on readFn, or only the
+// opts.only one, on top of base and returns the result with its overall
+// confidence recomputed.
+func calibrate(in *bufio.Reader, readFn func() (imu.IMURaw, error), base CalibrationResult, opts calibrateOptions) CalibrationResult {
+	res := base
+	res.CalibrationAt = time.Now().Format(time.RFC3339)
+
+	if opts.only == "" || opts.only == "gyro" {
+		calibrateGyro(in, readFn, opts, &res)
+	}
+	if opts.only == "" || opts.only == "accel" {
+		calibrateAccel(in, readFn, opts, &res)
+	}
+	if opts.only == "" || opts.only == "mag" {
+		calibrateMag(in, readFn, &res)
+	}
+
+	res.Confidence.Overall = calibration.OverallConfidence(res.Confidence.GyroStatic, res.Confidence.GyroRot, res.Confidence.Accel6Pt, res.Confidence.Mag)
+	emitConfidence("overall", res.Confidence.Overall)
+	return res
+}
+
+// calibrateGyro runs the warmup, static bias capture and guided rotations.
+func calibrateGyro(in *bufio.Reader, readFn func() (imu.IMURaw, error), opts calibrateOptions, res *CalibrationResult) {
+	res.GyroRotStats = map[string]PhaseStats{}
+
 	// ---------------- Gyro calibration ----------------
-	fmt.Println("Step 1/3 — Gyro static bias")
-	fmt.Println("Place the device on a stable surface and do not touch it.")
+	fmt.Fprintln(console, "Step 1/3 — Gyro static bias")
+	fmt.Fprintln(console, "Place the device on a stable surface and do not touch it.")
+
+	if opts.warmup > 0 || opts.warmupStd > 0 {
+		waitEnter(in, fmt.Sprintf("Press ENTER to start the IMU warmup (%v)...", opts.warmup))
+		emit(calEvent{Event: "phase_start", Phase: "warmup"})
+		ws, err := warmupIMU(readFn, opts.warmup, opts.warmupStd)
+		if err != nil {
+			fatal(err)
+		}
+		res.Warmup = ws
+		emit(calEvent{Event: "stats", Phase: "warmup", Stats: ws})
+		fmt.Fprintf(console, "Warmup done after %.0fs: gyro std X=%.2f Y=%.2f Z=%.2f, drift X=%.2f Y=%.2f Z=%.2f (counts)\n",
+			ws.DurationSec, ws.FinalStdDev.X, ws.FinalStdDev.Y, ws.FinalStdDev.Z,
+			ws.FinalDrift.X, ws.FinalDrift.Y, ws.FinalDrift.Z)
+		if !ws.Stable {
+			fmt.Fprintf(console, "WARNING: gyro did not settle below %.2f counts within %v; continuing anyway\n", opts.warmupStd, warmupMaxSettling)
+			emit(calEvent{Event: "warning", Phase: "warmup", Message: "gyro did not settle"})
+			res.Notes = append(res.Notes, "warmup_not_stable")
+		}
+		fmt.Fprintln(console, "Keep the device still.")
+	}
 	waitEnter(in, "Press ENTER to start static gyro bias capture (10s)...")
+	emit(calEvent{Event: "phase_start", Phase: "gyro_static"})
 
 	gyroStaticSamples, sStats, err := captureSamples(readFn, gyroStaticDuration, func(r imu.IMURaw) Vec3 {
 		return Vec3{X: float64(r.Gx), Y: float64(r.Gy), Z: float64(r.Gz)}
@@ -195,20 +481,22 @@ func main() {
 	res.GyroStaticStats = sStats
 	res.GyroBiasStatic = sStats.Mean
 
-	gyroStaticConf := stillnessConfidence(sStats.StdDev)
+	gyroStaticConf := calibration.StillnessConfidence(sStats.StdDev.array())
 	res.Confidence.GyroStatic = gyroStaticConf
+	emit(calEvent{Event: "stats", Phase: "gyro_static", Stats: sStats})
+	emitConfidence("gyro_static", gyroStaticConf)
 
-	fmt.Printf("Static gyro bias (counts): X=%.2f Y=%.2f Z=%.2f | confidence=%.2f\n",
+	fmt.Fprintf(console, "Static gyro bias (counts): X=%.2f Y=%.2f Z=%.2f | confidence=%.2f\n",
 		res.GyroBiasStatic.X, res.GyroBiasStatic.Y, res.GyroBiasStatic.Z, gyroStaticConf)
 
 	// Gyro dynamic refinement
-	fmt.Println("\nStep 1b/3 — Gyro dynamic refinement via guided rotations")
-	fmt.Println("For each axis (X, Y, Z), rotate the device 2–3 full turns around that axis.")
-	fmt.Println("Try to keep the rotation mostly around the prompted axis.")
-	fmt.Println("You will press ENTER to start capture and ENTER again to stop (or it stops automatically).")
-	fmt.Println()
+	fmt.Fprintln(console, "\nStep 1b/3 — Gyro dynamic refinement via guided rotations")
+	fmt.Fprintln(console, "For each axis (X, Y, Z), rotate the device 2–3 full turns around that axis.")
+	fmt.Fprintln(console, "Try to keep the rotation mostly around the prompted axis.")
+	fmt.Fprintln(console, "You will press ENTER to start capture and ENTER again to stop (or it stops automatically).")
+	fmt.Fprintln(console)
 
-	gyroDynBias, gyroRotConf := guidedGyroRotations(in, readFn, res.GyroBiasStatic, &res)
+	gyroDynBias, gyroRotConf := guidedGyroRotations(in, readFn, res.GyroBiasStatic, res)
 	res.GyroBiasDyn = gyroDynBias
 
 	// Combine static and dynamic (favor static but incorporate motion-validated bias)
@@ -219,21 +507,43 @@ func main() {
 		Z: alpha*res.GyroBiasStatic.Z + (1-alpha)*res.GyroBiasDyn.Z,
 	}
 	res.Confidence.GyroRot = gyroRotConf
+	emitConfidence("gyro_rotation", gyroRotConf)
 
-	fmt.Printf("Dynamic gyro bias (counts): X=%.2f Y=%.2f Z=%.2f | confidence=%.2f\n",
+	fmt.Fprintf(console, "Dynamic gyro bias (counts): X=%.2f Y=%.2f Z=%.2f | confidence=%.2f\n",
 		res.GyroBiasDyn.X, res.GyroBiasDyn.Y, res.GyroBiasDyn.Z, gyroRotConf)
-	fmt.Printf("Final gyro bias (counts):   X=%.2f Y=%.2f Z=%.2f\n",
+	fmt.Fprintf(console, "Final gyro bias (counts):   X=%.2f Y=%.2f Z=%.2f\n",
 		res.GyroBiasFinal.X, res.GyroBiasFinal.Y, res.GyroBiasFinal.Z)
 
 	_ = gyroStaticSamples // kept for possible future extensions
+}
+
+// calibrateAccel runs the 6-point capture and, with opts.accelMatrix, the
+// matrix fit. A previous matrix or temperature fit is dropped, since it
+// belongs to the old scale.
+func calibrateAccel(in *bufio.Reader, readFn func() (imu.IMURaw, error), opts calibrateOptions, res *CalibrationResult) {
+	res.AccelMatrix, res.AccelMatrixRMSErr = nil, 0
+	res.AccelScaleTempCoeff, res.AccelScaleRefTempC = nil, 0
+	res.AccelTempC = 0
 
 	// ---------------- Accel calibration (6-point) ----------------
-	fmt.Println("\nStep 2/3 — Accelerometer 6-point calibration (bias + scale)")
-	fmt.Println("You will place the device still in 6 orientations: +X, -X, +Y, -Y, +Z, -Z (axis UP).")
-	fmt.Println("Each pose captures 6 seconds. Keep it as still as possible.")
-	fmt.Println()
+	fmt.Fprintln(console, "\nStep 2/3 — Accelerometer 6-point calibration (bias + scale)")
+	fmt.Fprintln(console, "You will place the device still in 6 orientations: +X, -X, +Y, -Y, +Z, -Z (axis UP).")
+	fmt.Fprintln(console, "Each pose captures 6 seconds. Keep it as still as possible.")
+	fmt.Fprintln(console)
+
+	// Average the die temperature over the accel capture (for -fit-accel-temp)
+	var accTempSum float64
+	var accTempN int
+	accelReadFn := func() (imu.IMURaw, error) {
+		r, err := readFn()
+		if err == nil && r.TempValid {
+			accTempSum += imu.DieTempC(r.Temp)
+			accTempN++
+		}
+		return r, err
+	}
 
-	accBias, accScale, accConf, poseStats, err := guidedAccel6Point(in, readFn)
+	accBias, accScale, accConf, poseStats, err := guidedAccel6Point(in, accelReadFn)
 	if err != nil {
 		fatal(err)
 	}
@@ -241,18 +551,45 @@ func main() {
 	res.AccelScale = accScale
 	res.Confidence.Accel6Pt = accConf
 	res.AccelPoseStats = poseStats
+	emitConfidence("accel_6pt", accConf)
+	if accTempN > 0 {
+		res.AccelTempC = accTempSum / float64(accTempN)
+		fmt.Fprintf(console, "Accel die temperature: %.1f °C\n", res.AccelTempC)
+	}
+
+	fmt.Fprintf(console, "Accel bias (counts):  X=%.2f Y=%.2f Z=%.2f\n", accBias.X, accBias.Y, accBias.Z)
+	fmt.Fprintf(console, "Accel scale (counts): X=%.2f Y=%.2f Z=%.2f | confidence=%.2f\n", accScale.X, accScale.Y, accScale.Z, accConf)
 
-	fmt.Printf("Accel bias (counts):  X=%.2f Y=%.2f Z=%.2f\n", accBias.X, accBias.Y, accBias.Z)
-	fmt.Printf("Accel scale (counts): X=%.2f Y=%.2f Z=%.2f | confidence=%.2f\n", accScale.X, accScale.Y, accScale.Z, accConf)
+	if opts.accelMatrix {
+		bias, m, rmsErr, extra, err := guidedAccelMatrix(in, accelReadFn, poseStats)
+		res.AccelPoseStats = append(res.AccelPoseStats, extra...)
+		if err != nil {
+			fmt.Fprintf(console, "WARNING: accel matrix fit failed (%v); keeping the diagonal scale\n", err)
+			emit(calEvent{Event: "warning", Phase: "accel_matrix", Message: err.Error()})
+			res.Notes = append(res.Notes, "accel_matrix_fallback_diagonal: "+err.Error())
+		} else {
+			res.AccelBias = Vec3{X: bias[0], Y: bias[1], Z: bias[2]}
+			res.AccelMatrix = &m
+			res.AccelMatrixRMSErr = rmsErr
+			fmt.Fprintf(console, "Accel matrix fit from %d poses (rms error %.4f g):\n", len(res.AccelPoseStats), rmsErr)
+			for _, row := range m {
+				fmt.Fprintf(console, "  [%9.5f %9.5f %9.5f]\n", row[0], row[1], row[2])
+			}
+		}
+	}
+}
 
+// calibrateMag runs the guided 3D rotation for the mag offset and scale.
+func calibrateMag(in *bufio.Reader, readFn func() (imu.IMURaw, error), res *CalibrationResult) {
 	// ---------------- Mag calibration ----------------
-	fmt.Println("\nStep 3/3 — Magnetometer calibration (offset + diagonal scale)")
-	fmt.Println("Rotate the device through all orientations (3D).")
-	fmt.Println("Move away from large metal objects and power cables if possible.")
-	fmt.Println("You can stop early by pressing ENTER again.")
-	fmt.Println()
+	fmt.Fprintln(console, "\nStep 3/3 — Magnetometer calibration (offset + diagonal scale)")
+	fmt.Fprintln(console, "Rotate the device through all orientations (3D).")
+	fmt.Fprintln(console, "Move away from large metal objects and power cables if possible.")
+	fmt.Fprintln(console, "You can stop early by pressing ENTER again.")
+	fmt.Fprintln(console)
 
 	waitEnter(in, "Press ENTER to start magnetometer capture (default 60s, ENTER to stop earlier)...")
+	emit(calEvent{Event: "phase_start", Phase: "mag"})
 
 	magOffset, magScale, magConf, magStats, err := guidedMag(in, readFn, magDurationDefault)
 	if err != nil {
@@ -262,51 +599,81 @@ func main() {
 	res.MagScale = magScale
 	res.Confidence.Mag = magConf
 	res.MagStats = magStats
+	emit(calEvent{Event: "stats", Phase: "mag", Stats: magStats})
+	emitConfidence("mag", magConf)
 
-	fmt.Printf("Mag offset (counts): X=%.2f Y=%.2f Z=%.2f\n", magOffset.X, magOffset.Y, magOffset.Z)
-	fmt.Printf("Mag scale (counts):  X=%.2f Y=%.2f Z=%.2f | confidence=%.2f\n",
+	fmt.Fprintf(console, "Mag offset (µT): X=%.2f Y=%.2f Z=%.2f\n", magOffset.X, magOffset.Y, magOffset.Z)
+	fmt.Fprintf(console, "Mag scale:       X=%.3f Y=%.3f Z=%.3f | confidence=%.2f\n",
 		magScale.X, magScale.Y, magScale.Z, magConf)
+}
 
-	// ---------------- Overall confidence + store ----------------
-	res.Confidence.Overall = overallConfidence(res.Confidence.GyroStatic, res.Confidence.GyroRot, res.Confidence.Accel6Pt, res.Confidence.Mag)
-
-	if err := writeResult(res); err != nil {
-		fatal(err)
+// retryOrSave asks what to do with a low-confidence calibration: true to
+// retry, false to save anyway. Quitting exits without saving.
+func retryOrSave(in *bufio.Reader) bool {
+	for {
+		fmt.Fprint(console, "[R]etry calibration, [s]ave anyway or [q]uit without saving? (default: R): ")
+		line, _ := in.ReadString('\n')
+		switch strings.TrimSpace(strings.ToUpper(line)) {
+		case "", "R":
+			return true
+		case "S":
+			return false
+		case "Q":
+			fmt.Fprintln(console, "Calibration discarded.")
+			os.Exit(1)
+		}
+		fmt.Fprintln(console, "Invalid input. Type 'R', 'S' or 'Q'.")
 	}
-
-	fmt.Println("\nCalibration complete.")
-	fmt.Printf("Overall confidence: %.2f\n", res.Confidence.Overall)
-	fmt.Println("Saved to ./inertial_calibration.json")
 }
 
 // ---------- IMU selection ----------
 
-func pickIMU(in *bufio.Reader, leftOK, rightOK bool, mgr *sensors.IMUManager) (string, func() (imu.IMURaw, error)) {
+// pickIMU returns the IMU to calibrate: want ("left", "right" or "" = ask,
+// left with -auto) if available, else the only available one.
+func pickIMU(in *bufio.Reader, want string, leftOK, rightOK bool, mgr *sensors.IMUManager) (string, func() (imu.IMURaw, error), error) {
+	left := func() (imu.IMURaw, error) { return mgr.ReadLeftIMU() }
+	right := func() (imu.IMURaw, error) { return mgr.ReadRightIMU() }
+	switch {
+	case want == "left" && !leftOK, want == "right" && !rightOK:
+		return "", nil, fmt.Errorf("%s IMU not available", want)
+	case want == "left":
+		return "left", left, nil
+	case want == "right":
+		return "right", right, nil
+	}
+
 	if leftOK && !rightOK {
-		fmt.Println("Only left IMU available, using left IMU.")
-		time.Sleep(5 * time.Second)
-		return "left", func() (imu.IMURaw, error) { return mgr.ReadLeftIMU() }
+		fmt.Fprintln(console, "Only left IMU available, using left IMU.")
+		if !autoMode {
+			time.Sleep(5 * time.Second)
+		}
+		return "left", left, nil
 	}
 	if rightOK && !leftOK {
-		fmt.Println("Only right IMU available, using right IMU.")
-		time.Sleep(5 * time.Second)
-		return "right", func() (imu.IMURaw, error) { return mgr.ReadRightIMU() }
+		fmt.Fprintln(console, "Only right IMU available, using right IMU.")
+		if !autoMode {
+			time.Sleep(5 * time.Second)
+		}
+		return "right", right, nil
+	}
+	if autoMode {
+		return "left", left, nil
 	}
 
-	fmt.Println()
-	fmt.Println("Both IMUs available.")
+	fmt.Fprintln(console)
+	fmt.Fprintln(console, "Both IMUs available.")
 	time.Sleep(5 * time.Second) // Give user time to see the message
 	for {
-		fmt.Print("Select IMU to calibrate [L/R] (default: L): ")
+		fmt.Fprint(console, "Select IMU to calibrate [L/R] (default: L): ")
 		line, _ := in.ReadString('\n')
 		line = strings.TrimSpace(strings.ToUpper(line))
 		if line == "" || line == "L" {
-			return "left", func() (imu.IMURaw, error) { return mgr.ReadLeftIMU() }
+			return "left", left, nil
 		}
 		if line == "R" {
-			return "right", func() (imu.IMURaw, error) { return mgr.ReadRightIMU() }
+			return "right", right, nil
 		}
-		fmt.Println("Invalid input. Type 'L' or 'R'.")
+		fmt.Fprintln(console, "Invalid input. Type 'L' or 'R'.")
 	}
 }
 
@@ -320,11 +687,17 @@ func guidedGyroRotations(in *bufio.Reader, readFn func() (imu.IMURaw, error), bS
 	}
 	results := []axisResult{}
 
+	maxDur := gyroRotMaxDur
+	if autoMode {
+		maxDur = gyroRotAutoDur
+	}
 	for _, axis := range []string{"x", "y", "z"} {
-		fmt.Printf("Axis %s rotation: rotate mostly around %s-axis (2–3 full turns).\n", strings.ToUpper(axis), strings.ToUpper(axis))
+		phase := "gyro_rotation_" + axis
+		fmt.Fprintf(console, "Axis %s rotation: rotate mostly around %s-axis (2–3 full turns).\n", strings.ToUpper(axis), strings.ToUpper(axis))
 		waitEnter(in, "Press ENTER to start capture, then ENTER again to stop...")
+		emit(calEvent{Event: "phase_start", Phase: phase})
 
-		rotSamples, stats, err := captureUntilEnterOrTimeout(in, readFn, gyroRotMaxDur, func(r imu.IMURaw) Vec3 {
+		rotSamples, stats, err := captureUntilEnterOrTimeout(in, readFn, maxDur, func(r imu.IMURaw) Vec3 {
 			// subtract static bias before integrating & stats
 			return Vec3{
 				X: float64(r.Gx) - bStatic.X,
@@ -333,7 +706,8 @@ func guidedGyroRotations(in *bufio.Reader, readFn func() (imu.IMURaw, error), bS
 			}
 		})
 		if err != nil {
-			fmt.Printf("Warning: rotation capture failed for axis %s: %v\n", axis, err)
+			fmt.Fprintf(console, "Warning: rotation capture failed for axis %s: %v\n", axis, err)
+			emit(calEvent{Event: "warning", Phase: phase, Message: err.Error()})
 			stats.Notes = append(stats.Notes, "capture_error: "+err.Error())
 			res.GyroRotStats[axis] = stats
 			results = append(results, axisResult{axis: axis, bias: 0, conf: confFloor})
@@ -362,24 +736,22 @@ func guidedGyroRotations(in *bufio.Reader, readFn func() (imu.IMURaw, error), bS
 		}
 
 		// Confidence heuristic for this axis
-		conf := rotationConfidence(axis, stats)
+		conf := calibration.RotationConfidence(stats.DurationSec, dominantForAxis(axis, stats.AxisDominance), meanAbsForAxis(axis, stats.MeanAbs))
 
 		res.GyroRotStats[axis] = stats
 		results = append(results, axisResult{axis: axis, bias: b, conf: conf})
+		emit(calEvent{Event: "stats", Phase: phase, Stats: stats})
+		emitConfidence(phase, conf)
 
-		fmt.Printf("  Axis %s: residual bias=%.2f counts | dominance=%.2f | meanAbs=%.2f | conf=%.2f\n",
+		fmt.Fprintf(console, "  Axis %s: residual bias=%.2f counts | dominance=%.2f | meanAbs=%.2f | conf=%.2f\n",
 			strings.ToUpper(axis), b, dominantForAxis(axis, stats.AxisDominance), meanAbsForAxis(axis, stats.MeanAbs), conf)
 	}
 
 	// Combine axis biases
 	bDyn := Vec3{}
-	conf := 0.0
-	weights := 0.0
-
+	confs := make([]float64, 0, len(results))
 	for _, r := range results {
-		w := clamp01(r.conf)
-		weights += w
-		conf += w * r.conf
+		confs = append(confs, r.conf)
 		switch r.axis {
 		case "x":
 			bDyn.X = r.bias
@@ -389,12 +761,7 @@ func guidedGyroRotations(in *bufio.Reader, readFn func() (imu.IMURaw, error), bS
 			bDyn.Z = r.bias
 		}
 	}
-	if weights > 0 {
-		conf = conf / weights
-	} else {
-		conf = confFloor
-	}
-	return bDyn, clamp01(conf)
+	return bDyn, calibration.CombineRotationConfidence(confs)
 }
 
 // ---------- Guided accel 6-point ----------
@@ -410,18 +777,55 @@ func guidedAccel6Point(in *bufio.Reader, readFn func() (imu.IMURaw, error)) (bia
 	}
 	data := map[string]poseData{}
 
-	for _, p := range poses {
-		fmt.Printf("Pose %s UP: place the device so %s axis points upward, then keep it still.\n", p, p)
-		waitEnter(in, "Press ENTER to start capture (6s)...")
+	// The poses may be done in any order: a short probe detects which axis
+	// is up before each capture, and the capture must confirm it. Tilted
+	// placements and poses already captured are rejected with guidance.
+	accel := func(r imu.IMURaw) Vec3 {
+		return Vec3{X: float64(r.Ax), Y: float64(r.Ay), Z: float64(r.Az)}
+	}
+	for attempt := 0; len(data) < len(poses); attempt++ {
+		var done, remaining []string
+		for _, p := range poses {
+			if _, ok := data[p]; ok {
+				done = append(done, p+" ✓")
+			} else {
+				remaining = append(remaining, p)
+			}
+		}
+		if autoMode && attempt >= 2*len(poses) {
+			return Vec3{}, Vec3{}, 0, poseStats, fmt.Errorf("accelerometer calibration failed: poses %s not captured", strings.Join(remaining, ", "))
+		}
 
-		_, stats, e := captureSamples(readFn, accelPoseDuration, func(r imu.IMURaw) Vec3 {
-			return Vec3{X: float64(r.Ax), Y: float64(r.Ay), Z: float64(r.Az)}
-		})
+		fmt.Fprintf(console, "\nPoses captured: [%s]  remaining: [%s]\n", strings.Join(done, " "), strings.Join(remaining, " "))
+		fmt.Fprintln(console, "Place the device with one of the remaining axes pointing upward, then keep it still.")
+		waitEnter(in, "Press ENTER when in place...")
+
+		_, probe, e := captureSamples(readFn, poseProbeDuration, accel)
+		if e != nil {
+			return Vec3{}, Vec3{}, 0, nil, e
+		}
+		p, tilt := nearestPoseUp(probe.Mean)
+		if tilt > poseMaxTiltDeg {
+			warnPose(p, fmt.Sprintf("tilted %.0f° from %s up (max %.0f°): set the device flat on one face and try again", tilt, p, poseMaxTiltDeg))
+			continue
+		}
+		if _, dup := data[p]; dup {
+			warnPose(p, fmt.Sprintf("%s up is already captured: turn the device to one of %s", p, strings.Join(remaining, ", ")))
+			continue
+		}
+
+		fmt.Fprintf(console, "Detected %s UP (%.0f° tilt). Capturing %v, keep it still...\n", p, tilt, accelPoseDuration)
+		emit(calEvent{Event: "phase_start", Phase: "accel_pose_" + p})
+		_, stats, e := captureSamples(readFn, accelPoseDuration, accel)
 		if e != nil {
 			return Vec3{}, Vec3{}, 0, nil, e
 		}
+		if got, tilt := nearestPoseUp(stats.Mean); got != p || tilt > poseMaxTiltDeg {
+			warnPose(p, "device moved during the capture, capturing again")
+			continue
+		}
 
-		c := stillnessConfidence(stats.StdDev)
+		c := calibration.StillnessConfidence(stats.StdDev.array())
 		data[p] = poseData{pose: p, mean: stats.Mean, std: stats.StdDev, conf: c}
 		poseStats = append(poseStats, AccelPoseStats{
 			Pose:        p,
@@ -431,8 +835,10 @@ func guidedAccel6Point(in *bufio.Reader, readFn func() (imu.IMURaw, error)) (bia
 			StdDev:      stats.StdDev,
 			Confidence:  c,
 		})
+		emit(calEvent{Event: "stats", Phase: "accel_pose_" + p, Stats: poseStats[len(poseStats)-1]})
+		emitConfidence("accel_pose_"+p, c)
 
-		fmt.Printf("  Pose %s: mean=(%.1f, %.1f, %.1f) std=(%.1f, %.1f, %.1f) conf=%.2f\n",
+		fmt.Fprintf(console, "  Pose %s: mean=(%.1f, %.1f, %.1f) std=(%.1f, %.1f, %.1f) conf=%.2f\n",
 			p, stats.Mean.X, stats.Mean.Y, stats.Mean.Z, stats.StdDev.X, stats.StdDev.Y, stats.StdDev.Z, c)
 	}
 
@@ -478,36 +884,127 @@ func guidedAccel6Point(in *bufio.Reader, readFn func() (imu.IMURaw, error)) (bia
 	scale = Vec3{X: gx, Y: gy, Z: gz}
 
 	// Confidence: combine pose stillness confidences and gravity consistency
-	poseConf := 0.0
+	poseConfs := make([]float64, 0, len(poses))
 	for _, p := range poses {
-		poseConf += data[p].conf
+		poseConfs = append(poseConfs, data[p].conf)
 	}
-	poseConf /= float64(len(poses))
+	confidence = calibration.Accel6PointConfidence(poseConfs, gx, gy, gz)
+	return bias, scale, confidence, poseStats, nil
+}
 
-	consistency := gravityConsistencyConfidence(gx, gy, gz)
-	confidence = clamp01(0.65*poseConf + 0.35*consistency)
-	if confidence < confFloor {
-		confidence = confFloor
+// nearestPoseUp returns the pose name ("+X" … "-Z") of the sensor axis
+// closest to pointing up for a static mean accel vector (counts; the
+// accelerometer reads +1 g on the axis pointing up) and its angle from
+// vertical in degrees.
+func nearestPoseUp(mean Vec3) (pose string, tiltDeg float64) {
+	v := [3]float64{mean.X, mean.Y, mean.Z}
+	norm := math.Sqrt(v[0]*v[0] + v[1]*v[1] + v[2]*v[2])
+	if norm == 0 {
+		return "", 90
 	}
-	return bias, scale, confidence, poseStats, nil
+	axis := 0
+	for i := 1; i < 3; i++ {
+		if math.Abs(v[i]) > math.Abs(v[axis]) {
+			axis = i
+		}
+	}
+	sign := "+"
+	if v[axis] < 0 {
+		sign = "-"
+	}
+	return sign + string("XYZ"[axis]), math.Acos(math.Abs(v[axis])/norm) * 180 / math.Pi
+}
+
+// warnPose reports an accel pose problem on the console and as an event.
+func warnPose(pose, msg string) {
+	fmt.Fprintf(console, "  WARNING: pose %s: %s\n", pose, msg)
+	emit(calEvent{Event: "warning", Phase: "accel_pose_" + pose, Message: msg})
 }
 
-func gravityConsistencyConfidence(gx, gy, gz float64) float64 {
-	m := (gx + gy + gz) / 3
-	if m <= 0 {
-		return confFloor
+// guidedAccelMatrix offers extra static poses after the 6-point capture and
+// fits the accel scale/misalignment matrix over all of them. Extra poses are
+// named by the axes pointing up, e.g. "+Z" again or "+X+Y" for resting on an
+// edge; more poses average out placement errors.
+func guidedAccelMatrix(in *bufio.Reader, readFn func() (imu.IMURaw, error), sixPoint []AccelPoseStats) (bias [3]float64, m [3][3]float64, rmsErr float64, extra []AccelPoseStats, err error) {
+	var poses []imu.AccelPose
+	for _, ps := range sixPoint {
+		up, _ := poseUp(ps.Pose)
+		poses = append(poses, imu.AccelPose{Up: up, Mean: [3]float64{ps.Mean.X, ps.Mean.Y, ps.Mean.Z}})
 	}
-	// coefficient of variation
-	cv := std3(gx, gy, gz) / m
-	// map: cv 0 -> 1.0, cv 0.15 -> ~0.7, cv 0.35 -> ~0.3
-	return clamp01(1.0 - (cv / 0.5))
+
+	fmt.Fprintln(console)
+	fmt.Fprintln(console, "Optional extra accel poses improve the matrix fit: repeat a pose (e.g. +Z)")
+	fmt.Fprintln(console, "or rest the device on an edge (e.g. +X+Y = the X/Y edge up, at 45°).")
+	for !autoMode {
+		fmt.Fprint(console, "Extra pose (ENTER to finish): ")
+		emit(calEvent{Event: "prompt", Phase: "accel_matrix", Message: "extra pose name, empty line to finish"})
+		line, _ := in.ReadString('\n')
+		name := strings.ToUpper(strings.TrimSpace(line))
+		if name == "" {
+			break
+		}
+		up, ok := poseUp(name)
+		if !ok {
+			fmt.Fprintln(console, "Invalid pose. Use signed axes like +X, -Z or +X+Y.")
+			continue
+		}
+		waitEnter(in, "Keep the device still and press ENTER to start capture (6s)...")
+		emit(calEvent{Event: "phase_start", Phase: "accel_pose_" + name})
+		_, stats, e := captureSamples(readFn, accelPoseDuration, func(r imu.IMURaw) Vec3 {
+			return Vec3{X: float64(r.Ax), Y: float64(r.Ay), Z: float64(r.Az)}
+		})
+		if e != nil {
+			return bias, m, 0, extra, e
+		}
+		c := calibration.StillnessConfidence(stats.StdDev.array())
+		extra = append(extra, AccelPoseStats{
+			Pose:        name,
+			Samples:     stats.Samples,
+			DurationSec: stats.DurationSec,
+			Mean:        stats.Mean,
+			StdDev:      stats.StdDev,
+			Confidence:  c,
+		})
+		poses = append(poses, imu.AccelPose{Up: up, Mean: [3]float64{stats.Mean.X, stats.Mean.Y, stats.Mean.Z}})
+		emit(calEvent{Event: "stats", Phase: "accel_pose_" + name, Stats: extra[len(extra)-1]})
+		fmt.Fprintf(console, "  Pose %s: mean=(%.1f, %.1f, %.1f) conf=%.2f\n", name, stats.Mean.X, stats.Mean.Y, stats.Mean.Z, c)
+	}
+
+	bias, m, rmsErr, err = imu.FitAccelMatrix(poses)
+	return bias, m, rmsErr, extra, err
+}
+
+// poseUp parses a pose name made of signed axes ("+X", "-Y+Z") into the unit
+// direction pointing up in the sensor frame.
+func poseUp(name string) ([3]float64, bool) {
+	var up [3]float64
+	if name == "" || len(name)%2 != 0 {
+		return up, false
+	}
+	for i := 0; i < len(name); i += 2 {
+		sign := 1.0
+		switch name[i] {
+		case '+':
+		case '-':
+			sign = -1
+		default:
+			return up, false
+		}
+		axis := strings.IndexByte("XYZ", name[i+1])
+		if axis < 0 || up[axis] != 0 {
+			return up, false
+		}
+		up[axis] = sign
+	}
+	n := math.Sqrt(up[0]*up[0] + up[1]*up[1] + up[2]*up[2])
+	return [3]float64{up[0] / n, up[1] / n, up[2] / n}, true
 }
 
 // ---------- Guided mag calibration ----------
 
 func guidedMag(in *bufio.Reader, readFn func() (imu.IMURaw, error), maxDur time.Duration) (offset Vec3, scale Vec3, confidence float64, stats PhaseStats, err error) {
 	magSamples, st, err := captureUntilEnterOrTimeout(in, readFn, maxDur, func(r imu.IMURaw) Vec3 {
-		return Vec3{X: float64(r.Mx), Y: float64(r.My), Z: float64(r.Mz)}
+		return Vec3{X: imu.MagUT(r.Mx), Y: imu.MagUT(r.My), Z: imu.MagUT(r.Mz)}
 	})
 	if err != nil {
 		return Vec3{}, Vec3{}, 0, PhaseStats{}, err
@@ -526,79 +1023,89 @@ func guidedMag(in *bufio.Reader, readFn func() (imu.IMURaw, error), maxDur time.
 		maxV.Z = math.Max(maxV.Z, s.Z)
 	}
 
-	offset = Vec3{
-		X: (maxV.X + minV.X) / 2,
-		Y: (maxV.Y + minV.Y) / 2,
-		Z: (maxV.Z + minV.Z) / 2,
-	}
+	cal := imu.MagCalibrationFromRange([3]float64{minV.X, minV.Y, minV.Z}, [3]float64{maxV.X, maxV.Y, maxV.Z})
+	offset = Vec3{X: cal.OffsetUT[0], Y: cal.OffsetUT[1], Z: cal.OffsetUT[2]}
 	halfRange := Vec3{
 		X: (maxV.X - minV.X) / 2,
 		Y: (maxV.Y - minV.Y) / 2,
 		Z: (maxV.Z - minV.Z) / 2,
 	}
 
-	// Guard
-	if halfRange.X < 1 || halfRange.Y < 1 || halfRange.Z < 1 {
+	// Guard (0.1 µT = one mag count)
+	if halfRange.X < 0.1 || halfRange.Y < 0.1 || halfRange.Z < 0.1 {
 		stats.Notes = append(stats.Notes, "insufficient_mag_excitation: rotate more in 3D / move away from metal")
 		return offset, Vec3{X: 1, Y: 1, Z: 1}, confFloor, stats, nil
 	}
 
-	// Scale: normalize axes to common radius (average half-range)
-	rRef := (halfRange.X + halfRange.Y + halfRange.Z) / 3
-	scale = Vec3{
-		X: halfRange.X / rRef,
-		Y: halfRange.Y / rRef,
-		Z: halfRange.Z / rRef,
-	}
-	// Store scale in "counts" half-range as the divisor (like accel): corrected = (raw-offset)/halfRange * rRef
-	// For simplicity, store halfRange directly:
-	scale = halfRange
+	// Scale: normalize axes to common radius (average half-range), multiplied
+	// after removing the offset
+	scale = Vec3{X: cal.Scale[0], Y: cal.Scale[1], Z: cal.Scale[2]}
 
 	// Confidence based on coverage and sphericity after correction
-	coverage := magCoverageConfidence(halfRange)
-	sphericity := magSphericityConfidence(magSamples, offset, scale)
-
-	confidence = clamp01(0.55*coverage + 0.45*sphericity)
-	if confidence < confFloor {
-		confidence = confFloor
+	samples := make([][3]float64, len(magSamples))
+	for i, s := range magSamples {
+		samples[i] = s.array()
 	}
+	confidence = calibration.MagConfidence(halfRange.array(), samples, offset.array(), scale.array())
 	return offset, scale, confidence, stats, nil
 }
 
-func magCoverageConfidence(halfRange Vec3) float64 {
-	// Encourage balanced excitation across axes
-	m := (halfRange.X + halfRange.Y + halfRange.Z) / 3
-	if m <= 0 {
-		return confFloor
+// ---------- Sampling helpers ----------
+
+// warmupIMU reads and discards samples for dur. With stdThreshold > 0 it then
+// keeps reading in warmupWindow windows until the mean gyro std dev of a
+// window is at or below stdThreshold, for at most warmupMaxSettling.
+func warmupIMU(readFn func() (imu.IMURaw, error), dur time.Duration, stdThreshold float64) (WarmupStats, error) {
+	start := time.Now()
+	ws := WarmupStats{StdThreshold: stdThreshold, Stable: true}
+	gyro := func(r imu.IMURaw) Vec3 {
+		return Vec3{X: float64(r.Gx), Y: float64(r.Gy), Z: float64(r.Gz)}
 	}
-	cv := std3(halfRange.X, halfRange.Y, halfRange.Z) / m
-	return clamp01(1.0 - (cv / 0.7))
-}
 
-func magSphericityConfidence(samples []Vec3, offset Vec3, halfRange Vec3) float64 {
-	// Apply simple correction: (raw-offset)/halfRange (dimensionless) then check norm stability.
-	// If rotation covers all orientations, norms should be near-constant.
-	n := len(samples)
-	if n < 50 {
-		return confFloor
+	// Fixed warmup, reported in windows so the user sees progress
+	var prev *PhaseStats
+	window := func() (PhaseStats, error) {
+		values, st, err := captureSamples(readFn, warmupWindow, gyro)
+		if err != nil {
+			return PhaseStats{}, err
+		}
+		ws.Samples += len(values)
+		ws.FinalStdDev = st.StdDev
+		if prev != nil {
+			ws.FinalDrift = Vec3{X: st.Mean.X - prev.Mean.X, Y: st.Mean.Y - prev.Mean.Y, Z: st.Mean.Z - prev.Mean.Z}
+		}
+		prev = &st
+		return st, nil
 	}
-	norms := make([]float64, 0, n)
-	for _, s := range samples {
-		x := (s.X - offset.X) / safeDiv(halfRange.X)
-		y := (s.Y - offset.Y) / safeDiv(halfRange.Y)
-		z := (s.Z - offset.Z) / safeDiv(halfRange.Z)
-		norms = append(norms, math.Sqrt(x*x+y*y+z*z))
+
+	for time.Since(start) < dur {
+		st, err := window()
+		if err != nil {
+			return ws, err
+		}
+		fmt.Fprintf(console, "  warmup %3.0fs/%.0fs  gyro std %.2f\n", time.Since(start).Seconds(), dur.Seconds(), (st.StdDev.X+st.StdDev.Y+st.StdDev.Z)/3)
 	}
-	mean, sd := meanStd(norms)
-	if mean <= 0 {
-		return confFloor
+
+	if stdThreshold > 0 {
+		ws.Stable = false
+		settleStart := time.Now()
+		for time.Since(settleStart) < warmupMaxSettling {
+			st, err := window()
+			if err != nil {
+				return ws, err
+			}
+			s := (st.StdDev.X + st.StdDev.Y + st.StdDev.Z) / 3
+			if s <= stdThreshold {
+				ws.Stable = true
+				break
+			}
+			fmt.Fprintf(console, "  settling: gyro std %.2f > %.2f\n", s, stdThreshold)
+		}
 	}
-	cv := sd / mean
-	// map: cv 0.05 -> ~0.9, cv 0.15 -> ~0.7, cv 0.35 -> ~0.3
-	return clamp01(1.0 - (cv / 0.5))
-}
 
-// ---------- Sampling helpers ----------
+	ws.DurationSec = time.Since(start).Seconds()
+	return ws, nil
+}
 
 type sample struct {
 	T time.Time
@@ -624,7 +1131,12 @@ func captureSamples(readFn func() (imu.IMURaw, error), dur time.Duration, f func
 	return values, stats, nil
 }
 
+// captureUntilEnterOrTimeout captures until ENTER or maxDur; with -auto it
+// always captures for maxDur.
 func captureUntilEnterOrTimeout(in *bufio.Reader, readFn func() (imu.IMURaw, error), maxDur time.Duration, f func(imu.IMURaw) Vec3) ([]Vec3, PhaseStats, error) {
+	if autoMode {
+		return captureSamples(readFn, maxDur, f)
+	}
 	start := time.Now()
 	deadline := start.Add(maxDur)
 
@@ -719,54 +1231,7 @@ func integrate(values []Vec3) Vec3 {
 	return Vec3{X: ix, Y: iy, Z: iz}
 }
 
-// ---------- Confidence heuristics ----------
-
-func stillnessConfidence(std Vec3) float64 {
-	// Use average std dev across axes.
-	s := (std.X + std.Y + std.Z) / 3
-	switch {
-	case s <= stillStdGood:
-		return 1.0
-	case s >= stillStdBad:
-		return confFloor
-	default:
-		// Linear interpolation between good and bad
-		t := (s - stillStdGood) / (stillStdBad - stillStdGood)
-		return clamp01(1.0 - 0.95*t)
-	}
-}
-
-func rotationConfidence(axis string, st PhaseStats) float64 {
-	dom := dominantForAxis(axis, st.AxisDominance)
-	meanAbs := meanAbsForAxis(axis, st.MeanAbs)
-
-	// Duration factor
-	durFactor := clamp01(st.DurationSec / gyroRotMinDur.Seconds())
-	if st.DurationSec > gyroRotMaxDur.Seconds() {
-		durFactor = 1
-	}
-
-	// Dominance factor
-	var domFactor float64
-	switch {
-	case dom >= dominanceGood:
-		domFactor = 1
-	case dom <= dominanceBad:
-		domFactor = 0.2
-	default:
-		t := (dom - dominanceBad) / (dominanceGood - dominanceBad)
-		domFactor = 0.2 + 0.8*clamp01(t)
-	}
-
-	// Rotation magnitude factor
-	rateFactor := 0.2
-	if meanAbs >= minMeanAbsRate {
-		// Let it grow to 1.0 by ~4x threshold
-		rateFactor = clamp01(meanAbs / (4 * minMeanAbsRate))
-	}
-	conf := 0.25*durFactor + 0.45*domFactor + 0.30*rateFactor
-	return clamp01(max(conf, confFloor))
-}
+// ---------- Rotation axis helpers ----------
 
 func axisDominance(meanAbs Vec3) Vec3 {
 	sum := meanAbs.X + meanAbs.Y + meanAbs.Z
@@ -806,88 +1271,105 @@ func meanAbsForAxis(axis string, v Vec3) float64 {
 	}
 }
 
-func overallConfidence(gyroStatic, gyroRot, accel6, mag float64) float64 {
-	// Weighted; gyro static is foundational, mag matters for yaw.
-	wGS, wGR, wA, wM := 0.20, 0.20, 0.25, 0.35
-	return clamp01(wGS*gyroStatic + wGR*gyroRot + wA*accel6 + wM*mag)
-}
-
 // ---------- Output ----------
 
-func writeResult(res CalibrationResult) error {
+// writeResult writes res to a timestamped file and returns its name.
+func writeResult(res CalibrationResult) (string, error) {
 	ts := time.Now().Format("2006-01-02T15-04-05Z07-00")
 	name := fmt.Sprintf("%s_%s_inertial_calibration.json", res.IMU, ts)
 
 	b, err := json.MarshalIndent(res, "", "  ")
 	if err != nil {
-		return err
+		return "", err
 	}
 	if err := os.WriteFile(name, b, 0o644); err != nil {
-		return err
+		return "", err
 	}
-	fmt.Printf("\nWrote: %s\n", name)
-	return nil
-}
-
-// ---------- Console helpers ----------
-
-func waitEnter(in *bufio.Reader, prompt string) {
-	fmt.Print(prompt)
-	_, _ = in.ReadString('\n')
-}
-
-func fatal(err error) {
-	fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
-	os.Exit(1)
+	fmt.Fprintf(console, "\nWrote: %s\n", name)
+	return name, nil
 }
 
-// ---------- Small math helpers ----------
+// fitAccelScaleTemp fits accel scale temperature coefficients from several
+// calibrations of one IMU and writes the most recent one with the fitted
+// scale and coefficients as a new calibration file.
+func fitAccelScaleTemp(files []string) error {
+	var points []imu.AccelScaleTempPoint
+	var latest CalibrationResult
+	for i, name := range files {
+		name = strings.TrimSpace(name)
+		b, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		var r CalibrationResult
+		if err := json.Unmarshal(b, &r); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if r.SchemaVersion != imu.CLISchemaVersion && r.SchemaVersion != 2 {
+			return fmt.Errorf("%s: schema_version %d, need 2 or %d", name, r.SchemaVersion, imu.CLISchemaVersion)
+		}
+		if r.AccelTempC == 0 {
+			return fmt.Errorf("%s: no accel_temp_c (calibrated before die temperature was recorded)", name)
+		}
+		if i > 0 && r.IMU != latest.IMU {
+			return fmt.Errorf("%s: IMU %q, expected %q", name, r.IMU, latest.IMU)
+		}
+		if i == 0 || r.CalibrationAt > latest.CalibrationAt {
+			latest = r
+		}
+		points = append(points, imu.AccelScaleTempPoint{
+			TempC: r.AccelTempC,
+			Scale: [3]float64{r.AccelScale.X, r.AccelScale.Y, r.AccelScale.Z},
+		})
+		fmt.Fprintf(console, "%s: %.1f °C, accel scale X=%.2f Y=%.2f Z=%.2f\n", name, r.AccelTempC, r.AccelScale.X, r.AccelScale.Y, r.AccelScale.Z)
+	}
 
-func clamp01(x float64) float64 {
-	if x < 0 {
-		return 0
+	scale, coeff, refTempC, err := imu.FitAccelScaleTemp(points)
+	if err != nil {
+		return err
 	}
-	if x > 1 {
-		return 1
+	fmt.Fprintf(console, "Fitted accel scale at %.1f °C: X=%.2f Y=%.2f Z=%.2f\n", refTempC, scale[0], scale[1], scale[2])
+	fmt.Fprintf(console, "Temperature coefficients (ppm/°C): X=%.1f Y=%.1f Z=%.1f\n", coeff[0]*1e6, coeff[1]*1e6, coeff[2]*1e6)
+
+	latest.AccelScale = Vec3{X: scale[0], Y: scale[1], Z: scale[2]}
+	latest.AccelScaleTempCoeff = &Vec3{X: coeff[0], Y: coeff[1], Z: coeff[2]}
+	latest.AccelScaleRefTempC = refTempC
+	latest.CalibrationAt = time.Now().Format(time.RFC3339)
+	latest.Notes = append(latest.Notes, fmt.Sprintf("accel scale temperature fit from %d calibrations", len(points)))
+	name, err := writeResult(latest)
+	if err != nil {
+		return err
 	}
-	return x
+	emit(calEvent{Event: "result", IMU: latest.IMU, File: name, Result: &latest})
+	return nil
 }
 
-func safeDiv(x float64) float64 {
-	if math.Abs(x) < 1e-9 {
-		if x >= 0 {
-			return 1e-9
-		}
-		return -1e-9
-	}
-	return x
-}
+// ---------- Console helpers ----------
 
-func meanStd(xs []float64) (mean float64, sd float64) {
-	if len(xs) == 0 {
-		return 0, 0
-	}
-	for _, v := range xs {
-		mean += v
-	}
-	mean /= float64(len(xs))
-	var s float64
-	for _, v := range xs {
-		d := v - mean
-		s += d * d
+// waitEnter shows prompt and waits for ENTER; with -auto it waits autoDelay
+// instead.
+func waitEnter(in *bufio.Reader, prompt string) {
+	if autoMode {
+		countdown(autoDelay)
+		return
 	}
-	sd = math.Sqrt(s / float64(len(xs)))
-	return mean, sd
+	fmt.Fprint(console, prompt)
+	emit(calEvent{Event: "prompt", Message: prompt})
+	_, _ = in.ReadString('\n')
 }
 
-func std3(a, b, c float64) float64 {
-	m := (a + b + c) / 3
-	return math.Sqrt(((a-m)*(a-m) + (b-m)*(b-m) + (c-m)*(c-m)) / 3)
+// countdown waits d, showing the seconds left (-auto).
+func countdown(d time.Duration) {
+	emit(calEvent{Event: "countdown", Message: fmt.Sprintf("capture starts in %v", d)})
+	for left := d; left > 0; left -= time.Second {
+		fmt.Fprintf(console, "\r  starting in %2.0fs ", math.Ceil(left.Seconds()))
+		time.Sleep(min(left, time.Second))
+	}
+	fmt.Fprintln(console, "\r  capturing...      ")
 }
 
-func max(a, b float64) float64 {
-	if a > b {
-		return a
-	}
-	return b
+func fatal(err error) {
+	emit(calEvent{Event: "error", Message: err.Error()})
+	fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+	os.Exit(1)
 }
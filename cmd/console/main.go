@@ -6,7 +6,11 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/relabs-tech/inertial_computer/internal/app"
 )
@@ -14,7 +18,10 @@ import (
 func main() {
 	log.Println("starting inertial-computer (mock console)")
 
-	if err := app.RunMockConsole(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := app.RunMockConsole(ctx); err != nil {
 		log.Fatalf("fatal: %v", err)
 	}
 }
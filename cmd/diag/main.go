@@ -0,0 +1,225 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+// ./cmd/diag/main.go
+//
+// Startup diagnostics for a new board: checks the config, both IMUs
+// (MPU9250 WHO_AM_I, AK8963 WIA), both BMPs (chip id), that the GPS serial
+// port opens and that the MQTT broker accepts a connection, then prints a
+// pass/fail table. Exits non-zero if any check fails. Nothing is published or
+// written; run it with the producers stopped, since it opens the same
+// devices.
+//
+// Run:
+//
+//	go run ./cmd/diag
+//	go run ./cmd/diag -config /etc/inertial_config.txt
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	serial "github.com/jacobsa/go-serial/serial"
+
+	"github.com/relabs-tech/inertial_computer/internal/config"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
+	"github.com/relabs-tech/inertial_computer/internal/sensors"
+)
+
+// Expected device ids (see sensors.imuSource)
+const (
+	mpu9250WhoAmI = 0x71
+	ak8963WIA     = 0x48
+)
+
+// mqttTimeout bounds the broker connection check.
+const mqttTimeout = 5 * time.Second
+
+// Check results
+const (
+	statusPass = "PASS"
+	statusFail = "FAIL"
+	statusSkip = "SKIP"
+)
+
+type check struct {
+	name   string
+	status string
+	detail string
+}
+
+func main() {
+	configPath := flag.String("config", "inertial_config.txt", "Path to configuration file")
+	flag.Parse()
+
+	var checks []check
+	add := func(name, status, detail string) {
+		checks = append(checks, check{name, status, detail})
+	}
+
+	// Everything else depends on the config
+	if err := config.InitGlobal(*configPath); err != nil {
+		add("config", statusFail, err.Error())
+		os.Exit(report(checks))
+	}
+	cfg := config.Get()
+	add("config", statusPass, *configPath)
+	if err := logging.Setup(cfg.LogLevel, cfg.LogFormat); err != nil {
+		log.Fatalf("failed to set up logging: %v", err)
+	}
+
+	checkIMUs(cfg, add)
+	checkBMPs(add)
+	checkGPS(cfg, add)
+	checkMQTT(cfg, add)
+
+	os.Exit(report(checks))
+}
+
+// checkIMUs initializes the IMU manager and checks each enabled IMU's ids.
+func checkIMUs(cfg *config.Config, add func(name, status, detail string)) {
+	mgr := sensors.GetIMUManager()
+	initErr := mgr.Init()
+
+	sides := []struct {
+		id      string
+		enabled bool
+	}{
+		{"left", cfg.IMULeftEnabled},
+		{"right", cfg.IMURightEnabled},
+	}
+	for _, side := range sides {
+		imuName := side.id + " IMU WHO_AM_I"
+		magName := side.id + " AK8963 WIA"
+		switch {
+		case !side.enabled:
+			add(imuName, statusSkip, fmt.Sprintf("IMU_%s_ENABLED=false", strings.ToUpper(side.id)))
+			add(magName, statusSkip, "IMU disabled")
+			continue
+		case cfg.MockHardware:
+			add(imuName, statusSkip, "MOCK_HARDWARE")
+			add(magName, statusSkip, "MOCK_HARDWARE")
+			continue
+		}
+
+		whoAmI, magWIA, magReady, err := mgr.DeviceIDs(side.id)
+		if err != nil {
+			detail := err.Error()
+			if initErr != nil {
+				detail = initErr.Error()
+			}
+			add(imuName, statusFail, detail+" (see init output above)")
+			add(magName, statusSkip, "IMU not available")
+			continue
+		}
+		if whoAmI == mpu9250WhoAmI {
+			add(imuName, statusPass, fmt.Sprintf("0x%02X", whoAmI))
+		} else {
+			add(imuName, statusFail, fmt.Sprintf("0x%02X, expected 0x%02X", whoAmI, mpu9250WhoAmI))
+		}
+		switch {
+		case magWIA == ak8963WIA && magReady:
+			add(magName, statusPass, fmt.Sprintf("0x%02X", magWIA))
+		case magWIA == ak8963WIA:
+			add(magName, statusFail, fmt.Sprintf("0x%02X but magnetometer setup failed", magWIA))
+		default:
+			add(magName, statusFail, fmt.Sprintf("0x%02X, expected 0x%02X", magWIA, ak8963WIA))
+		}
+	}
+}
+
+// checkBMPs reports the chip detected on each BMP.
+func checkBMPs(add func(name, status, detail string)) {
+	left, right := sensors.EnvStatus()
+	for _, bmp := range []struct {
+		name string
+		info sensors.EnvInfo
+	}{
+		{"left BMP chip id", left},
+		{"right BMP chip id", right},
+	} {
+		switch {
+		case bmp.info.Chip == sensors.ChipMock:
+			add(bmp.name, statusSkip, "MOCK_HARDWARE")
+		case bmp.info.Available:
+			add(bmp.name, statusPass, bmp.info.Chip)
+		default:
+			add(bmp.name, statusFail, bmp.info.Error)
+		}
+	}
+}
+
+// checkGPS opens and closes the GPS serial port.
+func checkGPS(cfg *config.Config, add func(name, status, detail string)) {
+	const name = "GPS serial port"
+	if cfg.MockHardware {
+		add(name, statusSkip, "MOCK_HARDWARE")
+		return
+	}
+	port, err := serial.Open(serial.OpenOptions{
+		PortName:        cfg.GPSSerialPort,
+		BaudRate:        uint(cfg.GPSBaudRate),
+		DataBits:        8,
+		StopBits:        1,
+		MinimumReadSize: 1,
+		ParityMode:      serial.PARITY_NONE,
+	})
+	if err != nil {
+		add(name, statusFail, fmt.Sprintf("%s: %v", cfg.GPSSerialPort, err))
+		return
+	}
+	port.Close()
+	add(name, statusPass, fmt.Sprintf("%s at %d baud", cfg.GPSSerialPort, cfg.GPSBaudRate))
+}
+
+// checkMQTT connects to the broker and disconnects. It uses a bare client
+// rather than app.NewMQTTClient, so no status topic is published.
+func checkMQTT(cfg *config.Config, add func(name, status, detail string)) {
+	const name = "MQTT broker"
+	opts := mqtt.NewClientOptions().
+		AddBroker(cfg.MQTTBroker).
+		SetClientID(fmt.Sprintf("inertial-diag-%d", os.Getpid())).
+		SetConnectTimeout(mqttTimeout).
+		SetAutoReconnect(false)
+	client := mqtt.NewClient(opts)
+	token := client.Connect()
+	if !token.WaitTimeout(mqttTimeout) {
+		add(name, statusFail, fmt.Sprintf("%s: no answer within %s", cfg.MQTTBroker, mqttTimeout))
+		return
+	}
+	if err := token.Error(); err != nil {
+		add(name, statusFail, fmt.Sprintf("%s: %v", cfg.MQTTBroker, err))
+		return
+	}
+	client.Disconnect(250)
+	add(name, statusPass, cfg.MQTTBroker)
+}
+
+// report prints the table and returns the exit code: 1 if any check failed.
+func report(checks []check) int {
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHECK\tRESULT\tDETAIL")
+	code := 0
+	for _, c := range checks {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", c.name, c.status, c.detail)
+		if c.status == statusFail {
+			code = 1
+		}
+	}
+	w.Flush()
+	if code != 0 {
+		fmt.Println("\nSome checks failed")
+	} else {
+		fmt.Println("\nAll checks passed")
+	}
+	return code
+}
@@ -6,10 +6,15 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/relabs-tech/inertial_computer/internal/app"
 	"github.com/relabs-tech/inertial_computer/internal/config"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
 )
 
 func main() {
@@ -19,8 +24,14 @@ func main() {
 	if err := config.InitGlobal("inertial_config.txt"); err != nil {
 		log.Fatalf("failed to load config: %v", err)
 	}
+	if err := logging.Setup(config.Get().LogLevel, config.Get().LogFormat); err != nil {
+		log.Fatalf("failed to set up logging: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	if err := app.RunDisplay(); err != nil {
+	if err := app.RunDisplay(ctx); err != nil {
 		log.Fatalf("fatal: %v", err)
 	}
 }
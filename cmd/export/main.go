@@ -0,0 +1,88 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+// ./cmd/export/main.go
+//
+// Converts a recorded JSONL session (one {"time","topic","payload"} record per
+// line) into CSV files for analysis in Python or a spreadsheet. IMU samples,
+// poses and GPS fixes go to separate files, each with a header row:
+//
+//	<out>_imu.csv   time, unix_s, topic, source, ax..mz, mag_valid
+//	<out>_pose.csv  time, unix_s, topic, roll, pitch, yaw
+//	<out>_gps.csv   time, unix_s, topic, lat, lon, altitude_m, ...
+//
+// Run:
+//
+//	go run ./cmd/export -in session.jsonl            # writes session_*.csv
+//	go run ./cmd/export -in session.jsonl -out /tmp/run1
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/relabs-tech/inertial_computer/internal/recording"
+)
+
+func main() {
+	inPath := flag.String("in", "", "Recorded session (JSONL)")
+	outPrefix := flag.String("out", "", "Output path prefix (default: input path without extension)")
+	flag.Parse()
+
+	if *inPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	prefix := *outPrefix
+	if prefix == "" {
+		prefix = strings.TrimSuffix(*inPath, filepath.Ext(*inPath))
+	}
+
+	in, err := os.Open(*inPath)
+	if err != nil {
+		log.Fatalf("failed to open session: %v", err)
+	}
+	defer in.Close()
+
+	var files []*os.File
+	open := func(kind string) (io.Writer, error) {
+		f, err := os.Create(fmt.Sprintf("%s_%s.csv", prefix, kind))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+		return f, nil
+	}
+
+	stats, exportErr := recording.ExportCSV(in, open)
+	for _, f := range files {
+		if err := f.Close(); err != nil && exportErr == nil {
+			exportErr = err
+		}
+	}
+	if exportErr != nil {
+		log.Fatalf("export failed: %v", exportErr)
+	}
+
+	kinds := make([]string, 0, len(stats.Rows))
+	for kind := range stats.Rows {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		fmt.Printf("%s_%s.csv: %d rows\n", prefix, kind, stats.Rows[kind])
+	}
+	if stats.Skipped > 0 {
+		fmt.Printf("skipped %d records (not IMU, pose or GPS)\n", stats.Skipped)
+	}
+	if len(kinds) == 0 {
+		fmt.Println("no exportable records found")
+	}
+}
@@ -5,9 +5,17 @@
 package main
 
 import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/relabs-tech/inertial_computer/internal/app"
 )
 
 func main() {
-	app.RunHMC5983Producer()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	app.RunHMC5983Producer(ctx)
 }
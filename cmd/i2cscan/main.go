@@ -0,0 +1,75 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+// ./cmd/i2cscan/main.go
+//
+// Scans an I2C bus and reports which addresses acknowledge, with a best guess
+// of the device type, followed by a suggested inertial_config.txt snippet.
+//
+// Run:
+//
+//	go run ./cmd/i2cscan            # first available bus
+//	go run ./cmd/i2cscan -bus 1     # /dev/i2c-1
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/relabs-tech/inertial_computer/internal/sensors"
+)
+
+func main() {
+	busName := flag.String("bus", "", "I2C bus name or number (empty = first available)")
+	flag.Parse()
+
+	devices, err := sensors.ScanI2C(*busName)
+	if err != nil {
+		log.Fatalf("i2cscan: %v", err)
+	}
+
+	if len(devices) == 0 {
+		fmt.Println("No I2C devices responded (0x03-0x77). Check wiring and that I2C is enabled.")
+		return
+	}
+
+	fmt.Printf("Found %d device(s):\n", len(devices))
+	var oleds []uint16
+	var hmcAddr uint16
+	for _, d := range devices {
+		guess := d.Guess
+		if guess == "" {
+			guess = "unknown"
+		}
+		fmt.Printf("  0x%02X  %s\n", d.Addr, guess)
+
+		switch d.Addr {
+		case 0x3C, 0x3D:
+			oleds = append(oleds, d.Addr)
+		case 0x1E:
+			hmcAddr = d.Addr
+		}
+	}
+
+	// Suggested configuration for the devices this project addresses over I2C
+	fmt.Println()
+	fmt.Println("Suggested inertial_config.txt entries:")
+	if len(oleds) > 0 {
+		fmt.Printf("DISPLAY_LEFT_I2C_ADDR=0x%02X\n", oleds[0])
+		if len(oleds) > 1 {
+			fmt.Printf("DISPLAY_RIGHT_I2C_ADDR=0x%02X\n", oleds[1])
+		} else {
+			fmt.Println("# only one SSD1306 found; DISPLAY_RIGHT_I2C_ADDR must differ (solder the address jumper)")
+		}
+	} else {
+		fmt.Println("# no SSD1306 found at 0x3C/0x3D")
+	}
+	if hmcAddr != 0 {
+		fmt.Printf("HMC_I2C_ADDR=0x%02X\n", hmcAddr)
+		if *busName != "" {
+			fmt.Printf("HMC_I2C_BUS=%s\n", *busName)
+		}
+	}
+}
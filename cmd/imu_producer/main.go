@@ -6,11 +6,16 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/relabs-tech/inertial_computer/internal/app"
 	"github.com/relabs-tech/inertial_computer/internal/config"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
 )
 
 func main() {
@@ -23,8 +28,14 @@ func main() {
 	if err := config.InitGlobal(*configPath); err != nil {
 		log.Fatalf("failed to load config: %v", err)
 	}
+	if err := logging.Setup(config.Get().LogLevel, config.Get().LogFormat); err != nil {
+		log.Fatalf("failed to set up logging: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	if err := app.RunInertialProducer(); err != nil {
+	if err := app.RunInertialProducer(ctx); err != nil {
 		log.Fatalf("fatal: %v", err)
 	}
 }
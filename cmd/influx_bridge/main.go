@@ -0,0 +1,36 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/relabs-tech/inertial_computer/internal/app"
+	"github.com/relabs-tech/inertial_computer/internal/config"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
+)
+
+func main() {
+	log.Println("starting inertial-computer InfluxDB bridge (MQTT → line protocol)")
+
+	// Load configuration
+	if err := config.InitGlobal("inertial_config.txt"); err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+	if err := logging.Setup(config.Get().LogLevel, config.Get().LogFormat); err != nil {
+		log.Fatalf("failed to set up logging: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := app.RunInfluxBridge(ctx); err != nil {
+		log.Fatalf("fatal: %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+// ./cmd/logger/main.go
+//
+// Captures a time-aligned dataset from both IMUs, both BMPs and GPS (over
+// MQTT) at a fixed rate. Reads the sensors directly: stop imu_producer first.
+//
+// Run:
+//
+//	go run ./cmd/logger -rate 50 -duration 10m -out run1.jsonl   # recording.Record JSONL
+//	go run ./cmd/logger -out run1.csv                            # one wide CSV row per tick
+//
+// Stops on Ctrl+C / SIGTERM or when -duration expires; output is flushed either way.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/relabs-tech/inertial_computer/internal/app"
+	"github.com/relabs-tech/inertial_computer/internal/config"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
+)
+
+func main() {
+	rate := flag.Float64("rate", 25, "Rows per second")
+	duration := flag.Duration("duration", 0, "Stop after this long, e.g. 30s or 10m (0 = until interrupted)")
+	out := flag.String("out", "", "Output file; .csv for CSV, otherwise JSONL (default: session-<time>.jsonl)")
+	flag.Parse()
+
+	if *out == "" {
+		*out = "session-" + time.Now().Format("20060102-150405") + ".jsonl"
+	}
+
+	// Load configuration
+	if err := config.InitGlobal("inertial_config.txt"); err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+	if err := logging.Setup(config.Get().LogLevel, config.Get().LogFormat); err != nil {
+		log.Fatalf("failed to set up logging: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := app.RunLogger(ctx, app.LoggerOptions{Rate: *rate, Duration: *duration, Out: *out}); err != nil {
+		log.Fatalf("fatal: %v", err)
+	}
+}
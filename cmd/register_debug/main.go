@@ -10,6 +10,7 @@ import (
 
 	"github.com/relabs-tech/inertial_computer/internal/app"
 	"github.com/relabs-tech/inertial_computer/internal/config"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
 	"github.com/relabs-tech/inertial_computer/internal/sensors"
 )
 
@@ -19,6 +20,9 @@ func main() {
 	if err := config.InitGlobal("inertial_config.txt"); err != nil {
 		log.Fatalf("failed to load config: %v", err)
 	}
+	if err := logging.Setup(config.Get().LogLevel, config.Get().LogFormat); err != nil {
+		log.Fatalf("failed to set up logging: %v", err)
+	}
 
 	log.Println("Initializing IMU manager...")
 	imuManager := sensors.GetIMUManager()
@@ -44,6 +48,10 @@ func main() {
 	// API endpoint for live IMU data
 	http.HandleFunc("/api/imu", app.HandleIMUData)
 
+	// Runtime calibration: /api/imu then returns corrected samples
+	http.HandleFunc("/api/calibration/apply", app.HandleCalibrationApply)
+	http.HandleFunc("/api/calibration/clear", app.HandleCalibrationClear)
+
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		http.ServeFile(w, r, "web/register_debug.html")
 	})
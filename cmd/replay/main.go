@@ -0,0 +1,74 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+// ./cmd/replay/main.go
+//
+// Publishes a recorded JSONL session (one {"time","topic","payload"} record
+// per line, as written by cmd/logger) back onto MQTT with the original timing,
+// so web, console and display can be developed and demoed without sensors.
+//
+// Run:
+//
+//	go run ./cmd/replay -in session.jsonl                    # original speed, once
+//	go run ./cmd/replay -in session.jsonl -speed 4 -loop     # 4x, until Ctrl+C
+//	go run ./cmd/replay -in session.jsonl -map 'inertial/#=demo/inertial/#'
+//	go run ./cmd/replay -in session.jsonl -map inertial/gps=  # drop GPS
+//
+// -map from=to may be repeated; a from ending in # remaps every topic under
+// that prefix, and an empty to drops the topic.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/relabs-tech/inertial_computer/internal/app"
+	"github.com/relabs-tech/inertial_computer/internal/config"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
+)
+
+func main() {
+	in := flag.String("in", "", "Recorded session (JSONL)")
+	speed := flag.Float64("speed", 1, "Playback speed multiplier (2 = twice as fast)")
+	loop := flag.Bool("loop", false, "Start over at the end of the session")
+	retain := flag.Bool("retain", false, "Publish with the retain flag")
+	clientID := flag.String("client-id", "", "MQTT client ID (default inertial-replay)")
+	remap := map[string]string{}
+	flag.Func("map", "Topic remapping from=to (repeatable; from ending in # maps a prefix, empty to drops)", func(s string) error {
+		from, to, ok := strings.Cut(s, "=")
+		if !ok || from == "" {
+			return fmt.Errorf("want from=to, got %q", s)
+		}
+		remap[from] = to
+		return nil
+	})
+	flag.Parse()
+
+	if *in == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Load configuration
+	if err := config.InitGlobal("inertial_config.txt"); err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+	if err := logging.Setup(config.Get().LogLevel, config.Get().LogFormat); err != nil {
+		log.Fatalf("failed to set up logging: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	opts := app.ReplayOptions{In: *in, Speed: *speed, Loop: *loop, Remap: remap, Retain: *retain, ClientID: *clientID}
+	if err := app.RunReplay(ctx, opts); err != nil {
+		log.Fatalf("fatal: %v", err)
+	}
+}
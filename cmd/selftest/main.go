@@ -0,0 +1,92 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+// ./cmd/selftest/main.go
+//
+// Runs the MPU9250 factory self-test on the left and/or right IMU and prints
+// the per-axis deviation from factory trim against IMU_SELFTEST_MAX_DEVIATION.
+// Exits non-zero if any tested axis fails (useful for incoming hardware QA).
+//
+// Run:
+//
+//	go run ./cmd/selftest              # both IMUs
+//	go run ./cmd/selftest -imu left
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/relabs-tech/inertial_computer/internal/config"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
+	"github.com/relabs-tech/inertial_computer/internal/sensors"
+)
+
+func main() {
+	configPath := flag.String("config", "inertial_config.txt", "Path to configuration file")
+	imuFlag := flag.String("imu", "both", "IMU to test: left, right or both")
+	flag.Parse()
+
+	if err := config.InitGlobal(*configPath); err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+	if err := logging.Setup(config.Get().LogLevel, config.Get().LogFormat); err != nil {
+		log.Fatalf("failed to set up logging: %v", err)
+	}
+
+	var imus []string
+	switch *imuFlag {
+	case "left", "right":
+		imus = []string{*imuFlag}
+	case "both":
+		imus = []string{"left", "right"}
+	default:
+		log.Fatalf("invalid -imu %q (must be left, right or both)", *imuFlag)
+	}
+
+	mgr := sensors.GetIMUManager()
+	if err := mgr.Init(); err != nil {
+		log.Fatalf("failed to initialize IMU manager: %v", err)
+	}
+
+	failed := false
+	for _, imuID := range imus {
+		result, err := mgr.RunSelfTest(imuID)
+		if err != nil {
+			fmt.Printf("%s IMU: ERROR: %v\n", imuID, err)
+			failed = true
+			continue
+		}
+		printResult(result)
+		if !result.Pass {
+			failed = true
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func printResult(r sensors.SelfTestResult) {
+	status := "PASS"
+	if !r.Pass {
+		status = "FAIL"
+	}
+	fmt.Printf("%s IMU self-test: %s (limit ±%.1f%%)\n", r.IMU, status, r.LimitPercent)
+	printAxes("Accel", r.Accel)
+	printAxes("Gyro ", r.Gyro)
+}
+
+func printAxes(label string, axes []sensors.SelfTestAxis) {
+	for _, a := range axes {
+		mark := "ok"
+		if !a.Pass {
+			mark = "FAIL"
+		}
+		fmt.Printf("  %s %s: %+7.2f%%  %s\n", label, a.Axis, a.Deviation, mark)
+	}
+}
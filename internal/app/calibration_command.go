@@ -0,0 +1,182 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
+	"github.com/relabs-tech/inertial_computer/internal/sensors"
+)
+
+// Runtime calibration of imu_producer. The web server loads and validates the
+// calibration file and sends it to TOPIC_CALIBRATION + "/set"; the producer
+// activates it in its IMU manager and publishes the active calibrations,
+// retained, to TOPIC_CALIBRATION. The retained state also restores them when
+// the producer restarts.
+
+// Calibration command actions (POST /api/calibration/apply and /clear).
+const (
+	calibrationApply = "apply"
+	calibrationClear = "clear"
+)
+
+// calibrationCommand is the payload of the command topic.
+type calibrationCommand struct {
+	Action      string               `json:"action"`
+	IMU         string               `json:"imu"`                   // "left" or "right"
+	Calibration *imu_raw.Calibration `json:"calibration,omitempty"` // apply only
+}
+
+// calibrationTopicState is the retained payload of TOPIC_CALIBRATION; nil =
+// raw readings.
+type calibrationTopicState struct {
+	Left  *imu_raw.Calibration `json:"left,omitempty"`
+	Right *imu_raw.Calibration `json:"right,omitempty"`
+}
+
+// calibrationCommandTopic returns the command topic for a TOPIC_CALIBRATION
+// value.
+func calibrationCommandTopic(stateTopic string) string {
+	return stateTopic + "/set"
+}
+
+// subscribeCalibrationCommands restores the IMU manager's calibrations from
+// the retained state on topic and starts handling commands.
+func subscribeCalibrationCommands(client mqtt.Client, topic string) error {
+	mgr := sensors.GetIMUManager()
+
+	publish := func() {
+		st := calibrationTopicState{Left: mgr.Calibration("left"), Right: mgr.Calibration("right")}
+		var payload []byte
+		if st.Left != nil || st.Right != nil {
+			var err error
+			if payload, err = json.Marshal(st); err != nil {
+				logging.Errorf("calibration marshal error: %v", err)
+				return
+			}
+		}
+		if token := client.Publish(topic, 1, true, payload); token.Wait() && token.Error() != nil {
+			logging.Errorf("MQTT publish error (%s): %v", topic, token.Error())
+		}
+	}
+
+	// Retained state; the producer's own publishes echo back here too, which
+	// sets the same values again
+	token := client.Subscribe(topic, 1, func(_ mqtt.Client, msg mqtt.Message) {
+		var st calibrationTopicState
+		if len(msg.Payload()) > 0 {
+			if err := json.Unmarshal(msg.Payload(), &st); err != nil {
+				logging.Warnf("calibration: invalid state on %s: %v", topic, err)
+				return
+			}
+		}
+		for imu, cal := range map[string]*imu_raw.Calibration{"left": st.Left, "right": st.Right} {
+			if err := mgr.SetCalibration(imu, cal); err != nil {
+				logging.Warnf("calibration: cannot restore %s IMU calibration: %v", imu, err)
+			}
+		}
+	})
+	if token.Wait(); token.Error() != nil {
+		return token.Error()
+	}
+
+	cmdTopic := calibrationCommandTopic(topic)
+	token = client.Subscribe(cmdTopic, 1, func(_ mqtt.Client, msg mqtt.Message) {
+		var cmd calibrationCommand
+		if err := json.Unmarshal(msg.Payload(), &cmd); err != nil {
+			logging.Warnf("calibration: invalid command: %v", err)
+			return
+		}
+		switch cmd.Action {
+		case calibrationApply:
+			if cmd.Calibration == nil {
+				logging.Warnf("calibration: apply command for %s IMU without a calibration", cmd.IMU)
+				return
+			}
+			if err := mgr.SetCalibration(cmd.IMU, cmd.Calibration); err != nil {
+				logging.Errorf("calibration: cannot apply %s to %s IMU: %v", cmd.Calibration.Source, cmd.IMU, err)
+				return
+			}
+			logging.Infof("calibration: applied %s to %s IMU", cmd.Calibration.Source, cmd.IMU)
+		case calibrationClear:
+			if err := mgr.SetCalibration(cmd.IMU, nil); err != nil {
+				logging.Errorf("calibration: cannot clear: %v", err)
+				return
+			}
+			logging.Infof("calibration: cleared %s IMU calibration", cmd.IMU)
+		default:
+			logging.Warnf("calibration: invalid action %q (must be '%s' or '%s')", cmd.Action, calibrationApply, calibrationClear)
+			return
+		}
+		// Not on the callback goroutine: waiting for a publish there can
+		// block message delivery
+		go publish()
+	})
+	if token.Wait(); token.Error() != nil {
+		return token.Error()
+	}
+	logging.Infof("calibration: state on %s, commands on %s", topic, cmdTopic)
+	return nil
+}
+
+// calibrationCommandHandler returns the web server's handler for POST
+// /api/calibration/apply or /clear (by action): it validates the request
+// like HandleCalibrationApply/Clear, sends the command to imu_producer and
+// replies 202 with the requested state.
+func calibrationCommandHandler(client mqtt.Client, topic, action string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if topic == "" {
+			http.Error(w, "calibration commands disabled (TOPIC_CALIBRATION is empty)", http.StatusNotFound)
+			return
+		}
+		req, ok := decodeCalibrationRequest(w, r)
+		if !ok {
+			return
+		}
+
+		cmd := calibrationCommand{Action: action, IMU: req.IMU}
+		if action == calibrationApply {
+			cal, status, err := loadCalibration(req.IMU, req.File)
+			if err != nil {
+				http.Error(w, err.Error(), status)
+				return
+			}
+			if err := cal.Validate(); err != nil {
+				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+				return
+			}
+			if cal.IMU != req.IMU {
+				http.Error(w, fmt.Sprintf("calibration is for the %s IMU, not %s", cal.IMU, req.IMU), http.StatusUnprocessableEntity)
+				return
+			}
+			cmd.Calibration = &cal
+		}
+
+		payload, err := json.Marshal(cmd)
+		if err != nil {
+			logging.Errorf("calibration: command marshal error: %v", err)
+			http.Error(w, "command encoding failed", http.StatusInternalServerError)
+			return
+		}
+		token := client.Publish(calibrationCommandTopic(topic), 1, false, payload)
+		if token.Wait(); token.Error() != nil {
+			http.Error(w, "MQTT publish error: "+token.Error().Error(), http.StatusBadGateway)
+			return
+		}
+		logging.Infof("web: calibration %s for %s IMU sent to imu_producer", action, req.IMU)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		st := calibrationState{IMU: req.IMU, Active: cmd.Calibration != nil, Calibration: cmd.Calibration}
+		if err := json.NewEncoder(w).Encode(st); err != nil {
+			logging.Errorf("calibration: JSON encode error: %v", err)
+		}
+	}
+}
@@ -7,17 +7,21 @@ package app
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"math"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/relabs-tech/inertial_computer/internal/calibration"
+	"github.com/relabs-tech/inertial_computer/internal/config"
 	imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
 	"github.com/relabs-tech/inertial_computer/internal/sensors"
 )
 
@@ -35,9 +39,137 @@ type CalibrationSession struct {
 	currentPhase string
 	currentStep  int
 	results      CalibrationResult
+
+	// Sensor recalibrated by an "only" session ("" = all); the others keep
+	// the values of the calibration it started from
+	only string
+
+	// Set when complete() held back a low-confidence result until the
+	// client sends "confirm"
+	awaitingConfirm bool
+
+	// Sample counts and interval of each step (init message, else defaults)
+	capture CaptureSettings
+
+	// IMU this session holds in activeCalibrations ("" = none)
+	locked string
+
+	// Per-step confidences combined into the results as steps complete
+	rotationConfs []float64     // gyro X/Y/Z rotations
+	accelConfs    []float64     // accel poses, in step order
+	accelMeans    [6][3]float64 // accel pose means (counts), in step order
+}
+
+// activeCalibrations maps each IMU to the session calibrating it. There is one
+// IMU manager per process, so two sessions on one IMU (e.g. two browser tabs)
+// would interleave its reads and both get garbage.
+var activeCalibrations = struct {
+	sync.Mutex
+	imus map[string]*CalibrationSession
+}{imus: map[string]*CalibrationSession{}}
+
+// acquire makes s the calibration session of imu, releasing any other IMU s
+// held, or fails if another session is calibrating imu.
+func (s *CalibrationSession) acquire(imu string) error {
+	activeCalibrations.Lock()
+	defer activeCalibrations.Unlock()
+	if other, ok := activeCalibrations.imus[imu]; ok && other != s {
+		return fmt.Errorf("the %s IMU is already being calibrated in another session; finish or close it first", imu)
+	}
+	if s.locked != "" && s.locked != imu {
+		delete(activeCalibrations.imus, s.locked)
+	}
+	activeCalibrations.imus[imu] = s
+	s.locked = imu
+	return nil
 }
 
-// CalibrationResult matches the structure from cmd/calibration/main.go
+// release ends s's hold on its IMU, if any.
+func (s *CalibrationSession) release() {
+	activeCalibrations.Lock()
+	defer activeCalibrations.Unlock()
+	if s.locked != "" && activeCalibrations.imus[s.locked] == s {
+		delete(activeCalibrations.imus, s.locked)
+	}
+	s.locked = ""
+}
+
+// CaptureSettings sets how many samples each calibration step takes and how
+// far apart. Zero fields in an init message keep the defaults.
+type CaptureSettings struct {
+	IntervalMs          int `json:"interval_ms"`           // time between samples
+	GyroStaticSamples   int `json:"gyro_static_samples"`   // still gyro bias step
+	GyroRotationSamples int `json:"gyro_rotation_samples"` // each X/Y/Z rotation step
+	AccelSamples        int `json:"accel_samples"`         // each of the 6 poses
+	MagSamples          int `json:"mag_samples"`           // the figure-8 step
+}
+
+var defaultCaptureSettings = CaptureSettings{
+	IntervalMs:          100,
+	GyroStaticSamples:   100,
+	GyroRotationSamples: 50,
+	AccelSamples:        50,
+	MagSamples:          200,
+}
+
+// Capture setting bounds: enough samples for a meaningful mean/stddev, and
+// no step longer than maxCaptureStep.
+const (
+	minCaptureIntervalMs = 10
+	maxCaptureIntervalMs = 1000
+	minCaptureSamples    = 10
+	maxCaptureStep       = 5 * time.Minute
+)
+
+// withDefaults returns c with zero fields set to the defaults, or an error
+// if a value is out of bounds.
+func (c CaptureSettings) withDefaults() (CaptureSettings, error) {
+	d := defaultCaptureSettings
+	fields := []struct {
+		name string
+		v    int
+		dst  *int
+	}{
+		{"interval_ms", c.IntervalMs, &d.IntervalMs},
+		{"gyro_static_samples", c.GyroStaticSamples, &d.GyroStaticSamples},
+		{"gyro_rotation_samples", c.GyroRotationSamples, &d.GyroRotationSamples},
+		{"accel_samples", c.AccelSamples, &d.AccelSamples},
+		{"mag_samples", c.MagSamples, &d.MagSamples},
+	}
+	for _, f := range fields {
+		if f.v != 0 {
+			*f.dst = f.v
+		}
+	}
+	if d.IntervalMs < minCaptureIntervalMs || d.IntervalMs > maxCaptureIntervalMs {
+		return d, fmt.Errorf("invalid interval_ms %d: must be %d-%d", d.IntervalMs, minCaptureIntervalMs, maxCaptureIntervalMs)
+	}
+	for _, f := range fields[1:] {
+		n := *f.dst
+		if n < minCaptureSamples {
+			return d, fmt.Errorf("invalid %s %d: must be at least %d", f.name, n, minCaptureSamples)
+		}
+		if dur := d.duration(n); dur > maxCaptureStep {
+			return d, fmt.Errorf("invalid %s %d: %v at %d ms exceeds the %v step limit", f.name, n, dur, d.IntervalMs, maxCaptureStep)
+		}
+	}
+	return d, nil
+}
+
+// interval is the time between samples.
+func (c CaptureSettings) interval() time.Duration {
+	return time.Duration(c.IntervalMs) * time.Millisecond
+}
+
+// duration is how long taking n samples lasts.
+func (c CaptureSettings) duration(n int) time.Duration {
+	return time.Duration(n) * c.interval()
+}
+
+// CalibrationResult matches the structure from cmd/calibration/main.go.
+// Confidences are 0-1 and computed with the same heuristics as the CLI
+// (internal/calibration); files written before that hold 0-100 values, see
+// normalizeConfidence.
 type CalibrationResult struct {
 	Version   int       `json:"version"`
 	IMU       string    `json:"imu"`
@@ -47,7 +179,9 @@ type CalibrationResult struct {
 	GyroBiasX         float64 `json:"gyro_bias_x"`
 	GyroBiasY         float64 `json:"gyro_bias_y"`
 	GyroBiasZ         float64 `json:"gyro_bias_z"`
-	GyroConfidence    float64 `json:"gyro_confidence"`
+	GyroConfidence    float64 `json:"gyro_confidence"` // mean of the static and rotation confidences
+	GyroStaticConf    float64 `json:"gyro_static_confidence"`
+	GyroRotationConf  float64 `json:"gyro_rotation_confidence"`
 	GyroStaticStdDev  float64 `json:"gyro_static_stddev"`
 	GyroDynamicStdDev float64 `json:"gyro_dynamic_stddev"`
 
@@ -61,7 +195,8 @@ type CalibrationResult struct {
 	AccelConfidence float64 `json:"accel_confidence"`
 	AccelAvgStdDev  float64 `json:"accel_avg_stddev"`
 
-	// Magnetometer calibration
+	// Magnetometer calibration, in µT (see imu.MagCalibration):
+	// corrected = (MagUT(raw) - offset) * scale
 	MagOffsetX     float64 `json:"mag_offset_x"`
 	MagOffsetY     float64 `json:"mag_offset_y"`
 	MagOffsetZ     float64 `json:"mag_offset_z"`
@@ -74,17 +209,25 @@ type CalibrationResult struct {
 	MagRangeZ      float64 `json:"mag_range_z"`
 	MagSampleCount int     `json:"mag_sample_count"`
 
-	TotalSamples int `json:"total_samples"`
+	TotalSamples      int     `json:"total_samples"`
+	OverallConfidence float64 `json:"overall_confidence"`
+
+	// Calibration the untouched sensors were taken from ("only" sessions)
+	BaseFile string `json:"base_file,omitempty"`
 }
 
 // WebSocket message types
 type WSMessage struct {
-	Action string `json:"action"` // init, next, cancel
+	Action string `json:"action"` // init, next, confirm, cancel
 	IMU    string `json:"imu,omitempty"`
+	Only   string `json:"only,omitempty"` // init: gyro, accel or mag to recalibrate just that sensor
+
+	// init: sample counts/interval (omitted or zero fields = defaults)
+	Capture *CaptureSettings `json:"capture,omitempty"`
 }
 
 type WSResponse struct {
-	Type     string                 `json:"type"` // phase, step, progress, stats, complete, error
+	Type     string                 `json:"type"` // settings, phase, step, progress, stats, warning, complete, error
 	Phase    string                 `json:"phase,omitempty"`
 	Step     string                 `json:"step,omitempty"`
 	Progress float64                `json:"progress,omitempty"`
@@ -93,19 +236,217 @@ type WSResponse struct {
 	Message  string                 `json:"message,omitempty"`
 }
 
+// calibrationFileSuffix ends the files written by complete() and cmd/calibration:
+// {imu}_{timestamp}_inertial_calibration.json in the working directory.
+const calibrationFileSuffix = "_inertial_calibration.json"
+
+// latestCalibrationFile returns the most recently written calibration file
+// for imu ("left" or "right"), or an error wrapping os.ErrNotExist if there is
+// none. Files are compared by modification time because the web UI and the
+// CLI use different timestamp formats in the name.
+func latestCalibrationFile(imu string) (string, error) {
+	matches, err := filepath.Glob(imu + "_*" + calibrationFileSuffix)
+	if err != nil {
+		return "", err
+	}
+
+	var latest string
+	var latestMod time.Time
+	for _, m := range matches {
+		info, err := os.Stat(m)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if latest == "" || info.ModTime().After(latestMod) {
+			latest, latestMod = m, info.ModTime()
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("no calibration for %s IMU: %w", imu, os.ErrNotExist)
+	}
+	return latest, nil
+}
+
+// HandleCalibrationLatest serves the latest stored calibration for the IMU
+// given by ?imu=left|right as JSON (GET /api/calibration).
+func HandleCalibrationLatest(w http.ResponseWriter, r *http.Request) {
+	serveCalibration(w, r, false)
+}
+
+// HandleCalibrationDownload serves the same file as HandleCalibrationLatest
+// as an attachment (GET /api/calibration/download).
+func HandleCalibrationDownload(w http.ResponseWriter, r *http.Request) {
+	serveCalibration(w, r, true)
+}
+
+func serveCalibration(w http.ResponseWriter, r *http.Request, attachment bool) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	imu := r.URL.Query().Get("imu")
+	if imu != "left" && imu != "right" {
+		http.Error(w, "imu must be left or right", http.StatusBadRequest)
+		return
+	}
+
+	name, err := latestCalibrationFile(imu)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		logging.Errorf("calibration: lookup error: %v", err)
+		http.Error(w, "calibration lookup failed", http.StatusInternalServerError)
+		return
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		logging.Errorf("calibration: read %s: %v", name, err)
+		http.Error(w, "calibration read failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if attachment {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filepath.Base(name)))
+	}
+	if _, err := w.Write(data); err != nil {
+		logging.Errorf("calibration: write error: %v", err)
+	}
+}
+
+// calibrationRequest is the body of POST /api/calibration/apply and
+// /api/calibration/clear.
+type calibrationRequest struct {
+	IMU  string `json:"imu"`            // "left" or "right"
+	File string `json:"file,omitempty"` // calibration file name (apply only; "" = latest)
+}
+
+// calibrationState is the response of the apply/clear endpoints.
+type calibrationState struct {
+	IMU         string               `json:"imu"`
+	Active      bool                 `json:"active"` // false = raw readings
+	Calibration *imu_raw.Calibration `json:"calibration,omitempty"`
+}
+
+// HandleCalibrationApply loads a calibration file (the latest for the IMU if
+// no file is given), validates it and makes it the IMU manager's active
+// calibration (POST /api/calibration/apply).
+func HandleCalibrationApply(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeCalibrationRequest(w, r)
+	if !ok {
+		return
+	}
+
+	cal, status, err := loadCalibration(req.IMU, req.File)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	if err := sensors.GetIMUManager().SetCalibration(req.IMU, &cal); err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	logging.Infof("calibration: applied %s to %s IMU", cal.Source, req.IMU)
+	writeCalibrationState(w, req.IMU)
+}
+
+// HandleCalibrationClear reverts the IMU to raw readings
+// (POST /api/calibration/clear).
+func HandleCalibrationClear(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeCalibrationRequest(w, r)
+	if !ok {
+		return
+	}
+	if err := sensors.GetIMUManager().SetCalibration(req.IMU, nil); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	logging.Infof("calibration: cleared %s IMU calibration", req.IMU)
+	writeCalibrationState(w, req.IMU)
+}
+
+// loadCalibration reads and validates a calibration file for imu (name "" =
+// latest). On error it also returns the HTTP status to respond with.
+func loadCalibration(imu, name string) (imu_raw.Calibration, int, error) {
+	if name == "" {
+		var err error
+		name, err = latestCalibrationFile(imu)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return imu_raw.Calibration{}, http.StatusNotFound, err
+			}
+			logging.Errorf("calibration: lookup error: %v", err)
+			return imu_raw.Calibration{}, http.StatusInternalServerError, fmt.Errorf("calibration lookup failed")
+		}
+	} else if filepath.Base(name) != name || !strings.HasSuffix(name, calibrationFileSuffix) {
+		return imu_raw.Calibration{}, http.StatusBadRequest, fmt.Errorf("file must be a *%s name in the working directory", calibrationFileSuffix)
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return imu_raw.Calibration{}, http.StatusNotFound, fmt.Errorf("calibration file %s not found", name)
+		}
+		logging.Errorf("calibration: read %s: %v", name, err)
+		return imu_raw.Calibration{}, http.StatusInternalServerError, fmt.Errorf("calibration read failed")
+	}
+
+	cal, err := imu_raw.ParseCalibration(data)
+	if err != nil {
+		return imu_raw.Calibration{}, http.StatusUnprocessableEntity, fmt.Errorf("%s: %w", name, err)
+	}
+	cal.Source = name
+	if cal.Warning != "" {
+		logging.Warnf("calibration: %s: %s", name, cal.Warning)
+	}
+	return cal, http.StatusOK, nil
+}
+
+func decodeCalibrationRequest(w http.ResponseWriter, r *http.Request) (calibrationRequest, bool) {
+	var req calibrationRequest
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return req, false
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return req, false
+	}
+	if req.IMU != "left" && req.IMU != "right" {
+		http.Error(w, "imu must be left or right", http.StatusBadRequest)
+		return req, false
+	}
+	return req, true
+}
+
+func writeCalibrationState(w http.ResponseWriter, imu string) {
+	cal := sensors.GetIMUManager().Calibration(imu)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(calibrationState{IMU: imu, Active: cal != nil, Calibration: cal}); err != nil {
+		logging.Errorf("calibration: JSON encode error: %v", err)
+	}
+}
+
 // HandleCalibrationWS handles the WebSocket connection for calibration
 func HandleCalibrationWS(w http.ResponseWriter, r *http.Request) {
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		log.Printf("calibration: websocket upgrade error: %v", err)
+		logging.Warnf("calibration: websocket upgrade error: %v", err)
 		return
 	}
 	defer conn.Close()
+	defer keepAlive(conn)()
 
 	session := &CalibrationSession{
-		Conn: conn,
+		Conn:    conn,
+		capture: defaultCaptureSettings,
 		results: CalibrationResult{
-			Version:     1,
+			Version:     imu_raw.WebCalibVersion,
 			Timestamp:   time.Now(),
 			AccelScaleX: 1.0,
 			AccelScaleY: 1.0,
@@ -115,48 +456,149 @@ func HandleCalibrationWS(w http.ResponseWriter, r *http.Request) {
 			MagScaleZ:   1.0,
 		},
 	}
+	// Disconnect and cancel end up here
+	defer session.release()
 
 	// Main message loop
 	for {
 		var msg WSMessage
 		err := conn.ReadJSON(&msg)
+		if isPongTimeout(err) {
+			logging.Warnf("calibration: client stopped answering pings, closing")
+			closePongTimeout(conn)
+			break
+		}
 		if err != nil {
-			log.Printf("calibration: websocket read error: %v", err)
+			logging.Warnf("calibration: websocket read error: %v", err)
 			break
 		}
 
 		switch msg.Action {
 		case "init":
+			if msg.IMU != "left" && msg.IMU != "right" {
+				session.sendError("imu must be left or right")
+				break
+			}
+			capture := defaultCaptureSettings
+			if msg.Capture != nil {
+				var err error
+				if capture, err = msg.Capture.withDefaults(); err != nil {
+					session.sendError(err.Error())
+					break
+				}
+			}
+			if err := session.acquire(msg.IMU); err != nil {
+				logging.Warnf("calibration: rejected second session for the %s IMU", msg.IMU)
+				session.sendError(err.Error())
+				break
+			}
 			session.IMU = msg.IMU
 			session.results.IMU = msg.IMU
-			log.Printf("calibration: initialized for IMU: %s", msg.IMU)
+			session.capture = capture
+			session.sendSettings()
+			if msg.Only != "" {
+				if err := session.startOnly(msg.Only); err != nil {
+					session.release()
+					session.sendError(err.Error())
+					break
+				}
+				logging.Infof("calibration: initialized for IMU: %s (%s only, based on %s)", msg.IMU, msg.Only, session.results.BaseFile)
+				break
+			}
+			logging.Infof("calibration: initialized for IMU: %s", msg.IMU)
 
 		case "next":
 			session.mu.Lock()
-			err := session.runNextStep()
+			var err error
+			if session.locked == "" {
+				err = errors.New("no calibration in progress; select an IMU first")
+			} else {
+				err = session.runNextStep()
+			}
+			session.mu.Unlock()
+			if err != nil {
+				session.sendError(err.Error())
+			}
+
+		case "confirm":
+			session.mu.Lock()
+			var err error
+			if session.awaitingConfirm {
+				err = session.save()
+			}
 			session.mu.Unlock()
 			if err != nil {
 				session.sendError(err.Error())
 			}
 
 		case "cancel":
-			log.Printf("calibration: cancelled by user")
+			logging.Infof("calibration: cancelled by user")
 			return
 		}
+		// Steps run for up to minutes without reading pongs
+		extendReadDeadline(conn)
 	}
 }
 
+// startOnly makes the session recalibrate only the given sensor, starting
+// from the latest web calibration of the IMU so the other sensors keep their
+// values.
+func (s *CalibrationSession) startOnly(only string) error {
+	switch only {
+	case "gyro", "accel", "mag":
+	default:
+		return fmt.Errorf("invalid only %q: must be gyro, accel or mag", only)
+	}
+
+	name, err := latestCalibrationFile(s.IMU)
+	if err != nil {
+		return fmt.Errorf("no calibration to merge into; run a full calibration first (%w)", err)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	var base CalibrationResult
+	if err := json.Unmarshal(data, &base); err != nil || (base.Version != imu_raw.WebCalibVersion && base.Version != 1) {
+		return fmt.Errorf("latest calibration %s was not made in the web UI; run a full calibration first", name)
+	}
+	// A version 1 mag calibration is in the old AK8963 frame: the file only
+	// becomes current when the mag is what gets recalibrated
+	if base.Version != imu_raw.WebCalibVersion && only == "mag" {
+		base.Version = imu_raw.WebCalibVersion
+	}
+
+	base.normalizeConfidence()
+	base.IMU = s.IMU
+	base.Timestamp = time.Now()
+	base.BaseFile = name
+	s.results = base
+	s.only = only
+	return nil
+}
+
 func (s *CalibrationSession) runNextStep() error {
 	// State machine for calibration phases
 	switch s.currentPhase {
 	case "":
-		// Start with gyroscope
-		s.currentPhase = "gyro"
+		// Start with gyroscope, or the only sensor being recalibrated
 		s.currentStep = 0
+		switch s.only {
+		case "accel":
+			s.currentPhase = "accel"
+			return s.runAccelStep()
+		case "mag":
+			s.currentPhase = "mag"
+			return s.runMagStep()
+		}
+		s.currentPhase = "gyro"
 		return s.runGyroStep()
 
 	case "gyro":
 		s.currentStep++
+		if s.currentStep >= 4 && s.only != "" {
+			return s.complete()
+		}
 		if s.currentStep >= 4 {
 			// Move to accelerometer
 			s.currentPhase = "accel"
@@ -167,6 +609,9 @@ func (s *CalibrationSession) runNextStep() error {
 
 	case "accel":
 		s.currentStep++
+		if s.currentStep >= 6 && s.only != "" {
+			return s.complete()
+		}
 		if s.currentStep >= 6 {
 			// Move to magnetometer
 			s.currentPhase = "mag"
@@ -197,12 +642,12 @@ func (s *CalibrationSession) runGyroStep() error {
 		if !mgr.IsLeftIMUAvailable() {
 			return fmt.Errorf("left IMU not available")
 		}
-		readFunc = mgr.ReadLeftIMU
+		readFunc = uncalibratedReader(mgr, "left")
 	} else {
 		if !mgr.IsRightIMUAvailable() {
 			return fmt.Errorf("right IMU not available")
 		}
-		readFunc = mgr.ReadRightIMU
+		readFunc = uncalibratedReader(mgr, "right")
 	}
 
 	steps := []string{"gyro-static", "gyro-x", "gyro-y", "gyro-z"}
@@ -214,8 +659,9 @@ func (s *CalibrationSession) runGyroStep() error {
 		s.sendProgress(5)
 		time.Sleep(1 * time.Second) // Give user time to place device
 
-		samples := make([][3]float64, 0, 100)
-		for i := 0; i < 100; i++ {
+		n := s.capture.GyroStaticSamples
+		samples := make([][3]float64, 0, n)
+		for i := 0; i < n; i++ {
 			reading, err := readFunc()
 			if err != nil {
 				return err
@@ -225,23 +671,27 @@ func (s *CalibrationSession) runGyroStep() error {
 				float64(reading.Gy),
 				float64(reading.Gz),
 			})
-			s.sendProgress(5 + float64(i)*0.9)
-			time.Sleep(100 * time.Millisecond)
+			s.sendProgress(5 + float64(i)*90/float64(n))
+			time.Sleep(s.capture.interval())
 		}
 
 		// Calculate bias
 		s.results.GyroBiasX = mean(samples, 0)
 		s.results.GyroBiasY = mean(samples, 1)
 		s.results.GyroBiasZ = mean(samples, 2)
-		s.results.GyroStaticStdDev = (stddev(samples, 0) + stddev(samples, 1) + stddev(samples, 2)) / 3.0
+		std := [3]float64{stddev(samples, 0), stddev(samples, 1), stddev(samples, 2)}
+		s.results.GyroStaticStdDev = (std[0] + std[1] + std[2]) / 3.0
+		s.results.GyroStaticConf = calibration.StillnessConfidence(std)
 		s.results.TotalSamples += len(samples)
 
 	default: // Dynamic rotation steps
 		s.sendProgress(float64(s.currentStep) * 25)
 		time.Sleep(1 * time.Second)
 
-		samples := make([][3]float64, 0, 50)
-		for i := 0; i < 50; i++ {
+		n := s.capture.GyroRotationSamples
+		samples := make([][3]float64, 0, n)
+		start := time.Now()
+		for i := 0; i < n; i++ {
 			reading, err := readFunc()
 			if err != nil {
 				return err
@@ -253,8 +703,8 @@ func (s *CalibrationSession) runGyroStep() error {
 				float64(reading.Gz) - s.results.GyroBiasZ,
 			}
 			samples = append(samples, corrected)
-			s.sendProgress(float64(s.currentStep)*25 + float64(i)*0.5)
-			time.Sleep(100 * time.Millisecond)
+			s.sendProgress(float64(s.currentStep)*25 + float64(i)*25/float64(n))
+			time.Sleep(s.capture.interval())
 		}
 
 		// Calculate dynamic standard deviation
@@ -265,11 +715,27 @@ func (s *CalibrationSession) runGyroStep() error {
 			s.results.GyroDynamicStdDev = (s.results.GyroDynamicStdDev + dynamicStdDev) / 2.0
 		}
 		s.results.TotalSamples += len(samples)
+
+		// Rotation confidence from the share and size of the rate on the
+		// requested axis (steps 1-3 = X, Y, Z)
+		axis := s.currentStep - 1
+		var meanAbs [3]float64
+		for _, v := range samples {
+			for k := range meanAbs {
+				meanAbs[k] += math.Abs(v[k]) / float64(len(samples))
+			}
+		}
+		dominance := 0.0
+		if sum := meanAbs[0] + meanAbs[1] + meanAbs[2]; sum > 0 {
+			dominance = meanAbs[axis] / sum
+		}
+		s.rotationConfs = append(s.rotationConfs, calibration.RotationConfidence(time.Since(start).Seconds(), dominance, meanAbs[axis]))
+		s.results.GyroRotationConf = calibration.CombineRotationConfidence(s.rotationConfs)
 	}
 
-	// Calculate confidence
-	if s.results.GyroStaticStdDev > 0 {
-		s.results.GyroConfidence = 100.0 / (1.0 + s.results.GyroStaticStdDev*1000.0)
+	s.results.GyroConfidence = s.results.GyroStaticConf
+	if len(s.rotationConfs) > 0 {
+		s.results.GyroConfidence = (s.results.GyroStaticConf + s.results.GyroRotationConf) / 2
 	}
 
 	s.sendStats()
@@ -291,12 +757,12 @@ func (s *CalibrationSession) runAccelStep() error {
 		if !mgr.IsLeftIMUAvailable() {
 			return fmt.Errorf("left IMU not available")
 		}
-		readFunc = mgr.ReadLeftIMU
+		readFunc = uncalibratedReader(mgr, "left")
 	} else {
 		if !mgr.IsRightIMUAvailable() {
 			return fmt.Errorf("right IMU not available")
 		}
-		readFunc = mgr.ReadRightIMU
+		readFunc = uncalibratedReader(mgr, "right")
 	}
 
 	steps := []string{"accel-up", "accel-down", "accel-right", "accel-left", "accel-forward", "accel-back"}
@@ -307,8 +773,9 @@ func (s *CalibrationSession) runAccelStep() error {
 	time.Sleep(2 * time.Second) // Give user time to position device
 
 	// Collect samples for this orientation
-	samples := make([][3]float64, 0, 50)
-	for i := 0; i < 50; i++ {
+	n := s.capture.AccelSamples
+	samples := make([][3]float64, 0, n)
+	for i := 0; i < n; i++ {
 		reading, err := readFunc()
 		if err != nil {
 			return err
@@ -318,8 +785,8 @@ func (s *CalibrationSession) runAccelStep() error {
 			float64(reading.Ay),
 			float64(reading.Az),
 		})
-		s.sendProgress(float64(s.currentStep)*16.67 + float64(i)*0.33)
-		time.Sleep(100 * time.Millisecond)
+		s.sendProgress(float64(s.currentStep)*16.67 + float64(i)*16.67/float64(n))
+		time.Sleep(s.capture.interval())
 	}
 
 	// Calculate mean for this orientation
@@ -327,16 +794,8 @@ func (s *CalibrationSession) runAccelStep() error {
 	meanY := mean(samples, 1)
 	meanZ := mean(samples, 2)
 
-	// Expected gravity values for each orientation (in g's)
-	expected := [][3]float64{
-		{0, 0, 1},  // up
-		{0, 0, -1}, // down
-		{1, 0, 0},  // right
-		{-1, 0, 0}, // left
-		{0, 1, 0},  // forward
-		{0, -1, 0}, // back
-	}
-	_ = expected // Mark as used
+	// Steps are Z+, Z-, X+, X-, Y+, Y-
+	s.accelMeans[s.currentStep] = [3]float64{meanX, meanY, meanZ}
 
 	// Accumulate for bias and scale calculation
 	// Simple approach: use opposing pairs to calculate bias and scale
@@ -365,9 +824,21 @@ func (s *CalibrationSession) runAccelStep() error {
 		s.results.AccelAvgStdDev = (s.results.AccelAvgStdDev*float64(s.currentStep) + avgStdDev) / float64(s.currentStep+1)
 	}
 
-	// Calculate confidence
-	if s.results.AccelAvgStdDev > 0 {
-		s.results.AccelConfidence = 100.0 / (1.0 + s.results.AccelAvgStdDev*100.0)
+	// Confidence: pose stillness so far, with the gravity consistency once
+	// all six poses are in
+	s.accelConfs = append(s.accelConfs, calibration.StillnessConfidence([3]float64{stddev(samples, 0), stddev(samples, 1), stddev(samples, 2)}))
+	if len(s.accelConfs) == 6 {
+		m := s.accelMeans
+		gx := math.Abs(m[2][0]-m[3][0]) / 2
+		gy := math.Abs(m[4][1]-m[5][1]) / 2
+		gz := math.Abs(m[0][2]-m[1][2]) / 2
+		s.results.AccelConfidence = calibration.Accel6PointConfidence(s.accelConfs, gx, gy, gz)
+	} else {
+		var sum float64
+		for _, c := range s.accelConfs {
+			sum += c
+		}
+		s.results.AccelConfidence = sum / float64(len(s.accelConfs))
 	}
 
 	s.sendStats()
@@ -391,28 +862,36 @@ func (s *CalibrationSession) runMagStep() error {
 		if !mgr.IsLeftIMUAvailable() {
 			return fmt.Errorf("left IMU not available")
 		}
-		readFunc = mgr.ReadLeftIMU
+		readFunc = uncalibratedReader(mgr, "left")
 	} else {
 		if !mgr.IsRightIMUAvailable() {
 			return fmt.Errorf("right IMU not available")
 		}
-		readFunc = mgr.ReadRightIMU
+		readFunc = uncalibratedReader(mgr, "right")
 	}
 
 	time.Sleep(2 * time.Second) // Give user time to start moving
 
-	// Collect magnetometer samples for 20 seconds
-	samples := make([][3]float64, 0, 200)
+	// Collect magnetometer samples (20 seconds by default)
+	n := s.capture.MagSamples
+	samples := make([][3]float64, 0, n)
 	minX, minY, minZ := math.MaxFloat64, math.MaxFloat64, math.MaxFloat64
 	maxX, maxY, maxZ := -math.MaxFloat64, -math.MaxFloat64, -math.MaxFloat64
 
-	for i := 0; i < 200; i++ {
+	for i := 0; i < n; i++ {
 		reading, err := readFunc()
 		if err != nil {
 			return err
 		}
 
-		mx, my, mz := float64(reading.Mx), float64(reading.My), float64(reading.Mz)
+		// Skip overflowed or missing mag samples so they don't skew min/max
+		if !reading.MagValid {
+			s.sendProgress(float64(i) * 100 / float64(n))
+			time.Sleep(s.capture.interval())
+			continue
+		}
+
+		mx, my, mz := imu_raw.MagUT(reading.Mx), imu_raw.MagUT(reading.My), imu_raw.MagUT(reading.Mz)
 		samples = append(samples, [3]float64{mx, my, mz})
 
 		// Track min/max for each axis
@@ -435,24 +914,23 @@ func (s *CalibrationSession) runMagStep() error {
 			maxZ = mz
 		}
 
-		s.sendProgress(float64(i) * 0.5)
-		time.Sleep(100 * time.Millisecond)
+		s.sendProgress(float64(i) * 100 / float64(n))
+		time.Sleep(s.capture.interval())
 	}
 
-	// Calculate hard-iron offsets (center of ellipsoid)
-	s.results.MagOffsetX = (maxX + minX) / 2.0
-	s.results.MagOffsetY = (maxY + minY) / 2.0
-	s.results.MagOffsetZ = (maxZ + minZ) / 2.0
+	// Hard-iron offsets (center of ellipsoid) and soft-iron scale factors
+	// (diagonal approximation), in µT
+	cal := imu_raw.MagCalibrationFromRange([3]float64{minX, minY, minZ}, [3]float64{maxX, maxY, maxZ})
+	s.results.MagOffsetX = cal.OffsetUT[0]
+	s.results.MagOffsetY = cal.OffsetUT[1]
+	s.results.MagOffsetZ = cal.OffsetUT[2]
+	s.results.MagScaleX = cal.Scale[0]
+	s.results.MagScaleY = cal.Scale[1]
+	s.results.MagScaleZ = cal.Scale[2]
 
-	// Calculate soft-iron scale factors (diagonal approximation)
 	rangeX := maxX - minX
 	rangeY := maxY - minY
 	rangeZ := maxZ - minZ
-	avgRange := (rangeX + rangeY + rangeZ) / 3.0
-
-	s.results.MagScaleX = avgRange / rangeX
-	s.results.MagScaleY = avgRange / rangeY
-	s.results.MagScaleZ = avgRange / rangeZ
 
 	s.results.MagRangeX = rangeX
 	s.results.MagRangeY = rangeY
@@ -460,11 +938,9 @@ func (s *CalibrationSession) runMagStep() error {
 	s.results.MagSampleCount = len(samples)
 	s.results.TotalSamples += len(samples)
 
-	// Calculate confidence based on range coverage
-	minRange := math.Min(rangeX, math.Min(rangeY, rangeZ))
-	maxRange := math.Max(rangeX, math.Max(rangeY, rangeZ))
-	rangeRatio := minRange / maxRange
-	s.results.MagConfidence = rangeRatio * 100.0
+	// Confidence from range coverage and sphericity after correction
+	s.results.MagConfidence = calibration.MagConfidence(
+		[3]float64{rangeX / 2, rangeY / 2, rangeZ / 2}, samples, cal.OffsetUT, cal.Scale)
 
 	s.sendProgress(100)
 	s.sendStats()
@@ -474,9 +950,58 @@ func (s *CalibrationSession) runMagStep() error {
 	return s.complete()
 }
 
+// defaultCalibrationMinConfidence applies when CALIBRATION_MIN_CONFIDENCE is
+// not set.
+const defaultCalibrationMinConfidence = 0.5
+
+// overallConfidence weights the confidences like cmd/calibration, 0-1.
+func (r CalibrationResult) overallConfidence() float64 {
+	return calibration.OverallConfidence(r.GyroStaticConf, r.GyroRotationConf, r.AccelConfidence, r.MagConfidence)
+}
+
+// normalizeConfidence converts the 0-100 confidences of files written before
+// the web UI used the shared heuristics to 0-1, and fills the gyro static and
+// rotation confidences they lack from the combined gyro one.
+func (r *CalibrationResult) normalizeConfidence() {
+	if r.GyroConfidence > 1 || r.AccelConfidence > 1 || r.MagConfidence > 1 {
+		r.GyroConfidence /= 100
+		r.AccelConfidence /= 100
+		r.MagConfidence /= 100
+	}
+	if r.GyroStaticConf == 0 && r.GyroRotationConf == 0 {
+		r.GyroStaticConf = r.GyroConfidence
+		r.GyroRotationConf = r.GyroConfidence
+	}
+}
+
+// complete saves the results, unless their overall confidence is below
+// CALIBRATION_MIN_CONFIDENCE: then it sends a warning and waits for a
+// "confirm" action before saving.
 func (s *CalibrationSession) complete() error {
+	minConf := config.Get().CalibrationMinConfidence
+	if minConf <= 0 {
+		minConf = defaultCalibrationMinConfidence
+	}
+	s.results.OverallConfidence = s.results.overallConfidence()
+	if overall := s.results.OverallConfidence; overall < minConf {
+		s.awaitingConfirm = true
+		logging.Infof("calibration: overall confidence %.2f below %.2f, waiting for confirmation", overall, minConf)
+		s.send(WSResponse{
+			Type:    "warning",
+			Message: fmt.Sprintf("Overall confidence %.0f%% is below the %.0f%% minimum; the calibration may be useless.", overall*100, minConf*100),
+			Results: map[string]interface{}{"overall_confidence": overall, "min_confidence": minConf},
+		})
+		return nil
+	}
+	return s.save()
+}
+
+// save writes the results to a calibration file and reports it to the client.
+func (s *CalibrationSession) save() error {
+	s.awaitingConfirm = false
+
 	// Save results to file
-	filename := fmt.Sprintf("%s_%d_inertial_calibration.json", s.IMU, time.Now().Unix())
+	filename := fmt.Sprintf("%s_%d%s", s.IMU, time.Now().Unix(), calibrationFileSuffix)
 
 	// Use current directory
 	cwd, err := os.Getwd()
@@ -495,10 +1020,11 @@ func (s *CalibrationSession) complete() error {
 		return fmt.Errorf("failed to write calibration file: %w", err)
 	}
 
-	log.Printf("calibration: saved results to %s", filepath)
+	logging.Infof("calibration: saved results to %s", filepath)
+	s.release()
 
 	// Send completion message
-	s.Conn.WriteJSON(WSResponse{
+	s.send(WSResponse{
 		Type:    "complete",
 		Results: map[string]interface{}{"filename": filename},
 	})
@@ -506,15 +1032,39 @@ func (s *CalibrationSession) complete() error {
 	return nil
 }
 
+// sendSettings reports the effective capture settings and the resulting
+// step durations in seconds.
+func (s *CalibrationSession) sendSettings() {
+	c := s.capture
+	s.send(WSResponse{
+		Type: "settings",
+		Results: map[string]interface{}{
+			"capture": c,
+			"durations_sec": map[string]float64{
+				"gyro_static":   c.duration(c.GyroStaticSamples).Seconds(),
+				"gyro_rotation": c.duration(c.GyroRotationSamples).Seconds(),
+				"accel":         c.duration(c.AccelSamples).Seconds(),
+				"mag":           c.duration(c.MagSamples).Seconds(),
+			},
+		},
+	})
+}
+
+// send writes a message to the client, giving up after wsWriteWait.
+func (s *CalibrationSession) send(v interface{}) error {
+	s.Conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	return s.Conn.WriteJSON(v)
+}
+
 func (s *CalibrationSession) sendPhase(phase string) {
-	s.Conn.WriteJSON(WSResponse{
+	s.send(WSResponse{
 		Type:  "phase",
 		Phase: phase,
 	})
 }
 
 func (s *CalibrationSession) sendStep(step, phase string) {
-	s.Conn.WriteJSON(WSResponse{
+	s.send(WSResponse{
 		Type:  "step",
 		Step:  step,
 		Phase: phase,
@@ -522,7 +1072,7 @@ func (s *CalibrationSession) sendStep(step, phase string) {
 }
 
 func (s *CalibrationSession) sendProgress(progress float64) {
-	s.Conn.WriteJSON(WSResponse{
+	s.send(WSResponse{
 		Type:     "progress",
 		Progress: progress,
 	})
@@ -533,29 +1083,38 @@ func (s *CalibrationSession) sendStats() {
 		"gyro":    s.results.GyroConfidence,
 		"accel":   s.results.AccelConfidence,
 		"mag":     s.results.MagConfidence,
+		"overall": s.results.overallConfidence(),
 		"samples": s.results.TotalSamples,
 	}
-	s.Conn.WriteJSON(WSResponse{
+	s.send(WSResponse{
 		Type:  "stats",
 		Stats: stats,
 	})
 }
 
 func (s *CalibrationSession) sendActionReady() {
-	s.Conn.WriteJSON(WSResponse{
+	s.send(WSResponse{
 		Type:    "action",
 		Message: "ready",
 	})
 }
 
 func (s *CalibrationSession) sendError(message string) {
-	s.Conn.WriteJSON(WSResponse{
+	s.send(WSResponse{
 		Type:    "error",
 		Message: message,
 	})
 }
 
 // Helper functions for statistics
+// uncalibratedReader reads imu ignoring the active calibration (see
+// /api/calibration/apply), so new calibrations are computed from raw samples.
+func uncalibratedReader(mgr *sensors.IMUManager, imu string) func() (imu_raw.IMURaw, error) {
+	return func() (imu_raw.IMURaw, error) {
+		return mgr.ReadIMUUncalibrated(imu)
+	}
+}
+
 func mean(data [][3]float64, axis int) float64 {
 	sum := 0.0
 	for _, v := range data {
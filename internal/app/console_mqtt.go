@@ -6,102 +6,114 @@
 package app
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
-	"os"
-	"os/signal"
-	"syscall"
+	"time"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 
 	"github.com/relabs-tech/inertial_computer/internal/config"
 	"github.com/relabs-tech/inertial_computer/internal/gps"
 	imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
 	"github.com/relabs-tech/inertial_computer/internal/orientation"
 )
 
-func RunConsoleMQTT() error {
+// RunConsoleMQTT prints the pose, IMU and GPS topics until ctx is cancelled.
+func RunConsoleMQTT(ctx context.Context) error {
 	cfg := config.Get()
 
-	opts := mqtt.NewClientOptions().
-		AddBroker(cfg.MQTTBroker).
-		SetClientID(cfg.MQTTClientIDConsole)
-
-	client := mqtt.NewClient(opts)
-	if token := client.Connect(); token.Wait() && token.Error() != nil {
-		return token.Error()
+	client, err := NewMQTTClient(cfg.MQTTClientIDConsole)
+	if err != nil {
+		return err
 	}
-	log.Printf("console: connected to MQTT broker at %s", cfg.MQTTBroker)
+	logging.Infof("console: connected to MQTT broker at %s", cfg.MQTTBroker)
+
+	// Lost-message detection for streams with sequence numbers
+	// (MQTT_SEQUENCE_NUMBERS); each is only used by its own callback
+	var seqPoseLeft, seqPoseRight, seqPoseFused, seqIMULeft, seqIMURight imu_raw.SeqTracker
 
 	// Subscribe to left pose
 	poseLeftToken := client.Subscribe(cfg.TopicPoseLeft, 0, func(_ mqtt.Client, msg mqtt.Message) {
 		var p orientation.Pose
 		if err := json.Unmarshal(msg.Payload(), &p); err != nil {
-			log.Printf("console: left pose unmarshal error: %v", err)
+			logging.Warnf("console: left pose unmarshal error: %v", err)
 			return
 		}
+		if missed := seqPoseLeft.Observe(p.Seq); missed > 0 {
+			logging.Warnf("console: left pose: %d message(s) lost before seq %d", missed, p.Seq)
+		}
 
 		fmt.Printf(
-			"[LEFT]  ROLL=%6.2f  PITCH=%6.2f  YAW=%6.2f\n",
-			p.Roll, p.Pitch, p.Yaw,
+			"[LEFT]  ROLL=%6.2f  PITCH=%6.2f  YAW=%6.2f%s\n",
+			p.Roll, p.Pitch, p.Yaw, sampleLatency(p.Timestamp),
 		)
 	})
 	poseLeftToken.Wait()
 	if poseLeftToken.Error() != nil {
 		return poseLeftToken.Error()
 	}
-	log.Printf("console: subscribed to %s", cfg.TopicPoseLeft)
+	logging.Infof("console: subscribed to %s", cfg.TopicPoseLeft)
 
 	// Subscribe to right pose
 	poseRightToken := client.Subscribe(cfg.TopicPoseRight, 0, func(_ mqtt.Client, msg mqtt.Message) {
 		var p orientation.Pose
 		if err := json.Unmarshal(msg.Payload(), &p); err != nil {
-			log.Printf("console: right pose unmarshal error: %v", err)
+			logging.Warnf("console: right pose unmarshal error: %v", err)
 			return
 		}
+		if missed := seqPoseRight.Observe(p.Seq); missed > 0 {
+			logging.Warnf("console: right pose: %d message(s) lost before seq %d", missed, p.Seq)
+		}
 
 		fmt.Printf(
-			"[RIGHT] ROLL=%6.2f  PITCH=%6.2f  YAW=%6.2f\n",
-			p.Roll, p.Pitch, p.Yaw,
+			"[RIGHT] ROLL=%6.2f  PITCH=%6.2f  YAW=%6.2f%s\n",
+			p.Roll, p.Pitch, p.Yaw, sampleLatency(p.Timestamp),
 		)
 	})
 	poseRightToken.Wait()
 	if poseRightToken.Error() != nil {
 		return poseRightToken.Error()
 	}
-	log.Printf("console: subscribed to %s", cfg.TopicPoseRight)
+	logging.Infof("console: subscribed to %s", cfg.TopicPoseRight)
 
 	// Subscribe to fused orientation
 	fusedToken := client.Subscribe(cfg.TopicPoseFused, 0, func(_ mqtt.Client, msg mqtt.Message) {
 		var p orientation.Pose
 		if err := json.Unmarshal(msg.Payload(), &p); err != nil {
-			log.Printf("console: fused pose unmarshal error: %v", err)
+			logging.Warnf("console: fused pose unmarshal error: %v", err)
 			return
 		}
+		if missed := seqPoseFused.Observe(p.Seq); missed > 0 {
+			logging.Warnf("console: fused pose: %d message(s) lost before seq %d", missed, p.Seq)
+		}
 
 		fmt.Printf(
-			"[FUSE] ROLL=%6.2f  PITCH=%6.2f  YAW=%6.2f\n",
-			p.Roll, p.Pitch, p.Yaw,
+			"[FUSE] ROLL=%6.2f  PITCH=%6.2f  YAW=%6.2f%s\n",
+			p.Roll, p.Pitch, p.Yaw, sampleLatency(p.Timestamp),
 		)
 	})
 	fusedToken.Wait()
 	if fusedToken.Error() != nil {
 		return fusedToken.Error()
 	}
-	log.Printf("console: subscribed to %s", cfg.TopicPoseFused)
+	logging.Infof("console: subscribed to %s", cfg.TopicPoseFused)
 
 	// Subscribe to IMU left
 	imuLeftToken := client.Subscribe(cfg.TopicIMULeft, 0, func(_ mqtt.Client, msg mqtt.Message) {
 		var s imu_raw.IMURaw
 		if err := json.Unmarshal(msg.Payload(), &s); err != nil {
-			log.Printf("console: imu left unmarshal error: %v", err)
+			logging.Warnf("console: imu left unmarshal error: %v", err)
 			return
 		}
+		if missed := seqIMULeft.Observe(s.Seq); missed > 0 {
+			logging.Warnf("console: imu left: %d message(s) lost before seq %d", missed, s.Seq)
+		}
 
 		fmt.Printf(
-			"[IMU-L] ax=%6d ay=%6d az=%6d  gx=%6d gy=%6d gz=%6d  mx=%6d my=%6d mz=%6d\n",
-			s.Ax, s.Ay, s.Az, s.Gx, s.Gy, s.Gz, s.Mx, s.My, s.Mz,
+			"[IMU-L] ax=%6d ay=%6d az=%6d  gx=%6d gy=%6d gz=%6d  mx=%6d my=%6d mz=%6d%s\n",
+			s.Ax, s.Ay, s.Az, s.Gx, s.Gy, s.Gz, s.Mx, s.My, s.Mz, sampleLatency(s.Timestamp),
 		)
 	})
 	imuLeftToken.Wait()
@@ -113,12 +125,15 @@ func RunConsoleMQTT() error {
 	imuRightToken := client.Subscribe(cfg.TopicIMURight, 0, func(_ mqtt.Client, msg mqtt.Message) {
 		var s imu_raw.IMURaw
 		if err := json.Unmarshal(msg.Payload(), &s); err != nil {
-			log.Printf("console: imu right unmarshal error: %v", err)
+			logging.Warnf("console: imu right unmarshal error: %v", err)
 			return
 		}
+		if missed := seqIMURight.Observe(s.Seq); missed > 0 {
+			logging.Warnf("console: imu right: %d message(s) lost before seq %d", missed, s.Seq)
+		}
 		fmt.Printf(
-			"[IMU-R] ax=%6d ay=%6d az=%6d  gx=%6d gy=%6d gz=%6d  mx=%6d my=%6d mz=%6d\n",
-			s.Ax, s.Ay, s.Az, s.Gx, s.Gy, s.Gz, s.Mx, s.My, s.Mz,
+			"[IMU-R] ax=%6d ay=%6d az=%6d  gx=%6d gy=%6d gz=%6d  mx=%6d my=%6d mz=%6d%s\n",
+			s.Ax, s.Ay, s.Az, s.Gx, s.Gy, s.Gz, s.Mx, s.My, s.Mz, sampleLatency(s.Timestamp),
 		)
 	})
 
@@ -127,13 +142,13 @@ func RunConsoleMQTT() error {
 		return imuRightToken.Error()
 	}
 
-	log.Printf("console: subscribed to %s", cfg.TopicIMURight)
+	logging.Infof("console: subscribed to %s", cfg.TopicIMURight)
 
 	// Subscribe to GPS
 	gpsToken := client.Subscribe(cfg.TopicGPS, 0, func(_ mqtt.Client, msg mqtt.Message) {
 		var f gps.Fix
 		if err := json.Unmarshal(msg.Payload(), &f); err != nil {
-			log.Printf("console: gps unmarshal error: %v", err)
+			logging.Warnf("console: gps unmarshal error: %v", err)
 			return
 		}
 
@@ -146,14 +161,20 @@ func RunConsoleMQTT() error {
 	if gpsToken.Error() != nil {
 		return gpsToken.Error()
 	}
-	log.Printf("console: subscribed to %s", cfg.TopicGPS)
+	logging.Infof("console: subscribed to %s", cfg.TopicGPS)
 
-	// Wait for Ctrl+C
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
-	<-sigCh
+	<-ctx.Done()
 
-	log.Println("console: shutting down")
-	client.Disconnect(250)
+	logging.Infof("console: shutting down")
+	DisconnectMQTT(client)
 	return nil
 }
+
+// sampleLatency formats the time since a payload's producer timestamp
+// ("" for payloads without one).
+func sampleLatency(ts time.Time) string {
+	if ts.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("  delay=%dms", time.Since(ts).Milliseconds())
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package app
+
+import (
+	"net/http"
+	"strings"
+)
+
+// withCORS adds CORS headers to /api/* responses for the allowed origins and
+// answers preflight OPTIONS requests. Other paths (the static UI) are passed
+// through untouched. With no allowed origins it is a no-op.
+func withCORS(allowed []string, next http.Handler) http.Handler {
+	if len(allowed) == 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		origin := r.Header.Get("Origin")
+		if allowOrigin := corsAllowOrigin(allowed, origin); allowOrigin != "" {
+			h := w.Header()
+			h.Set("Access-Control-Allow-Origin", allowOrigin)
+			if allowOrigin != "*" {
+				h.Add("Vary", "Origin")
+			}
+			h.Set("Access-Control-Expose-Headers", "X-Received-At, X-Age-Ms, X-Stale")
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				h.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+				if reqHeaders := r.Header.Get("Access-Control-Request-Headers"); reqHeaders != "" {
+					h.Set("Access-Control-Allow-Headers", reqHeaders)
+				}
+				h.Set("Access-Control-Max-Age", "600")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// corsAllowOrigin returns the Access-Control-Allow-Origin value for origin,
+// or "" if it is not allowed.
+func corsAllowOrigin(allowed []string, origin string) string {
+	if origin == "" {
+		return ""
+	}
+	for _, a := range allowed {
+		if a == "*" {
+			return "*"
+		}
+		if strings.EqualFold(a, origin) {
+			return origin
+		}
+	}
+	return ""
+}
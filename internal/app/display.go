@@ -6,10 +6,11 @@
 package app
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"image"
-	"log"
+	"math"
 	"sync"
 	"time"
 
@@ -17,14 +18,17 @@ import (
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/basicfont"
 	"golang.org/x/image/math/fixed"
+	"periph.io/x/conn/v3/i2c"
 	"periph.io/x/conn/v3/i2c/i2creg"
 	"periph.io/x/devices/v3/ssd1306"
 	"periph.io/x/devices/v3/ssd1306/image1bit"
 	"periph.io/x/host/v3"
 
 	"github.com/relabs-tech/inertial_computer/internal/config"
+	"github.com/relabs-tech/inertial_computer/internal/env"
 	"github.com/relabs-tech/inertial_computer/internal/gps"
 	"github.com/relabs-tech/inertial_computer/internal/imu"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
 	"github.com/relabs-tech/inertial_computer/internal/orientation"
 )
 
@@ -44,12 +48,39 @@ type DisplayData struct {
 	poseRight     orientation.Pose
 	havePoseRight bool
 
+	// Environmental (BMP) data
+	envLeft      env.Sample
+	haveEnvLeft  bool
+	envRight     env.Sample
+	haveEnvRight bool
+
 	// GPS data
 	gpsPos  gps.Position
 	haveGPS bool
 }
 
-func RunDisplay() error {
+// cyclePages lists the content pages shown, in order, by the "cycle" content mode.
+var cyclePages = []string{"imu_raw_left", "imu_raw_right", "orientation_left", "orientation_right", "gps"}
+
+// displayCycler selects which page a "cycle" display shows at a given time.
+type displayCycler struct {
+	start time.Time
+	dwell time.Duration
+}
+
+func newDisplayCycler(dwell time.Duration) *displayCycler {
+	return &displayCycler{start: time.Now(), dwell: dwell}
+}
+
+// page returns the content page active at time now.
+func (c *displayCycler) page(now time.Time) string {
+	idx := int(now.Sub(c.start)/c.dwell) % len(cyclePages)
+	return cyclePages[idx]
+}
+
+// RunDisplay drives the left/right OLEDs from MQTT data until ctx is
+// cancelled.
+func RunDisplay(ctx context.Context) error {
 	cfg := config.Get()
 
 	// Initialize periph
@@ -64,41 +95,36 @@ func RunDisplay() error {
 	}
 	defer bus.Close()
 
-	// Initialize left display
-	leftDisplay, err := ssd1306.NewI2C(bus, cfg.DisplayLeftI2CAddr, &ssd1306.DefaultOpts)
-	if err != nil {
-		return fmt.Errorf("failed to initialize left display: %w", err)
-	}
-	log.Printf("display: left display initialized at 0x%02X", cfg.DisplayLeftI2CAddr)
-
-	// Initialize right display
-	rightDisplay, err := ssd1306.NewI2C(bus, cfg.DisplayRightI2CAddr, &ssd1306.DefaultOpts)
-	if err != nil {
-		return fmt.Errorf("failed to initialize right display: %w", err)
+	// Initialize displays. A display that fails here is retried by its
+	// update loop, so one missing OLED does not stop the other.
+	leftDisplay := newOLEDDisplay("left", bus, cfg.DisplayLeftI2CAddr, cfg.DisplayLeftContent, cfg)
+	rightDisplay := newOLEDDisplay("right", bus, cfg.DisplayRightI2CAddr, cfg.DisplayRightContent, cfg)
+	if leftDisplay.dev == nil && rightDisplay.dev == nil {
+		return fmt.Errorf("failed to initialize both displays")
 	}
-	log.Printf("display: right display initialized at 0x%02X", cfg.DisplayRightI2CAddr)
 
 	// Show splash screens
-	if err := showLeftSplash(leftDisplay); err != nil {
-		log.Printf("display: error showing left splash: %v", err)
+	if leftDisplay.dev != nil {
+		if err := showLeftSplash(leftDisplay.dev); err != nil {
+			logging.Errorf("display: error showing left splash: %v", err)
+		}
 	}
-	if err := showRightSplash(rightDisplay); err != nil {
-		log.Printf("display: error showing right splash: %v", err)
+	if rightDisplay.dev != nil {
+		if err := showRightSplash(rightDisplay.dev); err != nil {
+			logging.Errorf("display: error showing right splash: %v", err)
+		}
 	}
 
 	// Data storage
 	data := &DisplayData{}
 
 	// Connect to MQTT
-	opts := mqtt.NewClientOptions().
-		AddBroker(cfg.MQTTBroker).
-		SetClientID(cfg.MQTTClientIDDisplay)
-
-	client := mqtt.NewClient(opts)
-	if token := client.Connect(); token.Wait() && token.Error() != nil {
-		return token.Error()
+	client, err := NewMQTTClient(cfg.MQTTClientIDDisplay)
+	if err != nil {
+		return err
 	}
-	log.Printf("display: connected to MQTT broker at %s", cfg.MQTTBroker)
+	defer DisconnectMQTT(client)
+	logging.Infof("display: connected to MQTT broker at %s", cfg.MQTTBroker)
 
 	// Subscribe to topics based on display content configuration
 	if err := subscribeForContent(client, cfg.DisplayLeftContent, data, cfg); err != nil {
@@ -108,41 +134,157 @@ func RunDisplay() error {
 		return fmt.Errorf("failed to subscribe for right display: %w", err)
 	}
 
-	// Display update loop
-	ticker := time.NewTicker(time.Duration(cfg.DisplayUpdateInterval) * time.Millisecond)
-	defer ticker.Stop()
-
-	log.Println("display: starting update loop")
-
-	for range ticker.C {
-		// Read data without copying the mutex
-		data.mu.RLock()
-		snapshot := DisplayData{
-			imuRawLeft:      data.imuRawLeft,
-			haveIMURawLeft:  data.haveIMURawLeft,
-			imuRawRight:     data.imuRawRight,
-			haveIMURawRight: data.haveIMURawRight,
-			poseLeft:        data.poseLeft,
-			havePoseLeft:    data.havePoseLeft,
-			poseRight:       data.poseRight,
-			havePoseRight:   data.havePoseRight,
-			gpsPos:          data.gpsPos,
-			haveGPS:         data.haveGPS,
-		}
-		data.mu.RUnlock()
+	// Page rotation for "cycle" content mode
+	cycleSeconds := cfg.DisplayCycleSeconds
+	if cycleSeconds <= 0 {
+		cycleSeconds = 5
+	}
+	cycler := newDisplayCycler(time.Duration(cycleSeconds) * time.Second)
+
+	// Display update loops: one goroutine per display so a slow or failing
+	// I2C transaction on one OLED never delays the other.
+	interval := time.Duration(cfg.DisplayUpdateInterval) * time.Millisecond
+	logging.Infof("display: starting update loop")
+
+	var wg sync.WaitGroup
+	for _, d := range []*oledDisplay{leftDisplay, rightDisplay} {
+		wg.Add(1)
+		go func(d *oledDisplay) {
+			defer wg.Done()
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case now := <-ticker.C:
+					snapshot := data.snapshot()
+					d.update(&snapshot, cycler, now)
+				}
+			}
+		}(d)
+	}
+	wg.Wait()
 
-		// Update left display
-		if err := updateDisplay(leftDisplay, cfg.DisplayLeftContent, &snapshot); err != nil {
-			log.Printf("display: error updating left display: %v", err)
-		}
+	return nil
+}
+
+// snapshot copies the latest data without copying the mutex.
+func (d *DisplayData) snapshot() DisplayData {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return DisplayData{
+		imuRawLeft:      d.imuRawLeft,
+		haveIMURawLeft:  d.haveIMURawLeft,
+		imuRawRight:     d.imuRawRight,
+		haveIMURawRight: d.haveIMURawRight,
+		poseLeft:        d.poseLeft,
+		havePoseLeft:    d.havePoseLeft,
+		poseRight:       d.poseRight,
+		havePoseRight:   d.havePoseRight,
+		envLeft:         d.envLeft,
+		haveEnvLeft:     d.haveEnvLeft,
+		envRight:        d.envRight,
+		haveEnvRight:    d.haveEnvRight,
+		gpsPos:          d.gpsPos,
+		haveGPS:         d.haveGPS,
+	}
+}
+
+// oledDisplay tracks one SSD1306 and its I2C health.
+// After consecutive draw failures it backs off exponentially, and after
+// reinitAfter failures it re-creates the device with ssd1306.NewI2C.
+type oledDisplay struct {
+	name    string
+	bus     i2c.Bus
+	addr    uint16
+	content string
+	dev     *ssd1306.Dev // nil until (re)initialization succeeds
+
+	reinitAfter int
+	maxBackoff  time.Duration
+	baseBackoff time.Duration
+
+	failures int
+	retryAt  time.Time
+}
 
-		// Update right display
-		if err := updateDisplay(rightDisplay, cfg.DisplayRightContent, &snapshot); err != nil {
-			log.Printf("display: error updating right display: %v", err)
+func newOLEDDisplay(name string, bus i2c.Bus, addr uint16, content string, cfg *config.Config) *oledDisplay {
+	reinitAfter := cfg.DisplayReinitAfterFailures
+	if reinitAfter <= 0 {
+		reinitAfter = 5
+	}
+	maxBackoffMS := cfg.DisplayMaxBackoffMS
+	if maxBackoffMS <= 0 {
+		maxBackoffMS = 5000
+	}
+
+	d := &oledDisplay{
+		name:        name,
+		bus:         bus,
+		addr:        addr,
+		content:     content,
+		reinitAfter: reinitAfter,
+		maxBackoff:  time.Duration(maxBackoffMS) * time.Millisecond,
+		baseBackoff: time.Duration(cfg.DisplayUpdateInterval) * time.Millisecond,
+	}
+
+	dev, err := ssd1306.NewI2C(bus, addr, &ssd1306.DefaultOpts)
+	if err != nil {
+		logging.Warnf("display: %s display init failed at 0x%02X (will retry): %v", name, addr, err)
+		d.failures = reinitAfter // go straight to re-initialization on the first tick
+		return d
+	}
+	d.dev = dev
+	logging.Infof("display: %s display initialized at 0x%02X", name, addr)
+	return d
+}
+
+// update draws the current content, handling failures, backoff and recovery.
+func (d *oledDisplay) update(data *DisplayData, cycler *displayCycler, now time.Time) {
+	if now.Before(d.retryAt) {
+		return
+	}
+
+	// Re-create the device after too many consecutive failures
+	if d.dev == nil || d.failures >= d.reinitAfter {
+		dev, err := ssd1306.NewI2C(d.bus, d.addr, &ssd1306.DefaultOpts)
+		if err != nil {
+			d.fail(now, fmt.Errorf("reinit at 0x%02X: %w", d.addr, err))
+			return
 		}
+		d.dev = dev
+		logging.Infof("display: %s display reinitialized at 0x%02X", d.name, d.addr)
 	}
 
-	return nil
+	if err := updateDisplay(d.dev, d.content, data, cycler); err != nil {
+		d.fail(now, err)
+		return
+	}
+
+	if d.failures > 0 {
+		logging.Infof("display: %s display recovered after %d consecutive failures", d.name, d.failures)
+		d.failures = 0
+		d.retryAt = time.Time{}
+	}
+}
+
+// fail records a failure and schedules the next attempt with exponential backoff.
+// Only the first failure and each re-initialization threshold are logged.
+func (d *oledDisplay) fail(now time.Time, err error) {
+	d.failures++
+	if d.failures == 1 || d.failures%d.reinitAfter == 0 {
+		logging.Errorf("display: error updating %s display (%d consecutive): %v", d.name, d.failures, err)
+	}
+
+	backoff := d.baseBackoff
+	for i := 1; i < d.failures && backoff < d.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > d.maxBackoff {
+		backoff = d.maxBackoff
+	}
+	d.retryAt = now.Add(backoff)
 }
 
 func subscribeForContent(client mqtt.Client, content string, data *DisplayData, cfg *config.Config) error {
@@ -151,7 +293,7 @@ func subscribeForContent(client mqtt.Client, content string, data *DisplayData,
 		token := client.Subscribe(cfg.TopicIMULeft, 0, func(_ mqtt.Client, msg mqtt.Message) {
 			var raw imu.IMURaw
 			if err := json.Unmarshal(msg.Payload(), &raw); err != nil {
-				log.Printf("display: imu_raw_left unmarshal error: %v", err)
+				logging.Warnf("display: imu_raw_left unmarshal error: %v", err)
 				return
 			}
 			data.mu.Lock()
@@ -163,13 +305,13 @@ func subscribeForContent(client mqtt.Client, content string, data *DisplayData,
 		if token.Error() != nil {
 			return token.Error()
 		}
-		log.Printf("display: subscribed to %s", cfg.TopicIMULeft)
+		logging.Infof("display: subscribed to %s", cfg.TopicIMULeft)
 
 	case "imu_raw_right":
 		token := client.Subscribe(cfg.TopicIMURight, 0, func(_ mqtt.Client, msg mqtt.Message) {
 			var raw imu.IMURaw
 			if err := json.Unmarshal(msg.Payload(), &raw); err != nil {
-				log.Printf("display: imu_raw_right unmarshal error: %v", err)
+				logging.Warnf("display: imu_raw_right unmarshal error: %v", err)
 				return
 			}
 			data.mu.Lock()
@@ -181,13 +323,13 @@ func subscribeForContent(client mqtt.Client, content string, data *DisplayData,
 		if token.Error() != nil {
 			return token.Error()
 		}
-		log.Printf("display: subscribed to %s", cfg.TopicIMURight)
+		logging.Infof("display: subscribed to %s", cfg.TopicIMURight)
 
-	case "orientation_left":
+	case "orientation_left", "attitude_left":
 		token := client.Subscribe(cfg.TopicPoseLeft, 0, func(_ mqtt.Client, msg mqtt.Message) {
 			var p orientation.Pose
 			if err := json.Unmarshal(msg.Payload(), &p); err != nil {
-				log.Printf("display: orientation_left unmarshal error: %v", err)
+				logging.Warnf("display: orientation_left unmarshal error: %v", err)
 				return
 			}
 			data.mu.Lock()
@@ -199,13 +341,13 @@ func subscribeForContent(client mqtt.Client, content string, data *DisplayData,
 		if token.Error() != nil {
 			return token.Error()
 		}
-		log.Printf("display: subscribed to %s", cfg.TopicPoseLeft)
+		logging.Infof("display: subscribed to %s", cfg.TopicPoseLeft)
 
-	case "orientation_right":
+	case "orientation_right", "attitude_right":
 		token := client.Subscribe(cfg.TopicPoseRight, 0, func(_ mqtt.Client, msg mqtt.Message) {
 			var p orientation.Pose
 			if err := json.Unmarshal(msg.Payload(), &p); err != nil {
-				log.Printf("display: orientation_right unmarshal error: %v", err)
+				logging.Warnf("display: orientation_right unmarshal error: %v", err)
 				return
 			}
 			data.mu.Lock()
@@ -217,13 +359,49 @@ func subscribeForContent(client mqtt.Client, content string, data *DisplayData,
 		if token.Error() != nil {
 			return token.Error()
 		}
-		log.Printf("display: subscribed to %s", cfg.TopicPoseRight)
+		logging.Infof("display: subscribed to %s", cfg.TopicPoseRight)
+
+	case "env_left":
+		token := client.Subscribe(cfg.TopicBMPLeft, 0, func(_ mqtt.Client, msg mqtt.Message) {
+			var e env.Sample
+			if err := json.Unmarshal(msg.Payload(), &e); err != nil {
+				logging.Warnf("display: env_left unmarshal error: %v", err)
+				return
+			}
+			data.mu.Lock()
+			data.envLeft = e
+			data.haveEnvLeft = true
+			data.mu.Unlock()
+		})
+		token.Wait()
+		if token.Error() != nil {
+			return token.Error()
+		}
+		logging.Infof("display: subscribed to %s", cfg.TopicBMPLeft)
+
+	case "env_right":
+		token := client.Subscribe(cfg.TopicBMPRight, 0, func(_ mqtt.Client, msg mqtt.Message) {
+			var e env.Sample
+			if err := json.Unmarshal(msg.Payload(), &e); err != nil {
+				logging.Warnf("display: env_right unmarshal error: %v", err)
+				return
+			}
+			data.mu.Lock()
+			data.envRight = e
+			data.haveEnvRight = true
+			data.mu.Unlock()
+		})
+		token.Wait()
+		if token.Error() != nil {
+			return token.Error()
+		}
+		logging.Infof("display: subscribed to %s", cfg.TopicBMPRight)
 
 	case "gps":
 		token := client.Subscribe(cfg.TopicGPSPosition, 0, func(_ mqtt.Client, msg mqtt.Message) {
 			var pos gps.Position
 			if err := json.Unmarshal(msg.Payload(), &pos); err != nil {
-				log.Printf("display: gps unmarshal error: %v", err)
+				logging.Warnf("display: gps unmarshal error: %v", err)
 				return
 			}
 			data.mu.Lock()
@@ -235,7 +413,15 @@ func subscribeForContent(client mqtt.Client, content string, data *DisplayData,
 		if token.Error() != nil {
 			return token.Error()
 		}
-		log.Printf("display: subscribed to %s", cfg.TopicGPSPosition)
+		logging.Infof("display: subscribed to %s", cfg.TopicGPSPosition)
+
+	case "cycle":
+		// Cycling shows every page, so subscribe to all of their topics
+		for _, page := range cyclePages {
+			if err := subscribeForContent(client, page, data, cfg); err != nil {
+				return err
+			}
+		}
 
 	default:
 		return fmt.Errorf("unknown display content type: %s", content)
@@ -244,8 +430,10 @@ func subscribeForContent(client mqtt.Client, content string, data *DisplayData,
 	return nil
 }
 
-func updateDisplay(dev *ssd1306.Dev, content string, data *DisplayData) error {
+func updateDisplay(dev *ssd1306.Dev, content string, data *DisplayData, cycler *displayCycler) error {
 	switch content {
+	case "cycle":
+		return updateDisplay(dev, cycler.page(time.Now()), data, cycler)
 	case "imu_raw_left":
 		return updateIMURawDisplay(dev, data.imuRawLeft, data.haveIMURawLeft, "Left")
 	case "imu_raw_right":
@@ -254,6 +442,14 @@ func updateDisplay(dev *ssd1306.Dev, content string, data *DisplayData) error {
 		return updateOrientationDisplay(dev, data.poseLeft, data.havePoseLeft)
 	case "orientation_right":
 		return updateOrientationDisplay(dev, data.poseRight, data.havePoseRight)
+	case "attitude_left":
+		return updateAttitudeDisplay(dev, data.poseLeft, data.havePoseLeft)
+	case "attitude_right":
+		return updateAttitudeDisplay(dev, data.poseRight, data.havePoseRight)
+	case "env_left":
+		return updateEnvDisplay(dev, data.envLeft, data.haveEnvLeft, "Left")
+	case "env_right":
+		return updateEnvDisplay(dev, data.envRight, data.haveEnvRight, "Right")
 	case "gps":
 		return updateGPSDisplay(dev, data.gpsPos, data.haveGPS)
 	default:
@@ -335,6 +531,153 @@ func updateOrientationDisplay(dev *ssd1306.Dev, pose orientation.Pose, haveData
 	return dev.Draw(dev.Bounds(), img, image.Point{})
 }
 
+func updateAttitudeDisplay(dev *ssd1306.Dev, pose orientation.Pose, haveData bool) error {
+	img := image1bit.NewVerticalLSB(image.Rect(0, 0, 128, 64))
+
+	// Blank image
+	for i := 0; i < 1024; i++ {
+		img.Pix[i] = 0
+	}
+
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  &image.Uniform{image1bit.On},
+		Face: basicfont.Face7x13,
+	}
+
+	if !haveData {
+		drawer.Dot = fixed.P(0, 26)
+		drawer.DrawBytes([]byte("Attitude"))
+		drawer.Dot = fixed.P(0, 39)
+		drawer.DrawBytes([]byte("Waiting..."))
+	} else {
+		drawAttitudeIndicator(img, pose.Roll, pose.Pitch)
+
+		// Numeric readout in the left-hand corners
+		drawer.Dot = fixed.P(0, 11)
+		drawer.DrawBytes([]byte(fmt.Sprintf("R%+4.0f", pose.Roll)))
+		drawer.Dot = fixed.P(0, 63)
+		drawer.DrawBytes([]byte(fmt.Sprintf("P%+4.0f", pose.Pitch)))
+	}
+
+	return dev.Draw(dev.Bounds(), img, image.Point{})
+}
+
+// attitudePixelsPerDegree is the vertical horizon shift per degree of pitch.
+const attitudePixelsPerDegree = 1.0
+
+// drawAttitudeIndicator draws an artificial horizon on a 128x64 image:
+// the horizon line rotates with roll and shifts with pitch (nose up moves it
+// down), with a fixed aircraft symbol in the center. Pitch is clamped so the
+// horizon always stays on-screen.
+func drawAttitudeIndicator(img *image1bit.VerticalLSB, roll, pitch float64) {
+	b := img.Bounds()
+	cx := float64(b.Dx()) / 2
+	cy := float64(b.Dy()) / 2
+
+	maxShift := cy - 4
+	shift := pitch * attitudePixelsPerDegree
+	if shift > maxShift {
+		shift = maxShift
+	}
+	if shift < -maxShift {
+		shift = -maxShift
+	}
+
+	// Horizon line through the pitch-shifted center, long enough to span the screen
+	rollRad := roll * math.Pi / 180.0
+	half := float64(b.Dx())
+	dx := half * math.Cos(rollRad)
+	dy := half * math.Sin(rollRad)
+	hy := cy + shift
+	drawLine(img,
+		int(math.Round(cx-dx)), int(math.Round(hy+dy)),
+		int(math.Round(cx+dx)), int(math.Round(hy-dy)))
+
+	// Fixed aircraft symbol: two wings and a center dot
+	icx, icy := int(cx), int(cy)
+	drawLine(img, icx-20, icy, icx-6, icy)
+	drawLine(img, icx+6, icy, icx+20, icy)
+	drawLine(img, icx-6, icy, icx-6, icy+3)
+	drawLine(img, icx+6, icy, icx+6, icy+3)
+	img.SetBit(icx, icy, image1bit.On)
+}
+
+// drawLine draws a line between two points using Bresenham's algorithm.
+// Pixels outside the image bounds are skipped.
+func drawLine(img *image1bit.VerticalLSB, x0, y0, x1, y1 int) {
+	b := img.Bounds()
+	dx := x1 - x0
+	if dx < 0 {
+		dx = -dx
+	}
+	dy := -(y1 - y0)
+	if dy > 0 {
+		dy = -dy
+	}
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		if image.Pt(x0, y0).In(b) {
+			img.SetBit(x0, y0, image1bit.On)
+		}
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func updateEnvDisplay(dev *ssd1306.Dev, sample env.Sample, haveData bool, label string) error {
+	img := image1bit.NewVerticalLSB(image.Rect(0, 0, 128, 64))
+
+	// Blank image
+	for i := 0; i < 1024; i++ {
+		img.Pix[i] = 0
+	}
+
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  &image.Uniform{image1bit.On},
+		Face: basicfont.Face7x13,
+	}
+
+	if !haveData {
+		drawer.Dot = fixed.P(0, 26)
+		drawer.DrawBytes([]byte("BMP " + label))
+		drawer.Dot = fixed.P(0, 39)
+		drawer.DrawBytes([]byte("Waiting..."))
+	} else {
+		// Temperature
+		drawer.Dot = fixed.P(0, 13)
+		drawer.DrawBytes([]byte(fmt.Sprintf("T: %.1fC", sample.Temperature)))
+
+		// Pressure
+		drawer.Dot = fixed.P(0, 26)
+		drawer.DrawBytes([]byte(fmt.Sprintf("P: %.1fhPa", sample.PressureHPa)))
+
+		// Barometric altitude (approximate unless BMP_SEA_LEVEL_HPA is local QNH)
+		drawer.Dot = fixed.P(0, 39)
+		drawer.DrawBytes([]byte(fmt.Sprintf("Alt: %.0fm", sample.Altitude)))
+	}
+
+	return dev.Draw(dev.Bounds(), img, image.Point{})
+}
+
 func updateGPSDisplay(dev *ssd1306.Dev, pos gps.Position, haveData bool) error {
 	img := image1bit.NewVerticalLSB(image.Rect(0, 0, 128, 64))
 
@@ -0,0 +1,183 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/relabs-tech/inertial_computer/internal/config"
+	"github.com/relabs-tech/inertial_computer/internal/fusion"
+	"github.com/relabs-tech/inertial_computer/internal/gps"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
+	"github.com/relabs-tech/inertial_computer/internal/magmodel"
+	"github.com/relabs-tech/inertial_computer/internal/orientation"
+)
+
+// RunFusionProducer subscribes to the fused IMU pose and GPS topics, runs the
+// navigation estimator and publishes the fused state to TOPIC_FUSED_STATE.
+func RunFusionProducer(ctx context.Context) error {
+	cfg := config.Get()
+
+	minSpeed := cfg.FusionMinGPSSpeedKnots
+	if minSpeed <= 0 {
+		minSpeed = 2.0
+	}
+	ms := cfg.FusionPublishInterval
+	if ms <= 0 {
+		ms = 100
+	}
+
+	heading := fusion.NewHeadingFusion(minSpeed)
+	if cfg.FusionDRMaxDuration > 0 {
+		heading.MaxDRDuration = time.Duration(cfg.FusionDRMaxDuration) * time.Second
+	}
+	heading.DeclinationDeg = cfg.MagDeclinationDeg
+	if cfg.MagDeclinationTable != "" {
+		table, err := fusion.LoadDeclinationTable(cfg.MagDeclinationTable)
+		if err != nil {
+			return fmt.Errorf("MAG_DECLINATION_TABLE: %w", err)
+		}
+		heading.DeclinationTable = table
+		logging.Infof("fusion: declination from %s at the GPS position (%.1f° outside it)", cfg.MagDeclinationTable, cfg.MagDeclinationDeg)
+	}
+	if cfg.MagDeclinationModel {
+		model := magmodel.Default()
+		heading.DeclinationModel = model.Declination
+		logging.Infof("fusion: declination from %s (epoch %.1f) at the GPS position", model.Name, model.Epoch)
+		if !model.ValidAt(time.Now()) {
+			logging.Warnf("fusion: %s is outside its validity period, declination is extrapolated", model.Name)
+		}
+	}
+
+	var (
+		mu sync.Mutex
+		// Estimator is an interface so a Kalman filter can replace HeadingFusion later.
+		estimator fusion.Estimator = heading
+		lastPos   gps.Position
+		lastVel   gps.Velocity
+		moving    = make(map[string]bool) // stationarity per IMU (motion topics)
+	)
+
+	// 1) Connect to MQTT
+	client, err := NewMQTTClient(cfg.MQTTClientIDFusion)
+	if err != nil {
+		return err
+	}
+	defer DisconnectMQTT(client)
+	logging.Infof("fusion: connected to MQTT broker at %s", cfg.MQTTBroker)
+
+	// 2) Subscribe to fused IMU pose
+	poseToken := client.Subscribe(cfg.TopicPoseFused, 0, func(_ mqtt.Client, msg mqtt.Message) {
+		var p orientation.Pose
+		if err := json.Unmarshal(msg.Payload(), &p); err != nil {
+			logging.Warnf("fusion: pose unmarshal error: %v", err)
+			return
+		}
+		mu.Lock()
+		estimator.UpdatePose(p, time.Now())
+		mu.Unlock()
+	})
+	poseToken.Wait()
+	if poseToken.Error() != nil {
+		return poseToken.Error()
+	}
+	logging.Infof("fusion: subscribed to %s", cfg.TopicPoseFused)
+
+	// 3) Subscribe to GPS position
+	posToken := client.Subscribe(cfg.TopicGPSPosition, 0, func(_ mqtt.Client, msg mqtt.Message) {
+		var pos gps.Position
+		if err := json.Unmarshal(msg.Payload(), &pos); err != nil {
+			logging.Warnf("fusion: gps position unmarshal error: %v", err)
+			return
+		}
+		mu.Lock()
+		lastPos = pos
+		estimator.UpdateGPS(lastPos, lastVel, time.Now())
+		mu.Unlock()
+	})
+	posToken.Wait()
+	if posToken.Error() != nil {
+		return posToken.Error()
+	}
+	logging.Infof("fusion: subscribed to %s", cfg.TopicGPSPosition)
+
+	// 4) Subscribe to GPS velocity
+	velToken := client.Subscribe(cfg.TopicGPSVelocity, 0, func(_ mqtt.Client, msg mqtt.Message) {
+		var vel gps.Velocity
+		if err := json.Unmarshal(msg.Payload(), &vel); err != nil {
+			logging.Warnf("fusion: gps velocity unmarshal error: %v", err)
+			return
+		}
+		mu.Lock()
+		lastVel = vel
+		mu.Unlock()
+	})
+	velToken.Wait()
+	if velToken.Error() != nil {
+		return velToken.Error()
+	}
+	logging.Infof("fusion: subscribed to %s", cfg.TopicGPSVelocity)
+
+	// 4b) Subscribe to the per-IMU motion telemetry: the device counts as
+	// stationary while no IMU reports moving, which stops dead reckoning
+	for _, topic := range []string{cfg.TopicMotionLeft, cfg.TopicMotionRight} {
+		if topic == "" {
+			continue
+		}
+		token := client.Subscribe(topic, 0, func(_ mqtt.Client, msg mqtt.Message) {
+			var m orientation.Motion
+			if err := json.Unmarshal(msg.Payload(), &m); err != nil {
+				logging.Warnf("fusion: motion unmarshal error: %v", err)
+				return
+			}
+			mu.Lock()
+			moving[msg.Topic()] = m.Moving
+			anyMoving := false
+			for _, mv := range moving {
+				anyMoving = anyMoving || mv
+			}
+			estimator.UpdateMotion(anyMoving, time.Now())
+			mu.Unlock()
+		})
+		if token.Wait(); token.Error() != nil {
+			return token.Error()
+		}
+		logging.Infof("fusion: subscribed to %s", topic)
+	}
+
+	// 5) Publish loop
+	qos := publishQoS(cfg.MQTTQoSFusion)
+	ticker := time.NewTicker(time.Duration(ms) * time.Millisecond)
+	defer ticker.Stop()
+
+	logging.Infof("fusion: publishing fused state to %s every %dms (dead reckoning up to %s)", cfg.TopicFusedState, ms, heading.MaxDRDuration)
+
+	for {
+		var t time.Time
+		select {
+		case <-ctx.Done():
+			return nil
+		case t = <-ticker.C:
+		}
+		mu.Lock()
+		state := estimator.State(t)
+		mu.Unlock()
+
+		payload, err := json.Marshal(state)
+		if err != nil {
+			logging.Errorf("fusion: state marshal error: %v", err)
+			continue
+		}
+		if token := client.Publish(cfg.TopicFusedState, qos, cfg.MQTTRetainFusion, payload); token.Wait() && token.Error() != nil {
+			logging.Errorf("fusion: MQTT publish error (%s): %v", cfg.TopicFusedState, token.Error())
+		}
+	}
+}
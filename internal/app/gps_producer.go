@@ -7,9 +7,14 @@ package app
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
-	"log"
+	"fmt"
+	"io"
+	"os"
 	"strings"
+	"sync"
+	"time"
 
 	nmea "github.com/adrianmo/go-nmea"
 	mqtt "github.com/eclipse/paho.mqtt.golang"
@@ -17,44 +22,119 @@ import (
 
 	"github.com/relabs-tech/inertial_computer/internal/config"
 	"github.com/relabs-tech/inertial_computer/internal/gps"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
 )
 
+// gsvAssembly accumulates satellites across a multi-sentence GSV sequence.
+type gsvAssembly struct {
+	sats    []gps.Satellite
+	next    int64 // expected next MessageNumber (0 = no sequence in progress)
+	partial bool  // a sentence of the current sequence was missed
+}
+
+// subscribeRTCM forwards every message on topic (raw RTCM bytes) to the GPS
+// receiver through port. Writes run on the MQTT callback goroutine,
+// concurrently with the NMEA reader, and are serialized by a mutex so
+// messages are never interleaved on the wire.
+func subscribeRTCM(client mqtt.Client, topic string, port io.Writer) error {
+	var mu sync.Mutex
+	token := client.Subscribe(topic, 0, func(_ mqtt.Client, msg mqtt.Message) {
+		mu.Lock()
+		defer mu.Unlock()
+		if _, err := port.Write(msg.Payload()); err != nil {
+			logging.Errorf("GPS RTCM write error: %v", err)
+		}
+	})
+	token.Wait()
+	if token.Error() != nil {
+		return token.Error()
+	}
+	logging.Infof("GPS forwarding RTCM corrections from %s", topic)
+	return nil
+}
+
 // RunGPSProducer opens the GPS serial port, parses NMEA sentences, and
-// publishes combined GPS fixes as JSON to MQTT.
-func RunGPSProducer() error {
+// publishes combined GPS fixes as JSON to MQTT. It returns when ctx is
+// cancelled; a serial port is closed at that point to unblock the read.
+func RunGPSProducer(ctx context.Context) error {
 	cfg := config.Get()
 
 	// ---- 1) Connect to MQTT broker ----
-	opts := mqtt.NewClientOptions().
-		AddBroker(cfg.MQTTBroker).
-		SetClientID(cfg.MQTTClientIDGPS)
-
-	client := mqtt.NewClient(opts)
-	if token := client.Connect(); token.Wait() && token.Error() != nil {
-		log.Fatalf("MQTT connect error: %v", token.Error())
-		return token.Error()
+	client, err := NewMQTTClient(cfg.MQTTClientIDGPS)
+	if err != nil {
+		logging.Fatalf("MQTT connect error: %v", err)
+		return err
 	}
-	log.Printf("GPS producer connected to MQTT broker at %s", cfg.MQTTBroker)
-
-	// ---- 2) Open GPS serial port ----
-	serialOpts := serial.OpenOptions{
-		PortName:              cfg.GPSSerialPort,
-		BaudRate:              uint(cfg.GPSBaudRate),
-		DataBits:              8,
-		StopBits:              1,
-		MinimumReadSize:       1,
-		ParityMode:            serial.PARITY_NONE,
-		InterCharacterTimeout: 0,
+	defer DisconnectMQTT(client)
+	logging.Infof("GPS producer connected to MQTT broker at %s", cfg.MQTTBroker)
+
+	// ---- 2) Open GPS serial port (or replay file) ----
+	var reader interface {
+		ReadString(delim byte) (string, error)
 	}
+	if cfg.MockHardware {
+		lat, lon := gps.MockLat, gps.MockLon
+		if len(cfg.GPSGeofences) > 0 {
+			// Drive through the first geofence so its events fire
+			lat, lon = cfg.GPSGeofences[0].Lat, cfg.GPSGeofences[0].Lon
+		}
+		reader = gps.NewMockReader(lat, lon)
+		logging.Infof("MOCK_HARDWARE: simulating GPS around %.6f,%.6f", lat, lon)
+		if cfg.TopicRTCMIn != "" {
+			logging.Warnf("GPS mock: not forwarding RTCM corrections from %s", cfg.TopicRTCMIn)
+		}
+	} else if path, ok := strings.CutPrefix(cfg.GPSSerialPort, "file:"); ok {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		reader = gps.NewReplayReader(f, cfg.GPSReplayLoop)
+		logging.Infof("GPS replaying NMEA from %s (loop=%t)", path, cfg.GPSReplayLoop)
+		if cfg.TopicRTCMIn != "" {
+			logging.Warnf("GPS replay: not forwarding RTCM corrections from %s", cfg.TopicRTCMIn)
+		}
+	} else {
+		serialOpts := serial.OpenOptions{
+			PortName:              cfg.GPSSerialPort,
+			BaudRate:              uint(cfg.GPSBaudRate),
+			DataBits:              8,
+			StopBits:              1,
+			MinimumReadSize:       1,
+			ParityMode:            serial.PARITY_NONE,
+			InterCharacterTimeout: 0,
+		}
 
-	port, err := serial.Open(serialOpts)
-	if err != nil {
-		return err
+		port, err := serial.Open(serialOpts)
+		if err != nil {
+			return err
+		}
+		defer port.Close()
+		// A read blocks until the receiver sends data, so cancellation closes
+		// the port; the read error that follows ends the loop cleanly.
+		stop := context.AfterFunc(ctx, func() { port.Close() })
+		defer stop()
+		logging.Infof("GPS serial port opened on %s at %d baud", serialOpts.PortName, serialOpts.BaudRate)
+
+		reader = bufio.NewReader(port)
+
+		if cfg.TopicRTCMIn != "" {
+			if err := subscribeRTCM(client, cfg.TopicRTCMIn, port); err != nil {
+				return err
+			}
+		}
 	}
-	defer port.Close()
-	log.Printf("GPS serial port opened on %s at %d baud", serialOpts.PortName, serialOpts.BaudRate)
 
-	reader := bufio.NewReader(port)
+	// Optional raw NMEA log
+	var nmeaLog *os.File
+	if cfg.GPSNMEALog != "" {
+		nmeaLog, err = os.OpenFile(cfg.GPSNMEALog, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return fmt.Errorf("open GPS_NMEA_LOG: %w", err)
+		}
+		defer nmeaLog.Close()
+		logging.Infof("GPS logging raw NMEA to %s", cfg.GPSNMEALog)
+	}
 
 	// Accumulate data from multiple NMEA sentence types.
 	// Publish to separate topics for different data categories.
@@ -68,27 +148,93 @@ func RunGPSProducer() error {
 
 	// GSV messages come in multiple parts - accumulate satellites across messages
 	// Separate buffers for GPS (GPGSV) and GLONASS (GLGSV)
-	var gpsSatelliteBuffer []gps.Satellite
-	var glonassSatelliteBuffer []gps.Satellite
+	var gpsGSV, glonassGSV gsvAssembly
+	var gpsPartial, glonassPartial bool
+
+	minUsedSNR := int64(cfg.GPSMinUsedSNR)
+
+	var geofences *gps.GeofenceMonitor
+	if len(cfg.GPSGeofences) > 0 {
+		fences := make([]gps.Geofence, len(cfg.GPSGeofences))
+		for i, g := range cfg.GPSGeofences {
+			fences[i] = gps.Geofence{Name: g.Name, Lat: g.Lat, Lon: g.Lon, RadiusM: g.RadiusM}
+		}
+		hysteresis := cfg.GPSGeofenceHysteresisM
+		if hysteresis <= 0 {
+			hysteresis = 10
+		}
+		geofences = gps.NewGeofenceMonitor(fences, hysteresis)
+		logging.Infof("GPS monitoring %d geofence(s), events on %s", len(fences), cfg.TopicGPSEvents)
+	}
+
+	filter := gps.NewSentenceFilter(cfg.GPSSentenceFilter)
+	if len(cfg.GPSSentenceFilter) > 0 {
+		logging.Infof("GPS parsing only %s", strings.Join(cfg.GPSSentenceFilter, ","))
+	}
+
+	qos := publishQoS(cfg.MQTTQoSGPS)
+	retain := cfg.MQTTRetainGPS
 
 	// Helper to publish to a topic
 	publishJSON := func(topic string, data interface{}) {
 		payload, err := json.Marshal(data)
 		if err != nil {
-			log.Printf("JSON marshal error for %s: %v", topic, err)
+			logging.Errorf("JSON marshal error for %s: %v", topic, err)
 			return
 		}
-		token := client.Publish(topic, 0, false, payload)
+		token := client.Publish(topic, qos, retain, payload)
 		token.Wait()
 		if token.Error() != nil {
-			log.Printf("Publish error to %s: %v", topic, token.Error())
+			logging.Errorf("Publish error to %s: %v", topic, token.Error())
 		}
 	}
 
-	for {
+	// Helper to publish a completed (or partial) GSV sequence and refresh
+	// the SNR summary in the quality topic.
+	publishSatellites := func(isGPS bool, sats []gps.Satellite, partial bool) {
+		satsOnly := struct {
+			Satellites []gps.Satellite `json:"satellites"`
+			Count      int             `json:"count"`
+			Partial    bool            `json:"partial"`
+		}{
+			Satellites: sats,
+			Count:      len(sats),
+			Partial:    partial,
+		}
+
+		if isGPS {
+			// Publish only GPS satellites (no GLONASS fields)
+			current.GPSSatellitesInView = sats
+			gpsPartial = partial
+			publishJSON(cfg.TopicGPSSatellites, satsOnly)
+			logging.Debugf("[GPS-SAT] GPS satellites: %d visible (partial=%t)", len(sats), partial)
+		} else {
+			// Publish only GLONASS satellites (no GPS fields)
+			current.GLONASSSatellitesInView = sats
+			glonassPartial = partial
+			publishJSON(cfg.TopicGLONASSSatellites, satsOnly)
+			logging.Debugf("[GPS-SAT] GLONASS satellites: %d visible (partial=%t)", len(sats), partial)
+		}
+
+		all := make([]gps.Satellite, 0, len(current.GPSSatellitesInView)+len(current.GLONASSSatellitesInView))
+		all = append(all, current.GPSSatellitesInView...)
+		all = append(all, current.GLONASSSatellitesInView...)
+		quality.AvgSNR, quality.MaxSNR, quality.NumUsed = gps.SummarizeSNR(all, minUsedSNR)
+		quality.SNRPartial = gpsPartial || glonassPartial
+		publishJSON(cfg.TopicGPSQuality, quality)
+	}
+
+	for ctx.Err() == nil {
 		line, err := reader.ReadString('\n')
-		if err != nil {
-			log.Printf("GPS read error: %v", err)
+		if err != nil && !(err == io.EOF && line != "") {
+			if ctx.Err() != nil {
+				break
+			}
+			if err == io.EOF {
+				logging.Infof("GPS replay finished")
+				return nil
+			}
+			logging.Errorf("GPS read error: %v", err)
 			return err // or continue if you prefer to keep trying
 		}
 
@@ -97,13 +243,22 @@ func RunGPSProducer() error {
 			continue
 		}
 
+		if nmeaLog != nil {
+			if _, err := fmt.Fprintln(nmeaLog, line); err != nil {
+				logging.Errorf("GPS NMEA log write error: %v", err)
+			}
+		}
+
 		// Log all raw data received
-		log.Printf("[GPS-RAW] %s", line)
+		logging.Debugf("[GPS-RAW] %s", line)
 
 		// NMEA sentences usually start with '$'
 		if !strings.HasPrefix(line, "$") {
 			continue
 		}
+		if !filter.Allows(line) {
+			continue
+		}
 
 		sentence, err := nmea.Parse(line)
 		if err != nil {
@@ -141,10 +296,22 @@ func RunGPSProducer() error {
 			publishJSON(cfg.TopicGPSPosition, position)
 			publishJSON(cfg.TopicGPSVelocity, velocity)
 
+			// Geofence events (events are not retained)
+			if geofences != nil && m.Validity == nmea.ValidRMC {
+				for _, ev := range geofences.Update(m.Latitude, m.Longitude, time.Now()) {
+					payload, err := json.Marshal(ev)
+					if err != nil {
+						continue
+					}
+					client.Publish(cfg.TopicGPSEvents, qos, false, payload).Wait()
+					logging.Infof("[GPS-EVENT] %s geofence %q (%.0fm from center)", ev.Event, ev.Name, ev.DistanceM)
+				}
+			}
+
 			// Publish full fix to legacy topic (for backwards compatibility)
 			payloadFull, err := json.Marshal(current)
 			if err != nil {
-				log.Printf("GPS JSON marshal error: %v", err)
+				logging.Errorf("GPS JSON marshal error: %v", err)
 				continue
 			}
 
@@ -152,7 +319,7 @@ func RunGPSProducer() error {
 			if payloadStr != lastPublishedFull {
 				publishJSON(cfg.TopicGPS, current)
 				totalSats := len(current.GPSSatellitesInView) + len(current.GLONASSSatellitesInView)
-				log.Printf("published GPS: lat=%.6f lon=%.6f alt=%.1fm sats=%d/%d fix=%s",
+				logging.Debugf("published GPS: lat=%.6f lon=%.6f alt=%.1fm sats=%d/%d fix=%s",
 					current.Latitude, current.Longitude, current.Altitude,
 					current.NumSatellites, totalSats, current.FixType)
 				lastPublishedFull = payloadStr
@@ -238,9 +405,10 @@ func RunGPSProducer() error {
 			// GSV: GPS Satellites in View - provides satellite info with signal strength
 			m := sentence.(nmea.GSV)
 
-			// Determine constellation type from the raw sentence (GPGSV vs GLGSV)
-			isGPS := strings.HasPrefix(line, "$GPGSV")
-			isGLONASS := strings.HasPrefix(line, "$GLGSV")
+			// Determine constellation type from the talker ID (GPGSV vs GLGSV)
+			talker, _ := gps.SentenceAddress(line)
+			isGPS := talker == "GP"
+			isGLONASS := talker == "GL"
 
 			if !isGPS && !isGLONASS {
 				// Skip other constellations for now (GAGSV, GBGSV, etc.)
@@ -249,64 +417,46 @@ func RunGPSProducer() error {
 
 			// GSV messages can span multiple sentences (1 of 3, 2 of 3, etc.)
 			// MessageNumber and TotalMessages tell us which part we're on
+			asm := &gpsGSV
+			if isGLONASS {
+				asm = &glonassGSV
+			}
 
-			// If this is the first message in the sequence, reset the buffer
 			if m.MessageNumber == 1 {
-				if isGPS {
-					gpsSatelliteBuffer = make([]gps.Satellite, 0)
-				} else if isGLONASS {
-					glonassSatelliteBuffer = make([]gps.Satellite, 0)
+				// A new sequence starts. If the previous one never reached its
+				// last message (dropped sentence), publish what was collected.
+				if len(asm.sats) > 0 {
+					publishSatellites(isGPS, asm.sats, true)
 				}
+				asm.sats = make([]gps.Satellite, 0)
+				asm.partial = false
+			} else if m.MessageNumber != asm.next {
+				// Gap inside the sequence (or we joined mid-sequence)
+				asm.partial = true
 			}
+			asm.next = m.MessageNumber + 1
 
 			// Add satellites from this GSV message to the appropriate buffer
 			for _, sv := range m.Info {
-				sat := gps.Satellite{
+				asm.sats = append(asm.sats, gps.Satellite{
 					SVNumber:  sv.SVPRNNumber,
 					Elevation: sv.Elevation,
 					Azimuth:   sv.Azimuth,
 					SNR:       sv.SNR,
-				}
-				if isGPS {
-					gpsSatelliteBuffer = append(gpsSatelliteBuffer, sat)
-				} else if isGLONASS {
-					glonassSatelliteBuffer = append(glonassSatelliteBuffer, sat)
-				}
+				})
 			}
 
 			// If this is the last message in the sequence, publish satellites
 			if m.MessageNumber == m.TotalMessages {
-				if isGPS {
-					// Publish only GPS satellites (no GLONASS fields)
-					gpsOnly := struct {
-						Satellites []gps.Satellite `json:"satellites"`
-						Count      int             `json:"count"`
-					}{
-						Satellites: gpsSatelliteBuffer,
-						Count:      len(gpsSatelliteBuffer),
-					}
-					current.GPSSatellitesInView = gpsSatelliteBuffer
-
-					publishJSON(cfg.TopicGPSSatellites, gpsOnly)
-					log.Printf("[GPS-SAT] GPS satellites: %d visible", len(gpsSatelliteBuffer))
-				} else if isGLONASS {
-					// Publish only GLONASS satellites (no GPS fields)
-					glonassOnly := struct {
-						Satellites []gps.Satellite `json:"satellites"`
-						Count      int             `json:"count"`
-					}{
-						Satellites: glonassSatelliteBuffer,
-						Count:      len(glonassSatelliteBuffer),
-					}
-					current.GLONASSSatellitesInView = glonassSatelliteBuffer
-
-					publishJSON(cfg.TopicGLONASSSatellites, glonassOnly)
-					log.Printf("[GPS-SAT] GLONASS satellites: %d visible", len(glonassSatelliteBuffer))
-				}
+				publishSatellites(isGPS, asm.sats, asm.partial)
+				asm.sats = nil
+				asm.next = 0
 			}
 
 		default:
 			// Ignore other sentence types (GLL, etc.)
 		}
 	}
+	logging.Infof("GPS producer shutting down")
+	return nil
 }
@@ -0,0 +1,407 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/relabs-tech/inertial_computer/internal/config"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
+	"github.com/relabs-tech/inertial_computer/internal/sensors"
+)
+
+const (
+	defaultHealthStaleTimeout    = 5 * time.Second
+	defaultHealthPublishInterval = time.Second
+	defaultHealthHTTPPort        = 8081
+	defaultHealthClientID        = "inertial-health-monitor"
+
+	// Rollup status values, from best to worst.
+	healthOK       = "ok"       // every stream seen so far is live and no client is offline
+	healthDegraded = "degraded" // some streams are stale or a client is offline
+	healthDown     = "down"     // no stream is live
+)
+
+// StreamHealth is the health of one data topic.
+type StreamHealth struct {
+	Topic      string  `json:"topic"`
+	Seen       bool    `json:"seen"`                 // at least one message since the monitor started
+	Stale      bool    `json:"stale"`                // seen, but nothing within HEALTH_STALE_TIMEOUT
+	Messages   uint64  `json:"messages"`             // total since the monitor started
+	RatePerSec float64 `json:"rate_per_sec"`         // over the last rollup interval
+	LastSeen   string  `json:"last_seen,omitempty"`  // RFC3339
+	AgeMs      int64   `json:"age_ms,omitempty"`     // time since the last message
+	LastError  string  `json:"last_error,omitempty"` // e.g. a payload that is not JSON
+}
+
+// SystemHealth is the rollup published to TOPIC_SYSTEM_HEALTH and served on
+// /api/health.
+type SystemHealth struct {
+	Time    string                  `json:"time"`   // RFC3339
+	Status  string                  `json:"status"` // "ok", "degraded" or "down"
+	Streams map[string]StreamHealth `json:"streams"`
+	Clients map[string]clientStatus `json:"clients"`
+	IMU     *sensors.IMUMetrics     `json:"imu,omitempty"` // latest TOPIC_IMU_HEALTH message
+	Battery *BatteryReading         `json:"battery,omitempty"`
+}
+
+// BatteryReading is a supply voltage reading (BATTERY_SOURCE), published to
+// TOPIC_BATTERY and included in the rollup.
+type BatteryReading struct {
+	Time    string  `json:"time"`                // RFC3339
+	Source  string  `json:"source"`              // e.g. "ina219@0x40"
+	Voltage float64 `json:"voltage_v,omitempty"` // volts
+	Low     bool    `json:"low,omitempty"`       // below BATTERY_LOW_VOLTAGE
+	Error   string  `json:"error,omitempty"`     // read failure; no voltage
+}
+
+// streamTracker accumulates message counts for one topic between rollups.
+type streamTracker struct {
+	topic     string
+	messages  uint64
+	lastCount uint64 // messages at the previous rollup
+	lastSeen  time.Time
+	lastError string
+}
+
+// RunHealthMonitor subscribes to the client status topics and every
+// configured data topic, publishes a SystemHealth rollup to
+// TOPIC_SYSTEM_HEALTH every HEALTH_PUBLISH_INTERVAL and serves the latest one
+// on GET /api/health.
+//
+// Streams that have never been seen (e.g. an unused HMC5983) are reported but
+// do not degrade the rollup; a stream goes stale once it has been seen and then
+// stays quiet for HEALTH_STALE_TIMEOUT.
+func RunHealthMonitor(ctx context.Context) error {
+	logging.Infof("starting inertial-computer health monitor")
+
+	cfg := config.Get()
+
+	staleTimeout := time.Duration(cfg.HealthStaleTimeout) * time.Second
+	if staleTimeout <= 0 {
+		staleTimeout = defaultHealthStaleTimeout
+	}
+	interval := time.Duration(cfg.HealthPublishInterval) * time.Millisecond
+	if interval <= 0 {
+		interval = defaultHealthPublishInterval
+	}
+	port := cfg.HealthHTTPPort
+	if port == 0 {
+		port = defaultHealthHTTPPort
+	}
+	clientID := cfg.MQTTClientIDHealth
+	if clientID == "" {
+		clientID = defaultHealthClientID
+	}
+
+	var (
+		mu             sync.Mutex
+		streams        = make(map[string]*streamTracker)
+		clientStatuses = make(map[string]clientStatus)
+		lastIMUHealth  *sensors.IMUMetrics
+		latest         SystemHealth
+		haveLatest     bool
+	)
+
+	// 1) Connect to MQTT
+	client, err := NewMQTTClient(clientID)
+	if err != nil {
+		return err
+	}
+	defer DisconnectMQTT(client)
+	logging.Infof("health: connected to MQTT broker at %s", cfg.MQTTBroker)
+
+	// 2) Subscribe to client status topics
+	statusPrefix := statusTopic("")
+	statusToken := client.Subscribe(statusPrefix+"+", 0, func(_ mqtt.Client, msg mqtt.Message) {
+		id := strings.TrimPrefix(msg.Topic(), statusPrefix)
+		mu.Lock()
+		clientStatuses[id] = clientStatus{
+			Status:     string(msg.Payload()),
+			ReceivedAt: time.Now().Format(time.RFC3339),
+		}
+		mu.Unlock()
+	})
+	statusToken.Wait()
+	if statusToken.Error() != nil {
+		return statusToken.Error()
+	}
+	logging.Infof("health: subscribed to %s+", statusPrefix)
+
+	// 3) Subscribe to the data topics
+	watched := []struct{ name, topic string }{
+		{"imu_left", cfg.TopicIMULeft},
+		{"imu_right", cfg.TopicIMURight},
+		{"mag_left", cfg.TopicMagLeft},
+		{"mag_right", cfg.TopicMagRight},
+		{"pose_left", cfg.TopicPoseLeft},
+		{"pose_right", cfg.TopicPoseRight},
+		{"pose_fused", cfg.TopicPoseFused},
+		{"bmp_left", cfg.TopicBMPLeft},
+		{"bmp_right", cfg.TopicBMPRight},
+		{"bmp_diff", cfg.TopicBMPDiff},
+		{"vario_left", cfg.TopicVarioLeft},
+		{"vario_right", cfg.TopicVarioRight},
+		{"gps", cfg.TopicGPS},
+		{"gps_position", cfg.TopicGPSPosition},
+		{"mag_hmc", cfg.TopicMagHMC},
+		{"fused_state", cfg.TopicFusedState},
+	}
+	for _, w := range watched {
+		if w.topic == "" {
+			continue
+		}
+		tracker := &streamTracker{topic: w.topic}
+		streams[w.name] = tracker
+
+		token := client.Subscribe(w.topic, 0, func(_ mqtt.Client, msg mqtt.Message) {
+			// A retained message is the broker's copy of an old publish, not
+			// a sign that the producer is alive
+			if msg.Retained() {
+				return
+			}
+			valid := json.Valid(msg.Payload())
+			mu.Lock()
+			tracker.messages++
+			tracker.lastSeen = time.Now()
+			if valid {
+				tracker.lastError = ""
+			} else {
+				tracker.lastError = "invalid JSON payload"
+			}
+			mu.Unlock()
+		})
+		token.Wait()
+		if token.Error() != nil {
+			return token.Error()
+		}
+		logging.Infof("health: subscribed to %s", w.topic)
+	}
+
+	// 4) Subscribe to IMU read-rate/error metrics
+	if cfg.TopicIMUHealth != "" {
+		imuHealthToken := client.Subscribe(cfg.TopicIMUHealth, 0, func(_ mqtt.Client, msg mqtt.Message) {
+			var h sensors.IMUMetrics
+			if err := json.Unmarshal(msg.Payload(), &h); err != nil {
+				logging.Warnf("health: imu health unmarshal error: %v", err)
+				return
+			}
+			mu.Lock()
+			lastIMUHealth = &h
+			mu.Unlock()
+		})
+		imuHealthToken.Wait()
+		if imuHealthToken.Error() != nil {
+			return imuHealthToken.Error()
+		}
+		logging.Infof("health: subscribed to %s", cfg.TopicIMUHealth)
+	}
+
+	// 5) Optional supply/battery voltage; a missing source is not fatal
+	battery, err := sensors.NewVoltageSource()
+	switch {
+	case err != nil:
+		logging.Warnf("health: supply voltage unavailable: %v", err)
+	case battery != nil:
+		logging.Infof("health: reading supply voltage from %s", battery.Name())
+		if c, ok := battery.(io.Closer); ok {
+			defer c.Close()
+		}
+	}
+
+	// 6) HTTP endpoint
+	http.HandleFunc("/api/health", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !haveLatest {
+			http.Error(w, "no health rollup yet", http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(latest); err != nil {
+			logging.Errorf("health: JSON encode error: %v", err)
+		}
+	})
+
+	addr := net.JoinHostPort(cfg.WebBindAddr, strconv.Itoa(port))
+	go func() {
+		logging.Infof("health: listening on %s", addr)
+		if err := serveHTTP(ctx, addr, http.DefaultServeMux); err != nil {
+			logging.Errorf("health: HTTP server error: %v", err)
+		}
+	}()
+
+	// 7) Rollup loop
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	if cfg.TopicSystemHealth != "" {
+		logging.Infof("health: publishing rollup to %s every %v", cfg.TopicSystemHealth, interval)
+	}
+
+	prevStatus := ""
+	var prevBattery BatteryReading
+	prevTick := time.Now()
+	for {
+		var now time.Time
+		select {
+		case <-ctx.Done():
+			return nil
+		case now = <-ticker.C:
+		}
+		elapsed := now.Sub(prevTick).Seconds()
+		prevTick = now
+
+		// Read outside the lock: an I2C transaction can be slow
+		var reading *BatteryReading
+		if battery != nil {
+			reading = readBattery(now, battery, cfg.BatteryLowVoltage)
+			logBatteryChange(prevBattery, *reading)
+			prevBattery = *reading
+		}
+
+		mu.Lock()
+		health := rollupHealth(now, elapsed, staleTimeout, streams, clientStatuses, lastIMUHealth)
+		health.Battery = reading
+		latest = health
+		haveLatest = true
+		mu.Unlock()
+
+		if health.Status != prevStatus {
+			logging.Infof("health: status %s", health.Status)
+			prevStatus = health.Status
+		}
+
+		if reading != nil && reading.Error == "" && cfg.TopicBattery != "" {
+			if payload, err := json.Marshal(reading); err != nil {
+				logging.Errorf("health: battery marshal error: %v", err)
+			} else if token := client.Publish(cfg.TopicBattery, 0, true, payload); token.Wait() && token.Error() != nil {
+				logging.Errorf("health: MQTT publish error (%s): %v", cfg.TopicBattery, token.Error())
+			}
+		}
+
+		if cfg.TopicSystemHealth == "" {
+			continue
+		}
+		payload, err := json.Marshal(health)
+		if err != nil {
+			logging.Errorf("health: rollup marshal error: %v", err)
+			continue
+		}
+		if token := client.Publish(cfg.TopicSystemHealth, 1, true, payload); token.Wait() && token.Error() != nil {
+			logging.Errorf("health: MQTT publish error (%s): %v", cfg.TopicSystemHealth, token.Error())
+		}
+	}
+}
+
+// readBattery reads the supply voltage, flagging it low below lowVoltage
+// (0 = never).
+func readBattery(now time.Time, src sensors.VoltageSource, lowVoltage float64) *BatteryReading {
+	r := &BatteryReading{Time: now.Format(time.RFC3339), Source: src.Name()}
+	v, err := src.ReadVoltage()
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	r.Voltage = math.Round(v*1000) / 1000
+	r.Low = lowVoltage > 0 && v < lowVoltage
+	return r
+}
+
+// logBatteryChange logs read errors and low voltage when they start or stop,
+// not on every reading.
+func logBatteryChange(prev, cur BatteryReading) {
+	switch {
+	case cur.Error != "" && cur.Error != prev.Error:
+		logging.Warnf("health: supply voltage read error (%s): %s", cur.Source, cur.Error)
+	case cur.Error == "" && prev.Error != "":
+		logging.Infof("health: supply voltage readable again (%s): %.3f V", cur.Source, cur.Voltage)
+	}
+	switch {
+	case cur.Low && !prev.Low:
+		logging.Warnf("health: supply voltage low: %.3f V", cur.Voltage)
+	case !cur.Low && prev.Low && cur.Error == "":
+		logging.Infof("health: supply voltage recovered: %.3f V", cur.Voltage)
+	}
+}
+
+// rollupHealth builds a SystemHealth snapshot and resets the per-interval
+// counters. The caller must hold the lock guarding streams and clients.
+func rollupHealth(now time.Time, elapsed float64, staleTimeout time.Duration,
+	streams map[string]*streamTracker, clients map[string]clientStatus, imu *sensors.IMUMetrics) SystemHealth {
+
+	health := SystemHealth{
+		Time:    now.Format(time.RFC3339),
+		Streams: make(map[string]StreamHealth, len(streams)),
+		Clients: make(map[string]clientStatus, len(clients)),
+		IMU:     imu,
+	}
+
+	names := make([]string, 0, len(streams))
+	for name := range streams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	live, stale := 0, 0
+	for _, name := range names {
+		t := streams[name]
+		sh := StreamHealth{
+			Topic:     t.topic,
+			Seen:      !t.lastSeen.IsZero(),
+			Messages:  t.messages,
+			LastError: t.lastError,
+		}
+		if elapsed > 0 {
+			sh.RatePerSec = float64(t.messages-t.lastCount) / elapsed
+		}
+		t.lastCount = t.messages
+
+		if sh.Seen {
+			age := now.Sub(t.lastSeen)
+			sh.LastSeen = t.lastSeen.Format(time.RFC3339)
+			sh.AgeMs = age.Milliseconds()
+			sh.Stale = age > staleTimeout
+			if sh.Stale {
+				stale++
+			} else {
+				live++
+			}
+		}
+		health.Streams[name] = sh
+	}
+
+	offline := 0
+	for id, cs := range clients {
+		health.Clients[id] = cs
+		if cs.Status == statusOffline {
+			offline++
+		}
+	}
+
+	switch {
+	case live == 0:
+		health.Status = healthDown
+	case stale > 0 || offline > 0:
+		health.Status = healthDegraded
+	default:
+		health.Status = healthOK
+	}
+	return health
+}
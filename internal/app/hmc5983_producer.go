@@ -5,12 +5,14 @@
 package app
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
 
-	mqtt "github.com/eclipse/paho.mqtt.golang"
 	"github.com/relabs-tech/inertial_computer/internal/config"
+	imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
 	"periph.io/x/conn/v3/i2c/i2creg"
 	"periph.io/x/devices/v3/hmc5983"
 	"periph.io/x/host/v3"
@@ -28,17 +30,19 @@ type hmcPayload struct {
 	Time string  `json:"time"`
 }
 
-func RunHMC5983Producer() {
+// RunHMC5983Producer publishes the external HMC5983 magnetometer to
+// TOPIC_MAG_HMC until ctx is cancelled.
+func RunHMC5983Producer(ctx context.Context) {
 	// Load config.
 	if err := config.InitGlobal("./inertial_config.txt"); err != nil {
-		fmt.Printf("hmc: config init failed: %v\n", err)
+		logging.Errorf("hmc: config init failed: %v", err)
 		return
 	}
 	cfg := config.Get()
 
 	// Initialize periph host.
 	if _, err := host.Init(); err != nil {
-		fmt.Printf("hmc: periph host init failed: %v\n", err)
+		logging.Errorf("hmc: periph host init failed: %v", err)
 		return
 	}
 
@@ -49,7 +53,7 @@ func RunHMC5983Producer() {
 	}
 	bus, err := i2creg.Open(busName)
 	if err != nil {
-		fmt.Printf("hmc: i2c open failed on bus %s: %v\n", busName, err)
+		logging.Errorf("hmc: i2c open failed on bus %s: %v", busName, err)
 		return
 	}
 	defer bus.Close()
@@ -75,26 +79,27 @@ func RunHMC5983Producer() {
 	// Create device.
 	dev, err := hmc5983.New(bus, hmc5983.Opts{Addr: addr, ODRHz: odr, AvgSamples: avg, GainCode: gain, Mode: mode})
 	if err != nil {
-		fmt.Printf("hmc: init failed: %v\n", err)
+		logging.Errorf("hmc: init failed: %v", err)
 		return
 	}
 	ida, idb, idc, _ := dev.ID()
-	fmt.Printf("[HMC] ID=%q %q %q (addr=0x%X)\n", ida, idb, idc, addr)
+	logging.Infof("hmc: ID=%q %q %q (addr=0x%X)", ida, idb, idc, addr)
 
 	// MQTT client.
 	clientID := cfg.MQTTClientIDHMC
 	if clientID == "" {
 		clientID = "inertial-hmc-producer"
 	}
-	opts := mqtt.NewClientOptions().AddBroker(cfg.MQTTBroker).SetClientID(clientID)
-	client := mqtt.NewClient(opts)
-	if token := client.Connect(); token.Wait() && token.Error() != nil {
-		fmt.Printf("hmc: mqtt connect error: %v\n", token.Error())
+	client, err := NewMQTTClient(clientID)
+	if err != nil {
+		logging.Errorf("hmc: mqtt connect error: %v", err)
 		return
 	}
-	defer client.Disconnect(250)
+	defer DisconnectMQTT(client)
 
 	topic := cfg.TopicMagHMC
+	qos := publishQoS(cfg.MQTTQoSHMC)
+	retain := cfg.MQTTRetainHMC
 	if topic == "" {
 		topic = "inertial/mag/hmc"
 	}
@@ -105,26 +110,41 @@ func RunHMC5983Producer() {
 	}
 	interval := time.Duration(ms) * time.Millisecond
 	// Start loop.
-	fmt.Println("hmc: producer started")
+	logging.Infof("hmc: producer started, publishing to %s every %v", topic, interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	failing := false // log read errors when they start, not on every tick
 	for {
+		select {
+		case <-ctx.Done():
+			logging.Infof("hmc: producer stopped")
+			return
+		case <-ticker.C:
+		}
 		x, y, z, err := dev.Sense()
 		if err != nil {
-			fmt.Printf("hmc: read error: %v\n", err)
-			time.Sleep(interval)
+			if !failing {
+				logging.Errorf("hmc: read error: %v", err)
+			} else {
+				logging.Debugf("hmc: read error: %v", err)
+			}
+			failing = true
 			continue
 		}
+		if failing {
+			logging.Infof("hmc: reads recovered")
+			failing = false
+		}
 		// Compute magnitude in µT (float).
-		mx := float64(x) / 10.0
-		my := float64(y) / 10.0
-		mz := float64(z) / 10.0
+		mx := imu_raw.MagUT(x)
+		my := imu_raw.MagUT(y)
+		mz := imu_raw.MagUT(z)
 		norm := (mx*mx + my*my + mz*mz)
 		norm = sqrt(norm)
 		payload := hmcPayload{Mx: x, My: y, Mz: z, Norm: norm, Time: time.Now().UTC().Format(time.RFC3339)}
 		b, _ := json.Marshal(payload)
-		t := client.Publish(topic, 0, false, b)
+		t := client.Publish(topic, qos, retain, b)
 		t.Wait()
-		// brief sleep
-		time.Sleep(interval)
 	}
 }
 
@@ -0,0 +1,47 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package app
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+// httpShutdownTimeout bounds how long serveHTTP waits for open requests
+// (e.g. WebSockets) on shutdown before closing them.
+const httpShutdownTimeout = 5 * time.Second
+
+// serveHTTP serves handler on addr until ctx is cancelled, then shuts the
+// server down. Request contexts derive from ctx, so streaming handlers that
+// watch r.Context() end with it.
+func serveHTTP(ctx context.Context, addr string, handler http.Handler) error {
+	srv := &http.Server{
+		Addr:        addr,
+		Handler:     handler,
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.ListenAndServe() }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		srv.Close()
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -6,82 +6,268 @@
 package app
 
 import (
+	"context"
 	"encoding/json"
-	"log"
 	"math"
 	"time"
 
-	mqtt "github.com/eclipse/paho.mqtt.golang"
 	"github.com/relabs-tech/inertial_computer/internal/config"
+	"github.com/relabs-tech/inertial_computer/internal/env"
 	imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
 	"github.com/relabs-tech/inertial_computer/internal/orientation"
 	"github.com/relabs-tech/inertial_computer/internal/sensors"
 )
 
-// magNorm computes the magnitude of the magnetic field vector.
-// This is TEST/DEBUG code to validate magnetometer behavior end-to-end.
-func magNorm(mx, my, mz int16) float64 {
-	x := float64(mx)
-	y := float64(my)
-	z := float64(mz)
-	return math.Sqrt(x*x + y*y + z*z)
-}
-
-func RunInertialProducer() error {
-	log.Println("starting inertial-computer orientation/env producer")
+// RunInertialProducer reads both IMUs and BMPs every IMU_SAMPLE_INTERVAL and
+// publishes raw, environment and pose data until ctx is cancelled.
+func RunInertialProducer(ctx context.Context) error {
+	logging.Infof("starting inertial-computer orientation/env producer")
 
 	cfg := config.Get()
 
 	// --- Initialize IMU manager (both left and right) ---
 	imuManager := sensors.GetIMUManager()
 	if err := imuManager.Init(); err != nil {
-		log.Fatalf("failed to initialize IMU manager: %v", err)
+		logging.Fatalf("failed to initialize IMU manager: %v", err)
 		return err
 	}
 
+	// --- Wake-on-motion: stay in low-power mode until moved ---
+	if cfg.IMUWakeOnMotion {
+		logging.Infof("wake-on-motion: waiting for motion before streaming")
+		imuID, err := imuManager.SleepUntilMotion(ctx, cfg.IMUWOMThresholdMg, cfg.IMUWOMODRHz)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			logging.Warnf("wake-on-motion unavailable, streaming now: %v", err)
+		default:
+			logging.Infof("wake-on-motion: %s IMU moved, starting", imuID)
+		}
+	}
+
 	// --- Choose orientation source (mock vs real IMU) ---
 	useMock := false
 	var mockSrc orientation.Source
 
 	if useMock {
-		log.Println("using mock orientation source")
+		logging.Infof("using mock orientation source")
 		mockSrc = orientation.NewMockSource()
 	} else {
-		if imuManager.IsLeftIMUAvailable() {
-			log.Println("using left IMU for orientation")
-		} else {
-			log.Println("WARNING: left IMU not available, orientation may be unreliable")
+		switch {
+		case imuManager.IsLeftIMUAvailable():
+			logging.Infof("using left IMU for orientation")
+		case !cfg.IMULeftEnabled && imuManager.IsRightIMUAvailable():
+			logging.Infof("left IMU disabled, using right IMU for orientation")
+		default:
+			logging.Warnf("left IMU not available, orientation may be unreliable")
 		}
 	}
 
 	// --- connect to MQTT ---
-	opts := mqtt.NewClientOptions().
-		AddBroker(cfg.MQTTBroker).
-		SetClientID(cfg.MQTTClientIDProducer)
-
-	client := mqtt.NewClient(opts)
-	if token := client.Connect(); token.Wait() && token.Error() != nil {
-		log.Fatalf("MQTT connect error: %v", token.Error())
-		return token.Error()
+	client, err := NewMQTTClient(cfg.MQTTClientIDProducer)
+	if err != nil {
+		logging.Fatalf("MQTT connect error: %v", err)
+		return err
+	}
+	defer DisconnectMQTT(client)
+
+	logging.Infof("connected to MQTT, starting publish loop")
+
+	qos := publishQoS(cfg.MQTTQoSIMU)
+	retain := cfg.MQTTRetainIMU
+
+	// Orientation filter per IMU (ORIENTATION_ALGORITHM, falling back to
+	// ATTITUDE_MODE: gyro_full = complementary, otherwise tilt)
+	algorithm := cfg.OrientationAlgorithm
+	if algorithm == "" {
+		algorithm = orientation.AlgorithmTilt
+		if cfg.AttitudeMode == "gyro_full" {
+			algorithm = orientation.AlgorithmComplementary
+		}
+	}
+	filterOpts := orientation.FilterOptions{
+		ComplementaryAlpha: cfg.ComplementaryAlpha,
+		MadgwickBeta:       cfg.MadgwickBeta,
+		MahonyKp:           cfg.MahonyKp,
+		MahonyKi:           cfg.MahonyKi,
+	}
+	// The EKF takes raw samples (it also uses the magnetometer), the other
+	// algorithms are orientation.Filters
+	var orientLeft, orientRight orientation.Filter
+	var ekfLeft, ekfRight *orientation.AttitudeEKF
+	if algorithm == orientation.AlgorithmEKF {
+		ekfCfg := orientation.EKFConfig{
+			AccelRange:    cfg.IMUAccelRange,
+			GyroRange:     cfg.IMUGyroRange,
+			GyroNoise:     cfg.EKFGyroNoise,
+			GyroBiasNoise: cfg.EKFGyroBiasNoise,
+			AccelNoise:    cfg.EKFAccelNoise,
+			MagNoise:      cfg.EKFMagNoise,
+		}
+		ekfLeft, ekfRight = orientation.NewAttitudeEKF(ekfCfg), orientation.NewAttitudeEKF(ekfCfg)
+	} else {
+		orientLeft, err = orientation.NewFilter(algorithm, filterOpts)
+		if err != nil {
+			return err
+		}
+		orientRight, _ = orientation.NewFilter(algorithm, filterOpts)
 	}
-	defer client.Disconnect(250)
+	logging.Infof("orientation algorithm: %s", algorithm)
 
-	log.Println("connected to MQTT, starting publish loop")
+	// Level reference per IMU (TOPIC_POSE_REFERENCE, nil = off)
+	var refs *poseReferences
+	if cfg.TopicPoseReference != "" {
+		if refs, err = subscribePoseReferences(client, cfg.TopicPoseReference); err != nil {
+			return err
+		}
+	}
 
-	// Track previous pose and time for gyro integration
-	var prevPose orientation.Pose
+	// Runtime calibration commands from the web server (TOPIC_CALIBRATION)
+	if cfg.TopicCalibration != "" {
+		if err := subscribeCalibrationCommands(client, cfg.TopicCalibration); err != nil {
+			return err
+		}
+	}
+
+	// Per-topic sequence numbers, so consumers can count lost messages
+	var seqIMULeft, seqIMURight, seqPoseLeft, seqPoseRight, seqPoseFused uint64
+	nextSeq := func(counter *uint64) uint64 {
+		if !cfg.MQTTSequenceNumbers {
+			return 0
+		}
+		*counter++
+		return *counter
+	}
+
+	// Track time for gyro integration
 	var lastTickTime time.Time
+	computePose := func(s imu_raw.IMURaw, bias *orientation.GyroBiasEstimator, filter orientation.Filter, ekf *orientation.AttitudeEKF, deltaTime float64) orientation.Pose {
+		// The EKF estimates the gyro bias itself and gets the sample as is
+		if ekf != nil {
+			p := ekf.Update(s, deltaTime)
+			p.Timestamp = s.Timestamp
+			return p
+		}
+		// Remove the runtime gyro bias estimate (if tracking), then gyro
+		// counts -> °/s for the configured full-scale range; accel tilt is
+		// scale-invariant and stays in counts.
+		gxc, gyc, gzc := float64(s.Gx), float64(s.Gy), float64(s.Gz)
+		if bias != nil {
+			gxc, gyc, gzc = bias.Correct(s)
+		}
+		gx := orientation.GyroCountsToDegPerSec(gxc, cfg.IMUGyroRange)
+		gy := orientation.GyroCountsToDegPerSec(gyc, cfg.IMUGyroRange)
+		gz := orientation.GyroCountsToDegPerSec(gzc, cfg.IMUGyroRange)
+		p := filter.Update(float64(s.Ax), float64(s.Ay), float64(s.Az), gx, gy, gz, deltaTime)
+		p.Timestamp = s.Timestamp
+		return p
+	}
+
+	// Barometric variometers, one per BMP
+	varioTau := time.Duration(cfg.VarioTimeConstant) * time.Millisecond
+	varioLeft := env.NewVarioCalculator(varioTau)
+	varioRight := env.NewVarioCalculator(varioTau)
+	publishVario := func(calc *env.VarioCalculator, s env.Sample, topic string, t time.Time) {
+		vs, ok := calc.Update(s, t)
+		if !ok || topic == "" {
+			return
+		}
+		v := env.Vario{
+			Source:        s.Source,
+			VerticalSpeed: vs,
+			PressurePa:    calc.Pressure(),
+			Time:          t.Format(time.RFC3339),
+		}
+		if payload, err := json.Marshal(v); err != nil {
+			logging.Errorf("%s vario marshal error: %v", s.Source, err)
+		} else if token := client.Publish(topic, qos, retain, payload); token.Wait() && token.Error() != nil {
+			logging.Errorf("MQTT publish error (%s): %v", topic, token.Error())
+		}
+	}
+
+	// Runtime gyro bias tracking, one estimator per IMU (nil = off)
+	var biasLeft, biasRight *orientation.GyroBiasEstimator
+	if cfg.GyroBiasTracking {
+		accelThresh, gyroThresh := orientation.DefaultZUPTThresholds(cfg.IMUAccelRange, cfg.IMUGyroRange)
+		newBias := func() *orientation.GyroBiasEstimator {
+			zupt := orientation.NewZUPTDetector(orientation.DefaultZUPTWindow, accelThresh, gyroThresh)
+			return orientation.NewGyroBiasEstimator(zupt, gyroThresh, cfg.GyroBiasLearningRate)
+		}
+		biasLeft, biasRight = newBias(), newBias()
+		logging.Infof("gyro bias tracking enabled")
+	}
+
+	// Motion telemetry per IMU (turn rate, accel magnitude, moving)
+	motionLeft := orientation.NewMotionTracker(cfg.IMUAccelRange, cfg.IMUGyroRange)
+	motionRight := orientation.NewMotionTracker(cfg.IMUAccelRange, cfg.IMUGyroRange)
+	updateMotion := func(tracker *orientation.MotionTracker, bias *orientation.GyroBiasEstimator, s imu_raw.IMURaw, topic string, publish bool) {
+		gz := float64(s.Gz)
+		if bias != nil {
+			_, _, gz = bias.Correct(s)
+		}
+		// Tracked every sample (the ZUPT window counts samples), published
+		// with the raw topics
+		m := tracker.Update(s, gz)
+		if !publish || topic == "" {
+			return
+		}
+		if payload, err := json.Marshal(m); err != nil {
+			logging.Errorf("%s motion marshal error: %v", s.Source, err)
+		} else if token := client.Publish(topic, qos, retain, payload); token.Wait() && token.Error() != nil {
+			logging.Errorf("MQTT publish error (%s): %v", topic, token.Error())
+		}
+	}
+
+	// Optional raw accel/gyro smoothing, one filter per IMU (nil = off)
+	filterWindow := cfg.IMUFilterWindow
+	if filterWindow <= 0 {
+		filterWindow = imu_raw.DefaultFilterWindow
+	}
+	filterLeft, err := imu_raw.NewFilter(cfg.IMUFilter, filterWindow)
+	if err != nil {
+		return err
+	}
+	filterRight, _ := imu_raw.NewFilter(cfg.IMUFilter, filterWindow)
+	if filterLeft != nil {
+		logging.Infof("IMU filter: %s over %d samples", cfg.IMUFilter, filterWindow)
+	}
 
 	// Counter for per-second logging (log extra data every N ticks)
 	tickCounter := 0
 	logInterval := cfg.ConsoleLogInterval / cfg.IMUSampleInterval // Calculate ticks per log interval
 
-	// main tick
-	ticker := time.NewTicker(time.Duration(cfg.IMUSampleInterval) * time.Millisecond)
-	defer ticker.Stop()
+	// Raw IMU/mag topics are published every decimation-th tick; reading,
+	// integration and pose publishing still run every tick.
+	decimation := cfg.PublishDecimation
+	if decimation < 1 {
+		decimation = 1
+	}
+	rawTick := 0
+
+	// Pose topics are skipped while the pose stays within
+	// POSE_PUBLISH_DEADBAND, with a keepalive every POSE_PUBLISH_KEEPALIVE
+	keepalive := time.Duration(cfg.PosePublishKeepalive) * time.Second
+	deadbandLeft := orientation.PoseDeadband{Deg: cfg.PosePublishDeadband, Keepalive: keepalive}
+	deadbandRight := deadbandLeft
+	deadbandFused := deadbandLeft
+	if cfg.PosePublishDeadband > 0 {
+		logging.Infof("pose deadband: %.2f°, keepalive %s", cfg.PosePublishDeadband, keepalive)
+	}
+
+	// main tick: IMU_SAMPLE_INTERVAL, on the IMU data-ready edges with
+	// IMU_INTERRUPT_SAMPLING (see sensors.IMUManager.SampleClock)
+	clockCtx, stopClock := context.WithCancel(ctx)
+	defer stopClock()
 
-	for t := range ticker.C {
+	for t := range imuManager.SampleClock(clockCtx, time.Duration(cfg.IMUSampleInterval)*time.Millisecond) {
 		tickCounter++
+		rawTick++
+		publishRaw := rawTick%decimation == 0
+		if publishRaw {
+			rawTick = 0
+		}
 		// Calculate delta time for gyro integration
 		var deltaTime float64
 		if lastTickTime.IsZero() {
@@ -97,8 +283,8 @@ func RunInertialProducer() error {
 
 		if useMock {
 			// In mock mode, create dummy readings
-			imuL = imu_raw.IMURaw{Source: "mock"}
-			imuR = imu_raw.IMURaw{Source: "mock"}
+			imuL = imu_raw.IMURaw{Source: "mock", Timestamp: t}
+			imuR = imu_raw.IMURaw{Source: "mock", Timestamp: t}
 			hasLeftIMU = true
 			hasRightIMU = true
 		} else {
@@ -107,9 +293,15 @@ func RunInertialProducer() error {
 				var err error
 				imuL, err = imuManager.ReadLeftIMU()
 				if err != nil {
-					log.Printf("error reading left IMU: %v", err)
+					logging.Errorf("error reading left IMU: %v", err)
+					if filterLeft != nil {
+						filterLeft.Reset()
+					}
 				} else {
 					hasLeftIMU = true
+					if filterLeft != nil {
+						imuL = filterLeft.Apply(imuL)
+					}
 				}
 			}
 
@@ -118,101 +310,133 @@ func RunInertialProducer() error {
 				var err error
 				imuR, err = imuManager.ReadRightIMU()
 				if err != nil {
-					log.Printf("error reading right IMU: %v", err)
+					logging.Errorf("error reading right IMU: %v", err)
+					if filterRight != nil {
+						filterRight.Reset()
+					}
 				} else {
 					hasRightIMU = true
+					if filterRight != nil {
+						imuR = filterRight.Apply(imuR)
+					}
 				}
 			}
 		}
 
 		// Step 2: Publish left IMU raw data
-		if hasLeftIMU {
+		if hasLeftIMU && publishRaw {
+			imuL.Seq = nextSeq(&seqIMULeft)
 			if payload, err := json.Marshal(imuL); err != nil {
-				log.Printf("left IMU marshal error: %v", err)
+				logging.Errorf("left IMU marshal error: %v", err)
 			} else {
-				if token := client.Publish(cfg.TopicIMULeft, 0, true, payload); token.Wait() && token.Error() != nil {
-					log.Printf("MQTT publish error (imu/left): %v", token.Error())
+				if token := client.Publish(cfg.TopicIMULeft, qos, retain, payload); token.Wait() && token.Error() != nil {
+					logging.Errorf("MQTT publish error (imu/left): %v", token.Error())
 				}
 			}
 
 			// MAG TEST/DEBUG: publish mag-only topic for left IMU
-			mn := magNorm(imuL.Mx, imuL.My, imuL.Mz)
+			mn := imu_raw.MagNormUT(imuL.Mx, imuL.My, imuL.Mz)
 			magTest := struct {
-				Mx   int16   `json:"mx"`
-				My   int16   `json:"my"`
-				Mz   int16   `json:"mz"`
-				Norm float64 `json:"norm"`
-				Time string  `json:"time"`
+				Mx       int16   `json:"mx"`
+				My       int16   `json:"my"`
+				Mz       int16   `json:"mz"`
+				Norm     float64 `json:"norm"`
+				Valid    bool    `json:"valid"`
+				Overflow bool    `json:"overflow,omitempty"`
+				Time     string  `json:"time"`
 			}{
-				Mx:   imuL.Mx,
-				My:   imuL.My,
-				Mz:   imuL.Mz,
-				Norm: mn,
-				Time: t.Format(time.RFC3339),
+				Mx:       imuL.Mx,
+				My:       imuL.My,
+				Mz:       imuL.Mz,
+				Norm:     mn,
+				Valid:    imuL.MagValid,
+				Overflow: imuL.MagOverflow,
+				Time:     t.Format(time.RFC3339),
 			}
 			if payload, err := json.Marshal(magTest); err != nil {
-				log.Printf("mag marshal error: %v", err)
+				logging.Errorf("mag marshal error: %v", err)
 			} else {
-				client.Publish(cfg.TopicMagLeft, 0, true, payload)
+				client.Publish(cfg.TopicMagLeft, qos, retain, payload)
 			}
 		}
 
 		// Step 3: Publish right IMU raw data
-		if hasRightIMU {
+		if hasRightIMU && publishRaw {
+			imuR.Seq = nextSeq(&seqIMURight)
 			if payload, err := json.Marshal(imuR); err != nil {
-				log.Printf("right IMU marshal error: %v", err)
+				logging.Errorf("right IMU marshal error: %v", err)
 			} else {
-				if token := client.Publish(cfg.TopicIMURight, 0, true, payload); token.Wait() && token.Error() != nil {
-					log.Printf("MQTT publish error (imu/right): %v", token.Error())
+				if token := client.Publish(cfg.TopicIMURight, qos, retain, payload); token.Wait() && token.Error() != nil {
+					logging.Errorf("MQTT publish error (imu/right): %v", token.Error())
 				}
 			}
 
 			// MAG TEST/DEBUG: publish mag-only topic for right IMU
-			mn := magNorm(imuR.Mx, imuR.My, imuR.Mz)
+			mn := imu_raw.MagNormUT(imuR.Mx, imuR.My, imuR.Mz)
 			magTest := struct {
-				Mx   int16   `json:"mx"`
-				My   int16   `json:"my"`
-				Mz   int16   `json:"mz"`
-				Norm float64 `json:"norm"`
-				Time string  `json:"time"`
+				Mx       int16   `json:"mx"`
+				My       int16   `json:"my"`
+				Mz       int16   `json:"mz"`
+				Norm     float64 `json:"norm"`
+				Valid    bool    `json:"valid"`
+				Overflow bool    `json:"overflow,omitempty"`
+				Time     string  `json:"time"`
 			}{
-				Mx:   imuR.Mx,
-				My:   imuR.My,
-				Mz:   imuR.Mz,
-				Norm: mn,
-				Time: t.Format(time.RFC3339),
+				Mx:       imuR.Mx,
+				My:       imuR.My,
+				Mz:       imuR.Mz,
+				Norm:     mn,
+				Valid:    imuR.MagValid,
+				Overflow: imuR.MagOverflow,
+				Time:     t.Format(time.RFC3339),
 			}
 			if payload, err := json.Marshal(magTest); err != nil {
-				log.Printf("right mag marshal error: %v", err)
+				logging.Errorf("right mag marshal error: %v", err)
 			} else {
-				client.Publish(cfg.TopicMagRight, 0, true, payload)
+				client.Publish(cfg.TopicMagRight, qos, retain, payload)
 			}
 		}
 
-		// Step 4: Read and publish BMP environmental sensors
-		if envL, err := sensors.ReadLeftEnv(); err != nil {
-			log.Printf("left env read error: %v", err)
-			continue
-		} else if payload, err := json.Marshal(envL); err != nil {
-			log.Printf("left env marshal error: %v", err)
-			continue
-		} else {
-			if token := client.Publish(cfg.TopicBMPLeft, 0, true, payload); token.Wait() && token.Error() != nil {
-				log.Printf("MQTT publish error (bmp/left): %v", token.Error())
-				continue
+		// Step 4: Read and publish BMP environmental sensors. A missing BMP
+		// (reported once at init) or a failed read only skips that sensor.
+		var envL, envR env.Sample
+		haveEnvL := sensors.IsLeftEnvAvailable()
+		if haveEnvL {
+			var err error
+			if envL, err = sensors.ReadLeftEnv(); err != nil {
+				logging.Errorf("left env read error: %v", err)
+				haveEnvL = false
+			} else if payload, err := json.Marshal(envL); err != nil {
+				logging.Errorf("left env marshal error: %v", err)
+			} else if token := client.Publish(cfg.TopicBMPLeft, qos, retain, payload); token.Wait() && token.Error() != nil {
+				logging.Errorf("MQTT publish error (bmp/left): %v", token.Error())
+			} else {
+				publishVario(varioLeft, envL, cfg.TopicVarioLeft, t)
 			}
 		}
 
-		if envR, err := sensors.ReadRightEnv(); err != nil {
-			log.Printf("right env read error: %v", err)
-			continue
-		} else if payload, err := json.Marshal(envR); err != nil {
-			log.Printf("right env marshal error: %v", err)
-			continue
-		} else {
-			if token := client.Publish(cfg.TopicBMPRight, 0, true, payload); token.Wait() && token.Error() != nil {
-				log.Printf("MQTT publish error (bmp/right): %v", token.Error())
-				continue
+		haveEnvR := sensors.IsRightEnvAvailable()
+		if haveEnvR {
+			var err error
+			if envR, err = sensors.ReadRightEnv(); err != nil {
+				logging.Errorf("right env read error: %v", err)
+				haveEnvR = false
+			} else if payload, err := json.Marshal(envR); err != nil {
+				logging.Errorf("right env marshal error: %v", err)
+			} else if token := client.Publish(cfg.TopicBMPRight, qos, retain, payload); token.Wait() && token.Error() != nil {
+				logging.Errorf("MQTT publish error (bmp/right): %v", token.Error())
+			} else {
+				publishVario(varioRight, envR, cfg.TopicVarioRight, t)
+			}
+		}
+
+		// Left/right difference (airflow or thermal gradient across the rig)
+		if cfg.TopicBMPDiff != "" && (haveEnvL || haveEnvR) {
+			diff := env.NewBMPDiff(envL, haveEnvL, envR, haveEnvR, t)
+			if payload, err := json.Marshal(diff); err != nil {
+				logging.Errorf("bmp diff marshal error: %v", err)
+			} else if token := client.Publish(cfg.TopicBMPDiff, qos, retain, payload); token.Wait() && token.Error() != nil {
+				logging.Errorf("MQTT publish error (bmp/diff): %v", token.Error())
 			}
 		}
 
@@ -224,38 +448,38 @@ func RunInertialProducer() error {
 			var err error
 			poseLeft, err = mockSrc.Next()
 			if err != nil {
-				log.Printf("error from mock orientation source: %v", err)
+				logging.Errorf("error from mock orientation source: %v", err)
 				continue
 			}
+			poseLeft.Timestamp = t
+			if refs != nil {
+				poseLeft = refs.apply(&refs.left, poseLeft)
+			}
 			poseRight = poseLeft // Same for mock
 			poseFused = poseLeft // Same for mock
 		} else {
 			// Calculate pose from left IMU
 			if hasLeftIMU {
-				poseLeft = orientation.ComputePoseFromIMURaw(
-					float64(imuL.Ax),
-					float64(imuL.Ay),
-					float64(imuL.Az),
-					float64(imuL.Gx),
-					float64(imuL.Gy),
-					float64(imuL.Gz),
-					prevPose,
-					deltaTime,
-				)
+				if biasLeft != nil {
+					biasLeft.Update(imuL)
+				}
+				poseLeft = computePose(imuL, biasLeft, orientLeft, ekfLeft, deltaTime)
+				updateMotion(motionLeft, biasLeft, imuL, cfg.TopicMotionLeft, publishRaw)
+				if refs != nil {
+					poseLeft = refs.apply(&refs.left, poseLeft)
+				}
 			}
 
 			// Calculate pose from right IMU
 			if hasRightIMU {
-				poseRight = orientation.ComputePoseFromIMURaw(
-					float64(imuR.Ax),
-					float64(imuR.Ay),
-					float64(imuR.Az),
-					float64(imuR.Gx),
-					float64(imuR.Gy),
-					float64(imuR.Gz),
-					prevPose,
-					deltaTime,
-				)
+				if biasRight != nil {
+					biasRight.Update(imuR)
+				}
+				poseRight = computePose(imuR, biasRight, orientRight, ekfRight, deltaTime)
+				updateMotion(motionRight, biasRight, imuR, cfg.TopicMotionRight, publishRaw)
+				if refs != nil {
+					poseRight = refs.apply(&refs.right, poseRight)
+				}
 			}
 
 			// Calculate fused pose (simple average if both available, otherwise use available one)
@@ -264,6 +488,19 @@ func RunInertialProducer() error {
 					Roll:  (poseLeft.Roll + poseRight.Roll) / 2.0,
 					Pitch: (poseLeft.Pitch + poseRight.Pitch) / 2.0,
 					Yaw:   (poseLeft.Yaw + poseRight.Yaw) / 2.0,
+
+					// Fused pose is as recent as its older input, and as
+					// confident as its less confident one
+					Timestamp:  poseLeft.Timestamp,
+					Confidence: math.Min(poseLeft.Confidence, poseRight.Confidence),
+
+					// Standard error of the average of two independent estimates
+					RollStd:  math.Hypot(poseLeft.RollStd, poseRight.RollStd) / 2,
+					PitchStd: math.Hypot(poseLeft.PitchStd, poseRight.PitchStd) / 2,
+					YawStd:   math.Hypot(poseLeft.YawStd, poseRight.YawStd) / 2,
+				}
+				if poseRight.Timestamp.Before(poseFused.Timestamp) {
+					poseFused.Timestamp = poseRight.Timestamp
 				}
 			} else if hasLeftIMU {
 				poseFused = poseLeft
@@ -272,48 +509,49 @@ func RunInertialProducer() error {
 			}
 		}
 
-		// Update previous pose for next iteration (use fused)
-		prevPose = poseFused
-
 		// Publish left pose
-		if hasLeftIMU {
+		if hasLeftIMU && deadbandLeft.Publish(poseLeft, t) {
+			poseLeft.Seq = nextSeq(&seqPoseLeft)
 			if payload, err := json.Marshal(poseLeft); err != nil {
-				log.Printf("json marshal error (pose/left): %v", err)
+				logging.Errorf("json marshal error (pose/left): %v", err)
 			} else {
-				if token := client.Publish(cfg.TopicPoseLeft, 0, true, payload); token.Wait() && token.Error() != nil {
-					log.Printf("MQTT publish error (pose/left): %v", token.Error())
+				if token := client.Publish(cfg.TopicPoseLeft, qos, retain, payload); token.Wait() && token.Error() != nil {
+					logging.Errorf("MQTT publish error (pose/left): %v", token.Error())
 				}
 			}
 		}
 
 		// Publish right pose
-		if hasRightIMU {
+		if hasRightIMU && deadbandRight.Publish(poseRight, t) {
+			poseRight.Seq = nextSeq(&seqPoseRight)
 			if payload, err := json.Marshal(poseRight); err != nil {
-				log.Printf("json marshal error (pose/right): %v", err)
+				logging.Errorf("json marshal error (pose/right): %v", err)
 			} else {
-				if token := client.Publish(cfg.TopicPoseRight, 0, true, payload); token.Wait() && token.Error() != nil {
-					log.Printf("MQTT publish error (pose/right): %v", token.Error())
+				if token := client.Publish(cfg.TopicPoseRight, qos, retain, payload); token.Wait() && token.Error() != nil {
+					logging.Errorf("MQTT publish error (pose/right): %v", token.Error())
 				}
 			}
 		}
 
 		// Publish fused pose
-		if hasLeftIMU || hasRightIMU {
+		if (hasLeftIMU || hasRightIMU) && deadbandFused.Publish(poseFused, t) {
+			poseFused.Seq = nextSeq(&seqPoseFused)
 			if payload, err := json.Marshal(poseFused); err != nil {
-				log.Printf("json marshal error (pose/fused): %v", err)
+				logging.Errorf("json marshal error (pose/fused): %v", err)
 			} else {
-				if token := client.Publish(cfg.TopicPoseFused, 0, true, payload); token.Wait() && token.Error() != nil {
-					log.Printf("MQTT publish error (pose/fused): %v", token.Error())
+				if token := client.Publish(cfg.TopicPoseFused, qos, retain, payload); token.Wait() && token.Error() != nil {
+					logging.Errorf("MQTT publish error (pose/fused): %v", token.Error())
 				}
 			}
 		}
 
-		// --- Log all sensor data once per second ---
+		// --- Dump all sensor data (DEBUG) and publish IMU health once per second ---
 		if tickCounter >= logInterval {
 			tickCounter = 0
+			debug := logging.Enabled(logging.LevelDebug)
 
 			// Poses
-			log.Printf("%s | LEFT pose R=%.2f P=%.2f Y=%.2f | RIGHT pose R=%.2f P=%.2f Y=%.2f | FUSED pose R=%.2f P=%.2f Y=%.2f",
+			logging.Debugf("%s | LEFT pose R=%.2f P=%.2f Y=%.2f | RIGHT pose R=%.2f P=%.2f Y=%.2f | FUSED pose R=%.2f P=%.2f Y=%.2f",
 				t.Format(time.RFC3339),
 				poseLeft.Roll, poseLeft.Pitch, poseLeft.Yaw,
 				poseRight.Roll, poseRight.Pitch, poseRight.Yaw,
@@ -322,33 +560,59 @@ func RunInertialProducer() error {
 
 			// Left IMU
 			if hasLeftIMU {
-				mn := magNorm(imuL.Mx, imuL.My, imuL.Mz)
-				log.Printf("  [LEFT IMU] accel ax=%d ay=%d az=%d | gyro gx=%d gy=%d gz=%d | mag mx=%d my=%d mz=%d | |B|=%.1f",
+				mn := imu_raw.MagNormUT(imuL.Mx, imuL.My, imuL.Mz)
+				logging.Debugf("  [LEFT IMU] accel ax=%d ay=%d az=%d | gyro gx=%d gy=%d gz=%d | mag mx=%d my=%d mz=%d | |B|=%.1f valid=%t",
 					imuL.Ax, imuL.Ay, imuL.Az,
 					imuL.Gx, imuL.Gy, imuL.Gz,
 					imuL.Mx, imuL.My, imuL.Mz,
-					mn,
+					mn, imuL.MagValid,
 				)
 			}
 			// Right IMU
 			if hasRightIMU {
-				mnR := magNorm(imuR.Mx, imuR.My, imuR.Mz)
-				log.Printf("  [RIGHT IMU] accel ax=%d ay=%d az=%d | gyro gx=%d gy=%d gz=%d | mag mx=%d my=%d mz=%d | |B|=%.1f",
+				mnR := imu_raw.MagNormUT(imuR.Mx, imuR.My, imuR.Mz)
+				logging.Debugf("  [RIGHT IMU] accel ax=%d ay=%d az=%d | gyro gx=%d gy=%d gz=%d | mag mx=%d my=%d mz=%d | |B|=%.1f valid=%t",
 					imuR.Ax, imuR.Ay, imuR.Az,
 					imuR.Gx, imuR.Gy, imuR.Gz,
 					imuR.Mx, imuR.My, imuR.Mz,
-					mnR,
+					mnR, imuR.MagValid,
 				)
 			}
 
-			// Left BMP
-			if envL, err := sensors.ReadLeftEnv(); err == nil {
-				log.Printf("  [LEFT BMP] temp=%.2f°C pressure=%.2fmbar / %.2fhPa", envL.Temperature, envL.PressureMbar, envL.PressureHPa)
+			// BMPs (extra bus reads, so only when the dump is shown)
+			if debug {
+				if envL, err := sensors.ReadLeftEnv(); err == nil {
+					logging.Debugf("  [LEFT BMP] temp=%.2f°C pressure=%.2fmbar / %.2fhPa", envL.Temperature, envL.PressureMbar, envL.PressureHPa)
+				}
+				if envR, err := sensors.ReadRightEnv(); err == nil {
+					logging.Debugf("  [RIGHT BMP] temp=%.2f°C pressure=%.2fmbar / %.2fhPa", envR.Temperature, envR.PressureMbar, envR.PressureHPa)
+				}
 			}
 
-			// Right BMP
-			if envR, err := sensors.ReadRightEnv(); err == nil {
-				log.Printf("  [RIGHT BMP] temp=%.2f°C pressure=%.2fmbar / %.2fhPa", envR.Temperature, envR.PressureMbar, envR.PressureHPa)
+			// IMU read health
+			if !useMock {
+				metrics := imuManager.Metrics()
+				bmpL, bmpR := sensors.EnvStatus()
+				metrics.BMPLeft, metrics.BMPRight = &bmpL, &bmpR
+				if biasLeft != nil {
+					st := biasLeft.Status()
+					metrics.GyroBiasLeft = &st
+				}
+				if biasRight != nil {
+					st := biasRight.Status()
+					metrics.GyroBiasRight = &st
+				}
+				logging.Debugf("  [IMU HEALTH] left %.1f reads/s errors=%d/%d | right %.1f reads/s errors=%d/%d",
+					metrics.Left.ReadsPerSec, metrics.Left.Errors, metrics.Left.Reads,
+					metrics.Right.ReadsPerSec, metrics.Right.Errors, metrics.Right.Reads,
+				)
+				if cfg.TopicIMUHealth != "" {
+					if payload, err := json.Marshal(metrics); err != nil {
+						logging.Errorf("imu health marshal error: %v", err)
+					} else if token := client.Publish(cfg.TopicIMUHealth, qos, retain, payload); token.Wait() && token.Error() != nil {
+						logging.Errorf("MQTT publish error (imu/health): %v", token.Error())
+					}
+				}
 			}
 		}
 	}
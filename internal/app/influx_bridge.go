@@ -0,0 +1,349 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/relabs-tech/inertial_computer/internal/config"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
+)
+
+const (
+	defaultInfluxBatchSize     = 500
+	defaultInfluxFlushInterval = time.Second
+	defaultInfluxClientID      = "inertial-influx-bridge"
+
+	influxWriteAttempts = 3
+	influxRetryBackoff  = 500 * time.Millisecond
+	// influxMaxBuffered batches are kept while the endpoint is down; beyond
+	// that the oldest lines are dropped.
+	influxMaxBuffered = 20
+)
+
+// influxSeries is the measurement and tags of an MQTT topic.
+type influxSeries struct {
+	measurement string
+	tags        map[string]string
+}
+
+// influxSink batches messages as InfluxDB line protocol and writes them from
+// a background goroutine, to INFLUX_URL or stdout.
+type influxSink struct {
+	series    map[string]influxSeries // by topic
+	batchSize int
+	write     func([]byte) error
+
+	mu      sync.Mutex
+	pending []string
+	flushCh chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// newInfluxSink creates the "influx" sink from the INFLUX_* settings.
+func newInfluxSink(string) (Sink, error) {
+	cfg := config.Get()
+
+	batchSize := cfg.InfluxBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultInfluxBatchSize
+	}
+	interval := time.Duration(cfg.InfluxFlushInterval) * time.Millisecond
+	if interval <= 0 {
+		interval = defaultInfluxFlushInterval
+	}
+
+	s := &influxSink{
+		series: map[string]influxSeries{
+			cfg.TopicPoseLeft:    {"pose", map[string]string{"source": "left"}},
+			cfg.TopicPoseRight:   {"pose", map[string]string{"source": "right"}},
+			cfg.TopicPoseFused:   {"pose", map[string]string{"source": "fused"}},
+			cfg.TopicIMULeft:     {"imu", map[string]string{"imu": "left"}},
+			cfg.TopicIMURight:    {"imu", map[string]string{"imu": "right"}},
+			cfg.TopicMagLeft:     {"mag", map[string]string{"imu": "left"}},
+			cfg.TopicMagRight:    {"mag", map[string]string{"imu": "right"}},
+			cfg.TopicBMPLeft:     {"bmp", map[string]string{"source": "left"}},
+			cfg.TopicBMPRight:    {"bmp", map[string]string{"source": "right"}},
+			cfg.TopicGPSPosition: {"gps_position", nil},
+			cfg.TopicGPSVelocity: {"gps_velocity", nil},
+			cfg.TopicGPSQuality:  {"gps_quality", nil},
+		},
+		batchSize: batchSize,
+		write:     func(body []byte) error { _, err := os.Stdout.Write(body); return err },
+		flushCh:   make(chan struct{}, 1),
+		done:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+	delete(s.series, "")
+
+	if cfg.InfluxURL != "" {
+		s.write = influxHTTPWriter(cfg.InfluxURL, cfg.InfluxToken)
+		logging.Infof("influx: writing to %s in batches of %d", cfg.InfluxURL, batchSize)
+	} else {
+		logging.Infof("influx: writing line protocol to stdout")
+	}
+
+	go s.run(interval)
+	return s, nil
+}
+
+// Write queues one point. Topics without a measurement are ignored.
+func (s *influxSink) Write(topic string, payload []byte, t time.Time) error {
+	series, ok := s.series[topic]
+	if !ok {
+		return nil
+	}
+	line, err := influxLine(series.measurement, series.tags, payload, t)
+	if err != nil || line == "" {
+		return err
+	}
+
+	s.mu.Lock()
+	s.pending = append(s.pending, line)
+	full := len(s.pending) >= s.batchSize
+	s.mu.Unlock()
+	if full {
+		s.kick()
+	}
+	return nil
+}
+
+// Close stops the writer and writes what is pending, giving up at the first
+// batch that still fails after retries.
+func (s *influxSink) Close() error {
+	close(s.done)
+	<-s.stopped
+	for {
+		if ok, err := s.flush(); !ok || err != nil {
+			return err
+		}
+	}
+}
+
+func (s *influxSink) kick() {
+	select {
+	case s.flushCh <- struct{}{}:
+	default:
+	}
+}
+
+// run writes a batch every interval, or as soon as a full one is pending.
+func (s *influxSink) run(interval time.Duration) {
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		case <-s.flushCh:
+		}
+
+		if _, err := s.flush(); err != nil {
+			logging.Warnf("influx: write failed, keeping lines: %v", err)
+			s.mu.Lock()
+			if max := influxMaxBuffered * s.batchSize; len(s.pending) > max {
+				logging.Warnf("influx: dropping %d oldest lines", len(s.pending)-max)
+				s.pending = s.pending[len(s.pending)-max:]
+			}
+			s.mu.Unlock()
+			continue
+		}
+
+		s.mu.Lock()
+		more := len(s.pending) >= s.batchSize
+		s.mu.Unlock()
+		if more {
+			s.kick()
+		}
+	}
+}
+
+// flush writes up to one batch of pending lines with retries. It reports
+// whether there was anything to write; on error the lines stay pending.
+func (s *influxSink) flush() (bool, error) {
+	s.mu.Lock()
+	n := len(s.pending)
+	if n == 0 {
+		s.mu.Unlock()
+		return false, nil
+	}
+	if n > s.batchSize {
+		n = s.batchSize
+	}
+	batch := s.pending[:n:n]
+	s.mu.Unlock()
+
+	if err := writeWithRetry(s.write, []byte(strings.Join(batch, "\n")+"\n")); err != nil {
+		return true, err
+	}
+
+	s.mu.Lock()
+	s.pending = s.pending[n:]
+	s.mu.Unlock()
+	return true, nil
+}
+
+// RunInfluxBridge subscribes to the pose, IMU, magnetometer, BMP and GPS
+// topics and writes every message as an InfluxDB line protocol point through
+// the "influx" sink: numeric and boolean JSON fields become fields (nested
+// objects flattened with "_"), strings and arrays are skipped, and the point
+// is timestamped with the payload timestamp, or else on receipt.
+//
+// Lines are batched (INFLUX_BATCH_SIZE lines or INFLUX_FLUSH_INTERVAL) and
+// POSTed to INFLUX_URL, e.g. an InfluxDB 2 /api/v2/write URL, retrying a
+// failed write; with INFLUX_URL empty they are printed to stdout, for
+// telegraf's execd or a pipe.
+func RunInfluxBridge(ctx context.Context) error {
+	logging.Infof("starting inertial-computer InfluxDB bridge")
+
+	cfg := config.Get()
+	clientID := cfg.MQTTClientIDInflux
+	if clientID == "" {
+		clientID = defaultInfluxClientID
+	}
+
+	sink, err := newInfluxSink("")
+	if err != nil {
+		return err
+	}
+	return runSinks(ctx, clientID, []Sink{sink})
+}
+
+// influxHTTPWriter POSTs line protocol to url, with an InfluxDB 2 API token
+// if one is set.
+func influxHTTPWriter(url, token string) func([]byte) error {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	return func(body []byte) error {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+		if token != "" {
+			req.Header.Set("Authorization", "Token "+token)
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode/100 != 2 {
+			msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+			return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+		}
+		return nil
+	}
+}
+
+// writeWithRetry calls write up to influxWriteAttempts times with a growing
+// backoff.
+func writeWithRetry(write func([]byte) error, body []byte) error {
+	var err error
+	for attempt := 1; attempt <= influxWriteAttempts; attempt++ {
+		if err = write(body); err == nil {
+			return nil
+		}
+		if attempt < influxWriteAttempts {
+			time.Sleep(time.Duration(attempt) * influxRetryBackoff)
+		}
+	}
+	return err
+}
+
+// influxLine encodes a JSON object payload as one line protocol point,
+// timestamped with the payload's "timestamp" if it has one, otherwise t.
+// It returns "" if the payload has no numeric or boolean fields.
+func influxLine(measurement string, tags map[string]string, payload []byte, t time.Time) (string, error) {
+	var obj map[string]interface{}
+	if err := json.Unmarshal(payload, &obj); err != nil {
+		return "", fmt.Errorf("payload is not a JSON object: %w", err)
+	}
+
+	if ts, ok := obj["timestamp"].(string); ok {
+		if pt, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			t = pt
+		}
+	}
+
+	fields := make(map[string]string)
+	flattenInfluxFields("", obj, fields)
+	if len(fields) == 0 {
+		return "", nil
+	}
+
+	var b strings.Builder
+	b.WriteString(influxEscape(measurement, ", "))
+	for _, k := range sortedKeys(tags) {
+		b.WriteString("," + influxEscape(k, ",= ") + "=" + influxEscape(tags[k], ",= "))
+	}
+	for i, k := range sortedKeys(fields) {
+		sep := ","
+		if i == 0 {
+			sep = " "
+		}
+		b.WriteString(sep + influxEscape(k, ",= ") + "=" + fields[k])
+	}
+	b.WriteString(" " + strconv.FormatInt(t.UnixNano(), 10))
+	return b.String(), nil
+}
+
+// flattenInfluxFields adds the numeric and boolean values of obj to fields,
+// naming nested values parent_child.
+func flattenInfluxFields(prefix string, obj map[string]interface{}, fields map[string]string) {
+	for k, v := range obj {
+		name := k
+		if prefix != "" {
+			name = prefix + "_" + k
+		}
+		switch v := v.(type) {
+		case float64:
+			fields[name] = strconv.FormatFloat(v, 'g', -1, 64)
+		case bool:
+			fields[name] = strconv.FormatBool(v)
+		case map[string]interface{}:
+			flattenInfluxFields(name, v, fields)
+		}
+	}
+}
+
+// influxEscape backslash-escapes the characters in special.
+func influxEscape(s, special string) string {
+	if !strings.ContainsAny(s, special) {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(special, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,265 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/relabs-tech/inertial_computer/internal/config"
+	"github.com/relabs-tech/inertial_computer/internal/gps"
+	imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
+	"github.com/relabs-tech/inertial_computer/internal/recording"
+	"github.com/relabs-tech/inertial_computer/internal/sensors"
+)
+
+const defaultLoggerClientID = "inertial-logger"
+
+// LoggerOptions configures RunLogger.
+type LoggerOptions struct {
+	Rate     float64       // rows per second
+	Duration time.Duration // stop after this long (0 = until ctx is cancelled)
+	Out      string        // output file; ".csv" selects CSV, anything else JSONL
+}
+
+// rowWriter is implemented by the JSONL and CSV logger outputs.
+type rowWriter interface {
+	Write(recording.Row) error
+	Flush() error
+}
+
+// RunLogger captures a time-aligned dataset: at every tick it takes the
+// latest left/right IMU samples (IMU streaming API), reads both BMPs and adds
+// the latest GPS fix received over MQTT, then writes one row stamped with the
+// tick time.
+//
+// JSONL output uses the recording.Record format, one record per sensor with
+// the row's timestamp and the sensor's MQTT topic (GPS only when a new fix
+// arrived), so sessions can be exported and replayed like recorded MQTT
+// traffic. CSV output is one wide row per tick, see recording.RowCSVWriter.
+//
+// The logger reads the sensors directly, so imu_producer must not run at the
+// same time. GPS is optional: without a broker the GPS columns stay empty.
+func RunLogger(ctx context.Context, opts LoggerOptions) error {
+	logging.Infof("starting inertial-computer logger")
+
+	cfg := config.Get()
+
+	if opts.Rate <= 0 {
+		return fmt.Errorf("rate must be > 0, got %g", opts.Rate)
+	}
+	interval := time.Duration(float64(time.Second) / opts.Rate)
+	if streamEvery := sensors.StreamInterval(); interval < streamEvery {
+		logging.Warnf("logger: %v row interval is shorter than the %v IMU stream interval (IMU_STREAM_INTERVAL); IMU samples will repeat", interval, streamEvery)
+	}
+
+	// 1) Sensors
+	imuManager := sensors.GetIMUManager()
+	if err := imuManager.Init(); err != nil {
+		return fmt.Errorf("failed to initialize IMU manager: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu                sync.Mutex
+		imuLeft, imuRight *imu_raw.IMURaw
+		lastFix           *gps.Fix
+		fixReceived       time.Time
+		fixWritten        time.Time // fixReceived as of the previous row
+	)
+
+	follow := func(ch <-chan imu_raw.IMURaw, dst **imu_raw.IMURaw) {
+		for s := range ch {
+			s := s
+			mu.Lock()
+			*dst = &s
+			mu.Unlock()
+		}
+	}
+	if imuManager.IsLeftIMUAvailable() {
+		go follow(imuManager.StreamLeft(ctx), &imuLeft)
+	} else {
+		logging.Warnf("logger: left IMU not available, its columns stay empty")
+	}
+	if imuManager.IsRightIMUAvailable() {
+		go follow(imuManager.StreamRight(ctx), &imuRight)
+	} else {
+		logging.Warnf("logger: right IMU not available, its columns stay empty")
+	}
+
+	// 2) GPS over MQTT (optional)
+	clientID := cfg.MQTTClientIDLogger
+	if clientID == "" {
+		clientID = defaultLoggerClientID
+	}
+	if client, err := NewMQTTClient(clientID); err != nil {
+		logging.Warnf("logger: MQTT connect error, logging without GPS: %v", err)
+	} else {
+		defer DisconnectMQTT(client)
+		gpsToken := client.Subscribe(cfg.TopicGPS, 0, func(_ mqtt.Client, msg mqtt.Message) {
+			var f gps.Fix
+			if err := json.Unmarshal(msg.Payload(), &f); err != nil {
+				logging.Warnf("logger: gps unmarshal error: %v", err)
+				return
+			}
+			mu.Lock()
+			lastFix = &f
+			fixReceived = time.Now()
+			mu.Unlock()
+		})
+		gpsToken.Wait()
+		if gpsToken.Error() != nil {
+			return gpsToken.Error()
+		}
+		logging.Infof("logger: subscribed to %s", cfg.TopicGPS)
+	}
+
+	// 3) Output
+	f, err := os.Create(opts.Out)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", opts.Out, err)
+	}
+	defer f.Close()
+
+	var out rowWriter
+	if strings.EqualFold(filepath.Ext(opts.Out), ".csv") {
+		out = recording.NewRowCSVWriter(f)
+	} else {
+		out = &recordRowWriter{
+			w: recording.NewWriter(f),
+			topics: map[string]string{
+				"imu_left":  cfg.TopicIMULeft,
+				"imu_right": cfg.TopicIMURight,
+				"bmp_left":  cfg.TopicBMPLeft,
+				"bmp_right": cfg.TopicBMPRight,
+				"gps":       cfg.TopicGPS,
+			},
+		}
+	}
+
+	// 4) Capture loop
+	var done <-chan time.Time
+	if opts.Duration > 0 {
+		done = time.After(opts.Duration)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	logging.Infof("logger: writing %s at %g rows/s", opts.Out, opts.Rate)
+
+	rows := 0
+	var loopErr error
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			logging.Infof("logger: interrupted")
+			break loop
+		case <-done:
+			break loop
+		case t := <-ticker.C:
+			row := recording.Row{Time: t}
+			if sensors.IsLeftEnvAvailable() {
+				if s, err := sensors.ReadLeftEnv(); err != nil {
+					logging.Errorf("logger: left env read error: %v", err)
+				} else {
+					row.BMPLeft = &s
+				}
+			}
+			if sensors.IsRightEnvAvailable() {
+				if s, err := sensors.ReadRightEnv(); err != nil {
+					logging.Errorf("logger: right env read error: %v", err)
+				} else {
+					row.BMPRight = &s
+				}
+			}
+
+			mu.Lock()
+			row.IMULeft, row.IMURight = imuLeft, imuRight
+			if lastFix != nil {
+				row.GPS = lastFix
+				row.GPSAge = t.Sub(fixReceived)
+			}
+			row.GPSNew = !fixReceived.Equal(fixWritten)
+			fixWritten = fixReceived
+			mu.Unlock()
+
+			if err := out.Write(row); err != nil {
+				loopErr = fmt.Errorf("write row: %w", err)
+				break loop
+			}
+			rows++
+		}
+	}
+
+	if err := out.Flush(); err != nil && loopErr == nil {
+		loopErr = fmt.Errorf("flush: %w", err)
+	}
+	if err := f.Close(); err != nil && loopErr == nil {
+		loopErr = fmt.Errorf("close: %w", err)
+	}
+	logging.Infof("logger: wrote %d rows to %s", rows, opts.Out)
+	return loopErr
+}
+
+// recordRowWriter writes each Row as recording.Records, one per sensor, all
+// stamped with the row time. GPS is only written for a new fix.
+type recordRowWriter struct {
+	w      *recording.Writer
+	topics map[string]string // sensor key -> topic
+}
+
+func (rw *recordRowWriter) Write(r recording.Row) error {
+	write := func(key string, v interface{}) error {
+		payload, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		return rw.w.Write(recording.Record{Time: r.Time, Topic: rw.topics[key], Payload: payload})
+	}
+
+	if r.IMULeft != nil {
+		if err := write("imu_left", r.IMULeft); err != nil {
+			return err
+		}
+	}
+	if r.IMURight != nil {
+		if err := write("imu_right", r.IMURight); err != nil {
+			return err
+		}
+	}
+	if r.BMPLeft != nil {
+		if err := write("bmp_left", r.BMPLeft); err != nil {
+			return err
+		}
+	}
+	if r.BMPRight != nil {
+		if err := write("bmp_right", r.BMPRight); err != nil {
+			return err
+		}
+	}
+	if r.GPS != nil && r.GPSNew {
+		if err := write("gps", r.GPS); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rw *recordRowWriter) Flush() error {
+	return rw.w.Flush()
+}
@@ -6,18 +6,24 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/relabs-tech/inertial_computer/internal/orientation" // adjust to your module path
 )
 
-func RunMockConsole() error {
+func RunMockConsole(ctx context.Context) error {
 	src := orientation.NewMockSource()
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
 
-	for range ticker.C {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
 		pose, err := src.Next()
 		if err != nil {
 			return err
@@ -30,5 +36,4 @@ func RunMockConsole() error {
 			pose.Yaw,
 		)
 	}
-	return nil
 }
@@ -0,0 +1,189 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package app
+
+import (
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/relabs-tech/inertial_computer/internal/config"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
+)
+
+const (
+	// defaultMQTTKeepAlive applies when MQTT_KEEPALIVE is not configured.
+	defaultMQTTKeepAlive = 30 * time.Second
+	// defaultStatusTopicPrefix applies when TOPIC_STATUS_PREFIX is not configured.
+	defaultStatusTopicPrefix = "inertial/status"
+
+	// Retained payloads on the per-client status topic. The broker publishes
+	// statusOffline as the last-will when a client drops ungracefully.
+	statusOnline  = "online"
+	statusOffline = "offline"
+
+	// resubscribeTimeout bounds the wait for each SUBACK after a reconnect.
+	resubscribeTimeout = 5 * time.Second
+)
+
+// NewMQTTClient connects to the configured broker with the options shared by
+// every entry point: auto-reconnect, clean session, keepalive and a retained
+// "offline" last-will on statusTopic(clientID). A retained "online" is
+// published there on every (re)connect.
+//
+// The returned client remembers its subscriptions and restores them on every
+// reconnect: with a clean session the broker drops them with the connection,
+// so without this a consumer would silently stop receiving after a broker
+// restart or network drop.
+func NewMQTTClient(clientID string) (mqtt.Client, error) {
+	subs := &subscriptions{topics: make(map[string]subscription)}
+	client := &resubscribingClient{
+		Client: mqtt.NewClient(newMQTTClientOptions(clientID, subs)),
+		subs:   subs,
+	}
+	if token := client.Connect(); token.Wait() && token.Error() != nil {
+		return nil, token.Error()
+	}
+	return client, nil
+}
+
+// subscription is one topic filter subscribed through a resubscribingClient.
+type subscription struct {
+	qos      byte
+	callback mqtt.MessageHandler
+}
+
+// subscriptions is the set of topic filters to restore on reconnect.
+type subscriptions struct {
+	mu     sync.Mutex
+	topics map[string]subscription
+}
+
+func (s *subscriptions) add(topic string, qos byte, callback mqtt.MessageHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.topics[topic] = subscription{qos: qos, callback: callback}
+}
+
+func (s *subscriptions) remove(topics ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range topics {
+		delete(s.topics, t)
+	}
+}
+
+// restore subscribes every recorded topic again and logs the outcome of
+// each, so a reconnect that fails to resume a stream is visible.
+func (s *subscriptions) restore(client mqtt.Client, clientID string) {
+	s.mu.Lock()
+	topics := make(map[string]subscription, len(s.topics))
+	for t, sub := range s.topics {
+		topics[t] = sub
+	}
+	s.mu.Unlock()
+
+	tokens := make(map[string]mqtt.Token, len(topics))
+	for t, sub := range topics {
+		tokens[t] = client.Subscribe(t, sub.qos, sub.callback)
+	}
+	for t, token := range tokens {
+		switch {
+		case !token.WaitTimeout(resubscribeTimeout):
+			logging.Warnf("mqtt %s: resubscribe %s: no SUBACK within %v", clientID, t, resubscribeTimeout)
+		case token.Error() != nil:
+			logging.Errorf("mqtt %s: resubscribe %s: %v", clientID, t, token.Error())
+		default:
+			logging.Infof("mqtt %s: resubscribed to %s", clientID, t)
+		}
+	}
+}
+
+// resubscribingClient records Subscribe/SubscribeMultiple/Unsubscribe calls
+// so the OnConnect handler of newMQTTClientOptions can restore them.
+type resubscribingClient struct {
+	mqtt.Client
+	subs *subscriptions
+}
+
+func (c *resubscribingClient) Subscribe(topic string, qos byte, callback mqtt.MessageHandler) mqtt.Token {
+	c.subs.add(topic, qos, callback)
+	return c.Client.Subscribe(topic, qos, callback)
+}
+
+func (c *resubscribingClient) SubscribeMultiple(filters map[string]byte, callback mqtt.MessageHandler) mqtt.Token {
+	for topic, qos := range filters {
+		c.subs.add(topic, qos, callback)
+	}
+	return c.Client.SubscribeMultiple(filters, callback)
+}
+
+func (c *resubscribingClient) Unsubscribe(topics ...string) mqtt.Token {
+	c.subs.remove(topics...)
+	return c.Client.Unsubscribe(topics...)
+}
+
+// newMQTTClientOptions builds the client options used by NewMQTTClient; the
+// OnConnect handler restores subs.
+func newMQTTClientOptions(clientID string, subs *subscriptions) *mqtt.ClientOptions {
+	cfg := config.Get()
+
+	keepAlive := time.Duration(cfg.MQTTKeepAlive) * time.Second
+	if keepAlive <= 0 {
+		keepAlive = defaultMQTTKeepAlive
+	}
+
+	return mqtt.NewClientOptions().
+		AddBroker(cfg.MQTTBroker).
+		SetClientID(clientID).
+		SetAutoReconnect(true).
+		SetCleanSession(true).
+		SetKeepAlive(keepAlive).
+		SetWill(statusTopic(clientID), statusOffline, 1, true).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			logging.Warnf("mqtt %s: connection lost: %v", clientID, err)
+		}).
+		SetReconnectingHandler(func(_ mqtt.Client, _ *mqtt.ClientOptions) {
+			logging.Infof("mqtt %s: reconnecting to %s", clientID, cfg.MQTTBroker)
+		}).
+		SetOnConnectHandler(func(c mqtt.Client) {
+			publishStatus(c, clientID, statusOnline)
+			subs.restore(c, clientID)
+		})
+}
+
+// DisconnectMQTT publishes a retained "offline" status (the broker only sends
+// the last-will on ungraceful drops) and disconnects.
+func DisconnectMQTT(client mqtt.Client) {
+	r := client.OptionsReader()
+	publishStatus(client, r.ClientID(), statusOffline)
+	client.Disconnect(250)
+}
+
+// publishStatus publishes a retained status payload for clientID.
+func publishStatus(client mqtt.Client, clientID, status string) {
+	token := client.Publish(statusTopic(clientID), 1, true, status)
+	if token.WaitTimeout(2*time.Second) && token.Error() != nil {
+		logging.Errorf("mqtt %s: status publish error: %v", clientID, token.Error())
+	}
+}
+
+// statusTopic returns the per-client status topic, e.g. inertial/status/<clientID>.
+func statusTopic(clientID string) string {
+	prefix := config.Get().TopicStatusPrefix
+	if prefix == "" {
+		prefix = defaultStatusTopicPrefix
+	}
+	return prefix + "/" + clientID
+}
+
+// publishQoS resolves a per-publisher QoS override (-1 = use MQTT_QOS).
+func publishQoS(override int) byte {
+	if override >= 0 {
+		return byte(override)
+	}
+	return config.Get().MQTTQoS
+}
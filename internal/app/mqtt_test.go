@@ -0,0 +1,51 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package app
+
+import (
+	"testing"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/relabs-tech/inertial_computer/internal/config"
+)
+
+// testConfigPath is the config package's test fixture; it leaves
+// TOPIC_STATUS_PREFIX and MQTT_KEEPALIVE unset, so the defaults apply.
+const testConfigPath = "../config/testdata/inertial_config.txt"
+
+func TestMQTTClientOptions(t *testing.T) {
+	if err := config.InitGlobal(testConfigPath); err != nil {
+		t.Fatalf("InitGlobal: %v", err)
+	}
+	opts := newMQTTClientOptions("inertial-test", &subscriptions{topics: make(map[string]subscription)})
+	r := mqtt.NewOptionsReader(opts)
+
+	if !r.WillEnabled() {
+		t.Fatal("last-will not set")
+	}
+	if got, want := r.WillTopic(), defaultStatusTopicPrefix+"/inertial-test"; got != want {
+		t.Errorf("WillTopic = %q, want %q", got, want)
+	}
+	if got := string(r.WillPayload()); got != statusOffline {
+		t.Errorf("WillPayload = %q, want %q", got, statusOffline)
+	}
+	if !r.WillRetained() {
+		t.Error("WillRetained = false, want true")
+	}
+	if r.WillQos() != 1 {
+		t.Errorf("WillQos = %d, want 1", r.WillQos())
+	}
+
+	if !r.AutoReconnect() || !r.CleanSession() {
+		t.Errorf("AutoReconnect = %t, CleanSession = %t, want both true", r.AutoReconnect(), r.CleanSession())
+	}
+	if r.KeepAlive() != defaultMQTTKeepAlive {
+		t.Errorf("KeepAlive = %v, want %v", r.KeepAlive(), defaultMQTTKeepAlive)
+	}
+	if got := r.ClientID(); got != "inertial-test" {
+		t.Errorf("ClientID = %q, want inertial-test", got)
+	}
+}
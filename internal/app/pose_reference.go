@@ -0,0 +1,152 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
+	"github.com/relabs-tech/inertial_computer/internal/orientation"
+)
+
+// Level reference ("zero") of the published poses. The references live in
+// imu_producer, one orientation.Reference per IMU; the web server sends
+// commands to TOPIC_POSE_REFERENCE + "/set", and the producer publishes the
+// active references, retained, to TOPIC_POSE_REFERENCE. The retained state
+// also restores the references when the producer restarts.
+
+// Pose reference actions (POST /api/orientation/reference, /ws/orientation).
+const (
+	poseReferenceCapture = "capture" // current pose becomes the zero
+	poseReferenceClear   = "clear"   // back to absolute poses
+)
+
+// poseReferenceCommand is the payload of the command topic.
+type poseReferenceCommand struct {
+	Action string `json:"action"`
+}
+
+// poseReferenceState is the retained payload of TOPIC_POSE_REFERENCE; an
+// empty payload means no reference.
+type poseReferenceState struct {
+	Left  *orientation.Pose `json:"left,omitempty"`
+	Right *orientation.Pose `json:"right,omitempty"`
+}
+
+// poseReferenceCommandTopic returns the command topic for a
+// TOPIC_POSE_REFERENCE value.
+func poseReferenceCommandTopic(stateTopic string) string {
+	return stateTopic + "/set"
+}
+
+// validPoseReferenceAction reports an error for unknown actions.
+func validPoseReferenceAction(action string) error {
+	switch action {
+	case poseReferenceCapture, poseReferenceClear:
+		return nil
+	}
+	return fmt.Errorf("invalid action %q (must be '%s' or '%s')", action, poseReferenceCapture, poseReferenceClear)
+}
+
+// poseReferences holds the producer's per-IMU references and keeps the
+// retained state topic in sync with them.
+type poseReferences struct {
+	client mqtt.Client
+	topic  string
+	left   orientation.Reference
+	right  orientation.Reference
+}
+
+// subscribePoseReferences restores the references from the retained state on
+// topic and starts handling commands.
+func subscribePoseReferences(client mqtt.Client, topic string) (*poseReferences, error) {
+	refs := &poseReferences{client: client, topic: topic}
+
+	// Retained state; the producer's own publishes echo back here too, which
+	// sets the same values again
+	token := client.Subscribe(topic, 1, func(_ mqtt.Client, msg mqtt.Message) {
+		if len(msg.Payload()) == 0 {
+			refs.left.Clear()
+			refs.right.Clear()
+			return
+		}
+		var st poseReferenceState
+		if err := json.Unmarshal(msg.Payload(), &st); err != nil {
+			logging.Warnf("pose reference: invalid state on %s: %v", topic, err)
+			return
+		}
+		if st.Left != nil {
+			refs.left.Set(*st.Left)
+		}
+		if st.Right != nil {
+			refs.right.Set(*st.Right)
+		}
+	})
+	if token.Wait(); token.Error() != nil {
+		return nil, token.Error()
+	}
+
+	cmdTopic := poseReferenceCommandTopic(topic)
+	token = client.Subscribe(cmdTopic, 1, func(_ mqtt.Client, msg mqtt.Message) {
+		var cmd poseReferenceCommand
+		if err := json.Unmarshal(msg.Payload(), &cmd); err != nil {
+			logging.Warnf("pose reference: invalid command: %v", err)
+			return
+		}
+		switch cmd.Action {
+		case poseReferenceCapture:
+			refs.left.Capture()
+			refs.right.Capture()
+			logging.Infof("pose reference: capturing the current pose as level")
+		case poseReferenceClear:
+			refs.left.Clear()
+			refs.right.Clear()
+			// Not on the callback goroutine: waiting for a publish there can
+			// block message delivery
+			go refs.publish()
+			logging.Infof("pose reference: cleared")
+		default:
+			logging.Warnf("pose reference: %v", validPoseReferenceAction(cmd.Action))
+		}
+	})
+	if token.Wait(); token.Error() != nil {
+		return nil, token.Error()
+	}
+	logging.Infof("pose reference: state on %s, commands on %s", topic, cmdTopic)
+	return refs, nil
+}
+
+// apply levels a pose of one IMU and publishes the state after a capture.
+func (r *poseReferences) apply(ref *orientation.Reference, p orientation.Pose) orientation.Pose {
+	out, captured := ref.Apply(p)
+	if captured {
+		r.publish()
+	}
+	return out
+}
+
+// publish sends the current references, retained (empty = none).
+func (r *poseReferences) publish() {
+	var st poseReferenceState
+	if p, ok := r.left.Get(); ok {
+		st.Left = &p
+	}
+	if p, ok := r.right.Get(); ok {
+		st.Right = &p
+	}
+	var payload []byte
+	if st.Left != nil || st.Right != nil {
+		var err error
+		if payload, err = json.Marshal(st); err != nil {
+			logging.Errorf("pose reference marshal error: %v", err)
+			return
+		}
+	}
+	if token := r.client.Publish(r.topic, 1, true, payload); token.Wait() && token.Error() != nil {
+		logging.Errorf("MQTT publish error (%s): %v", r.topic, token.Error())
+	}
+}
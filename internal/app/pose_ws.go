@@ -0,0 +1,145 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
+)
+
+const (
+	// wsPingInterval is how often a WebSocket (orientation, calibration,
+	// register debug) is pinged; a client that does not answer within
+	// wsPongWait is dropped.
+	wsPingInterval = 15 * time.Second
+	wsPongWait     = 2 * wsPingInterval
+	wsWriteWait    = 5 * time.Second
+)
+
+// keepAlive pings conn every wsPingInterval until the returned stop func is
+// called, and keeps the read deadline wsPongWait past the last pong. It is for
+// request/response sessions whose handler loop reads the connection itself:
+// pongs are only processed while reading, so the loop must also call
+// extendReadDeadline after each (possibly long) request it handles.
+func keepAlive(conn *websocket.Conn) (stop func()) {
+	extendReadDeadline(conn)
+	conn.SetPongHandler(func(string) error {
+		return extendReadDeadline(conn)
+	})
+	done := make(chan struct{})
+	go func() {
+		ping := time.NewTicker(wsPingInterval)
+		defer ping.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ping.C:
+				// WriteControl may run concurrently with the session's writes
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+					return
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+func extendReadDeadline(conn *websocket.Conn) error {
+	return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+}
+
+// isPongTimeout reports whether a read failed because the client stopped
+// answering pings; closePongTimeout then ends the session with a close frame.
+func isPongTimeout(err error) bool {
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+func closePongTimeout(conn *websocket.Conn) {
+	msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "ping timeout")
+	conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteWait))
+}
+
+// serveWS pushes pose updates over a WebSocket until the client disconnects,
+// one JSON poseEvent ({"source", "pose"}) per message. ?source= restricts the
+// stream to "left", "right" or "fused", as for serveSSE. The client may send
+// {"action": "capture"} or {"action": "clear"} to set or clear the level
+// reference (see /api/orientation/reference); other messages are ignored.
+func (b *poseBroadcaster) serveWS(w http.ResponseWriter, r *http.Request) {
+	filter := r.URL.Query().Get("source")
+	switch filter {
+	case "", "left", "right", "fused":
+	default:
+		http.Error(w, "invalid source (must be 'left', 'right' or 'fused')", http.StatusBadRequest)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logging.Warnf("web: orientation websocket upgrade error: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	c := b.subscribe()
+	defer b.unsubscribe(c)
+
+	// Reader: handles pongs, close frames and reference actions, and ends the
+	// stream when the client goes away
+	done := make(chan struct{})
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+	go func() {
+		defer close(done)
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var cmd poseReferenceCommand
+			if json.Unmarshal(msg, &cmd) != nil || cmd.Action == "" || b.referenceAction == nil {
+				continue
+			}
+			if err := b.referenceAction(cmd.Action); err != nil {
+				logging.Warnf("web: orientation websocket action %q: %v", cmd.Action, err)
+			} else {
+				logging.Infof("web: pose reference %s requested", cmd.Action)
+			}
+		}
+	}()
+
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+		case <-c.notify:
+			for _, ev := range c.take() {
+				if filter != "" && ev.Source != filter {
+					continue
+				}
+				conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+				if err := conn.WriteJSON(ev); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
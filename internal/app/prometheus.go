@@ -0,0 +1,80 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package app
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// promSample is one sample of a metric family. labels are name/value pairs.
+type promSample struct {
+	labels []string
+	value  float64
+}
+
+// promWriter builds a Prometheus text exposition (format version 0.0.4)
+// without pulling in the client library.
+type promWriter struct {
+	b strings.Builder
+}
+
+// family writes the HELP/TYPE header and samples of one metric family.
+// Families without samples are omitted.
+func (p *promWriter) family(name, typ, help string, samples ...promSample) {
+	if len(samples) == 0 {
+		return
+	}
+	fmt.Fprintf(&p.b, "# HELP %s %s\n", name, strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help))
+	fmt.Fprintf(&p.b, "# TYPE %s %s\n", name, typ)
+	for _, s := range samples {
+		p.b.WriteString(name)
+		if len(s.labels) > 0 {
+			p.b.WriteByte('{')
+			for i := 0; i+1 < len(s.labels); i += 2 {
+				if i > 0 {
+					p.b.WriteByte(',')
+				}
+				fmt.Fprintf(&p.b, "%s=\"%s\"", s.labels[i], promEscapeLabel(s.labels[i+1]))
+			}
+			p.b.WriteByte('}')
+		}
+		p.b.WriteByte(' ')
+		p.b.WriteString(promFormatValue(s.value))
+		p.b.WriteByte('\n')
+	}
+}
+
+// String returns the exposition text.
+func (p *promWriter) String() string {
+	return p.b.String()
+}
+
+func promEscapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+func promFormatValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+// promBool converts a flag to a 0/1 gauge value.
+func promBool(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
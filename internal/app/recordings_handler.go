@@ -0,0 +1,187 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/relabs-tech/inertial_computer/internal/config"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
+	"github.com/relabs-tech/inertial_computer/internal/recording"
+)
+
+// recordingSuffix is the extension of session files listed by the API.
+const recordingSuffix = ".jsonl"
+
+// RecordingInfo is the metadata of one recorded session.
+type RecordingInfo struct {
+	Name       string  `json:"name"`
+	SizeBytes  int64   `json:"size_bytes"`
+	Modified   string  `json:"modified"`              // RFC3339
+	Start      string  `json:"start,omitempty"`       // first record, RFC3339
+	DurationS  float64 `json:"duration_s"`            // first to last record
+	Records    int     `json:"records"`               // sample count
+	ParseError string  `json:"parse_error,omitempty"` // file is not a valid session
+}
+
+// recordingSummaries caches session summaries by name; an entry is reused
+// while the file's size and modification time are unchanged.
+var recordingSummaries = struct {
+	sync.Mutex
+	m map[string]cachedSummary
+}{m: make(map[string]cachedSummary)}
+
+type cachedSummary struct {
+	size    int64
+	modTime time.Time
+	summary recording.Summary
+	err     error
+}
+
+// recordingsDir returns RECORDINGS_DIR, or the working directory where
+// cmd/logger writes by default.
+func recordingsDir() string {
+	if dir := config.Get().RecordingsDir; dir != "" {
+		return dir
+	}
+	return "."
+}
+
+// HandleRecordingsList lists the sessions in RECORDINGS_DIR, newest first
+// (GET /api/recordings).
+func HandleRecordingsList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	root, err := os.OpenRoot(recordingsDir())
+	if err != nil {
+		logging.Errorf("recordings: open dir: %v", err)
+		http.Error(w, "recordings directory unavailable", http.StatusInternalServerError)
+		return
+	}
+	defer root.Close()
+
+	entries, err := fs.ReadDir(root.FS(), ".")
+	if err != nil {
+		logging.Errorf("recordings: list dir: %v", err)
+		http.Error(w, "recordings listing failed", http.StatusInternalServerError)
+		return
+	}
+
+	list := make([]RecordingInfo, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), recordingSuffix) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		list = append(list, recordingInfo(root, info))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Modified > list[j].Modified })
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(list); err != nil {
+		logging.Errorf("recordings: JSON encode error: %v", err)
+	}
+}
+
+// recordingInfo builds the metadata of a session file, summarizing it unless
+// a cached summary is still valid.
+func recordingInfo(root *os.Root, info fs.FileInfo) RecordingInfo {
+	name := info.Name()
+	ri := RecordingInfo{
+		Name:      name,
+		SizeBytes: info.Size(),
+		Modified:  info.ModTime().UTC().Format(time.RFC3339),
+	}
+
+	recordingSummaries.Lock()
+	c, ok := recordingSummaries.m[name]
+	recordingSummaries.Unlock()
+	if !ok || c.size != info.Size() || !c.modTime.Equal(info.ModTime()) {
+		c = cachedSummary{size: info.Size(), modTime: info.ModTime()}
+		f, err := root.Open(name)
+		if err != nil {
+			c.err = err
+		} else {
+			c.summary, c.err = recording.Summarize(f)
+			f.Close()
+		}
+		recordingSummaries.Lock()
+		recordingSummaries.m[name] = c
+		recordingSummaries.Unlock()
+	}
+
+	if c.err != nil {
+		ri.ParseError = c.err.Error()
+	}
+	ri.Records = c.summary.Records
+	if c.summary.Records > 0 {
+		ri.Start = c.summary.Start.UTC().Format(time.RFC3339Nano)
+		ri.DurationS = c.summary.Duration().Seconds()
+	}
+	return ri
+}
+
+// HandleRecordingDownload serves one session file as an attachment
+// (GET /api/recordings/{name}). The name must be a plain .jsonl file name
+// inside RECORDINGS_DIR.
+func HandleRecordingDownload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/api/recordings/")
+	if name == "" || name != filepath.Base(name) || !filepath.IsLocal(name) || !strings.HasSuffix(name, recordingSuffix) {
+		http.Error(w, "invalid recording name", http.StatusBadRequest)
+		return
+	}
+
+	// os.Root also rejects symlinks that lead out of the directory
+	root, err := os.OpenRoot(recordingsDir())
+	if err != nil {
+		logging.Errorf("recordings: open dir: %v", err)
+		http.Error(w, "recordings directory unavailable", http.StatusInternalServerError)
+		return
+	}
+	defer root.Close()
+
+	f, err := root.Open(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			http.Error(w, "recording not found", http.StatusNotFound)
+			return
+		}
+		logging.Errorf("recordings: open %s: %v", name, err)
+		http.Error(w, "recording read failed", http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		http.Error(w, "recording not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	http.ServeContent(w, r, name, info.ModTime(), f)
+}
@@ -5,22 +5,37 @@
 package app
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"math"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/relabs-tech/inertial_computer/internal/config"
 	"github.com/relabs-tech/inertial_computer/internal/imu"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
 	"github.com/relabs-tech/inertial_computer/internal/sensors"
 )
 
 // RegisterDebugSession holds WebSocket connection state for register debugging
 type RegisterDebugSession struct {
 	Conn *websocket.Conn
+
+	// Serializes writes: watchers send from their own goroutines
+	writeMu sync.Mutex
+
+	// Register watchers (see register_watch.go), all cancelled with ctx when
+	// the connection ends
+	ctx     context.Context
+	watchMu sync.Mutex
+	watches map[registerWatchKey]context.CancelFunc
 }
 
 // WebSocket message types for register debugging
@@ -28,6 +43,7 @@ type RegisterReadCmd struct {
 	Action  string `json:"action"` // "read", "read_all"
 	IMU     string `json:"imu"`    // "left" or "right"
 	Address string `json:"addr,omitempty"`
+	Device  string `json:"device,omitempty"` // "mpu9250" (default) or "ak8963" (read via the I2C master); "read" only
 }
 
 type RegisterWriteCmd struct {
@@ -37,6 +53,15 @@ type RegisterWriteCmd struct {
 	Value   string `json:"value"`
 }
 
+type RegisterSetFieldCmd struct {
+	Action  string `json:"action"` // "set_field"
+	IMU     string `json:"imu"`
+	Device  string `json:"device,omitempty"` // only "mpu9250" is writable
+	Address string `json:"addr"`
+	Field   string `json:"field"` // bitfield name from the register map, e.g. "GYRO_FS_SEL"
+	Value   string `json:"value"` // field value (decimal or 0x hex), not the whole byte
+}
+
 type RegisterInitCmd struct {
 	Action string `json:"action"` // "init"
 	IMU    string `json:"imu"`
@@ -54,19 +79,44 @@ type RegisterExportCmd struct {
 	IMU    string `json:"imu"`
 }
 
+type RegisterImportCmd struct {
+	Action string             `json:"action"` // "import_config"
+	IMU    string             `json:"imu"`    // target IMU (defaults to the IMU in the file)
+	Config RegisterConfigFile `json:"config"` // previously exported config (object or JSON string)
+}
+
+// RegisterImportResult reports the outcome of restoring one register
+type RegisterImportResult struct {
+	Address string `json:"addr"`
+	Value   string `json:"value"`
+	Status  string `json:"status"` // "written", "skipped", "failed"
+	Message string `json:"message,omitempty"`
+}
+
 // Response types
 type RegisterResponse struct {
-	Type        string            `json:"type"` // "register_data", "register_map", "status", "error"
-	IMU         string            `json:"imu,omitempty"`
-	Address     string            `json:"addr,omitempty"`
-	Value       string            `json:"value,omitempty"`
-	Registers   map[string]string `json:"registers,omitempty"` // for bulk read
-	Timestamp   string            `json:"timestamp,omitempty"`
-	Message     string            `json:"message,omitempty"`
-	Status      string            `json:"status,omitempty"`
-	ReadSpeed   int64             `json:"read_speed,omitempty"`
-	WriteSpeed  int64             `json:"write_speed,omitempty"`
-	RegisterMap []RegisterInfo    `json:"register_map,omitempty"`
+	Type        string                   `json:"type"` // "register_data", "register_watch", "register_map", "status", "error"
+	IMU         string                   `json:"imu,omitempty"`
+	Address     string                   `json:"addr,omitempty"`
+	Device      string                   `json:"device,omitempty"` // register_watch: "mpu9250" or "ak8963"
+	Value       string                   `json:"value,omitempty"`
+	Registers   map[string]string        `json:"registers,omitempty"` // for bulk read
+	Timestamp   string                   `json:"timestamp,omitempty"`
+	Message     string                   `json:"message,omitempty"`
+	Status      string                   `json:"status,omitempty"`
+	ReadSpeed   int64                    `json:"read_speed,omitempty"`
+	WriteSpeed  int64                    `json:"write_speed,omitempty"`
+	RegisterMap []RegisterInfo           `json:"register_map,omitempty"`
+	WhoAmI      string                   `json:"who_am_i,omitempty"` // MPU9250 WHO_AM_I (expected 0x71)
+	MagWIA      string                   `json:"mag_wia,omitempty"`  // AK8963 WIA (expected 0x48)
+	MagReady    bool                     `json:"mag_ready,omitempty"`
+	MagMode     string                   `json:"mag_mode,omitempty"`       // AK8963 CNTL1 MODE applied at init
+	MagBits     int                      `json:"mag_resolution,omitempty"` // 14 or 16
+	Decoded     *sensors.DecodedRegister `json:"decoded,omitempty"`        // bitfield breakdown for single reads
+	Results     []RegisterImportResult   `json:"results,omitempty"`        // per-register outcome of import_config
+	SelfTest    *sensors.SelfTestResult  `json:"self_test,omitempty"`
+	FIFO        []imu.IMURaw             `json:"fifo,omitempty"` // decoded samples of a FIFO dump
+	Power       *sensors.PowerState      `json:"power,omitempty"`
 }
 
 type RegisterInfo struct {
@@ -90,16 +140,19 @@ type RegisterConfigFile struct {
 func HandleRegisterDebugWS(w http.ResponseWriter, r *http.Request) {
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		log.Printf("register_debug: websocket upgrade error: %v", err)
+		logging.Warnf("register_debug: websocket upgrade error: %v", err)
 		return
 	}
 	defer conn.Close()
+	defer keepAlive(conn)()
 
-	session := &RegisterDebugSession{Conn: conn}
+	ctx, stopWatches := context.WithCancel(r.Context())
+	defer stopWatches()
+	session := &RegisterDebugSession{Conn: conn, ctx: ctx}
 
 	// Send register map on connection
 	if err := session.sendRegisterMap(); err != nil {
-		log.Printf("register_debug: error sending register map: %v", err)
+		logging.Errorf("register_debug: error sending register map: %v", err)
 		return
 	}
 
@@ -107,9 +160,14 @@ func HandleRegisterDebugWS(w http.ResponseWriter, r *http.Request) {
 	for {
 		var rawMsg map[string]interface{}
 		err := conn.ReadJSON(&rawMsg)
+		if isPongTimeout(err) {
+			logging.Warnf("register_debug: client stopped answering pings, closing")
+			closePongTimeout(conn)
+			break
+		}
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				log.Printf("register_debug: websocket error: %v", err)
+				logging.Warnf("register_debug: websocket error: %v", err)
 			}
 			break
 		}
@@ -130,18 +188,43 @@ func HandleRegisterDebugWS(w http.ResponseWriter, r *http.Request) {
 			session.handleReadAll(rawMsg)
 		case "write":
 			session.handleWrite(rawMsg)
+		case "set_field":
+			session.handleSetField(rawMsg)
 		case "init":
 			session.handleInit(rawMsg)
 		case "set_spi_speed":
 			session.handleSetSPISpeed(rawMsg)
 		case "export_config":
 			session.handleExportConfig(rawMsg)
+		case "import_config":
+			session.handleImportConfig(rawMsg)
+		case "identify":
+			session.handleIdentify(rawMsg)
+		case "self_test":
+			session.handleSelfTest(rawMsg)
+		case "fifo":
+			session.handleFIFO(rawMsg)
+		case "power":
+			session.handlePower(rawMsg)
+		case "watch":
+			session.handleWatch(rawMsg)
+		case "unwatch":
+			session.handleUnwatch(rawMsg)
 		default:
 			session.sendError(fmt.Sprintf("unknown action: %s", action))
 		}
+		extendReadDeadline(conn)
 	}
 }
 
+// send writes a message to the client, giving up after wsWriteWait.
+func (s *RegisterDebugSession) send(v interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.Conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	return s.Conn.WriteJSON(v)
+}
+
 func (s *RegisterDebugSession) handleRead(rawMsg map[string]interface{}) {
 	imu, _ := rawMsg["imu"].(string)
 	addr, _ := rawMsg["addr"].(string)
@@ -158,9 +241,13 @@ func (s *RegisterDebugSession) handleRead(rawMsg map[string]interface{}) {
 		return
 	}
 
-	// Read register via IMU manager
-	mgr := sensors.GetIMUManager()
-	value, err := mgr.ReadRegister(imu, addrByte)
+	// Read register via IMU manager, from the device whose map decodes it
+	device, err := readableDevice(rawMsg)
+	if err != nil {
+		s.sendError(err.Error())
+		return
+	}
+	value, err := readDeviceRegister(imu, device, addrByte)
 	if err != nil {
 		s.sendError(fmt.Sprintf("read error: %v", err))
 		return
@@ -174,7 +261,38 @@ func (s *RegisterDebugSession) handleRead(rawMsg map[string]interface{}) {
 		Value:     fmt.Sprintf("0x%02X", value),
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
-	s.Conn.WriteJSON(resp)
+
+	// Decode bitfields from the register map metadata
+	if decoded, err := sensors.DecodeRegister(device, addrByte, value); err != nil {
+		logging.Errorf("register_debug: decode 0x%02X: %v", addrByte, err)
+	} else {
+		resp.Decoded = &decoded
+		resp.Message = decoded.String()
+	}
+	s.send(resp)
+}
+
+// readableDevice returns the "device" of a read or watch command: "mpu9250"
+// (also when omitted) or "ak8963".
+func readableDevice(rawMsg map[string]interface{}) (string, error) {
+	device, _ := rawMsg["device"].(string)
+	switch device {
+	case "", sensors.DeviceMPU9250:
+		return sensors.DeviceMPU9250, nil
+	case sensors.DeviceAK8963:
+		return device, nil
+	}
+	return "", fmt.Errorf("unknown device: %s (must be '%s' or '%s')", device, sensors.DeviceMPU9250, sensors.DeviceAK8963)
+}
+
+// readDeviceRegister reads a register of the MPU9250 or, for "ak8963", of
+// its magnetometer through the I2C master.
+func readDeviceRegister(imu, device string, addr byte) (byte, error) {
+	mgr := sensors.GetIMUManager()
+	if device == sensors.DeviceAK8963 {
+		return mgr.ReadMagRegister(imu, addr)
+	}
+	return mgr.ReadRegister(imu, addr)
 }
 
 func (s *RegisterDebugSession) handleReadAll(rawMsg map[string]interface{}) {
@@ -205,7 +323,7 @@ func (s *RegisterDebugSession) handleReadAll(rawMsg map[string]interface{}) {
 		Registers: regMap,
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
-	s.Conn.WriteJSON(resp)
+	s.send(resp)
 }
 
 func (s *RegisterDebugSession) handleWrite(rawMsg map[string]interface{}) {
@@ -252,7 +370,64 @@ func (s *RegisterDebugSession) handleWrite(rawMsg map[string]interface{}) {
 		Timestamp: time.Now().Format(time.RFC3339),
 		Message:   "write successful",
 	}
-	s.Conn.WriteJSON(resp)
+	s.send(resp)
+}
+
+func (s *RegisterDebugSession) handleSetField(rawMsg map[string]interface{}) {
+	imu, _ := rawMsg["imu"].(string)
+	device, _ := rawMsg["device"].(string)
+	addr, _ := rawMsg["addr"].(string)
+	field, _ := rawMsg["field"].(string)
+	valueStr, _ := rawMsg["value"].(string)
+
+	if imu == "" || addr == "" || field == "" || valueStr == "" {
+		s.sendError("missing imu, addr, field, or value field")
+		return
+	}
+	if device != "" && device != sensors.DeviceMPU9250 {
+		s.sendError(fmt.Sprintf("set_field not supported for device %s (only %s)", device, sensors.DeviceMPU9250))
+		return
+	}
+
+	// Parse hex address and field value
+	var addrByte byte
+	if _, err := fmt.Sscanf(addr, "0x%X", &addrByte); err != nil {
+		s.sendError(fmt.Sprintf("invalid address format: %s", addr))
+		return
+	}
+	fieldValue, err := strconv.ParseUint(valueStr, 0, 8)
+	if err != nil {
+		s.sendError(fmt.Sprintf("invalid field value: %s", valueStr))
+		return
+	}
+
+	// Validate write range
+	cfg := config.Get()
+	if !isRegisterWritable(addrByte, cfg.RegisterDebugAllowedRanges) {
+		s.sendError(fmt.Sprintf("register 0x%02X not in allowed write ranges", addrByte))
+		return
+	}
+
+	// Read-modify-write via IMU manager
+	mgr := sensors.GetIMUManager()
+	oldValue, newValue, err := mgr.WriteRegisterField(imu, addrByte, field, fieldValue)
+	if err != nil {
+		s.sendError(fmt.Sprintf("set field error: %v", err))
+		return
+	}
+
+	resp := RegisterResponse{
+		Type:      "register_data",
+		IMU:       imu,
+		Address:   addr,
+		Value:     fmt.Sprintf("0x%02X", newValue),
+		Timestamp: time.Now().Format(time.RFC3339),
+		Message:   fmt.Sprintf("%s set to %d (0x%02X -> 0x%02X)", field, fieldValue, oldValue, newValue),
+	}
+	if decoded, err := sensors.DecodeRegister(sensors.DeviceMPU9250, addrByte, newValue); err == nil {
+		resp.Decoded = &decoded
+	}
+	s.send(resp)
 }
 
 func (s *RegisterDebugSession) handleInit(rawMsg map[string]interface{}) {
@@ -279,7 +454,176 @@ func (s *RegisterDebugSession) handleInit(rawMsg map[string]interface{}) {
 		WriteSpeed: writeSpeed,
 		Message:    "IMU reinitialized successfully",
 	}
-	s.Conn.WriteJSON(resp)
+	s.send(resp)
+}
+
+func (s *RegisterDebugSession) handleIdentify(rawMsg map[string]interface{}) {
+	imu, _ := rawMsg["imu"].(string)
+	if imu == "" {
+		s.sendError("missing imu field")
+		return
+	}
+
+	mgr := sensors.GetIMUManager()
+	whoAmI, magWIA, magReady, err := mgr.DeviceIDs(imu)
+	if err != nil {
+		s.sendError(fmt.Sprintf("identify error: %v", err))
+		return
+	}
+
+	resp := RegisterResponse{
+		Type:      "identity",
+		IMU:       imu,
+		WhoAmI:    fmt.Sprintf("0x%02X", whoAmI),
+		MagWIA:    fmt.Sprintf("0x%02X", magWIA),
+		MagReady:  magReady,
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	if magReady {
+		if mode, scale, err := mgr.MagSettings(imu); err == nil {
+			resp.MagMode = fmt.Sprintf("0x%02X", mode)
+			resp.MagBits = 14
+			if scale == 1 {
+				resp.MagBits = 16
+			}
+		}
+	}
+	s.send(resp)
+}
+
+func (s *RegisterDebugSession) handleSelfTest(rawMsg map[string]interface{}) {
+	imu, _ := rawMsg["imu"].(string)
+	if imu == "" {
+		s.sendError("missing imu field")
+		return
+	}
+
+	mgr := sensors.GetIMUManager()
+	result, err := mgr.RunSelfTest(imu)
+	if err != nil {
+		s.sendError(fmt.Sprintf("self-test error: %v", err))
+		return
+	}
+
+	status := "passed"
+	if !result.Pass {
+		status = "FAILED"
+	}
+	resp := RegisterResponse{
+		Type:      "self_test",
+		IMU:       imu,
+		Timestamp: time.Now().Format(time.RFC3339),
+		Message:   fmt.Sprintf("self-test %s (limit ±%.0f%%)", status, result.LimitPercent),
+		SelfTest:  &result,
+	}
+	s.send(resp)
+}
+
+// handleFIFO enables, disables, resets or dumps the FIFO of an IMU
+// ({"action":"fifo","imu","op":"enable|disable|reset|dump"}).
+func (s *RegisterDebugSession) handleFIFO(rawMsg map[string]interface{}) {
+	imu, _ := rawMsg["imu"].(string)
+	op, _ := rawMsg["op"].(string)
+	if imu == "" {
+		s.sendError("missing imu field")
+		return
+	}
+
+	mgr := sensors.GetIMUManager()
+	resp := RegisterResponse{
+		Type:      "fifo",
+		IMU:       imu,
+		Status:    op,
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	var err error
+	switch op {
+	case "enable":
+		err = mgr.EnableFIFO(imu, true)
+		resp.Message = "FIFO enabled (accel, temperature, gyro)"
+	case "disable":
+		err = mgr.EnableFIFO(imu, false)
+		resp.Message = "FIFO disabled"
+	case "reset":
+		err = mgr.ResetFIFO(imu)
+		resp.Message = "FIFO reset"
+	case "dump":
+		resp.FIFO, err = mgr.ReadFIFO(imu)
+		resp.Message = fmt.Sprintf("%d samples read from FIFO", len(resp.FIFO))
+	default:
+		s.sendError(fmt.Sprintf("unknown fifo op: %q (want enable, disable, reset or dump)", op))
+		return
+	}
+	if err != nil {
+		s.sendError(fmt.Sprintf("fifo %s error: %v", op, err))
+		return
+	}
+	logging.Infof("register_debug: %s %s", imu, resp.Message)
+	s.send(resp)
+}
+
+// handlePower changes or reports the power mode of an IMU
+// ({"action":"power","imu","op":"sleep|wake|cycle|axes|status"}, with
+// "odr_hz" for cycle and "axes" (PWR_MGMT_2 disable mask, hex) for axes).
+func (s *RegisterDebugSession) handlePower(rawMsg map[string]interface{}) {
+	imu, _ := rawMsg["imu"].(string)
+	op, _ := rawMsg["op"].(string)
+	if imu == "" {
+		s.sendError("missing imu field")
+		return
+	}
+
+	mgr := sensors.GetIMUManager()
+	var err error
+	switch op {
+	case "sleep":
+		err = mgr.Sleep(imu)
+	case "wake":
+		err = mgr.Wake(imu)
+	case "cycle":
+		odr, _ := rawMsg["odr_hz"].(float64)
+		err = mgr.SetCycleMode(imu, odr)
+	case "axes":
+		axes, _ := rawMsg["axes"].(string)
+		var mask byte
+		if _, err := fmt.Sscanf(axes, "0x%X", &mask); err != nil {
+			s.sendError(fmt.Sprintf("invalid axes mask format: %q", axes))
+			return
+		}
+		err = mgr.SetDisabledAxes(imu, mask)
+	case "status":
+	default:
+		s.sendError(fmt.Sprintf("unknown power op: %q (want sleep, wake, cycle, axes or status)", op))
+		return
+	}
+	if err != nil {
+		s.sendError(fmt.Sprintf("power %s error: %v", op, err))
+		return
+	}
+
+	state, err := mgr.PowerState(imu)
+	if err != nil {
+		s.sendError(fmt.Sprintf("power state error: %v", err))
+		return
+	}
+	msg := state.Mode
+	if state.Mode == sensors.PowerCycle {
+		msg += fmt.Sprintf(" at %.2f Hz", state.CycleODRHz)
+	}
+	if state.DisabledAxes != 0 {
+		msg += fmt.Sprintf(", disabled axes 0x%02X", state.DisabledAxes)
+	}
+	if op != "status" {
+		logging.Infof("register_debug: %s IMU power %s: %s", imu, op, msg)
+	}
+	s.send(RegisterResponse{
+		Type:      "power",
+		IMU:       imu,
+		Status:    op,
+		Message:   msg,
+		Timestamp: time.Now().Format(time.RFC3339),
+		Power:     &state,
+	})
 }
 
 func (s *RegisterDebugSession) handleSetSPISpeed(rawMsg map[string]interface{}) {
@@ -311,22 +655,40 @@ func (s *RegisterDebugSession) handleSetSPISpeed(rawMsg map[string]interface{})
 		writeSpeedInt = cfg.RegisterDebugMaxSPISpeed
 	}
 
-	// Set SPI speeds
+	// Set SPI speeds (the manager clamps them to the MPU9250 register limit)
 	mgr := sensors.GetIMUManager()
-	if err := mgr.SetSPISpeed(imu, readSpeedInt, writeSpeedInt); err != nil {
+	effRead, effWrite, err := mgr.SetSPISpeed(imu, readSpeedInt, writeSpeedInt)
+	if err != nil && !errors.Is(err, sensors.ErrSPISpeedUnsupported) {
 		s.sendError(fmt.Sprintf("set spi speed error: %v", err))
 		return
 	}
 
-	// Send confirmation
+	var notes []string
+	if effRead != readSpeedInt || effWrite != writeSpeedInt {
+		notes = append(notes, fmt.Sprintf("SPI speeds clamped to the MPU9250 register limit of %d Hz", sensors.MPU9250SPIRegisterMaxHz))
+	}
+	if err != nil {
+		notes = append(notes, err.Error())
+	}
+	message := "SPI speeds updated"
+	if len(notes) > 0 {
+		message = strings.Join(notes, "; ")
+	}
+
+	// Send confirmation with the speeds now in use
+	curRead, curWrite, err := mgr.GetSPISpeed(imu)
+	if err != nil {
+		s.sendError(fmt.Sprintf("get spi speed error: %v", err))
+		return
+	}
 	resp := RegisterResponse{
 		Type:       "status",
 		IMU:        imu,
-		ReadSpeed:  readSpeedInt,
-		WriteSpeed: writeSpeedInt,
-		Message:    "SPI speeds updated",
+		ReadSpeed:  curRead,
+		WriteSpeed: curWrite,
+		Message:    message,
 	}
-	s.Conn.WriteJSON(resp)
+	s.send(resp)
 }
 
 func (s *RegisterDebugSession) handleExportConfig(rawMsg map[string]interface{}) {
@@ -367,7 +729,110 @@ func (s *RegisterDebugSession) handleExportConfig(rawMsg map[string]interface{})
 		"config":   string(configJSON),
 		"filename": fmt.Sprintf("%s_%s_registers.json", imu, time.Now().Format("20060102_150405")),
 	}
-	s.Conn.WriteJSON(rawResp)
+	s.send(rawResp)
+}
+
+func (s *RegisterDebugSession) handleImportConfig(rawMsg map[string]interface{}) {
+	// The config may arrive as an object or as the JSON string produced by export_config
+	var configFile RegisterConfigFile
+	var configJSON []byte
+	switch c := rawMsg["config"].(type) {
+	case string:
+		configJSON = []byte(c)
+	case map[string]interface{}:
+		configJSON, _ = json.Marshal(c)
+	default:
+		s.sendError("missing or invalid config field")
+		return
+	}
+	if err := json.Unmarshal(configJSON, &configFile); err != nil {
+		s.sendError(fmt.Sprintf("invalid config: %v", err))
+		return
+	}
+	if len(configFile.Registers) == 0 {
+		s.sendError("config contains no registers")
+		return
+	}
+
+	imu, _ := rawMsg["imu"].(string)
+	if imu == "" {
+		imu = configFile.IMU
+	}
+	if imu == "" {
+		s.sendError("missing imu field")
+		return
+	}
+
+	// Index the register map so read-only and unknown registers can be skipped
+	mgr := sensors.GetIMUManager()
+	regInfo := make(map[byte]sensors.RegisterInfo)
+	for _, r := range mgr.GetRegisterMap() {
+		var a byte
+		if _, err := fmt.Sscanf(r.Address, "0x%X", &a); err == nil {
+			if _, dup := regInfo[a]; !dup {
+				regInfo[a] = r
+			}
+		}
+	}
+
+	// Apply in address order so results are deterministic
+	addrs := make([]string, 0, len(configFile.Registers))
+	for addr := range configFile.Registers {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+
+	cfg := config.Get()
+	results := make([]RegisterImportResult, 0, len(addrs))
+	var written, skipped, failed int
+	for _, addr := range addrs {
+		valueStr := configFile.Registers[addr]
+		result := RegisterImportResult{Address: addr, Value: valueStr}
+
+		var addrByte, valueByte byte
+		if _, err := fmt.Sscanf(addr, "0x%X", &addrByte); err != nil {
+			result.Status, result.Message = "failed", "invalid address format"
+		} else if _, err := fmt.Sscanf(valueStr, "0x%X", &valueByte); err != nil {
+			result.Status, result.Message = "failed", "invalid value format"
+		} else if port, isPort := sensors.MPU9250DataPort(addrByte); isPort {
+			result.Status, result.Message = "skipped", fmt.Sprintf("%s is a data port, not configuration", port)
+		} else if info, known := regInfo[addrByte]; !known {
+			result.Status, result.Message = "skipped", "not in register map"
+		} else if info.Access == "R" {
+			result.Status, result.Message = "skipped", fmt.Sprintf("%s is read-only", info.Name)
+		} else if !isRegisterWritable(addrByte, cfg.RegisterDebugAllowedRanges) {
+			result.Status, result.Message = "skipped", "not in allowed write ranges"
+		} else if err := mgr.WriteRegister(imu, addrByte, valueByte); err != nil {
+			result.Status, result.Message = "failed", err.Error()
+		} else {
+			result.Status = "written"
+		}
+
+		switch result.Status {
+		case "written":
+			written++
+		case "skipped":
+			skipped++
+		default:
+			failed++
+		}
+		results = append(results, result)
+	}
+
+	logf := logging.Infof
+	if failed > 0 {
+		logf = logging.Warnf
+	}
+	logf("register_debug: import_config to %s IMU: %d written, %d skipped, %d failed", imu, written, skipped, failed)
+
+	resp := RegisterResponse{
+		Type:      "import_result",
+		IMU:       imu,
+		Timestamp: time.Now().Format(time.RFC3339),
+		Message:   fmt.Sprintf("config imported: %d written, %d skipped, %d failed", written, skipped, failed),
+		Results:   results,
+	}
+	s.send(resp)
 }
 
 func (s *RegisterDebugSession) sendRegisterMap() error {
@@ -391,7 +856,7 @@ func (s *RegisterDebugSession) sendRegisterMap() error {
 		Type:        "register_map",
 		RegisterMap: mappedRegs,
 	}
-	return s.Conn.WriteJSON(resp)
+	return s.send(resp)
 }
 
 func (s *RegisterDebugSession) sendError(message string) {
@@ -399,11 +864,13 @@ func (s *RegisterDebugSession) sendError(message string) {
 		Type:    "error",
 		Message: message,
 	}
-	s.Conn.WriteJSON(resp)
+	s.send(resp)
 }
 
 // HandleIMUData serves live IMU data via REST API
 // Query parameter: ?imu=left or ?imu=right (defaults to left)
+// With ?corrected=true the response is {"raw", "corrected", "calibration",
+// "active"}, each sample in the same format as the plain response.
 func HandleIMUData(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -412,40 +879,64 @@ func HandleIMUData(w http.ResponseWriter, r *http.Request) {
 	if imuID == "" {
 		imuID = "left"
 	}
+	if imuID != "left" && imuID != "right" {
+		http.Error(w, `{"error": "invalid imu parameter, use 'left' or 'right'"}`, http.StatusBadRequest)
+		return
+	}
 
 	mgr := sensors.GetIMUManager()
 
-	var raw interface{}
-	var err error
+	// ?corrected=true: raw and calibration-corrected samples side by side,
+	// using the active calibration or else the latest stored one
+	if r.URL.Query().Get("corrected") == "true" {
+		cal := mgr.Calibration(imuID)
+		if cal == nil {
+			loaded, status, err := loadCalibration(imuID, "")
+			if err != nil {
+				http.Error(w, fmt.Sprintf(`{"error": %q}`, err.Error()), status)
+				return
+			}
+			cal = &loaded
+		}
 
-	if imuID == "left" {
-		raw, err = mgr.ReadLeftIMU()
-	} else if imuID == "right" {
-		raw, err = mgr.ReadRightIMU()
-	} else {
-		http.Error(w, `{"error": "invalid imu parameter, use 'left' or 'right'"}`, http.StatusBadRequest)
+		raw, err := mgr.ReadIMUUncalibrated(imuID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf(`{"error": "%v"}`, err), http.StatusInternalServerError)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"raw":         imuDataResponse(raw),
+			"corrected":   imuDataResponse(cal.Apply(raw)),
+			"calibration": cal.Source,
+			"active":      mgr.Calibration(imuID) != nil,
+		})
 		return
 	}
 
+	var imuRaw imu.IMURaw
+	var err error
+	if imuID == "left" {
+		imuRaw, err = mgr.ReadLeftIMU()
+	} else {
+		imuRaw, err = mgr.ReadRightIMU()
+	}
 	if err != nil {
 		http.Error(w, fmt.Sprintf(`{"error": "%v"}`, err), http.StatusInternalServerError)
 		return
 	}
 
-	// Format the response for the JavaScript UI
-	imuRaw, ok := raw.(imu.IMURaw)
-	if !ok {
-		// Fallback if not IMURaw type
-		json.NewEncoder(w).Encode(raw)
-		return
-	}
+	json.NewEncoder(w).Encode(imuDataResponse(imuRaw))
+}
 
+// imuDataResponse formats a sample for the register debug UI.
+func imuDataResponse(imuRaw imu.IMURaw) map[string]interface{} {
 	// Calculate magnetometer magnitude
 	magMag := math.Sqrt(float64(imuRaw.Mx)*float64(imuRaw.Mx) +
 		float64(imuRaw.My)*float64(imuRaw.My) +
 		float64(imuRaw.Mz)*float64(imuRaw.Mz))
 
-	response := map[string]interface{}{
+	return map[string]interface{}{
 		"accel": map[string]int16{
 			"x": imuRaw.Ax,
 			"y": imuRaw.Ay,
@@ -461,20 +952,24 @@ func HandleIMUData(w http.ResponseWriter, r *http.Request) {
 			"y":         imuRaw.My,
 			"z":         imuRaw.Mz,
 			"magnitude": magMag,
+			"valid":     imuRaw.MagValid,
+			"overflow":  imuRaw.MagOverflow,
 		},
 	}
-
-	json.NewEncoder(w).Encode(response)
 }
 
-// isRegisterWritable checks if a register address is in the allowed write ranges
+// isRegisterWritable checks if a register address is in the allowed write
+// ranges ("0x1B-0x1D,0x6B"). An empty or unparsable setting allows nothing.
 func isRegisterWritable(addr byte, allowedRanges string) bool {
-	if allowedRanges == "" {
-		return false // Empty means no writes allowed by default
+	ranges, err := config.ParseRegisterRanges(allowedRanges)
+	if err != nil {
+		logging.Warnf("register_debug: ignoring REGISTER_DEBUG_ALLOWED_RANGES: %v", err)
+		return false
 	}
-
-	// Parse ranges like "0x1B-0x1D,0x6B,0x1A-0x20"
-	// For simplicity, if configured, allow the write
-	// TODO: implement proper range parsing
-	return true
+	for _, r := range ranges {
+		if addr >= r.Lo && addr <= r.Hi {
+			return true
+		}
+	}
+	return false
 }
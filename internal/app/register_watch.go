@@ -0,0 +1,187 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/relabs-tech/inertial_computer/internal/logging"
+	"github.com/relabs-tech/inertial_computer/internal/sensors"
+)
+
+const (
+	// Poll interval of a watched register: default, and the fastest allowed
+	// so a few watches can't saturate the SPI bus or the WebSocket
+	registerWatchDefaultInterval = 500 * time.Millisecond
+	registerWatchMinInterval     = 50 * time.Millisecond
+
+	// registerWatchMax caps the watched registers per session.
+	registerWatchMax = 8
+)
+
+// registerWatchKey identifies a watched register.
+type registerWatchKey struct {
+	imu    string
+	device string // sensors.DeviceMPU9250 or sensors.DeviceAK8963
+	addr   byte
+}
+
+// handleWatch starts polling a register ({"action":"watch","imu","addr",
+// "interval_ms","device"}) and streams a "register_watch" message per read
+// until unwatch or disconnect. Watching an already watched register restarts
+// it with the new interval.
+func (s *RegisterDebugSession) handleWatch(rawMsg map[string]interface{}) {
+	imu, _ := rawMsg["imu"].(string)
+	addr, _ := rawMsg["addr"].(string)
+	if imu == "" || addr == "" {
+		s.sendError("missing imu or addr field")
+		return
+	}
+	device, err := readableDevice(rawMsg)
+	if err != nil {
+		s.sendError(err.Error())
+		return
+	}
+	var addrByte byte
+	if _, err := fmt.Sscanf(addr, "0x%X", &addrByte); err != nil {
+		s.sendError(fmt.Sprintf("invalid address format: %s", addr))
+		return
+	}
+	interval := registerWatchDefaultInterval
+	if ms, ok := rawMsg["interval_ms"].(float64); ok && ms > 0 {
+		interval = time.Duration(ms) * time.Millisecond
+	}
+	if interval < registerWatchMinInterval {
+		interval = registerWatchMinInterval
+	}
+
+	key := registerWatchKey{imu, device, addrByte}
+	s.watchMu.Lock()
+	if cancel, ok := s.watches[key]; ok {
+		cancel()
+		delete(s.watches, key)
+	}
+	if len(s.watches) >= registerWatchMax {
+		s.watchMu.Unlock()
+		s.sendError(fmt.Sprintf("at most %d registers can be watched; unwatch one first", registerWatchMax))
+		return
+	}
+	if s.watches == nil {
+		s.watches = map[registerWatchKey]context.CancelFunc{}
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.watches[key] = cancel
+	s.watchMu.Unlock()
+
+	logging.Infof("register_debug: watching %s %s 0x%02X every %v", imu, device, addrByte, interval)
+	s.send(RegisterResponse{
+		Type:    "status",
+		IMU:     imu,
+		Address: addr,
+		Device:  device,
+		Status:  "watching",
+		Message: fmt.Sprintf("polling every %d ms", interval.Milliseconds()),
+	})
+	go s.watch(ctx, key, addr, interval)
+}
+
+// watch polls one register until ctx is cancelled or a read fails.
+func (s *RegisterDebugSession) watch(ctx context.Context, key registerWatchKey, addr string, interval time.Duration) {
+	defer s.stopWatch(ctx, key)
+
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	for {
+		value, err := readDeviceRegister(key.imu, key.device, key.addr)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			s.sendError(fmt.Sprintf("watch %s %s %s stopped: read error: %v", key.imu, key.device, addr, err))
+			return
+		}
+		resp := RegisterResponse{
+			Type:      "register_watch",
+			IMU:       key.imu,
+			Address:   addr,
+			Device:    key.device,
+			Value:     fmt.Sprintf("0x%02X", value),
+			Timestamp: time.Now().Format(time.RFC3339Nano),
+		}
+		if decoded, err := sensors.DecodeRegister(key.device, key.addr, value); err == nil {
+			resp.Decoded = &decoded
+			resp.Message = decoded.String()
+		}
+		if s.send(resp) != nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+	}
+}
+
+// stopWatch forgets key if it is still the watch started with ctx (not a
+// newer one replacing it).
+func (s *RegisterDebugSession) stopWatch(ctx context.Context, key registerWatchKey) {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+	if cancel, ok := s.watches[key]; ok && ctx.Err() == nil {
+		cancel()
+		delete(s.watches, key)
+	}
+}
+
+// handleUnwatch stops watching a register ({"action":"unwatch","imu","addr",
+// "device"}; no device = both), every watched register of an IMU (no addr) or
+// all of them (no imu either).
+func (s *RegisterDebugSession) handleUnwatch(rawMsg map[string]interface{}) {
+	imu, _ := rawMsg["imu"].(string)
+	addr, _ := rawMsg["addr"].(string)
+	device := ""
+	if _, ok := rawMsg["device"]; ok {
+		d, err := readableDevice(rawMsg)
+		if err != nil {
+			s.sendError(err.Error())
+			return
+		}
+		device = d
+	}
+	var addrByte byte
+	if addr != "" {
+		if imu == "" {
+			s.sendError("addr needs an imu")
+			return
+		}
+		if _, err := fmt.Sscanf(addr, "0x%X", &addrByte); err != nil {
+			s.sendError(fmt.Sprintf("invalid address format: %s", addr))
+			return
+		}
+	}
+
+	s.watchMu.Lock()
+	stopped := 0
+	for key, cancel := range s.watches {
+		if (imu == "" || key.imu == imu) && (device == "" || key.device == device) && (addr == "" || key.addr == addrByte) {
+			cancel()
+			delete(s.watches, key)
+			stopped++
+		}
+	}
+	s.watchMu.Unlock()
+
+	s.send(RegisterResponse{
+		Type:    "status",
+		IMU:     imu,
+		Address: addr,
+		Status:  "unwatched",
+		Message: fmt.Sprintf("stopped %d watch(es)", stopped),
+	})
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/relabs-tech/inertial_computer/internal/config"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
+	"github.com/relabs-tech/inertial_computer/internal/recording"
+)
+
+const defaultReplayClientID = "inertial-replay"
+
+// ReplayOptions configures RunReplay.
+type ReplayOptions struct {
+	In       string            // recorded session (recording.Record JSONL)
+	Speed    float64           // playback rate multiplier (1 = original timing)
+	Loop     bool              // start over at the end of the session
+	Remap    map[string]string // recorded topic -> published topic, see replayTopic
+	Retain   bool              // publish with the retain flag
+	ClientID string            // MQTT client ID ("" = inertial-replay)
+}
+
+// RunReplay publishes the records of a recorded session back onto MQTT,
+// keeping the original gaps between them divided by opts.Speed, so the web
+// server, console and display can be developed and demoed without sensors.
+// Payloads are sent unchanged, so timestamps inside them are the recorded
+// ones. Records out of time order are published immediately.
+func RunReplay(ctx context.Context, opts ReplayOptions) error {
+	logging.Infof("starting inertial-computer replay of %s", opts.In)
+
+	if opts.Speed <= 0 {
+		return fmt.Errorf("speed must be > 0, got %g", opts.Speed)
+	}
+	clientID := opts.ClientID
+	if clientID == "" {
+		clientID = defaultReplayClientID
+	}
+	qos := config.Get().MQTTQoS
+
+	client, err := NewMQTTClient(clientID)
+	if err != nil {
+		return err
+	}
+	defer DisconnectMQTT(client)
+	logging.Infof("replay: connected to MQTT broker at %s (speed x%g, loop %t)", config.Get().MQTTBroker, opts.Speed, opts.Loop)
+
+	publish := func(rec recording.Record) error {
+		topic := replayTopic(rec.Topic, opts.Remap)
+		if topic == "" {
+			return nil
+		}
+		token := client.Publish(topic, qos, opts.Retain, []byte(rec.Payload))
+		token.Wait()
+		return token.Error()
+	}
+
+	for pass := 1; ; pass++ {
+		start := time.Now()
+		n, err := replayPass(ctx, opts.In, opts.Speed, publish)
+		if err != nil {
+			return err
+		}
+		logging.Infof("replay: pass %d: published %d records in %v", pass, n, time.Since(start).Round(time.Millisecond))
+		if !opts.Loop || n == 0 || ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// replayPass publishes every record of the session once, paced by the
+// recorded times. It returns the number of records published and nil when
+// ctx is cancelled.
+func replayPass(ctx context.Context, path string, speed float64, publish func(recording.Record) error) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open session: %w", err)
+	}
+	defer f.Close()
+
+	r := recording.NewReader(f)
+	var first time.Time
+	start := time.Now()
+	n := 0
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, fmt.Errorf("%s: %w", path, err)
+		}
+
+		if n == 0 {
+			first = rec.Time
+		}
+		due := start.Add(time.Duration(float64(rec.Time.Sub(first)) / speed))
+		if wait := time.Until(due); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return n, nil
+			case <-time.After(wait):
+			}
+		} else if ctx.Err() != nil {
+			return n, nil
+		}
+
+		if err := publish(rec); err != nil {
+			logging.Errorf("replay: MQTT publish error (%s): %v", rec.Topic, err)
+		}
+		n++
+	}
+}
+
+// replayTopic applies the -map remapping to a recorded topic: an exact
+// "from" match is replaced by "to", and a "from" ending in "/#" replaces that
+// prefix (inertial/# -> rig1/inertial/# moves every topic under rig1/). An
+// empty "to" drops the topic. Unmapped topics are published as recorded.
+func replayTopic(topic string, remap map[string]string) string {
+	if to, ok := remap[topic]; ok {
+		return to
+	}
+	matched, best := false, ""
+	for from := range remap {
+		prefix, ok := strings.CutSuffix(from, "#")
+		if ok && strings.HasPrefix(topic, prefix) && (!matched || len(prefix) > len(best)) {
+			matched, best = true, prefix
+		}
+	}
+	if !matched {
+		return topic
+	}
+	to := remap[best+"#"]
+	if to == "" {
+		return ""
+	}
+	return strings.TrimSuffix(to, "#") + strings.TrimPrefix(topic, best)
+}
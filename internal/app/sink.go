@@ -0,0 +1,213 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/relabs-tech/inertial_computer/internal/config"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
+	"github.com/relabs-tech/inertial_computer/internal/recording"
+)
+
+const (
+	defaultSinkClientID = "inertial-sink-router"
+	fileSinkFlushEvery  = time.Second
+)
+
+// Sink stores MQTT messages. Write is called from MQTT callbacks, so
+// implementations must be safe for concurrent use; Close flushes anything
+// buffered.
+type Sink interface {
+	Write(topic string, payload []byte, t time.Time) error
+	Close() error
+}
+
+// SinkFactory creates a sink from the argument after the colon of its SINKS
+// entry ("" if there is none).
+type SinkFactory func(arg string) (Sink, error)
+
+// sinkRegistry maps SINKS entry names to their factories.
+var sinkRegistry = map[string]SinkFactory{
+	"stdout": newStdoutSink,
+	"file":   newFileSink,
+	"influx": newInfluxSink,
+}
+
+// RegisterSink makes a sink available to SINKS under name.
+func RegisterSink(name string, factory SinkFactory) {
+	sinkRegistry[name] = factory
+}
+
+// OpenSinks creates the sinks for SINKS entries of the form "name" or
+// "name:arg". On error the sinks already opened are closed.
+func OpenSinks(specs []string) ([]Sink, error) {
+	var sinks []Sink
+	for _, spec := range specs {
+		name, arg, _ := strings.Cut(spec, ":")
+		factory, ok := sinkRegistry[name]
+		if !ok {
+			closeSinks(sinks)
+			return nil, fmt.Errorf("unknown sink %q (available: %s)", name, strings.Join(sinkNames(), ", "))
+		}
+		s, err := factory(arg)
+		if err != nil {
+			closeSinks(sinks)
+			return nil, fmt.Errorf("sink %q: %w", spec, err)
+		}
+		sinks = append(sinks, s)
+	}
+	return sinks, nil
+}
+
+func sinkNames() []string {
+	names := make([]string, 0, len(sinkRegistry))
+	for name := range sinkRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func closeSinks(sinks []Sink) {
+	for _, s := range sinks {
+		if err := s.Close(); err != nil {
+			logging.Errorf("sink: close error: %v", err)
+		}
+	}
+}
+
+// sinkTopics returns the data topics fanned out to sinks.
+func sinkTopics(cfg *config.Config) []string {
+	all := []string{
+		cfg.TopicPoseLeft, cfg.TopicPoseRight, cfg.TopicPoseFused,
+		cfg.TopicIMULeft, cfg.TopicIMURight,
+		cfg.TopicMagLeft, cfg.TopicMagRight,
+		cfg.TopicBMPLeft, cfg.TopicBMPRight,
+		cfg.TopicGPSPosition, cfg.TopicGPSVelocity, cfg.TopicGPSQuality,
+	}
+	var topics []string
+	for _, t := range all {
+		if t != "" {
+			topics = append(topics, t)
+		}
+	}
+	return topics
+}
+
+// RunSinkRouter subscribes to the data topics and writes every message to the
+// sinks listed in SINKS until ctx is cancelled, then closes them.
+func RunSinkRouter(ctx context.Context) error {
+	cfg := config.Get()
+	if len(cfg.Sinks) == 0 {
+		return fmt.Errorf("SINKS is empty (available: %s)", strings.Join(sinkNames(), ", "))
+	}
+	sinks, err := OpenSinks(cfg.Sinks)
+	if err != nil {
+		return err
+	}
+	clientID := cfg.MQTTClientIDSinks
+	if clientID == "" {
+		clientID = defaultSinkClientID
+	}
+	return runSinks(ctx, clientID, sinks)
+}
+
+// runSinks fans out the data topics to sinks until ctx is cancelled and
+// closes the sinks on return.
+func runSinks(ctx context.Context, clientID string, sinks []Sink) error {
+	defer closeSinks(sinks)
+
+	cfg := config.Get()
+
+	client, err := NewMQTTClient(clientID)
+	if err != nil {
+		return err
+	}
+	defer DisconnectMQTT(client)
+	logging.Infof("sink: connected to MQTT broker at %s", cfg.MQTTBroker)
+
+	for _, topic := range sinkTopics(cfg) {
+		token := client.Subscribe(topic, 0, func(_ mqtt.Client, msg mqtt.Message) {
+			now := time.Now()
+			for _, s := range sinks {
+				if err := s.Write(msg.Topic(), msg.Payload(), now); err != nil {
+					logging.Errorf("sink: %s: %v", msg.Topic(), err)
+				}
+			}
+		})
+		token.Wait()
+		if token.Error() != nil {
+			return token.Error()
+		}
+		logging.Infof("sink: subscribed to %s", topic)
+	}
+
+	<-ctx.Done()
+	logging.Infof("sink: shutting down")
+	return nil
+}
+
+// recordSink writes recording.Record JSONL, flushing at most every
+// fileSinkFlushEvery (always, if flushEvery is 0).
+type recordSink struct {
+	mu         sync.Mutex
+	w          *recording.Writer
+	f          *os.File // nil for stdout
+	flushEvery time.Duration
+	lastFlush  time.Time
+}
+
+// newStdoutSink writes JSONL records to stdout.
+func newStdoutSink(string) (Sink, error) {
+	return &recordSink{w: recording.NewWriter(os.Stdout)}, nil
+}
+
+// newFileSink appends JSONL records to the file named by arg, in the
+// recording format read by cmd/export.
+func newFileSink(path string) (Sink, error) {
+	if path == "" {
+		return nil, fmt.Errorf("missing path (file:/path/to/session.jsonl)")
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	logging.Infof("sink: writing records to %s", path)
+	return &recordSink{w: recording.NewWriter(f), f: f, flushEvery: fileSinkFlushEvery}, nil
+}
+
+func (s *recordSink) Write(topic string, payload []byte, t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.w.Write(recording.Record{Time: t, Topic: topic, Payload: payload}); err != nil {
+		return err
+	}
+	if t.Sub(s.lastFlush) >= s.flushEvery {
+		s.lastFlush = t
+		return s.w.Flush()
+	}
+	return nil
+}
+
+func (s *recordSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.w.Flush()
+	if s.f != nil {
+		if cerr := s.f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
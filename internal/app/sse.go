@@ -0,0 +1,157 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/relabs-tech/inertial_computer/internal/logging"
+	"github.com/relabs-tech/inertial_computer/internal/orientation"
+)
+
+// sseKeepAlive is how often an idle SSE connection gets a comment line, so
+// proxies and browsers do not time it out.
+const sseKeepAlive = 15 * time.Second
+
+// poseEvent is one pose update pushed to SSE and WebSocket clients.
+type poseEvent struct {
+	Source string           `json:"source"` // "left", "right" or "fused"
+	Pose   orientation.Pose `json:"pose"`
+}
+
+// poseBroadcaster fans pose updates from the MQTT callbacks out to SSE and
+// WebSocket clients. Each client has a one-slot mailbox per source: a slow client skips
+// intermediate poses and always receives the latest one.
+type poseBroadcaster struct {
+	mu      sync.Mutex
+	clients map[*poseClient]struct{}
+
+	// referenceAction forwards a level reference action ("capture" or
+	// "clear") received from a WebSocket client; nil = disabled. Set before
+	// serving.
+	referenceAction func(action string) error
+}
+
+type poseClient struct {
+	mu     sync.Mutex
+	latest map[string]orientation.Pose // pending pose per source
+	notify chan struct{}               // signalled when latest changes
+}
+
+func newPoseBroadcaster() *poseBroadcaster {
+	return &poseBroadcaster{clients: make(map[*poseClient]struct{})}
+}
+
+func (b *poseBroadcaster) subscribe() *poseClient {
+	c := &poseClient{
+		latest: make(map[string]orientation.Pose),
+		notify: make(chan struct{}, 1),
+	}
+	b.mu.Lock()
+	b.clients[c] = struct{}{}
+	b.mu.Unlock()
+	return c
+}
+
+func (b *poseBroadcaster) unsubscribe(c *poseClient) {
+	b.mu.Lock()
+	delete(b.clients, c)
+	b.mu.Unlock()
+}
+
+// publish hands a pose to every client without blocking; a pose still
+// pending for the same source is overwritten.
+func (b *poseBroadcaster) publish(source string, p orientation.Pose) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for c := range b.clients {
+		c.mu.Lock()
+		c.latest[source] = p
+		c.mu.Unlock()
+
+		select {
+		case c.notify <- struct{}{}:
+		default: // already signalled
+		}
+	}
+}
+
+// take returns and clears the pending poses.
+func (c *poseClient) take() []poseEvent {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	events := make([]poseEvent, 0, len(c.latest))
+	for _, src := range []string{"left", "right", "fused"} {
+		if p, ok := c.latest[src]; ok {
+			events = append(events, poseEvent{Source: src, Pose: p})
+			delete(c.latest, src)
+		}
+	}
+	return events
+}
+
+// serveSSE streams pose updates as Server-Sent Events until the client
+// disconnects. Each event is named after its source ("left", "right" or
+// "fused"); ?source= restricts the stream to one of them.
+func (b *poseBroadcaster) serveSSE(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	filter := r.URL.Query().Get("source")
+	switch filter {
+	case "", "left", "right", "fused":
+	default:
+		http.Error(w, "invalid source (must be 'left', 'right' or 'fused')", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	c := b.subscribe()
+	defer b.unsubscribe(c)
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-c.notify:
+			for _, ev := range c.take() {
+				if filter != "" && ev.Source != filter {
+					continue
+				}
+				data, err := json.Marshal(ev)
+				if err != nil {
+					logging.Errorf("web: pose stream JSON encode error: %v", err)
+					continue
+				}
+				if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Source, data); err != nil {
+					return
+				}
+			}
+			flusher.Flush()
+		}
+	}
+}
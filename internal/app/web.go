@@ -5,11 +5,15 @@
 package app
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
-	"log"
+	"net"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 
@@ -17,10 +21,54 @@ import (
 	"github.com/relabs-tech/inertial_computer/internal/env"
 	"github.com/relabs-tech/inertial_computer/internal/gps"
 	imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
+	"github.com/relabs-tech/inertial_computer/internal/logging"
 	"github.com/relabs-tech/inertial_computer/internal/orientation"
+	"github.com/relabs-tech/inertial_computer/internal/sensors"
 )
 
-func RunWeb() error {
+// defaultStaleThreshold applies when WEB_STALE_THRESHOLD is not configured.
+const defaultStaleThreshold = 3 * time.Second
+
+// gsvStaleThreshold is the minimum stale threshold of the satellite streams:
+// a GSV sequence spans several sentences and receivers often send it at a
+// lower rate than the RMC/GGA fix.
+const gsvStaleThreshold = 10 * time.Second
+
+// streamStaleThreshold returns the age past which a stream is reported
+// stale: staleAfter (WEB_STALE_THRESHOLD) for streams published at the
+// sample or fix rate, longer for streams that are published less often.
+// TOPIC_IMU_HEALTH goes out once per CONSOLE_LOG_INTERVAL, so it tolerates
+// one missed message.
+func streamStaleThreshold(key string, staleAfter, consoleLogInterval time.Duration) time.Duration {
+	switch key {
+	case "imu_health":
+		return max(staleAfter, 2*consoleLogInterval)
+	case "gps_satellites", "glonass_satellites":
+		return max(staleAfter, gsvStaleThreshold)
+	}
+	return staleAfter
+}
+
+// streamFreshness tells how old the latest message of a stream is.
+type streamFreshness struct {
+	ReceivedAt string `json:"received_at"` // RFC3339, web server receive time
+	AgeMs      int64  `json:"age_ms"`
+	Stale      bool   `json:"stale"` // older than the stream's threshold, see streamStaleThreshold
+
+	// For payloads with a producer timestamp (poses, IMU samples)
+	SampledAt string `json:"sampled_at,omitempty"` // RFC3339, payload timestamp
+	LatencyMs *int64 `json:"latency_ms,omitempty"` // receive time - payload timestamp
+}
+
+// clientStatus is the latest status message of an MQTT client.
+type clientStatus struct {
+	Status     string `json:"status"`      // "online" or "offline"
+	ReceivedAt string `json:"received_at"` // RFC3339, web server receive time
+}
+
+// RunWeb serves the dashboard and its APIs from the latest MQTT data until
+// ctx is cancelled.
+func RunWeb(ctx context.Context) error {
 	cfg := config.Get()
 
 	var (
@@ -34,9 +82,18 @@ func RunWeb() error {
 		lastFusedPose orientation.Pose
 		haveFusedPose bool
 
+		// Level reference published by imu_producer (TOPIC_POSE_REFERENCE)
+		lastPoseReference poseReferenceState
+
 		lastFix gps.Fix
 		haveFix bool
 
+		// Published separately by gps_producer (TOPIC_GPS_QUALITY / TOPIC_GPS_VELOCITY)
+		lastGPSQuality  gps.Quality
+		haveGPSQuality  bool
+		lastGPSVelocity gps.Velocity
+		haveGPSVelocity bool
+
 		lastIMULeft  imu_raw.IMURaw
 		haveIMULeft  bool
 		lastIMURight imu_raw.IMURaw
@@ -50,12 +107,14 @@ func RunWeb() error {
 		lastGPSSatellites struct {
 			Satellites []gps.Satellite `json:"satellites"`
 			Count      int             `json:"count"`
+			Partial    bool            `json:"partial"`
 		}
 		haveGPSSatellites bool
 
 		lastGLONASSSatellites struct {
 			Satellites []gps.Satellite `json:"satellites"`
 			Count      int             `json:"count"`
+			Partial    bool            `json:"partial"`
 		}
 		haveGLONASSSatellites bool
 
@@ -68,133 +127,287 @@ func RunWeb() error {
 			Time string  `json:"time"`
 		}
 		haveHMCMag bool
+
+		lastIMUHealth sensors.IMUMetrics
+		haveIMUHealth bool
+
+		// Receive time of the latest message per stream (keyed as in /api/state)
+		received = make(map[string]time.Time)
+		// Producer timestamp of the latest message, for streams that carry one
+		sampled = make(map[string]time.Time)
+		// Lost-message counts, for streams with sequence numbers
+		sequences = make(map[string]*imu_raw.SeqTracker)
+
+		// Online/offline status per MQTT client ID
+		clientStatuses = make(map[string]clientStatus)
 	)
 
 	// 1) Connect to MQTT
-	opts := mqtt.NewClientOptions().
-		AddBroker(cfg.MQTTBroker).
-		SetClientID(cfg.MQTTClientIDWeb)
+	client, err := NewMQTTClient(cfg.MQTTClientIDWeb)
+	if err != nil {
+		return err
+	}
+	defer DisconnectMQTT(client)
+	logging.Infof("web: connected to MQTT broker at %s", cfg.MQTTBroker)
+
+	// Pose updates are also pushed to /api/orientation/stream clients
+	poseStream := newPoseBroadcaster()
+
+	// A stream is reported stale when its latest message is older than this
+	staleAfter := time.Duration(cfg.WebStaleThreshold) * time.Millisecond
+	if staleAfter <= 0 {
+		staleAfter = defaultStaleThreshold
+	}
+	consoleLogInterval := time.Duration(cfg.ConsoleLogInterval) * time.Millisecond
+
+	// freshness reports the age of a stream's latest message; mu must be held.
+	freshness := func(key string) streamFreshness {
+		t := received[key]
+		age := time.Since(t)
+		f := streamFreshness{
+			ReceivedAt: t.Format(time.RFC3339Nano),
+			AgeMs:      age.Milliseconds(),
+			Stale:      age > streamStaleThreshold(key, staleAfter, consoleLogInterval),
+		}
+		if ts, ok := sampled[key]; ok {
+			latency := t.Sub(ts).Milliseconds()
+			f.SampledAt = ts.Format(time.RFC3339Nano)
+			f.LatencyMs = &latency
+		}
+		return f
+	}
+
+	// markReceived records the receive time and, if the payload has them, the
+	// producer timestamp and sequence number of a stream's latest message;
+	// mu must be held.
+	markReceived := func(key string, ts time.Time, seq uint64) {
+		received[key] = time.Now()
+		if ts.IsZero() {
+			delete(sampled, key)
+		} else {
+			sampled[key] = ts
+		}
+		if seq != 0 {
+			st := sequences[key]
+			if st == nil {
+				st = &imu_raw.SeqTracker{}
+				sequences[key] = st
+			}
+			if missed := st.Observe(seq); missed > 0 {
+				logging.Warnf("web: %s: %d message(s) lost before seq %d", key, missed, seq)
+			}
+		}
+	}
 
-	client := mqtt.NewClient(opts)
-	if token := client.Connect(); token.Wait() && token.Error() != nil {
-		return token.Error()
+	// writeFreshness adds the freshness of a stream as response headers, so
+	// the per-stream endpoints keep returning the bare payload.
+	writeFreshness := func(w http.ResponseWriter, key string) {
+		f := freshness(key)
+		w.Header().Set("X-Received-At", f.ReceivedAt)
+		w.Header().Set("X-Age-Ms", strconv.FormatInt(f.AgeMs, 10))
+		w.Header().Set("X-Stale", strconv.FormatBool(f.Stale))
+		if f.LatencyMs != nil {
+			w.Header().Set("X-Latency-Ms", strconv.FormatInt(*f.LatencyMs, 10))
+		}
 	}
-	log.Printf("web: connected to MQTT broker at %s", cfg.MQTTBroker)
 
 	// 2) Subscribe to left pose
 	poseLeftToken := client.Subscribe(cfg.TopicPoseLeft, 0, func(_ mqtt.Client, msg mqtt.Message) {
 		var p orientation.Pose
 		if err := json.Unmarshal(msg.Payload(), &p); err != nil {
-			log.Printf("web: pose left unmarshal error: %v", err)
+			logging.Warnf("web: pose left unmarshal error: %v", err)
 			return
 		}
 		mu.Lock()
 		lastPoseLeft = p
 		havePoseLeft = true
+		markReceived("orientation_left", p.Timestamp, p.Seq)
 		mu.Unlock()
+		poseStream.publish("left", p)
 	})
 	poseLeftToken.Wait()
 	if poseLeftToken.Error() != nil {
 		return poseLeftToken.Error()
 	}
-	log.Printf("web: subscribed to MQTT topic %s", cfg.TopicPoseLeft)
+	logging.Infof("web: subscribed to MQTT topic %s", cfg.TopicPoseLeft)
 
 	// 3) Subscribe to right pose
 	poseRightToken := client.Subscribe(cfg.TopicPoseRight, 0, func(_ mqtt.Client, msg mqtt.Message) {
 		var p orientation.Pose
 		if err := json.Unmarshal(msg.Payload(), &p); err != nil {
-			log.Printf("web: pose right unmarshal error: %v", err)
+			logging.Warnf("web: pose right unmarshal error: %v", err)
 			return
 		}
 		mu.Lock()
 		lastPoseRight = p
 		havePoseRight = true
+		markReceived("orientation_right", p.Timestamp, p.Seq)
 		mu.Unlock()
+		poseStream.publish("right", p)
 	})
 	poseRightToken.Wait()
 	if poseRightToken.Error() != nil {
 		return poseRightToken.Error()
 	}
-	log.Printf("web: subscribed to MQTT topic %s", cfg.TopicPoseRight)
+	logging.Infof("web: subscribed to MQTT topic %s", cfg.TopicPoseRight)
 
 	// 4) Subscribe to fused pose
 	fusedToken := client.Subscribe(cfg.TopicPoseFused, 0, func(_ mqtt.Client, msg mqtt.Message) {
 		var p orientation.Pose
 		if err := json.Unmarshal(msg.Payload(), &p); err != nil {
-			log.Printf("web: fused pose unmarshal error: %v", err)
+			logging.Warnf("web: fused pose unmarshal error: %v", err)
 			return
 		}
 		mu.Lock()
 		lastFusedPose = p
 		haveFusedPose = true
+		markReceived("orientation_fused", p.Timestamp, p.Seq)
 		mu.Unlock()
+		poseStream.publish("fused", p)
 	})
 	fusedToken.Wait()
 	if fusedToken.Error() != nil {
 		return fusedToken.Error()
 	}
-	log.Printf("web: subscribed to MQTT topic %s", cfg.TopicPoseFused)
+	logging.Infof("web: subscribed to MQTT topic %s", cfg.TopicPoseFused)
+
+	// 4a) Level reference: follow the producer's retained state, and send
+	// capture/clear commands from the REST and WebSocket actions
+	if cfg.TopicPoseReference != "" {
+		refToken := client.Subscribe(cfg.TopicPoseReference, 0, func(_ mqtt.Client, msg mqtt.Message) {
+			var st poseReferenceState
+			if len(msg.Payload()) > 0 {
+				if err := json.Unmarshal(msg.Payload(), &st); err != nil {
+					logging.Warnf("web: pose reference unmarshal error: %v", err)
+					return
+				}
+			}
+			mu.Lock()
+			lastPoseReference = st
+			mu.Unlock()
+		})
+		refToken.Wait()
+		if refToken.Error() != nil {
+			return refToken.Error()
+		}
+		logging.Infof("web: subscribed to MQTT topic %s", cfg.TopicPoseReference)
+
+		poseStream.referenceAction = func(action string) error {
+			if err := validPoseReferenceAction(action); err != nil {
+				return err
+			}
+			payload, _ := json.Marshal(poseReferenceCommand{Action: action})
+			token := client.Publish(poseReferenceCommandTopic(cfg.TopicPoseReference), 1, false, payload)
+			token.Wait()
+			return token.Error()
+		}
+	}
 
 	// 5) Subscribe to GPS
 	// 5) Subscribe to GPS
 	gpsToken := client.Subscribe(cfg.TopicGPS, 0, func(_ mqtt.Client, msg mqtt.Message) {
 		var f gps.Fix
 		if err := json.Unmarshal(msg.Payload(), &f); err != nil {
-			log.Printf("web: gps unmarshal error: %v", err)
+			logging.Warnf("web: gps unmarshal error: %v", err)
 			return
 		}
 		mu.Lock()
 		lastFix = f
 		haveFix = true
+		received["gps"] = time.Now()
 		mu.Unlock()
 	})
 	gpsToken.Wait()
 	if gpsToken.Error() != nil {
 		return gpsToken.Error()
 	}
-	log.Printf("web: subscribed to MQTT topic %s", cfg.TopicGPS)
+	logging.Infof("web: subscribed to MQTT topic %s", cfg.TopicGPS)
+
+	// Subscribe to GPS quality (DOP, fix type) and velocity, if configured
+	if cfg.TopicGPSQuality != "" {
+		qualityToken := client.Subscribe(cfg.TopicGPSQuality, 0, func(_ mqtt.Client, msg mqtt.Message) {
+			var q gps.Quality
+			if err := json.Unmarshal(msg.Payload(), &q); err != nil {
+				logging.Warnf("web: gps quality unmarshal error: %v", err)
+				return
+			}
+			mu.Lock()
+			lastGPSQuality = q
+			haveGPSQuality = true
+			received["gps_quality"] = time.Now()
+			mu.Unlock()
+		})
+		qualityToken.Wait()
+		if qualityToken.Error() != nil {
+			return qualityToken.Error()
+		}
+		logging.Infof("web: subscribed to MQTT topic %s", cfg.TopicGPSQuality)
+	}
+	if cfg.TopicGPSVelocity != "" {
+		velocityToken := client.Subscribe(cfg.TopicGPSVelocity, 0, func(_ mqtt.Client, msg mqtt.Message) {
+			var v gps.Velocity
+			if err := json.Unmarshal(msg.Payload(), &v); err != nil {
+				logging.Warnf("web: gps velocity unmarshal error: %v", err)
+				return
+			}
+			mu.Lock()
+			lastGPSVelocity = v
+			haveGPSVelocity = true
+			received["gps_velocity"] = time.Now()
+			mu.Unlock()
+		})
+		velocityToken.Wait()
+		if velocityToken.Error() != nil {
+			return velocityToken.Error()
+		}
+		logging.Infof("web: subscribed to MQTT topic %s", cfg.TopicGPSVelocity)
+	}
 
 	// Subscribe to GPS satellites
 	gpsSatToken := client.Subscribe(cfg.TopicGPSSatellites, 0, func(_ mqtt.Client, msg mqtt.Message) {
 		var satsData struct {
 			Satellites []gps.Satellite `json:"satellites"`
 			Count      int             `json:"count"`
+			Partial    bool            `json:"partial"`
 		}
 		if err := json.Unmarshal(msg.Payload(), &satsData); err != nil {
-			log.Printf("web: gps satellites unmarshal error: %v", err)
+			logging.Warnf("web: gps satellites unmarshal error: %v", err)
 			return
 		}
 		mu.Lock()
 		lastGPSSatellites = satsData
 		haveGPSSatellites = true
+		received["gps_satellites"] = time.Now()
 		mu.Unlock()
 	})
 	gpsSatToken.Wait()
 	if gpsSatToken.Error() != nil {
 		return gpsSatToken.Error()
 	}
-	log.Printf("web: subscribed to MQTT topic %s", cfg.TopicGPSSatellites)
+	logging.Infof("web: subscribed to MQTT topic %s", cfg.TopicGPSSatellites)
 
 	// Subscribe to GLONASS satellites
 	glonassSatToken := client.Subscribe(cfg.TopicGLONASSSatellites, 0, func(_ mqtt.Client, msg mqtt.Message) {
 		var satsData struct {
 			Satellites []gps.Satellite `json:"satellites"`
 			Count      int             `json:"count"`
+			Partial    bool            `json:"partial"`
 		}
 		if err := json.Unmarshal(msg.Payload(), &satsData); err != nil {
-			log.Printf("web: glonass satellites unmarshal error: %v", err)
+			logging.Warnf("web: glonass satellites unmarshal error: %v", err)
 			return
 		}
 		mu.Lock()
 		lastGLONASSSatellites = satsData
 		haveGLONASSSatellites = true
+		received["glonass_satellites"] = time.Now()
 		mu.Unlock()
 	})
 	glonassSatToken.Wait()
 	if glonassSatToken.Error() != nil {
 		return glonassSatToken.Error()
 	}
-	log.Printf("web: subscribed to MQTT topic %s", cfg.TopicGLONASSSatellites)
+	logging.Infof("web: subscribed to MQTT topic %s", cfg.TopicGLONASSSatellites)
 
 	// Subscribe to external HMC magnetometer (if configured)
 	hmcTopic := cfg.TopicMagHMC
@@ -208,92 +421,135 @@ func RunWeb() error {
 				Time string  `json:"time"`
 			}
 			if err := json.Unmarshal(msg.Payload(), &m); err != nil {
-				log.Printf("web: hmc mag unmarshal error: %v", err)
+				logging.Warnf("web: hmc mag unmarshal error: %v", err)
 				return
 			}
 			mu.Lock()
 			lastHMCMag = m
 			haveHMCMag = true
+			received["hmc"] = time.Now()
 			mu.Unlock()
 		})
 		hmcToken.Wait()
 		if hmcToken.Error() != nil {
 			return hmcToken.Error()
 		}
-		log.Printf("web: subscribed to %s", hmcTopic)
+		logging.Infof("web: subscribed to %s", hmcTopic)
+	}
+
+	// Subscribe to per-client online/offline status
+	statusPrefix := statusTopic("")
+	statusToken := client.Subscribe(statusPrefix+"+", 0, func(_ mqtt.Client, msg mqtt.Message) {
+		id := strings.TrimPrefix(msg.Topic(), statusPrefix)
+		mu.Lock()
+		clientStatuses[id] = clientStatus{
+			Status:     string(msg.Payload()),
+			ReceivedAt: time.Now().Format(time.RFC3339),
+		}
+		mu.Unlock()
+	})
+	statusToken.Wait()
+	if statusToken.Error() != nil {
+		return statusToken.Error()
+	}
+	logging.Infof("web: subscribed to %s+", statusPrefix)
+
+	// Subscribe to IMU health metrics (if configured)
+	if cfg.TopicIMUHealth != "" {
+		imuHealthToken := client.Subscribe(cfg.TopicIMUHealth, 0, func(_ mqtt.Client, msg mqtt.Message) {
+			var h sensors.IMUMetrics
+			if err := json.Unmarshal(msg.Payload(), &h); err != nil {
+				logging.Warnf("web: imu health unmarshal error: %v", err)
+				return
+			}
+			mu.Lock()
+			lastIMUHealth = h
+			haveIMUHealth = true
+			received["imu_health"] = time.Now()
+			mu.Unlock()
+		})
+		imuHealthToken.Wait()
+		if imuHealthToken.Error() != nil {
+			return imuHealthToken.Error()
+		}
+		logging.Infof("web: subscribed to %s", cfg.TopicIMUHealth)
 	}
 
 	// Subscribe to IMU left
 	imuLeftToken := client.Subscribe(cfg.TopicIMULeft, 0, func(_ mqtt.Client, msg mqtt.Message) {
 		var s imu_raw.IMURaw
 		if err := json.Unmarshal(msg.Payload(), &s); err != nil {
-			log.Printf("web: imu left unmarshal error: %v", err)
+			logging.Warnf("web: imu left unmarshal error: %v", err)
 			return
 		}
 		mu.Lock()
 		lastIMULeft = s
 		haveIMULeft = true
+		markReceived("imu_left", s.Timestamp, s.Seq)
 		mu.Unlock()
 	})
 	imuLeftToken.Wait()
 	if imuLeftToken.Error() != nil {
 		return imuLeftToken.Error()
 	}
-	log.Printf("web: subscribed to %s", cfg.TopicIMULeft)
+	logging.Infof("web: subscribed to %s", cfg.TopicIMULeft)
 
 	// Subscribe to IMU right
 	imuRightToken := client.Subscribe(cfg.TopicIMURight, 0, func(_ mqtt.Client, msg mqtt.Message) {
 		var s imu_raw.IMURaw
 		if err := json.Unmarshal(msg.Payload(), &s); err != nil {
-			log.Printf("web: imu right unmarshal error: %v", err)
+			logging.Warnf("web: imu right unmarshal error: %v", err)
 			return
 		}
 		mu.Lock()
 		lastIMURight = s
 		haveIMURight = true
+		markReceived("imu_right", s.Timestamp, s.Seq)
 		mu.Unlock()
 	})
 	imuRightToken.Wait()
 	if imuRightToken.Error() != nil {
 		return imuRightToken.Error()
 	}
-	log.Printf("web: subscribed to %s", cfg.TopicIMURight)
+	logging.Infof("web: subscribed to %s", cfg.TopicIMURight)
 
 	// Subscribe to BMP left
 	envLeftToken := client.Subscribe(cfg.TopicBMPLeft, 0, func(_ mqtt.Client, msg mqtt.Message) {
 		var s env.Sample
 		if err := json.Unmarshal(msg.Payload(), &s); err != nil {
-			log.Printf("web: env left unmarshal error: %v", err)
+			logging.Warnf("web: env left unmarshal error: %v", err)
 			return
 		}
 		mu.Lock()
 		lastEnvLeft = s
 		haveEnvLeft = true
+		received["env_left"] = time.Now()
 		mu.Unlock()
 	})
 	envLeftToken.Wait()
 	if envLeftToken.Error() != nil {
 		return envLeftToken.Error()
 	}
-	log.Printf("web: subscribed to %s", cfg.TopicBMPLeft)
+	logging.Infof("web: subscribed to %s", cfg.TopicBMPLeft)
 
 	// 4e) Subscribe to BMP right
 	envRightToken := client.Subscribe(cfg.TopicBMPRight, 0, func(_ mqtt.Client, msg mqtt.Message) {
 		var s env.Sample
 		if err := json.Unmarshal(msg.Payload(), &s); err != nil {
-			log.Printf("web: env right unmarshal error: %v", err)
+			logging.Warnf("web: env right unmarshal error: %v", err)
 			return
 		}
 		mu.Lock()
 		lastEnvRight = s
 		haveEnvRight = true
+		received["env_right"] = time.Now()
 		mu.Unlock()
 	})
 	envRightToken.Wait()
 	if envRightToken.Error() != nil {
 		return envRightToken.Error()
 	}
-	log.Printf("web: subscribed to %s", cfg.TopicBMPRight)
+	logging.Infof("web: subscribed to %s", cfg.TopicBMPRight)
 
 	// 5) JSON API: latest left pose
 	http.HandleFunc("/api/orientation/left", func(w http.ResponseWriter, r *http.Request) {
@@ -304,10 +560,11 @@ func RunWeb() error {
 			http.Error(w, "no left orientation data yet", http.StatusServiceUnavailable)
 			return
 		}
+		writeFreshness(w, "orientation_left")
 
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(lastPoseLeft); err != nil {
-			log.Printf("web: left orientation JSON encode error: %v", err)
+			logging.Errorf("web: left orientation JSON encode error: %v", err)
 		}
 	})
 
@@ -320,10 +577,11 @@ func RunWeb() error {
 			http.Error(w, "no right orientation data yet", http.StatusServiceUnavailable)
 			return
 		}
+		writeFreshness(w, "orientation_right")
 
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(lastPoseRight); err != nil {
-			log.Printf("web: right orientation JSON encode error: %v", err)
+			logging.Errorf("web: right orientation JSON encode error: %v", err)
 		}
 	})
 
@@ -336,13 +594,56 @@ func RunWeb() error {
 			http.Error(w, "no fused orientation data yet", http.StatusServiceUnavailable)
 			return
 		}
+		writeFreshness(w, "orientation_fused")
 
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(lastFusedPose); err != nil {
-			log.Printf("web: fused orientation JSON encode error: %v", err)
+			logging.Errorf("web: fused orientation JSON encode error: %v", err)
+		}
+	})
+
+	// 5d) Level reference: GET returns the active references, POST
+	// {"action": "capture"|"clear"} asks imu_producer to change them. The
+	// change shows up in the poses (and here) once the producer has applied it.
+	http.HandleFunc("/api/orientation/reference", func(w http.ResponseWriter, r *http.Request) {
+		if poseStream.referenceAction == nil {
+			http.Error(w, "pose reference disabled (TOPIC_POSE_REFERENCE is empty)", http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			mu.RLock()
+			st := lastPoseReference
+			mu.RUnlock()
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(st); err != nil {
+				logging.Errorf("web: pose reference JSON encode error: %v", err)
+			}
+		case http.MethodPost:
+			var cmd poseReferenceCommand
+			if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+				http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := validPoseReferenceAction(cmd.Action); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := poseStream.referenceAction(cmd.Action); err != nil {
+				http.Error(w, "MQTT publish error: "+err.Error(), http.StatusBadGateway)
+				return
+			}
+			logging.Infof("web: pose reference %s requested", cmd.Action)
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		}
 	})
 
+	// 5e) SSE and WebSocket streams: pose updates as they arrive from MQTT
+	http.HandleFunc("/api/orientation/stream", poseStream.serveSSE)
+	http.HandleFunc("/ws/orientation", poseStream.serveWS)
+
 	// 6) JSON API: latest GPS fix
 	http.HandleFunc("/api/gps", func(w http.ResponseWriter, r *http.Request) {
 		mu.RLock()
@@ -352,10 +653,43 @@ func RunWeb() error {
 			http.Error(w, "no gps data yet", http.StatusServiceUnavailable)
 			return
 		}
+		writeFreshness(w, "gps")
 
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(lastFix); err != nil {
-			log.Printf("web: gps JSON encode error: %v", err)
+			logging.Errorf("web: gps JSON encode error: %v", err)
+		}
+	})
+
+	// 6-1) JSON API: GPS quality and velocity
+	http.HandleFunc("/api/gps/quality", func(w http.ResponseWriter, r *http.Request) {
+		mu.RLock()
+		defer mu.RUnlock()
+
+		if !haveGPSQuality {
+			http.Error(w, "no gps quality data yet", http.StatusServiceUnavailable)
+			return
+		}
+		writeFreshness(w, "gps_quality")
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(lastGPSQuality); err != nil {
+			logging.Errorf("web: gps quality JSON encode error: %v", err)
+		}
+	})
+	http.HandleFunc("/api/gps/velocity", func(w http.ResponseWriter, r *http.Request) {
+		mu.RLock()
+		defer mu.RUnlock()
+
+		if !haveGPSVelocity {
+			http.Error(w, "no gps velocity data yet", http.StatusServiceUnavailable)
+			return
+		}
+		writeFreshness(w, "gps_velocity")
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(lastGPSVelocity); err != nil {
+			logging.Errorf("web: gps velocity JSON encode error: %v", err)
 		}
 	})
 
@@ -368,10 +702,11 @@ func RunWeb() error {
 			http.Error(w, "no gps satellites data yet", http.StatusServiceUnavailable)
 			return
 		}
+		writeFreshness(w, "gps_satellites")
 
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(lastGPSSatellites); err != nil {
-			log.Printf("web: gps satellites JSON encode error: %v", err)
+			logging.Errorf("web: gps satellites JSON encode error: %v", err)
 		}
 	})
 
@@ -384,10 +719,11 @@ func RunWeb() error {
 			http.Error(w, "no glonass satellites data yet", http.StatusServiceUnavailable)
 			return
 		}
+		writeFreshness(w, "glonass_satellites")
 
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(lastGLONASSSatellites); err != nil {
-			log.Printf("web: glonass satellites JSON encode error: %v", err)
+			logging.Errorf("web: glonass satellites JSON encode error: %v", err)
 		}
 	})
 
@@ -400,9 +736,10 @@ func RunWeb() error {
 			http.Error(w, "no left imu data yet", http.StatusServiceUnavailable)
 			return
 		}
+		writeFreshness(w, "imu_left")
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(lastIMULeft); err != nil {
-			log.Printf("web: left imu JSON encode error: %v", err)
+			logging.Errorf("web: left imu JSON encode error: %v", err)
 		}
 	})
 
@@ -413,9 +750,24 @@ func RunWeb() error {
 			http.Error(w, "no right imu data yet", http.StatusServiceUnavailable)
 			return
 		}
+		writeFreshness(w, "imu_right")
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(lastIMURight); err != nil {
-			log.Printf("web: right imu JSON encode error: %v", err)
+			logging.Errorf("web: right imu JSON encode error: %v", err)
+		}
+	})
+
+	http.HandleFunc("/api/imu/health", func(w http.ResponseWriter, r *http.Request) {
+		mu.RLock()
+		defer mu.RUnlock()
+		if !haveIMUHealth {
+			http.Error(w, "no imu health data yet", http.StatusServiceUnavailable)
+			return
+		}
+		writeFreshness(w, "imu_health")
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(lastIMUHealth); err != nil {
+			logging.Errorf("web: imu health JSON encode error: %v", err)
 		}
 	})
 
@@ -426,9 +778,10 @@ func RunWeb() error {
 			http.Error(w, "no left env data yet", http.StatusServiceUnavailable)
 			return
 		}
+		writeFreshness(w, "env_left")
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(lastEnvLeft); err != nil {
-			log.Printf("web: left env JSON encode error: %v", err)
+			logging.Errorf("web: left env JSON encode error: %v", err)
 		}
 	})
 
@@ -439,9 +792,10 @@ func RunWeb() error {
 			http.Error(w, "no right env data yet", http.StatusServiceUnavailable)
 			return
 		}
+		writeFreshness(w, "env_right")
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(lastEnvRight); err != nil {
-			log.Printf("web: right env JSON encode error: %v", err)
+			logging.Errorf("web: right env JSON encode error: %v", err)
 		}
 	})
 
@@ -453,9 +807,153 @@ func RunWeb() error {
 			http.Error(w, "no hmc data yet", http.StatusServiceUnavailable)
 			return
 		}
+		writeFreshness(w, "hmc")
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(lastHMCMag); err != nil {
-			log.Printf("web: hmc JSON encode error: %v", err)
+			logging.Errorf("web: hmc JSON encode error: %v", err)
+		}
+	})
+
+	// 6d) JSON API: all latest values in one response. Missing streams are
+	// null, with "have" telling which ones have reported so far.
+	http.HandleFunc("/api/state", func(w http.ResponseWriter, r *http.Request) {
+		mu.RLock()
+		defer mu.RUnlock()
+
+		state := make(map[string]interface{})
+		have := make(map[string]bool)
+		fresh := make(map[string]streamFreshness)
+		add := func(key string, value interface{}, ok bool) {
+			have[key] = ok
+			if ok {
+				state[key] = value
+				fresh[key] = freshness(key)
+			} else {
+				state[key] = nil
+			}
+		}
+		add("orientation_left", lastPoseLeft, havePoseLeft)
+		add("orientation_right", lastPoseRight, havePoseRight)
+		add("orientation_fused", lastFusedPose, haveFusedPose)
+		add("gps", lastFix, haveFix)
+		add("gps_quality", lastGPSQuality, haveGPSQuality)
+		add("gps_velocity", lastGPSVelocity, haveGPSVelocity)
+		add("gps_satellites", lastGPSSatellites, haveGPSSatellites)
+		add("glonass_satellites", lastGLONASSSatellites, haveGLONASSSatellites)
+		add("imu_left", lastIMULeft, haveIMULeft)
+		add("imu_right", lastIMURight, haveIMURight)
+		add("imu_health", lastIMUHealth, haveIMUHealth)
+		add("env_left", lastEnvLeft, haveEnvLeft)
+		add("env_right", lastEnvRight, haveEnvRight)
+		add("hmc", lastHMCMag, haveHMCMag)
+		state["have"] = have
+		state["freshness"] = fresh
+		state["clients"] = clientStatuses
+		if len(sequences) > 0 {
+			state["sequence"] = sequences
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(state); err != nil {
+			logging.Errorf("web: state JSON encode error: %v", err)
+		}
+	})
+
+	// 6e) JSON API: online/offline status of every MQTT client seen so far
+	http.HandleFunc("/api/status", func(w http.ResponseWriter, r *http.Request) {
+		mu.RLock()
+		defer mu.RUnlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(clientStatuses); err != nil {
+			logging.Errorf("web: status JSON encode error: %v", err)
+		}
+	})
+
+	// 6f) Prometheus metrics from the latest cached values
+	http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		var p promWriter
+
+		mu.RLock()
+		var roll, pitch, yaw []promSample
+		for _, src := range []struct {
+			name string
+			pose orientation.Pose
+			have bool
+		}{
+			{"left", lastPoseLeft, havePoseLeft},
+			{"right", lastPoseRight, havePoseRight},
+			{"fused", lastFusedPose, haveFusedPose},
+		} {
+			if !src.have {
+				continue
+			}
+			labels := []string{"source", src.name}
+			roll = append(roll, promSample{labels, src.pose.Roll})
+			pitch = append(pitch, promSample{labels, src.pose.Pitch})
+			yaw = append(yaw, promSample{labels, src.pose.Yaw})
+		}
+		p.family("inertial_pose_roll_degrees", "gauge", "Last roll angle in degrees.", roll...)
+		p.family("inertial_pose_pitch_degrees", "gauge", "Last pitch angle in degrees.", pitch...)
+		p.family("inertial_pose_yaw_degrees", "gauge", "Last yaw angle in degrees.", yaw...)
+
+		if haveFix {
+			p.family("inertial_gps_fix_valid", "gauge", "1 if the last RMC fix was valid (A), 0 otherwise.",
+				promSample{nil, promBool(lastFix.Validity == "A")})
+			p.family("inertial_gps_fix_info", "gauge", "GPS fix quality and type of the last fix.",
+				promSample{[]string{"fix_quality", lastFix.FixQuality, "fix_type", lastFix.FixType}, 1})
+			p.family("inertial_gps_satellites_used", "gauge", "Number of satellites used in the fix.",
+				promSample{nil, float64(lastFix.NumSatellites)})
+			p.family("inertial_gps_hdop", "gauge", "Horizontal dilution of precision.",
+				promSample{nil, lastFix.HDOP})
+		}
+		var inView []promSample
+		if haveGPSSatellites {
+			inView = append(inView, promSample{[]string{"constellation", "gps"}, float64(lastGPSSatellites.Count)})
+		}
+		if haveGLONASSSatellites {
+			inView = append(inView, promSample{[]string{"constellation", "glonass"}, float64(lastGLONASSSatellites.Count)})
+		}
+		p.family("inertial_gps_satellites_in_view", "gauge", "Number of satellites in view.", inView...)
+
+		var temp, pressure []promSample
+		if haveEnvLeft {
+			temp = append(temp, promSample{[]string{"sensor", "left"}, lastEnvLeft.Temperature})
+			pressure = append(pressure, promSample{[]string{"sensor", "left"}, lastEnvLeft.Pressure})
+		}
+		if haveEnvRight {
+			temp = append(temp, promSample{[]string{"sensor", "right"}, lastEnvRight.Temperature})
+			pressure = append(pressure, promSample{[]string{"sensor", "right"}, lastEnvRight.Pressure})
+		}
+		p.family("inertial_env_temperature_celsius", "gauge", "BMP280 temperature in degrees Celsius.", temp...)
+		p.family("inertial_env_pressure_pascals", "gauge", "BMP280 pressure in pascals.", pressure...)
+
+		if haveIMUHealth {
+			left := []string{"imu", "left"}
+			right := []string{"imu", "right"}
+			p.family("inertial_imu_reads_total", "counter", "IMU read attempts since imu_producer start.",
+				promSample{left, float64(lastIMUHealth.Left.Reads)},
+				promSample{right, float64(lastIMUHealth.Right.Reads)})
+			p.family("inertial_imu_read_errors_total", "counter", "IMU read errors since imu_producer start.",
+				promSample{left, float64(lastIMUHealth.Left.Errors)},
+				promSample{right, float64(lastIMUHealth.Right.Errors)})
+			p.family("inertial_imu_reads_per_second", "gauge", "Recent IMU read rate.",
+				promSample{left, lastIMUHealth.Left.ReadsPerSec},
+				promSample{right, lastIMUHealth.Right.ReadsPerSec})
+		}
+
+		var dropped []promSample
+		for key, st := range sequences {
+			dropped = append(dropped, promSample{[]string{"stream", key}, float64(st.Dropped)})
+		}
+		sort.Slice(dropped, func(i, j int) bool { return dropped[i].labels[1] < dropped[j].labels[1] })
+		p.family("inertial_mqtt_messages_dropped_total", "counter",
+			"Messages lost between producer and web server, from sequence number gaps.", dropped...)
+		mu.RUnlock()
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if _, err := w.Write([]byte(p.String())); err != nil {
+			logging.Errorf("web: metrics write error: %v", err)
 		}
 	})
 
@@ -466,18 +964,33 @@ func RunWeb() error {
 			"weather_update_interval_minutes": cfg.WeatherUpdateIntervalMinutes,
 		}
 		if err := json.NewEncoder(w).Encode(configData); err != nil {
-			log.Printf("web: config JSON encode error: %v", err)
+			logging.Errorf("web: config JSON encode error: %v", err)
 		}
 	})
 
 	// Calibration WebSocket endpoint
 	http.HandleFunc("/api/calibration/ws", HandleCalibrationWS)
 
+	// Latest stored calibration per IMU
+	http.HandleFunc("/api/calibration", HandleCalibrationLatest)
+	http.HandleFunc("/api/calibration/download", HandleCalibrationDownload)
+
+	// Runtime calibration of imu_producer, sent over TOPIC_CALIBRATION
+	http.HandleFunc("/api/calibration/apply", calibrationCommandHandler(client, cfg.TopicCalibration, calibrationApply))
+	http.HandleFunc("/api/calibration/clear", calibrationCommandHandler(client, cfg.TopicCalibration, calibrationClear))
+
+	// Recorded sessions
+	http.HandleFunc("/api/recordings", HandleRecordingsList)
+	http.HandleFunc("/api/recordings/", HandleRecordingDownload)
+
 	// 7) Static UI from ./web
 	fs := http.FileServer(http.Dir("web"))
 	http.Handle("/", fs)
 
-	addr := fmt.Sprintf(":%d", cfg.WebServerPort)
-	log.Printf("web: listening on %s", addr)
-	return http.ListenAndServe(addr, nil)
+	addr := net.JoinHostPort(cfg.WebBindAddr, strconv.Itoa(cfg.WebServerPort))
+	if len(cfg.WebCORSAllowedOrigins) > 0 {
+		logging.Infof("web: CORS enabled for %s", strings.Join(cfg.WebCORSAllowedOrigins, ", "))
+	}
+	logging.Infof("web: listening on %s", addr)
+	return serveHTTP(ctx, addr, withCORS(cfg.WebCORSAllowedOrigins, http.DefaultServeMux))
 }
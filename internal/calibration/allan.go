@@ -0,0 +1,60 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+// Package calibration holds sensor characterization math shared by the
+// calibration tools.
+package calibration
+
+import "math"
+
+// allanPointsPerDecade is the density of the tau grid returned by
+// AllanDeviation.
+const allanPointsPerDecade = 10
+
+// AllanDeviation computes the overlapping Allan deviation of a static rate
+// signal (e.g. one gyro or accel axis) sampled every dt seconds. Cluster sizes
+// are log-spaced, about allanPointsPerDecade per decade, from one sample up to
+// half the record; taus are in seconds and adev in the units of samples.
+//
+// On a log-log plot the curve shows the usual noise terms: a -1/2 slope for
+// white noise (angle/velocity random walk, read at tau = 1 s), a flat minimum
+// for bias instability (min adev / 0.664), and a +1/2 slope for rate random
+// walk. The estimate at tau uses len(samples)*dt/tau clusters, so points near
+// the long end are noisy: trust taus up to about a tenth of the record.
+func AllanDeviation(samples []float64, dt float64) (taus, adev []float64) {
+	n := len(samples)
+	if n < 3 || dt <= 0 {
+		return nil, nil
+	}
+
+	// theta[k] is the integral of the signal up to sample k
+	theta := make([]float64, n+1)
+	for i, v := range samples {
+		theta[i+1] = theta[i] + v*dt
+	}
+
+	maxM := (n - 1) / 2
+	prev := 0
+	for i := 0; ; i++ {
+		m := int(math.Round(math.Pow(10, float64(i)/allanPointsPerDecade)))
+		if m > maxM {
+			break
+		}
+		if m == prev {
+			continue
+		}
+		prev = m
+
+		tau := float64(m) * dt
+		var sum float64
+		terms := n + 1 - 2*m
+		for k := 0; k < terms; k++ {
+			d := theta[k+2*m] - 2*theta[k+m] + theta[k]
+			sum += d * d
+		}
+		taus = append(taus, tau)
+		adev = append(adev, math.Sqrt(sum/(2*tau*tau*float64(terms))))
+	}
+	return taus, adev
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package calibration
+
+import (
+	"math"
+	"time"
+)
+
+// Confidence heuristics shared by cmd/calibration and the web calibration
+// UI, so both report the same numbers for the same data. Every confidence is
+// 0-1 (1 = nothing suspicious); a completed step never scores below
+// ConfidenceFloor. Gyro and accel inputs are raw counts, mag inputs µT.
+const (
+	// ConfidenceFloor is the lowest confidence of a completed step
+	ConfidenceFloor = 0.05
+
+	// Mean per-axis standard deviation (counts) of a static capture:
+	// confidence 1 up to StillStdGood, falling linearly to the floor at
+	// StillStdBad
+	StillStdGood = 3.0
+	StillStdBad  = 12.0
+
+	// A guided single-axis rotation scores full duration credit from
+	// RotationMinDuration
+	RotationMinDuration = 8 * time.Second
+
+	// Share of the mean absolute rate on the requested rotation axis
+	dominanceGood = 0.70
+	dominanceBad  = 0.45
+
+	// MinMeanAbsRate is the mean absolute gyro rate (counts) on the
+	// requested axis below which a rotation is not considered real
+	MinMeanAbsRate = 20.0
+
+	// Weights of the overall confidence: gyro static is foundational, mag
+	// matters for yaw
+	weightGyroStatic   = 0.20
+	weightGyroRotation = 0.20
+	weightAccel        = 0.25
+	weightMag          = 0.35
+)
+
+// StillnessConfidence rates a static capture from its per-axis standard
+// deviations (counts).
+func StillnessConfidence(std [3]float64) float64 {
+	s := (std[0] + std[1] + std[2]) / 3
+	switch {
+	case s <= StillStdGood:
+		return 1
+	case s >= StillStdBad:
+		return ConfidenceFloor
+	default:
+		t := (s - StillStdGood) / (StillStdBad - StillStdGood)
+		return clamp01(1 - 0.95*t)
+	}
+}
+
+// RotationConfidence rates a guided rotation about one axis from its
+// duration, the share of the mean absolute rate on that axis (0-1) and the
+// mean absolute rate on it (counts).
+func RotationConfidence(durationSec, dominance, meanAbsRate float64) float64 {
+	durFactor := clamp01(durationSec / RotationMinDuration.Seconds())
+
+	var domFactor float64
+	switch {
+	case dominance >= dominanceGood:
+		domFactor = 1
+	case dominance <= dominanceBad:
+		domFactor = 0.2
+	default:
+		t := (dominance - dominanceBad) / (dominanceGood - dominanceBad)
+		domFactor = 0.2 + 0.8*clamp01(t)
+	}
+
+	// Grows to 1 at 4x the minimum rate
+	rateFactor := 0.2
+	if meanAbsRate >= MinMeanAbsRate {
+		rateFactor = clamp01(meanAbsRate / (4 * MinMeanAbsRate))
+	}
+	return floored(0.25*durFactor + 0.45*domFactor + 0.30*rateFactor)
+}
+
+// CombineRotationConfidence combines the confidences of the X/Y/Z guided
+// rotations, weighting each by itself so a poor axis counts less than in a
+// plain mean.
+func CombineRotationConfidence(axisConfidences []float64) float64 {
+	var conf, weights float64
+	for _, c := range axisConfidences {
+		w := clamp01(c)
+		weights += w
+		conf += w * c
+	}
+	if weights == 0 {
+		return ConfidenceFloor
+	}
+	return clamp01(conf / weights)
+}
+
+// GravityConsistencyConfidence rates how well the 1 g magnitudes seen on the
+// three accel axes (counts, half the +/- pose difference) agree.
+func GravityConsistencyConfidence(gx, gy, gz float64) float64 {
+	m := (gx + gy + gz) / 3
+	if m <= 0 {
+		return ConfidenceFloor
+	}
+	// Coefficient of variation: 0 -> 1, 0.15 -> 0.7, 0.35 -> 0.3
+	return clamp01(1 - std3(gx, gy, gz)/m/0.5)
+}
+
+// Accel6PointConfidence combines the stillness confidence of each pose with
+// the gravity consistency of the 6-point fit.
+func Accel6PointConfidence(poseConfidences []float64, gx, gy, gz float64) float64 {
+	if len(poseConfidences) == 0 {
+		return ConfidenceFloor
+	}
+	var poseConf float64
+	for _, c := range poseConfidences {
+		poseConf += c
+	}
+	poseConf /= float64(len(poseConfidences))
+	return floored(0.65*poseConf + 0.35*GravityConsistencyConfidence(gx, gy, gz))
+}
+
+// MagConfidence rates a min/max magnetometer calibration from the per-axis
+// half ranges of the capture (µT) and how constant the field magnitude of
+// the samples is after applying (sample - offset) * scale. Captures with an
+// axis barely excited (under 0.1 µT, one count) get the floor.
+func MagConfidence(halfRange [3]float64, samples [][3]float64, offset, scale [3]float64) float64 {
+	for _, r := range halfRange {
+		if r < 0.1 {
+			return ConfidenceFloor
+		}
+	}
+	return floored(0.55*magCoverageConfidence(halfRange) + 0.45*magSphericityConfidence(samples, offset, scale))
+}
+
+// magCoverageConfidence rewards balanced excitation of the three axes.
+func magCoverageConfidence(halfRange [3]float64) float64 {
+	m := (halfRange[0] + halfRange[1] + halfRange[2]) / 3
+	if m <= 0 {
+		return ConfidenceFloor
+	}
+	return clamp01(1 - std3(halfRange[0], halfRange[1], halfRange[2])/m/0.7)
+}
+
+// magSphericityConfidence checks that the corrected field magnitude is
+// near-constant, as it is when the rotation covered all orientations.
+func magSphericityConfidence(samples [][3]float64, offset, scale [3]float64) float64 {
+	if len(samples) < 50 {
+		return ConfidenceFloor
+	}
+	var sum, sumSq float64
+	for _, s := range samples {
+		var n2 float64
+		for i := range s {
+			v := (s[i] - offset[i]) * scale[i]
+			n2 += v * v
+		}
+		n := math.Sqrt(n2)
+		sum += n
+		sumSq += n * n
+	}
+	mean := sum / float64(len(samples))
+	if mean <= 0 {
+		return ConfidenceFloor
+	}
+	sd := math.Sqrt(math.Max(sumSq/float64(len(samples))-mean*mean, 0))
+	// Coefficient of variation: 0.05 -> 0.9, 0.15 -> 0.7, 0.35 -> 0.3
+	return clamp01(1 - sd/mean/0.5)
+}
+
+// OverallConfidence is the weighted confidence of a full calibration.
+func OverallConfidence(gyroStatic, gyroRotation, accel, mag float64) float64 {
+	return clamp01(weightGyroStatic*gyroStatic + weightGyroRotation*gyroRotation + weightAccel*accel + weightMag*mag)
+}
+
+func clamp01(x float64) float64 {
+	return math.Min(math.Max(x, 0), 1)
+}
+
+func floored(x float64) float64 {
+	return clamp01(math.Max(x, ConfidenceFloor))
+}
+
+func std3(a, b, c float64) float64 {
+	m := (a + b + c) / 3
+	return math.Sqrt(((a-m)*(a-m) + (b-m)*(b-m) + (c-m)*(c-m)) / 3)
+}
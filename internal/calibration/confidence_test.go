@@ -0,0 +1,126 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package calibration
+
+import (
+	"math"
+	"testing"
+)
+
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+func TestStillnessConfidence(t *testing.T) {
+	tests := []struct {
+		std  [3]float64
+		want float64
+	}{
+		{[3]float64{1, 2, 3}, 1},           // mean 2 counts: still
+		{[3]float64{3, 3, 3}, 1},           // at the good threshold
+		{[3]float64{7.5, 7.5, 7.5}, 0.525}, // halfway: 1 - 0.95/2
+		{[3]float64{12, 12, 12}, ConfidenceFloor},
+		{[3]float64{50, 0, 0}, ConfidenceFloor},
+	}
+	for _, tt := range tests {
+		if got := StillnessConfidence(tt.std); !near(got, tt.want) {
+			t.Errorf("StillnessConfidence(%v) = %v, want %v", tt.std, got, tt.want)
+		}
+	}
+}
+
+func TestRotationConfidence(t *testing.T) {
+	tests := []struct {
+		name                    string
+		dur, dominance, meanAbs float64
+		want                    float64
+	}{
+		{"good", 10, 0.8, 100, 1},
+		{"short", 4, 0.8, 100, 0.875},           // 0.25*0.5 + 0.45 + 0.30
+		{"off axis", 10, 0.3, 100, 0.64},        // 0.25 + 0.45*0.2 + 0.30
+		{"barely moving", 10, 0.8, 10, 0.76},    // 0.25 + 0.45 + 0.30*0.2
+		{"nothing", 0, 0, 0, 0.15},              // 0.45*0.2 + 0.30*0.2
+		{"half dominance", 10, 0.575, 40, 0.67}, // 0.25 + 0.45*0.6 + 0.30*0.5
+	}
+	for _, tt := range tests {
+		if got := RotationConfidence(tt.dur, tt.dominance, tt.meanAbs); !near(got, tt.want) {
+			t.Errorf("%s: RotationConfidence = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCombineRotationConfidence(t *testing.T) {
+	// (1*1 + 0.5*0.5) / 1.5
+	if got := CombineRotationConfidence([]float64{1, 0.5}); !near(got, 1.25/1.5) {
+		t.Errorf("got %v, want %v", got, 1.25/1.5)
+	}
+	if got := CombineRotationConfidence(nil); got != ConfidenceFloor {
+		t.Errorf("no axes: got %v, want the floor", got)
+	}
+}
+
+func TestAccel6PointConfidence(t *testing.T) {
+	still := []float64{1, 1, 1, 1, 1, 1}
+	if got := Accel6PointConfidence(still, 16384, 16384, 16384); !near(got, 1) {
+		t.Errorf("ideal: got %v, want 1", got)
+	}
+	// One axis sees a 20% larger 1 g: cv = 0.0943, consistency 0.811
+	got := Accel6PointConfidence(still, 16384, 16384, 16384*1.2)
+	want := 0.65 + 0.35*(1-std3(1, 1, 1.2)/(3.2/3)/0.5)
+	if !near(got, want) || got < 0.9 || got > 0.95 {
+		t.Errorf("uneven axes: got %v, want %v", got, want)
+	}
+	if got := Accel6PointConfidence(nil, 1, 1, 1); got != ConfidenceFloor {
+		t.Errorf("no poses: got %v, want the floor", got)
+	}
+}
+
+func TestMagConfidence(t *testing.T) {
+	// Samples on a sphere of 50 µT around (10, -5, 20), covering all axes
+	offset := [3]float64{10, -5, 20}
+	var samples [][3]float64
+	for i := range 200 {
+		theta := float64(i) * 0.31
+		phi := float64(i) * 0.17
+		samples = append(samples, [3]float64{
+			offset[0] + 50*math.Sin(theta)*math.Cos(phi),
+			offset[1] + 50*math.Sin(theta)*math.Sin(phi),
+			offset[2] + 50*math.Cos(theta),
+		})
+	}
+	half := [3]float64{50, 50, 50}
+	one := [3]float64{1, 1, 1}
+	if got := MagConfidence(half, samples, offset, one); !near(got, 1) {
+		t.Errorf("sphere: got %v, want 1", got)
+	}
+
+	// Wrong offset: the corrected magnitude varies
+	if got := MagConfidence(half, samples, [3]float64{}, one); got > 0.8 {
+		t.Errorf("wrong offset: got %v, want < 0.8", got)
+	}
+
+	// Unbalanced coverage: the coverage term drops to about 0
+	if got := MagConfidence([3]float64{50, 50, 0.5}, samples, offset, one); !near(got, 0.55*(1-std3(50, 50, 0.5)/(100.5/3)/0.7)+0.45) {
+		t.Errorf("unbalanced: got %v", got)
+	}
+
+	if got := MagConfidence([3]float64{50, 50, 0.05}, samples, offset, one); got != ConfidenceFloor {
+		t.Errorf("unexcited axis: got %v, want the floor", got)
+	}
+	if got := MagConfidence(half, samples[:10], offset, one); !near(got, 0.55+0.45*ConfidenceFloor) {
+		t.Errorf("few samples: got %v, want %v", got, 0.55+0.45*ConfidenceFloor)
+	}
+}
+
+func TestOverallConfidence(t *testing.T) {
+	if got := OverallConfidence(1, 1, 1, 1); !near(got, 1) {
+		t.Errorf("all 1: got %v", got)
+	}
+	// Mag weighs most
+	if got := OverallConfidence(1, 1, 1, 0); !near(got, 0.65) {
+		t.Errorf("no mag: got %v, want 0.65", got)
+	}
+	if got := OverallConfidence(0.5, 0.5, 0.5, 0.5); !near(got, 0.5) {
+		t.Errorf("all 0.5: got %v", got)
+	}
+}
@@ -5,9 +5,12 @@
 package config
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
+	"log"
+	"net"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
@@ -23,25 +26,64 @@ type Config struct {
 	MQTTClientIDWeb      string
 	MQTTClientIDDisplay  string
 	MQTTClientIDHMC      string
+	MQTTClientIDFusion   string
+	MQTTClientIDHealth   string
+	MQTTClientIDLogger   string
+	MQTTClientIDInflux   string
+	MQTTClientIDSinks    string
+	MQTTKeepAlive        int // seconds (0 = 30)
+
+	// MQTT publish settings. Per-publisher QoS of -1 falls back to MQTTQoS.
+	MQTTQoS          byte
+	MQTTQoSIMU       int
+	MQTTQoSGPS       int
+	MQTTQoSHMC       int
+	MQTTQoSFusion    int
+	MQTTRetainIMU    bool
+	MQTTRetainGPS    bool
+	MQTTRetainHMC    bool
+	MQTTRetainFusion bool
+
+	MQTTSequenceNumbers bool // add per-topic "seq" to IMU and pose payloads
 
 	// Topics
+	TopicPrefix            string // namespace for topics left at their default ("" = none)
 	TopicPoseLeft          string
 	TopicPoseRight         string
 	TopicPoseFused         string
+	TopicPoseReference     string // retained level reference state; commands on <topic>/set ("" = off)
+	TopicCalibration       string // retained active IMU calibrations; commands on <topic>/set ("" = off)
 	TopicIMULeft           string
 	TopicIMURight          string
 	TopicMagLeft           string
 	TopicMagRight          string
 	TopicBMPLeft           string
 	TopicBMPRight          string
+	TopicBMPDiff           string
+	TopicVarioLeft         string
+	TopicVarioRight        string
+	TopicMotionLeft        string // turn rate, accel magnitude, moving ("" = off)
+	TopicMotionRight       string
 	TopicGPSPosition       string
 	TopicGPSVelocity       string
 	TopicGPSQuality        string
 	TopicGPSSatellites     string
 	TopicGLONASSSatellites string
 	TopicGPS               string
+	TopicRTCMIn            string // RTCM corrections to forward to the GPS receiver ("" = off)
+	TopicGPSEvents         string // geofence enter/exit events
 	// External magnetometer topic
 	TopicMagHMC string
+	// Fused navigation state topic
+	TopicFusedState string
+	// IMU read-rate/error health metrics topic
+	TopicIMUHealth string
+	// Per-client status topics: <prefix>/<client id>
+	TopicStatusPrefix string
+	// System health rollup published by the health monitor
+	TopicSystemHealth string
+	// Supply/battery voltage published by the health monitor
+	TopicBattery string
 
 	// HMC5983 external magnetometer
 	HMCI2CBus         int
@@ -53,11 +95,29 @@ type Config struct {
 	HMCSampleInterval int // milliseconds
 
 	// IMU Hardware
+	IMULeftEnabled    bool // false = never opened or read (single-IMU rigs)
+	IMURightEnabled   bool
 	IMULeftSPIDevice  string
 	IMULeftCSPin      string
 	IMURightSPIDevice string
 	IMURightCSPin     string
 
+	// Data-ready interrupt sampling: read on the MPU9250 INT edge instead of a
+	// timer (falls back to polling for an IMU without an INT pin)
+	IMUInterruptSampling bool
+	IMULeftIntPin        string // GPIO wired to the left IMU INT pin ("" = none)
+	IMURightIntPin       string // GPIO wired to the right IMU INT pin ("" = none)
+
+	// Wake-on-motion: the producer keeps the IMUs in low-power accel mode and
+	// starts streaming once one of them moves (battery deployments)
+	IMUWakeOnMotion   bool
+	IMUWOMThresholdMg int     // motion threshold, 4-1020 mg (0 = 100)
+	IMUWOMODRHz       float64 // low-power accel rate, 0.24-500 Hz (0 = 31.25)
+
+	// Sensor-to-vehicle axis remap per IMU, e.g. "+y,-x,+z" (zero = as mounted)
+	IMULeftAxisMap  AxisMap
+	IMURightAxisMap AxisMap
+
 	// IMU Sensor Ranges
 	// Accelerometer: 0=±2g, 1=±4g, 2=±8g, 3=±16g
 	IMUAccelRange byte
@@ -69,10 +129,38 @@ type Config struct {
 	IMUSampleRateDiv byte // Sample rate divider (output rate = internal rate / (1 + div))
 	IMUAccelDLPF     byte // Accelerometer DLPF configuration (0-7)
 
+	// Factory self-test pass limit: max |deviation| from factory trim (%)
+	IMUSelfTestMaxDeviation float64
+
+	// Attitude estimation in imu_producer
+	AttitudeMode         string  // "accel_yaw" (accel roll/pitch + gyro yaw) or "gyro_full"; used when OrientationAlgorithm is empty
+	OrientationAlgorithm string  // "tilt", "complementary", "madgwick", "mahony" or "ekf" ("" = from AttitudeMode)
+	ComplementaryAlpha   float64 // gyro weight for "complementary" (0 = 0.98)
+	MadgwickBeta         float64 // Madgwick gain (0 = 0.1)
+	MahonyKp             float64 // Mahony proportional gain (0 = 0.5)
+	MahonyKi             float64 // Mahony integral gain (0 = off)
+	EKFGyroNoise         float64 // EKF gyro noise density, °/s/√Hz (0 = 0.05)
+	EKFGyroBiasNoise     float64 // EKF gyro bias random walk, °/s/√s (0 = 0.002)
+	EKFAccelNoise        float64 // EKF accel noise, g (0 = 0.05)
+	EKFMagNoise          float64 // EKF mag heading noise, degrees (0 = 5)
+
+	// Runtime gyro bias tracking in imu_producer (learns while stationary)
+	GyroBiasTracking     bool
+	GyroBiasLearningRate float64 // per stationary sample, 0-1 (0 = 0.01)
+
+	// Optional raw accel/gyro smoothing in imu_producer
+	IMUFilter       string // "none", "moving_average" or "median"
+	IMUFilterWindow int    // samples (0 = 5)
+
 	// BMP Hardware
 	BMPLeftSPIDevice  string
 	BMPRightSPIDevice string
 
+	// Sea-level reference pressure for barometric altitude (hPa)
+	BMPSeaLevelHPa float64
+	// Variometer pressure/rate filter time constant (milliseconds, 0 = 1000)
+	VarioTimeConstant int
+
 	// BMP Left Configuration
 	BMPLeftPressureOSR byte
 	BMPLeftTempOSR     byte
@@ -88,8 +176,16 @@ type Config struct {
 	BMPRightStandbyTime byte
 
 	// GPS
-	GPSSerialPort string
+	GPSSerialPort string // serial device, or "file:/path" to replay captured NMEA
 	GPSBaudRate   int
+	GPSMinUsedSNR int    // dB; satellites at or above this SNR count as "used" in quality summary (default 25, 0 = every satellite)
+	GPSNMEALog    string // append raw NMEA lines to this file ("" = off)
+	GPSReplayLoop bool   // replay: start over at end of file instead of exiting
+
+	GPSSentenceFilter []string // NMEA types ("RMC") or talker+type ("GNRMC") to parse; empty = all
+
+	GPSGeofences           []Geofence // GPS_GEOFENCE, one per line
+	GPSGeofenceHysteresisM float64    // boundary band against flapping (0 = 10 m)
 
 	// Magnetometer Configuration
 	MagWriteDelayMS      int  // Delay after magnetometer write operations (ms)
@@ -103,20 +199,70 @@ type Config struct {
 	RegisterDebugMagReadDelay  int  // Experimental read delay override (-1 = use MAG_READ_DELAY_MS)
 	RegisterDebugMagUnsafeMode bool // Allow unsafe magnetometer operations in register debug
 
+	// Simulated IMUs, BMPs and GPS instead of SPI/serial hardware
+	MockHardware bool
+
 	// Timing
-	IMUSampleInterval  int // milliseconds
-	ConsoleLogInterval int // milliseconds
+	IMUSampleInterval    int     // milliseconds
+	IMUStreamInterval    int     // milliseconds, StreamLeft/StreamRight reader rate (0 = IMU_SAMPLE_INTERVAL)
+	PublishDecimation    int     // publish raw IMU/mag every Nth sample (0/1 = every sample)
+	PosePublishDeadband  float64 // degrees a pose must move before it is republished (0 = every sample)
+	PosePublishKeepalive int     // seconds between pose publishes inside the deadband (0 = 2)
+	ConsoleLogInterval   int     // milliseconds
+
+	// Logging
+	LogLevel  string // debug, info, warn or error ("" = info)
+	LogFormat string // text or json ("" = text)
+
+	// Fusion
+	FusionPublishInterval  int     // milliseconds
+	FusionMinGPSSpeedKnots float64 // GPS course is used for heading above this speed
+	FusionDRMaxDuration    int     // seconds of dead reckoning during a GPS outage (0 = 30)
+	MagDeclinationDeg      float64 // magnetic declination, positive east (true = magnetic + declination)
+	MagDeclinationTable    string  // lat,lon,declination grid file looked up at the GPS position ("" = constant only)
+	MagDeclinationModel    bool    // compute the declination at the GPS position from the embedded WMM
+
+	// Health monitor
+	HealthStaleTimeout    int // seconds without a message before a topic is stale (0 = 5)
+	HealthPublishInterval int // milliseconds between rollups (0 = 1000)
+	HealthHTTPPort        int // HTTP port for /api/health (0 = 8081)
+
+	// Supply/battery voltage read by the health monitor
+	BatterySource       string  // "" (none), "file" or "ina219"
+	BatteryFilePath     string  // file holding a number, for BATTERY_SOURCE=file
+	BatteryI2CBus       int     // I2C bus of the INA219 (0 = 1)
+	BatteryI2CAddr      uint16  // INA219 address (0 = 0x40)
+	BatteryVoltageScale float64 // multiplier applied to readings (0 = 1)
+	BatteryLowVoltage   float64 // readings below it are flagged low (0 = off)
+
+	// InfluxDB bridge
+	InfluxURL           string // line protocol write endpoint ("" = stdout)
+	InfluxToken         string // InfluxDB 2 API token ("" = none)
+	InfluxBatchSize     int    // lines per write (0 = 500)
+	InfluxFlushInterval int    // milliseconds between writes of a partial batch (0 = 1000)
+
+	// Sink router
+	Sinks []string // "name" or "name:arg" entries, e.g. "stdout", "file:/data/run.jsonl", "influx"
 
 	// Web Server
 	WebServerPort                int
+	WebBindAddr                  string   // interface to listen on ("" = all interfaces)
+	WebCORSAllowedOrigins        []string // origins allowed to call /api/* ("*" = any, empty = same-origin only)
+	WebStaleThreshold            int      // milliseconds; API reports a stream stale past this age (0 = 3000; low-rate streams allow more)
+	RecordingsDir                string   // JSONL sessions served by /api/recordings ("" = working directory)
+	CalibrationMinConfidence     float64  // 0-1; web calibrations below it need a confirm to save (0 = 0.5)
 	WeatherUpdateIntervalMinutes int
 
 	// Display
 	DisplayLeftI2CAddr    uint16
 	DisplayRightI2CAddr   uint16
 	DisplayUpdateInterval int    // milliseconds
-	DisplayLeftContent    string // what to show: "imu_raw_left", "imu_raw_right", "orientation_left", "orientation_right", "gps"
-	DisplayRightContent   string // what to show: "imu_raw_left", "imu_raw_right", "orientation_left", "orientation_right", "gps"
+	DisplayLeftContent    string // what to show: "imu_raw_left", "imu_raw_right", "orientation_left", "orientation_right", "attitude_left", "attitude_right", "env_left", "env_right", "gps", "cycle"
+	DisplayRightContent   string // what to show: "imu_raw_left", "imu_raw_right", "orientation_left", "orientation_right", "attitude_left", "attitude_right", "env_left", "env_right", "gps", "cycle"
+	DisplayCycleSeconds   int    // dwell time per page for the "cycle" content mode
+
+	DisplayReinitAfterFailures int // consecutive draw failures before re-creating the SSD1306 device
+	DisplayMaxBackoffMS        int // upper bound for retry backoff after draw failures (ms)
 
 	// Register Debugging Topics
 	TopicRegistersCmdRead     string
@@ -129,7 +275,7 @@ type Config struct {
 	TopicRegistersStatus      string
 
 	// Register Debugging Configuration
-	RegisterDebugAllowedRanges     string // e.g., "0x1B-0x1D,0x6B" - writable register ranges
+	RegisterDebugAllowedRanges     string // e.g., "0x1B-0x1D,0x6B" - writable register ranges ("" = none), see ParseRegisterRanges
 	RegisterDebugDefaultReadSpeed  int64  // Hz
 	RegisterDebugDefaultWriteSpeed int64  // Hz
 	RegisterDebugMaxSPISpeed       int64  // Hz
@@ -153,7 +299,9 @@ var (
 	configMu     sync.RWMutex
 )
 
-// Load reads the configuration file and returns a Config struct.
+// Load reads the configuration file and returns a Config struct. Files
+// ending in .yaml/.yml or .toml are read as YAML or TOML (see structured.go
+// for how nested keys map to KEY names); anything else is KEY=VALUE lines.
 func Load(configPath string) (*Config, error) {
 	file, err := os.Open(configPath)
 	if err != nil {
@@ -161,47 +309,180 @@ func Load(configPath string) (*Config, error) {
 	}
 	defer file.Close()
 
-	cfg := &Config{}
-	scanner := bufio.NewScanner(file)
-	lineNum := 0
+	// Defaults for keys whose zero value is not the historical behavior
+	cfg := &Config{
+		MQTTQoSIMU:    -1,
+		MQTTQoSGPS:    -1,
+		MQTTQoSHMC:    -1,
+		MQTTQoSFusion: -1,
+		MQTTRetainIMU: true,
 
-	for scanner.Scan() {
-		lineNum++
-		line := strings.TrimSpace(scanner.Text())
+		IMULeftEnabled:  true,
+		IMURightEnabled: true,
 
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
+		GPSMinUsedSNR: 25,
+	}
+	var entries []entry
+	switch strings.ToLower(filepath.Ext(configPath)) {
+	case ".yaml", ".yml":
+		entries, err = parseYAML(file)
+	case ".toml":
+		entries, err = parseTOML(file)
+	default:
+		entries, err = parseKeyValue(file)
+	}
+	if err != nil {
+		return nil, err
+	}
 
-		// Parse KEY=VALUE
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
-			return nil, fmt.Errorf("invalid config line %d: %q", lineNum, line)
+	for _, e := range entries {
+		if err := cfg.setValue(e.key, e.value); err != nil {
+			return nil, fmt.Errorf("config line %d: %w", e.line, err)
 		}
+	}
+	cfg.applyTopicPrefix()
 
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
+	// Validate required fields
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Geofence is a circular zone from GPS_GEOFENCE.
+type Geofence struct {
+	Name    string
+	Lat     float64 // decimal degrees
+	Lon     float64 // decimal degrees
+	RadiusM float64
+}
 
-		if err := cfg.setValue(key, value); err != nil {
-			return nil, fmt.Errorf("config line %d: %w", lineNum, err)
+// parseGeofence parses a "name,lat,lon,radius_m" GPS_GEOFENCE value.
+func parseGeofence(value string) (Geofence, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return Geofence{}, fmt.Errorf("must be name,lat,lon,radius_m")
+	}
+	g := Geofence{Name: strings.TrimSpace(parts[0])}
+	if g.Name == "" {
+		return Geofence{}, fmt.Errorf("name is empty")
+	}
+	var nums [3]float64
+	for i, p := range parts[1:] {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return Geofence{}, err
 		}
+		nums[i] = v
 	}
+	g.Lat, g.Lon, g.RadiusM = nums[0], nums[1], nums[2]
+	if g.Lat < -90 || g.Lat > 90 || g.Lon < -180 || g.Lon > 180 {
+		return Geofence{}, fmt.Errorf("center out of range")
+	}
+	if g.RadiusM <= 0 {
+		return Geofence{}, fmt.Errorf("radius must be > 0")
+	}
+	return g, nil
+}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading config file: %w", err)
+// AxisMap is a parsed IMU_*_AXIS_MAP: entry i is the sensor axis feeding
+// vehicle axis i as a signed 1-based index (+2 = +y, -1 = -x). The zero value
+// means no remap.
+type AxisMap [3]int8
+
+// RegisterRange is an inclusive range of register addresses from
+// REGISTER_DEBUG_ALLOWED_RANGES.
+type RegisterRange struct {
+	Lo, Hi byte
+}
+
+// ParseRegisterRanges parses a comma-separated list of hex addresses and
+// ranges ("0x1A-0x1E,0x6B"). An empty value is an empty list.
+func ParseRegisterRanges(value string) ([]RegisterRange, error) {
+	var ranges []RegisterRange
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	for _, part := range strings.Split(value, ",") {
+		lo, hi, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		if !isRange {
+			hi = lo
+		}
+		var r RegisterRange
+		for _, p := range []struct {
+			s   string
+			dst *byte
+		}{{lo, &r.Lo}, {hi, &r.Hi}} {
+			digits, ok := strings.CutPrefix(strings.ToLower(strings.TrimSpace(p.s)), "0x")
+			v, err := strconv.ParseUint(digits, 16, 8)
+			if !ok || err != nil {
+				return nil, fmt.Errorf("%q is not a hex register address like 0x1B", strings.TrimSpace(p.s))
+			}
+			*p.dst = byte(v)
+		}
+		if r.Lo > r.Hi {
+			return nil, fmt.Errorf("range %q is reversed", strings.TrimSpace(part))
+		}
+		ranges = append(ranges, r)
 	}
+	return ranges, nil
+}
 
-	// Validate required fields
-	if err := cfg.validate(); err != nil {
-		return nil, err
+// parseAxisMap parses "+y,-x,+z" style IMU_*_AXIS_MAP values. Every sensor
+// axis must be used once and the result must be a rotation (right-handed),
+// which leaves 24 valid maps.
+func parseAxisMap(value string) (AxisMap, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 3 {
+		return AxisMap{}, fmt.Errorf("must be three signed axes like +y,-x,+z")
+	}
+	var m AxisMap
+	var used [3]bool
+	var rows [3][3]int
+	for i, p := range parts {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if len(p) != 2 || (p[0] != '+' && p[0] != '-') {
+			return AxisMap{}, fmt.Errorf("axis %q must be +x, -x, +y, -y, +z or -z", p)
+		}
+		axis := strings.IndexByte("xyz", p[1])
+		if axis < 0 {
+			return AxisMap{}, fmt.Errorf("axis %q must be +x, -x, +y, -y, +z or -z", p)
+		}
+		if used[axis] {
+			return AxisMap{}, fmt.Errorf("axis %c used twice", p[1])
+		}
+		used[axis] = true
+		sign := 1
+		if p[0] == '-' {
+			sign = -1
+		}
+		m[i] = int8(sign * (axis + 1))
+		rows[i][axis] = sign
 	}
+	det := rows[0][0]*(rows[1][1]*rows[2][2]-rows[1][2]*rows[2][1]) -
+		rows[0][1]*(rows[1][0]*rows[2][2]-rows[1][2]*rows[2][0]) +
+		rows[0][2]*(rows[1][0]*rows[2][1]-rows[1][1]*rows[2][0])
+	if det != 1 {
+		return AxisMap{}, fmt.Errorf("mirrors the frame (left-handed); flip the sign of one axis")
+	}
+	return m, nil
+}
 
-	return cfg, nil
+// deprecatedKeys maps renamed config keys to their replacement, so old files
+// keep loading. Each use logs a warning; if a file sets both, the later line
+// wins.
+var deprecatedKeys = map[string]string{
+	"TOPIC_POSE": "TOPIC_POSE_LEFT", // single pose topic from before the left/right/fused split
 }
 
 // setValue sets a config value based on the key.
 func (c *Config) setValue(key, value string) error {
+	if newKey, ok := deprecatedKeys[key]; ok {
+		log.Printf("config: %s is deprecated, use %s instead", key, newKey)
+		key = newKey
+	}
+
 	switch key {
 	// MQTT
 	case "MQTT_BROKER":
@@ -218,14 +499,84 @@ func (c *Config) setValue(key, value string) error {
 		c.MQTTClientIDDisplay = value
 	case "MQTT_CLIENT_ID_HMC":
 		c.MQTTClientIDHMC = value
+	case "MQTT_CLIENT_ID_FUSION":
+		c.MQTTClientIDFusion = value
+	case "MQTT_CLIENT_ID_HEALTH":
+		c.MQTTClientIDHealth = value
+	case "MQTT_CLIENT_ID_INFLUX":
+		c.MQTTClientIDInflux = value
+	case "MQTT_CLIENT_ID_SINKS":
+		c.MQTTClientIDSinks = value
+	case "MQTT_CLIENT_ID_LOGGER":
+		c.MQTTClientIDLogger = value
+	case "MQTT_KEEPALIVE":
+		secs, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid MQTT_KEEPALIVE %q: %w", value, err)
+		}
+		if secs < 0 {
+			return fmt.Errorf("MQTT_KEEPALIVE must be >= 0, got %d", secs)
+		}
+		c.MQTTKeepAlive = secs
+	case "MQTT_QOS":
+		qos, err := parseQoS(key, value)
+		if err != nil {
+			return err
+		}
+		if qos < 0 {
+			return fmt.Errorf("MQTT_QOS must be 0, 1 or 2, got %d", qos)
+		}
+		c.MQTTQoS = byte(qos)
+	case "MQTT_QOS_IMU", "MQTT_QOS_GPS", "MQTT_QOS_HMC", "MQTT_QOS_FUSION":
+		qos, err := parseQoS(key, value)
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "MQTT_QOS_IMU":
+			c.MQTTQoSIMU = qos
+		case "MQTT_QOS_GPS":
+			c.MQTTQoSGPS = qos
+		case "MQTT_QOS_HMC":
+			c.MQTTQoSHMC = qos
+		case "MQTT_QOS_FUSION":
+			c.MQTTQoSFusion = qos
+		}
+	case "MQTT_RETAIN_IMU", "MQTT_RETAIN_GPS", "MQTT_RETAIN_HMC", "MQTT_RETAIN_FUSION":
+		val, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", key, value, err)
+		}
+		switch key {
+		case "MQTT_RETAIN_IMU":
+			c.MQTTRetainIMU = val
+		case "MQTT_RETAIN_GPS":
+			c.MQTTRetainGPS = val
+		case "MQTT_RETAIN_HMC":
+			c.MQTTRetainHMC = val
+		case "MQTT_RETAIN_FUSION":
+			c.MQTTRetainFusion = val
+		}
+	case "MQTT_SEQUENCE_NUMBERS":
+		val, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid MQTT_SEQUENCE_NUMBERS %q: %w", value, err)
+		}
+		c.MQTTSequenceNumbers = val
 
 	// Topics
+	case "TOPIC_STATUS_PREFIX":
+		c.TopicStatusPrefix = strings.TrimSuffix(value, "/")
 	case "TOPIC_POSE_LEFT":
 		c.TopicPoseLeft = value
 	case "TOPIC_POSE_RIGHT":
 		c.TopicPoseRight = value
 	case "TOPIC_POSE_FUSED":
 		c.TopicPoseFused = value
+	case "TOPIC_POSE_REFERENCE":
+		c.TopicPoseReference = value
+	case "TOPIC_CALIBRATION":
+		c.TopicCalibration = value
 	case "TOPIC_IMU_LEFT":
 		c.TopicIMULeft = value
 	case "TOPIC_IMU_RIGHT":
@@ -238,6 +589,16 @@ func (c *Config) setValue(key, value string) error {
 		c.TopicBMPLeft = value
 	case "TOPIC_BMP_RIGHT":
 		c.TopicBMPRight = value
+	case "TOPIC_BMP_DIFF":
+		c.TopicBMPDiff = value
+	case "TOPIC_VARIO_LEFT":
+		c.TopicVarioLeft = value
+	case "TOPIC_VARIO_RIGHT":
+		c.TopicVarioRight = value
+	case "TOPIC_MOTION_LEFT":
+		c.TopicMotionLeft = value
+	case "TOPIC_MOTION_RIGHT":
+		c.TopicMotionRight = value
 	case "TOPIC_GPS_POSITION":
 		c.TopicGPSPosition = value
 	case "TOPIC_GPS_VELOCITY":
@@ -250,8 +611,26 @@ func (c *Config) setValue(key, value string) error {
 		c.TopicGLONASSSatellites = value
 	case "TOPIC_GPS":
 		c.TopicGPS = value
+	case "TOPIC_RTCM_IN":
+		c.TopicRTCMIn = value
+	case "TOPIC_GPS_EVENTS":
+		c.TopicGPSEvents = value
 	case "TOPIC_MAG_HMC":
 		c.TopicMagHMC = value
+	case "TOPIC_FUSED_STATE":
+		c.TopicFusedState = value
+	case "TOPIC_IMU_HEALTH":
+		c.TopicIMUHealth = value
+	case "TOPIC_SYSTEM_HEALTH":
+		c.TopicSystemHealth = value
+	case "TOPIC_BATTERY":
+		c.TopicBattery = value
+	case "TOPIC_PREFIX":
+		prefix, err := parseTopicPrefix(value)
+		if err != nil {
+			return err
+		}
+		c.TopicPrefix = prefix
 
 	// HMC5983 external magnetometer
 	case "HMC_I2C_BUS":
@@ -294,6 +673,18 @@ func (c *Config) setValue(key, value string) error {
 		c.HMCSampleInterval = v
 
 	// IMU Hardware
+	case "IMU_LEFT_ENABLED":
+		val, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid IMU_LEFT_ENABLED %q: %w", value, err)
+		}
+		c.IMULeftEnabled = val
+	case "IMU_RIGHT_ENABLED":
+		val, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid IMU_RIGHT_ENABLED %q: %w", value, err)
+		}
+		c.IMURightEnabled = val
 	case "IMU_LEFT_SPI_DEVICE":
 		c.IMULeftSPIDevice = value
 	case "IMU_LEFT_CS_PIN":
@@ -302,6 +693,53 @@ func (c *Config) setValue(key, value string) error {
 		c.IMURightSPIDevice = value
 	case "IMU_RIGHT_CS_PIN":
 		c.IMURightCSPin = value
+	case "IMU_INTERRUPT_SAMPLING":
+		val, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid IMU_INTERRUPT_SAMPLING %q: %w", value, err)
+		}
+		c.IMUInterruptSampling = val
+	case "IMU_LEFT_INT_PIN":
+		c.IMULeftIntPin = value
+	case "IMU_RIGHT_INT_PIN":
+		c.IMURightIntPin = value
+	case "IMU_WAKE_ON_MOTION":
+		val, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid IMU_WAKE_ON_MOTION %q: %w", value, err)
+		}
+		c.IMUWakeOnMotion = val
+	case "IMU_WOM_THRESHOLD_MG":
+		val, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid IMU_WOM_THRESHOLD_MG %q: %w", value, err)
+		}
+		if val != 0 && (val < 4 || val > 1020) {
+			return fmt.Errorf("IMU_WOM_THRESHOLD_MG must be 0 or 4-1020, got %d", val)
+		}
+		c.IMUWOMThresholdMg = val
+	case "IMU_WOM_ODR_HZ":
+		val, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid IMU_WOM_ODR_HZ %q: %w", value, err)
+		}
+		if val < 0 || val > 500 {
+			return fmt.Errorf("IMU_WOM_ODR_HZ must be 0-500, got %.2f", val)
+		}
+		c.IMUWOMODRHz = val
+	case "IMU_LEFT_AXIS_MAP", "IMU_RIGHT_AXIS_MAP":
+		var m AxisMap
+		if value != "" {
+			var err error
+			if m, err = parseAxisMap(value); err != nil {
+				return fmt.Errorf("invalid %s %q: %w", key, value, err)
+			}
+		}
+		if key == "IMU_LEFT_AXIS_MAP" {
+			c.IMULeftAxisMap = m
+		} else {
+			c.IMURightAxisMap = m
+		}
 
 	// IMU Sensor Ranges
 	case "IMU_ACCEL_RANGE":
@@ -351,6 +789,99 @@ func (c *Config) setValue(key, value string) error {
 			return fmt.Errorf("IMU_ACCEL_DLPF must be 0-7, got %d", val)
 		}
 		c.IMUAccelDLPF = byte(val)
+	case "ATTITUDE_MODE":
+		if value != "accel_yaw" && value != "gyro_full" {
+			return fmt.Errorf("invalid ATTITUDE_MODE %q: must be accel_yaw or gyro_full", value)
+		}
+		c.AttitudeMode = value
+	case "ORIENTATION_ALGORITHM":
+		switch value {
+		case "tilt", "complementary", "madgwick", "mahony", "ekf":
+		default:
+			return fmt.Errorf("invalid ORIENTATION_ALGORITHM %q: must be tilt, complementary, madgwick, mahony or ekf", value)
+		}
+		c.OrientationAlgorithm = value
+	case "MADGWICK_BETA", "MAHONY_KP", "MAHONY_KI":
+		gain, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", key, value, err)
+		}
+		if gain < 0 {
+			return fmt.Errorf("%s must be >= 0, got %.3f", key, gain)
+		}
+		switch key {
+		case "MADGWICK_BETA":
+			c.MadgwickBeta = gain
+		case "MAHONY_KP":
+			c.MahonyKp = gain
+		case "MAHONY_KI":
+			c.MahonyKi = gain
+		}
+	case "EKF_GYRO_NOISE", "EKF_GYRO_BIAS_NOISE", "EKF_ACCEL_NOISE", "EKF_MAG_NOISE":
+		noise, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", key, value, err)
+		}
+		if noise < 0 {
+			return fmt.Errorf("%s must be >= 0, got %.3f", key, noise)
+		}
+		switch key {
+		case "EKF_GYRO_NOISE":
+			c.EKFGyroNoise = noise
+		case "EKF_GYRO_BIAS_NOISE":
+			c.EKFGyroBiasNoise = noise
+		case "EKF_ACCEL_NOISE":
+			c.EKFAccelNoise = noise
+		case "EKF_MAG_NOISE":
+			c.EKFMagNoise = noise
+		}
+	case "COMPLEMENTARY_ALPHA":
+		alpha, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid COMPLEMENTARY_ALPHA %q: %w", value, err)
+		}
+		if alpha < 0 || alpha > 1 {
+			return fmt.Errorf("COMPLEMENTARY_ALPHA must be between 0 and 1, got %.3f", alpha)
+		}
+		c.ComplementaryAlpha = alpha
+	case "GYRO_BIAS_TRACKING":
+		val, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid GYRO_BIAS_TRACKING %q: %w", value, err)
+		}
+		c.GyroBiasTracking = val
+	case "GYRO_BIAS_LEARNING_RATE":
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid GYRO_BIAS_LEARNING_RATE %q: %w", value, err)
+		}
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("GYRO_BIAS_LEARNING_RATE must be between 0 and 1, got %.4f", rate)
+		}
+		c.GyroBiasLearningRate = rate
+	case "IMU_FILTER":
+		if value != "none" && value != "moving_average" && value != "median" {
+			return fmt.Errorf("invalid IMU_FILTER %q: must be none, moving_average or median", value)
+		}
+		c.IMUFilter = value
+	case "IMU_FILTER_WINDOW":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid IMU_FILTER_WINDOW %q: %w", value, err)
+		}
+		if n < 0 || n > 100 {
+			return fmt.Errorf("IMU_FILTER_WINDOW must be 0-100, got %d", n)
+		}
+		c.IMUFilterWindow = n
+	case "IMU_SELFTEST_MAX_DEVIATION":
+		val, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid IMU_SELFTEST_MAX_DEVIATION %q: %w", value, err)
+		}
+		if val <= 0 || val > 100 {
+			return fmt.Errorf("IMU_SELFTEST_MAX_DEVIATION must be > 0 and <= 100, got %.2f", val)
+		}
+		c.IMUSelfTestMaxDeviation = val
 
 	// BMP Hardware
 	case "BMP_LEFT_SPI_DEVICE":
@@ -358,6 +889,25 @@ func (c *Config) setValue(key, value string) error {
 	case "BMP_RIGHT_SPI_DEVICE":
 		c.BMPRightSPIDevice = value
 
+	case "BMP_SEA_LEVEL_HPA":
+		p, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid BMP_SEA_LEVEL_HPA %q: %w", value, err)
+		}
+		if p < 800 || p > 1100 {
+			return fmt.Errorf("BMP_SEA_LEVEL_HPA must be 800-1100 hPa, got %.2f", p)
+		}
+		c.BMPSeaLevelHPa = p
+	case "VARIO_TIME_CONSTANT":
+		ms, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid VARIO_TIME_CONSTANT %q: %w", value, err)
+		}
+		if ms < 0 {
+			return fmt.Errorf("VARIO_TIME_CONSTANT must be >= 0, got %d", ms)
+		}
+		c.VarioTimeConstant = ms
+
 	// BMP Left Configuration
 	case "BMP_LEFT_PRESSURE_OSR":
 		val, err := strconv.Atoi(value)
@@ -461,6 +1011,58 @@ func (c *Config) setValue(key, value string) error {
 			return fmt.Errorf("invalid GPS_BAUD_RATE %q: %w", value, err)
 		}
 		c.GPSBaudRate = rate
+	case "GPS_MIN_USED_SNR":
+		snr, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid GPS_MIN_USED_SNR %q: %w", value, err)
+		}
+		if snr < 0 || snr > 99 {
+			return fmt.Errorf("GPS_MIN_USED_SNR must be 0-99 dB, got %d", snr)
+		}
+		c.GPSMinUsedSNR = snr
+	case "GPS_NMEA_LOG":
+		c.GPSNMEALog = value
+	case "GPS_REPLAY_LOOP":
+		val, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid GPS_REPLAY_LOOP %q: %w", value, err)
+		}
+		c.GPSReplayLoop = val
+	case "GPS_SENTENCE_FILTER":
+		c.GPSSentenceFilter = nil
+		for _, entry := range strings.Split(value, ",") {
+			entry = strings.ToUpper(strings.TrimSpace(entry))
+			if entry == "" {
+				continue
+			}
+			if len(entry) != 3 && len(entry) != 5 {
+				return fmt.Errorf("invalid GPS_SENTENCE_FILTER entry %q: must be a sentence type (RMC) or talker+type (GNRMC)", entry)
+			}
+			c.GPSSentenceFilter = append(c.GPSSentenceFilter, entry)
+		}
+	case "GPS_GEOFENCE":
+		if value == "" {
+			break
+		}
+		g, err := parseGeofence(value)
+		if err != nil {
+			return fmt.Errorf("invalid GPS_GEOFENCE %q: %w", value, err)
+		}
+		for _, existing := range c.GPSGeofences {
+			if existing.Name == g.Name {
+				return fmt.Errorf("invalid GPS_GEOFENCE %q: duplicate name %q", value, g.Name)
+			}
+		}
+		c.GPSGeofences = append(c.GPSGeofences, g)
+	case "GPS_GEOFENCE_HYSTERESIS_M":
+		h, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid GPS_GEOFENCE_HYSTERESIS_M %q: %w", value, err)
+		}
+		if h < 0 {
+			return fmt.Errorf("invalid GPS_GEOFENCE_HYSTERESIS_M %q: must be >= 0", value)
+		}
+		c.GPSGeofenceHysteresisM = h
 
 	// Magnetometer Configuration
 	case "MAG_WRITE_DELAY_MS":
@@ -490,6 +1092,16 @@ func (c *Config) setValue(key, value string) error {
 			return fmt.Errorf("MAG_SCALE must be 0 or 1, got %d", val)
 		}
 		c.MagScale = byte(val)
+	case "MAG_RESOLUTION":
+		// Alternative to MAG_SCALE expressed in bits
+		switch value {
+		case "14":
+			c.MagScale = 0
+		case "16":
+			c.MagScale = 1
+		default:
+			return fmt.Errorf("MAG_RESOLUTION must be 14 or 16, got %q", value)
+		}
 	case "MAG_MODE":
 		// Parse hex value (0x06) or decimal
 		var val uint64
@@ -547,6 +1159,14 @@ func (c *Config) setValue(key, value string) error {
 		}
 		c.RegisterDebugMagUnsafeMode = val
 
+	// Simulation
+	case "MOCK_HARDWARE":
+		val, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid MOCK_HARDWARE %q: %w", value, err)
+		}
+		c.MockHardware = val
+
 	// Timing
 	case "IMU_SAMPLE_INTERVAL":
 		interval, err := strconv.Atoi(value)
@@ -554,6 +1174,42 @@ func (c *Config) setValue(key, value string) error {
 			return fmt.Errorf("invalid IMU_SAMPLE_INTERVAL %q: %w", value, err)
 		}
 		c.IMUSampleInterval = interval
+	case "IMU_STREAM_INTERVAL":
+		interval, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid IMU_STREAM_INTERVAL %q: %w", value, err)
+		}
+		if interval < 0 {
+			return fmt.Errorf("IMU_STREAM_INTERVAL must be >= 0, got %d", interval)
+		}
+		c.IMUStreamInterval = interval
+	case "PUBLISH_DECIMATION":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid PUBLISH_DECIMATION %q: %w", value, err)
+		}
+		if n < 0 {
+			return fmt.Errorf("PUBLISH_DECIMATION must be >= 0, got %d", n)
+		}
+		c.PublishDecimation = n
+	case "POSE_PUBLISH_DEADBAND":
+		deg, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid POSE_PUBLISH_DEADBAND %q: %w", value, err)
+		}
+		if deg < 0 || deg > 180 {
+			return fmt.Errorf("POSE_PUBLISH_DEADBAND must be between 0 and 180, got %.2f", deg)
+		}
+		c.PosePublishDeadband = deg
+	case "POSE_PUBLISH_KEEPALIVE":
+		secs, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid POSE_PUBLISH_KEEPALIVE %q: %w", value, err)
+		}
+		if secs < 0 {
+			return fmt.Errorf("POSE_PUBLISH_KEEPALIVE must be >= 0, got %d", secs)
+		}
+		c.PosePublishKeepalive = secs
 	case "CONSOLE_LOG_INTERVAL":
 		interval, err := strconv.Atoi(value)
 		if err != nil {
@@ -561,6 +1217,182 @@ func (c *Config) setValue(key, value string) error {
 		}
 		c.ConsoleLogInterval = interval
 
+	// Logging
+	case "LOG_LEVEL":
+		switch strings.ToLower(value) {
+		case "", "debug", "info", "warn", "warning", "error":
+		default:
+			return fmt.Errorf("invalid LOG_LEVEL %q: must be debug, info, warn or error", value)
+		}
+		c.LogLevel = strings.ToLower(value)
+	case "LOG_FORMAT":
+		switch value {
+		case "", "text", "json":
+		default:
+			return fmt.Errorf("invalid LOG_FORMAT %q: must be text or json", value)
+		}
+		c.LogFormat = value
+
+	// Fusion
+	case "FUSION_PUBLISH_INTERVAL":
+		interval, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid FUSION_PUBLISH_INTERVAL %q: %w", value, err)
+		}
+		c.FusionPublishInterval = interval
+	case "FUSION_MIN_GPS_SPEED_KNOTS":
+		speed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid FUSION_MIN_GPS_SPEED_KNOTS %q: %w", value, err)
+		}
+		if speed < 0 {
+			return fmt.Errorf("FUSION_MIN_GPS_SPEED_KNOTS must be >= 0, got %.2f", speed)
+		}
+		c.FusionMinGPSSpeedKnots = speed
+	case "FUSION_DR_MAX_DURATION":
+		secs, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid FUSION_DR_MAX_DURATION %q: %w", value, err)
+		}
+		if secs < 0 {
+			return fmt.Errorf("FUSION_DR_MAX_DURATION must be >= 0, got %d", secs)
+		}
+		c.FusionDRMaxDuration = secs
+	case "MAG_DECLINATION_DEG":
+		decl, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid MAG_DECLINATION_DEG %q: %w", value, err)
+		}
+		if decl < -180 || decl > 180 {
+			return fmt.Errorf("MAG_DECLINATION_DEG must be between -180 and 180, got %.2f", decl)
+		}
+		c.MagDeclinationDeg = decl
+	case "MAG_DECLINATION_TABLE":
+		c.MagDeclinationTable = value
+	case "MAG_DECLINATION_MODEL":
+		val, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid MAG_DECLINATION_MODEL %q: %w", value, err)
+		}
+		c.MagDeclinationModel = val
+
+	// Health monitor
+	case "HEALTH_STALE_TIMEOUT":
+		secs, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid HEALTH_STALE_TIMEOUT %q: %w", value, err)
+		}
+		if secs < 0 {
+			return fmt.Errorf("HEALTH_STALE_TIMEOUT must be >= 0, got %d", secs)
+		}
+		c.HealthStaleTimeout = secs
+	case "HEALTH_PUBLISH_INTERVAL":
+		ms, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid HEALTH_PUBLISH_INTERVAL %q: %w", value, err)
+		}
+		if ms < 0 {
+			return fmt.Errorf("HEALTH_PUBLISH_INTERVAL must be >= 0, got %d", ms)
+		}
+		c.HealthPublishInterval = ms
+	case "HEALTH_HTTP_PORT":
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid HEALTH_HTTP_PORT %q: %w", value, err)
+		}
+		if port < 0 || port > 65535 {
+			return fmt.Errorf("HEALTH_HTTP_PORT must be 0-65535, got %d", port)
+		}
+		c.HealthHTTPPort = port
+
+	// Supply/battery voltage
+	case "BATTERY_SOURCE":
+		switch value {
+		case "", "file", "ina219":
+		default:
+			return fmt.Errorf("invalid BATTERY_SOURCE %q: must be empty, file or ina219", value)
+		}
+		c.BatterySource = value
+	case "BATTERY_FILE_PATH":
+		c.BatteryFilePath = value
+	case "BATTERY_I2C_BUS":
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid BATTERY_I2C_BUS %q: %w", value, err)
+		}
+		if v < 0 {
+			return fmt.Errorf("BATTERY_I2C_BUS must be >= 0, got %d", v)
+		}
+		c.BatteryI2CBus = v
+	case "BATTERY_I2C_ADDR":
+		addr, err := strconv.ParseUint(value, 0, 16)
+		if err != nil {
+			return fmt.Errorf("invalid BATTERY_I2C_ADDR %q: %w", value, err)
+		}
+		if addr > 0x7F {
+			return fmt.Errorf("BATTERY_I2C_ADDR must be a 7-bit address, got 0x%X", addr)
+		}
+		c.BatteryI2CAddr = uint16(addr)
+	case "BATTERY_VOLTAGE_SCALE":
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid BATTERY_VOLTAGE_SCALE %q: %w", value, err)
+		}
+		if v < 0 {
+			return fmt.Errorf("BATTERY_VOLTAGE_SCALE must be >= 0, got %g", v)
+		}
+		c.BatteryVoltageScale = v
+	case "BATTERY_LOW_VOLTAGE":
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid BATTERY_LOW_VOLTAGE %q: %w", value, err)
+		}
+		if v < 0 {
+			return fmt.Errorf("BATTERY_LOW_VOLTAGE must be >= 0, got %g", v)
+		}
+		c.BatteryLowVoltage = v
+
+	// InfluxDB bridge
+	case "INFLUX_URL":
+		if value != "" && !strings.HasPrefix(value, "http://") && !strings.HasPrefix(value, "https://") {
+			return fmt.Errorf("invalid INFLUX_URL %q: must start with http:// or https://", value)
+		}
+		c.InfluxURL = value
+	case "INFLUX_TOKEN":
+		c.InfluxToken = value
+	case "INFLUX_BATCH_SIZE":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid INFLUX_BATCH_SIZE %q: %w", value, err)
+		}
+		if n < 0 {
+			return fmt.Errorf("INFLUX_BATCH_SIZE must be >= 0, got %d", n)
+		}
+		c.InfluxBatchSize = n
+	case "INFLUX_FLUSH_INTERVAL":
+		ms, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid INFLUX_FLUSH_INTERVAL %q: %w", value, err)
+		}
+		if ms < 0 {
+			return fmt.Errorf("INFLUX_FLUSH_INTERVAL must be >= 0, got %d", ms)
+		}
+		c.InfluxFlushInterval = ms
+
+	// Sink router
+	case "SINKS":
+		c.Sinks = nil
+		for _, entry := range strings.Split(value, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+			if name, _, _ := strings.Cut(entry, ":"); name == "" {
+				return fmt.Errorf("invalid SINKS entry %q: missing sink name", entry)
+			}
+			c.Sinks = append(c.Sinks, entry)
+		}
+
 	// Web Server
 	case "WEB_SERVER_PORT":
 		port, err := strconv.Atoi(value)
@@ -568,6 +1400,43 @@ func (c *Config) setValue(key, value string) error {
 			return fmt.Errorf("invalid WEB_SERVER_PORT %q: %w", value, err)
 		}
 		c.WebServerPort = port
+	case "WEB_BIND_ADDR":
+		if value != "" && value != "localhost" && net.ParseIP(value) == nil {
+			return fmt.Errorf("invalid WEB_BIND_ADDR %q: must be an IP address or localhost", value)
+		}
+		c.WebBindAddr = value
+	case "WEB_CORS_ALLOWED_ORIGIN":
+		c.WebCORSAllowedOrigins = nil
+		for _, origin := range strings.Split(value, ",") {
+			origin = strings.TrimSpace(origin)
+			if origin == "" {
+				continue
+			}
+			if origin != "*" && !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+				return fmt.Errorf("invalid WEB_CORS_ALLOWED_ORIGIN %q: origins must start with http:// or https://", origin)
+			}
+			c.WebCORSAllowedOrigins = append(c.WebCORSAllowedOrigins, strings.TrimSuffix(origin, "/"))
+		}
+	case "RECORDINGS_DIR":
+		c.RecordingsDir = value
+	case "CALIBRATION_MIN_CONFIDENCE":
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid CALIBRATION_MIN_CONFIDENCE %q: %w", value, err)
+		}
+		if v < 0 || v > 1 {
+			return fmt.Errorf("CALIBRATION_MIN_CONFIDENCE must be between 0 and 1, got %v", v)
+		}
+		c.CalibrationMinConfidence = v
+	case "WEB_STALE_THRESHOLD":
+		ms, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid WEB_STALE_THRESHOLD %q: %w", value, err)
+		}
+		if ms < 0 {
+			return fmt.Errorf("WEB_STALE_THRESHOLD must be >= 0, got %d", ms)
+		}
+		c.WebStaleThreshold = ms
 	case "WEATHER_UPDATE_INTERVAL_MINUTES":
 		minutes, err := strconv.Atoi(value)
 		if err != nil {
@@ -598,6 +1467,30 @@ func (c *Config) setValue(key, value string) error {
 		c.DisplayLeftContent = value
 	case "DISPLAY_RIGHT_CONTENT":
 		c.DisplayRightContent = value
+	case "DISPLAY_CYCLE_SECONDS":
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid DISPLAY_CYCLE_SECONDS %q: %w", value, err)
+		}
+		if seconds < 1 {
+			return fmt.Errorf("DISPLAY_CYCLE_SECONDS must be >= 1, got %d", seconds)
+		}
+		c.DisplayCycleSeconds = seconds
+	case "DISPLAY_REINIT_AFTER_FAILURES":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid DISPLAY_REINIT_AFTER_FAILURES %q: %w", value, err)
+		}
+		if n < 1 {
+			return fmt.Errorf("DISPLAY_REINIT_AFTER_FAILURES must be >= 1, got %d", n)
+		}
+		c.DisplayReinitAfterFailures = n
+	case "DISPLAY_MAX_BACKOFF_MS":
+		ms, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid DISPLAY_MAX_BACKOFF_MS %q: %w", value, err)
+		}
+		c.DisplayMaxBackoffMS = ms
 
 	// Register Debugging Topics
 	case "TOPIC_REGISTERS_CMD_READ":
@@ -619,6 +1512,9 @@ func (c *Config) setValue(key, value string) error {
 
 	// Register Debugging Configuration
 	case "REGISTER_DEBUG_ALLOWED_RANGES":
+		if _, err := ParseRegisterRanges(value); err != nil {
+			return fmt.Errorf("invalid REGISTER_DEBUG_ALLOWED_RANGES: %w", err)
+		}
 		c.RegisterDebugAllowedRanges = value
 	case "REGISTER_DEBUG_DEFAULT_READ_SPEED":
 		speed, err := strconv.ParseInt(value, 10, 64)
@@ -656,15 +1552,30 @@ func (c *Config) setValue(key, value string) error {
 	return nil
 }
 
+// parseQoS parses an MQTT QoS level (0-2, or -1 for "use MQTT_QOS").
+func parseQoS(key, value string) (int, error) {
+	qos, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", key, value, err)
+	}
+	if qos < -1 || qos > 2 {
+		return 0, fmt.Errorf("%s must be -1, 0, 1 or 2, got %d", key, qos)
+	}
+	return qos, nil
+}
+
 // validate checks that all required fields are set.
 func (c *Config) validate() error {
 	if c.MQTTBroker == "" {
 		return fmt.Errorf("MQTT_BROKER is required")
 	}
-	if c.IMULeftSPIDevice == "" {
+	if !c.IMULeftEnabled && !c.IMURightEnabled {
+		return fmt.Errorf("at least one of IMU_LEFT_ENABLED and IMU_RIGHT_ENABLED must be true")
+	}
+	if c.IMULeftEnabled && c.IMULeftSPIDevice == "" {
 		return fmt.Errorf("IMU_LEFT_SPI_DEVICE is required")
 	}
-	if c.IMURightSPIDevice == "" {
+	if c.IMURightEnabled && c.IMURightSPIDevice == "" {
 		return fmt.Errorf("IMU_RIGHT_SPI_DEVICE is required")
 	}
 	if c.GPSSerialPort == "" {
@@ -673,6 +1584,9 @@ func (c *Config) validate() error {
 	if c.GPSBaudRate == 0 {
 		return fmt.Errorf("GPS_BAUD_RATE is required")
 	}
+	if len(c.GPSGeofences) > 0 && c.TopicGPSEvents == "" {
+		return fmt.Errorf("TOPIC_GPS_EVENTS is required with GPS_GEOFENCE")
+	}
 	if c.IMUSampleInterval == 0 {
 		return fmt.Errorf("IMU_SAMPLE_INTERVAL is required")
 	}
@@ -720,12 +1634,29 @@ func (c *Config) validate() error {
 // Uses sync.Once to ensure this only runs once, even if called multiple times.
 // Acquires write lock (configMu.Lock) during initialization to prevent concurrent access.
 // This is the only function that can set globalConfig.
+// A missing file is created from the example with -init-config (see
+// ensureConfigFile); otherwise the error explains how to create one.
+// With -print-config the loaded config is printed (see Dump) and the
+// process exits, before any hardware is touched.
 func InitGlobal(configPath string) error {
 	var err error
 	configOnce.Do(func() {
 		configMu.Lock()
 		defer configMu.Unlock()
+		if err = ensureConfigFile(configPath); err != nil {
+			return
+		}
 		globalConfig, err = Load(configPath)
+		if err != nil {
+			return
+		}
+		if !flag.Parsed() {
+			flag.Parse()
+		}
+		if *printConfig {
+			fmt.Println(globalConfig.dump())
+			os.Exit(0)
+		}
 	})
 	return err
 }
@@ -0,0 +1,56 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package config
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+)
+
+// printConfig is registered on the default flag set like -init-config, so
+// every command accepts -print-config (see InitGlobal).
+var printConfig = flag.Bool("print-config", false, "print the effective config (defaults applied, secrets redacted) as JSON and exit")
+
+// redacted replaces secret values in Dump output, as url.URL.Redacted does
+// for passwords.
+const redacted = "xxxxx"
+
+// Dump returns the global configuration as indented JSON, after defaults
+// and validation, with secrets redacted: INFLUX_TOKEN and the password of
+// any user:password@ in MQTT_BROKER or INFLUX_URL. Fields are named as in
+// Config; a zero value means the default described in the config file.
+func Dump() string {
+	cfg := Get()
+	if cfg == nil {
+		return "null"
+	}
+	return cfg.dump()
+}
+
+func (c *Config) dump() string {
+	out := *c
+	if out.InfluxToken != "" {
+		out.InfluxToken = redacted
+	}
+	out.MQTTBroker = redactURL(out.MQTTBroker)
+	out.InfluxURL = redactURL(out.InfluxURL)
+
+	data, err := json.MarshalIndent(&out, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("config: dump failed: %v", err)
+	}
+	return string(data)
+}
+
+// redactURL hides the password of a URL with user info.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	return u.Redacted()
+}
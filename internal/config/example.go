@@ -0,0 +1,64 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package config
+
+import (
+	_ "embed"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// exampleConfig is a fully commented config with every key and its default,
+// written by WriteExample. TestExampleCoversEveryKey keeps it complete.
+//
+//go:embed example_config.txt
+var exampleConfig []byte
+
+// initConfig is registered on the default flag set so that every command
+// accepts -init-config to create a missing config file (see InitGlobal).
+var initConfig = flag.Bool("init-config", false, "write a commented example config if the config file does not exist")
+
+// WriteExample writes a fully commented example config with every key to
+// path. It never overwrites an existing file. The example is in the
+// KEY=VALUE format, so path must not name a YAML or TOML file.
+func WriteExample(path string) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".toml":
+		return fmt.Errorf("write example config: %s: the example is KEY=VALUE, use a .txt path", path)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("write example config: %w", err)
+	}
+	if _, err := f.Write(exampleConfig); err != nil {
+		f.Close()
+		return fmt.Errorf("write example config: %w", err)
+	}
+	return f.Close()
+}
+
+// ensureConfigFile handles a missing config file: with -init-config the
+// example is written there, otherwise the error says how to create one.
+func ensureConfigFile(path string) error {
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		return nil // present, or an error Load reports
+	}
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+	if !*initConfig {
+		return fmt.Errorf("config file %s not found: run the command again with -init-config to create a commented example there (see config.WriteExample)", path)
+	}
+	if err := WriteExample(path); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "config: wrote example config to %s; review the hardware settings (SPI devices, pins, MQTT broker)\n", path)
+	return nil
+}
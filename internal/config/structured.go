@@ -0,0 +1,628 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// YAML and TOML config files are read with small in-house parsers covering
+// the subset a flat config needs (no external dependency). Syntax outside
+// that subset is rejected with an error naming the line rather than read
+// differently from a full parser: in TOML inline tables, multi-line strings
+// and arrays; in YAML flow mappings, block scalars, multi-line plain values,
+// anchors, aliases, tags, merge and complex keys, directives and multiple
+// documents. Both build a tree that is flattened back to KEY=VALUE entries,
+// so every key goes through setValue and validate exactly as in the legacy
+// format:
+//
+//   - nested tables / mappings join with "_" and are uppercased:
+//     [imu.left] axis_map = "+y,-x,+z"  ->  IMU_LEFT_AXIS_MAP=+y,-x,+z
+//   - arrays of scalars join with ",":  sinks = ["mqtt", "csv"]  ->  SINKS=mqtt,csv
+//   - repeatable keys (GPS_GEOFENCE) take one entry per array element, either
+//     a "name,lat,lon,radius_m" string or a table with those fields
+
+// entry is one KEY=VALUE setting and the file line it came from.
+type entry struct {
+	line  int
+	key   string
+	value string
+}
+
+// repeatedKeyFields lists the keys that may appear several times, with the
+// field order used to build the legacy value from a table or mapping.
+var repeatedKeyFields = map[string][]string{
+	"GPS_GEOFENCE": {"name", "lat", "lon", "radius_m"},
+}
+
+// node is a parsed YAML/TOML value: a scalar, a mapping (keys in file order)
+// or a list.
+type node struct {
+	line  int
+	value string
+	keys  []string
+	items map[string]*node
+	list  []*node
+	isMap bool
+	isSeq bool
+}
+
+func newMap(line int) *node {
+	return &node{line: line, isMap: true, items: map[string]*node{}}
+}
+
+// set adds key to a mapping node, rejecting duplicates.
+func (n *node) set(key string, child *node) error {
+	if _, ok := n.items[key]; ok {
+		return fmt.Errorf("line %d: duplicate key %q", child.line, key)
+	}
+	n.keys = append(n.keys, key)
+	n.items[key] = child
+	return nil
+}
+
+// parseKeyValue reads the legacy KEY=VALUE format.
+func parseKeyValue(r io.Reader) ([]entry, error) {
+	var entries []entry
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+
+		// Skip empty lines and comments
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Parse KEY=VALUE
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid config line %d: %q", lineNum, line)
+		}
+		entries = append(entries, entry{lineNum, strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+	return entries, nil
+}
+
+// flatten turns a parsed tree into KEY=VALUE entries.
+func flatten(root *node) ([]entry, error) {
+	var entries []entry
+	var walk func(key string, n *node) error
+	walk = func(key string, n *node) error {
+		switch {
+		case n.isMap:
+			if _, ok := repeatedKeyFields[key]; ok {
+				return walk(key, &node{line: n.line, isSeq: true, list: []*node{n}})
+			}
+			for _, k := range n.keys {
+				child := strings.ToUpper(strings.ReplaceAll(k, "-", "_"))
+				if key != "" {
+					child = key + "_" + child
+				}
+				if err := walk(child, n.items[k]); err != nil {
+					return err
+				}
+			}
+		case n.isSeq:
+			if fields, ok := repeatedKeyFields[key]; ok {
+				for _, item := range n.list {
+					value, err := joinFields(key, fields, item)
+					if err != nil {
+						return err
+					}
+					entries = append(entries, entry{item.line, key, value})
+				}
+				return nil
+			}
+			values := make([]string, len(n.list))
+			for i, item := range n.list {
+				if item.isMap || item.isSeq {
+					return fmt.Errorf("line %d: %s: only lists of plain values are supported", item.line, key)
+				}
+				values[i] = item.value
+			}
+			entries = append(entries, entry{n.line, key, strings.Join(values, ",")})
+		default:
+			if key == "" {
+				return fmt.Errorf("line %d: value outside of a key", n.line)
+			}
+			entries = append(entries, entry{n.line, key, n.value})
+		}
+		return nil
+	}
+	if err := walk("", root); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// joinFields builds the legacy comma-separated value of a repeatable key
+// from a scalar or a table with the given fields.
+func joinFields(key string, fields []string, n *node) (string, error) {
+	switch {
+	case n.isSeq:
+		return "", fmt.Errorf("line %d: %s: nested lists are not supported", n.line, key)
+	case !n.isMap:
+		return n.value, nil
+	}
+	values := make([]string, len(fields))
+	for i, f := range fields {
+		v, ok := n.items[f]
+		if !ok {
+			return "", fmt.Errorf("line %d: %s: missing %q", n.line, key, f)
+		}
+		if v.isMap || v.isSeq {
+			return "", fmt.Errorf("line %d: %s: %q must be a plain value", v.line, key, f)
+		}
+		values[i] = v.value
+	}
+	if len(n.keys) != len(fields) {
+		for _, k := range n.keys {
+			if !contains(fields, k) {
+				return "", fmt.Errorf("line %d: %s: unknown field %q (want %s)", n.line, key, k, strings.Join(fields, ", "))
+			}
+		}
+	}
+	return strings.Join(values, ","), nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// stripComment removes a trailing # comment outside of quotes. YAML needs
+// the # to start the line or follow whitespace; TOML does not.
+func stripComment(line string, needSpace bool) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (!needSpace || i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// parseScalar parses a quoted or plain scalar. Plain values are kept as
+// written ("0x3C", "true", "9600"), as setValue expects.
+func parseScalar(s string, lineNum int) (*node, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"':
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid string %s", lineNum, s)
+		}
+		return &node{line: lineNum, value: v}, nil
+	case len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'':
+		return &node{line: lineNum, value: strings.ReplaceAll(s[1:len(s)-1], "''", "'")}, nil
+	case s != "" && (s[0] == '"' || s[0] == '\''):
+		return nil, fmt.Errorf("line %d: unterminated string %s", lineNum, s)
+	}
+	return &node{line: lineNum, value: s}, nil
+}
+
+// parseInlineList parses a single-line [a, b, c] list of scalars.
+func parseInlineList(s string, lineNum int) (*node, error) {
+	inner := strings.TrimSpace(s[1:])
+	if !strings.HasSuffix(inner, "]") {
+		return nil, fmt.Errorf("line %d: lists must be closed on the same line", lineNum)
+	}
+	inner = strings.TrimSpace(inner[:len(inner)-1])
+	list := &node{line: lineNum, isSeq: true}
+	if inner == "" {
+		return list, nil
+	}
+
+	var quote byte
+	start := 0
+	for i := 0; i <= len(inner); i++ {
+		if i < len(inner) {
+			c := inner[i]
+			if quote != 0 {
+				if c == '\\' && quote == '"' {
+					i++
+				} else if c == quote {
+					quote = 0
+				}
+				continue
+			}
+			if c == '"' || c == '\'' {
+				quote = c
+				continue
+			}
+			if c == '[' || c == '{' {
+				return nil, fmt.Errorf("line %d: nested lists and inline tables are not supported", lineNum)
+			}
+			if c != ',' {
+				continue
+			}
+		}
+		item := strings.TrimSpace(inner[start:i])
+		if item == "" {
+			if i == len(inner) {
+				break // trailing comma
+			}
+			return nil, fmt.Errorf("line %d: empty list element", lineNum)
+		}
+		v, err := parseScalar(item, lineNum)
+		if err != nil {
+			return nil, err
+		}
+		list.list = append(list.list, v)
+		start = i + 1
+	}
+	return list, nil
+}
+
+// parseTOML reads the TOML subset: key = value pairs with dotted keys,
+// [table] and [[array.of.tables]] headers, strings, numbers, booleans and
+// single-line arrays of those.
+func parseTOML(r io.Reader) ([]entry, error) {
+	root := newMap(0)
+	current := root
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(stripComment(scanner.Text(), false))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			isArray := strings.HasPrefix(line, "[[")
+			name := strings.TrimPrefix(line, "[")
+			closing := "]"
+			if isArray {
+				name, closing = strings.TrimPrefix(name, "["), "]]"
+			}
+			if !strings.HasSuffix(name, closing) {
+				return nil, fmt.Errorf("config line %d: invalid table header %q", lineNum, line)
+			}
+			path := splitDotted(strings.TrimSuffix(name, closing))
+			if path == nil {
+				return nil, fmt.Errorf("config line %d: invalid table header %q", lineNum, line)
+			}
+			parent, err := tomlTable(root, path[:len(path)-1], lineNum)
+			if err != nil {
+				return nil, fmt.Errorf("config line %d: %w", lineNum, err)
+			}
+			last := path[len(path)-1]
+			existing := parent.items[last]
+			switch {
+			case isArray && existing == nil:
+				list := &node{line: lineNum, isSeq: true}
+				parent.set(last, list)
+				existing = list
+				fallthrough
+			case isArray && existing.isSeq:
+				current = newMap(lineNum)
+				existing.list = append(existing.list, current)
+			case !isArray && existing == nil:
+				current = newMap(lineNum)
+				parent.set(last, current)
+			case !isArray && existing.isMap:
+				current = existing
+			default:
+				return nil, fmt.Errorf("config line %d: %q redefines %s", lineNum, line, strings.Join(path, "."))
+			}
+			continue
+		}
+
+		eq := strings.Index(line, "=")
+		if eq < 0 {
+			return nil, fmt.Errorf("invalid config line %d: %q", lineNum, line)
+		}
+		path := splitDotted(line[:eq])
+		if path == nil {
+			return nil, fmt.Errorf("invalid config line %d: %q", lineNum, line)
+		}
+		raw := strings.TrimSpace(line[eq+1:])
+		var value *node
+		var err error
+		switch {
+		case strings.HasPrefix(raw, "{"):
+			err = fmt.Errorf("line %d: inline tables are not supported, use a [table] header", lineNum)
+		case strings.HasPrefix(raw, `"""`) || strings.HasPrefix(raw, "'''"):
+			err = fmt.Errorf("line %d: multi-line strings are not supported", lineNum)
+		case strings.HasPrefix(raw, "["):
+			value, err = parseInlineList(raw, lineNum)
+		default:
+			value, err = parseScalar(raw, lineNum)
+			if err == nil && !strings.HasPrefix(raw, "\"") && !strings.HasPrefix(raw, "'") {
+				value.value = strings.ReplaceAll(value.value, "_", "") // 1_000
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("config %w", err)
+		}
+		parent, err := tomlTable(current, path[:len(path)-1], lineNum)
+		if err == nil {
+			err = parent.set(path[len(path)-1], value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("config line %d: %w", lineNum, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+	entries, err := flatten(root)
+	if err != nil {
+		return nil, fmt.Errorf("config %w", err)
+	}
+	return entries, nil
+}
+
+// tomlTable walks (creating as needed) the tables at path below t.
+func tomlTable(t *node, path []string, lineNum int) (*node, error) {
+	for _, name := range path {
+		child, ok := t.items[name]
+		if !ok {
+			child = newMap(lineNum)
+			t.set(name, child)
+		}
+		if child.isSeq && len(child.list) > 0 {
+			child = child.list[len(child.list)-1] // latest [[table]]
+		}
+		if !child.isMap {
+			return nil, fmt.Errorf("%s is not a table", name)
+		}
+		t = child
+	}
+	return t, nil
+}
+
+// splitDotted splits a TOML key like imu.left or "imu".left, returning nil
+// if it is empty or malformed.
+func splitDotted(key string) []string {
+	var path []string
+	for _, part := range strings.Split(key, ".") {
+		part = strings.Trim(strings.TrimSpace(part), "\"'")
+		if part == "" {
+			return nil
+		}
+		path = append(path, part)
+	}
+	return path
+}
+
+// yamlLine is a non-blank YAML line with its indentation.
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+// parseYAML reads the YAML subset: block mappings and sequences indented
+// with spaces, plain and quoted scalars, and [a, b] flow sequences.
+func parseYAML(r io.Reader) ([]entry, error) {
+	var lines []yamlLine
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		raw := scanner.Text()
+		text := strings.TrimRight(stripComment(raw, true), " \t")
+		trimmed := strings.TrimLeft(text, " ")
+		switch {
+		case trimmed == "":
+			continue
+		case trimmed == "---" && len(lines) == 0:
+			continue // start of the (only) document
+		case trimmed == "---" || trimmed == "...":
+			return nil, fmt.Errorf("config line %d: multiple documents are not supported", lineNum)
+		case strings.HasPrefix(trimmed, "%"):
+			return nil, fmt.Errorf("config line %d: directives are not supported", lineNum)
+		case strings.HasPrefix(trimmed, "? "):
+			return nil, fmt.Errorf("config line %d: complex keys are not supported", lineNum)
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("config line %d: tabs are not allowed for indentation", lineNum)
+		}
+		lines = append(lines, yamlLine{lineNum, len(text) - len(trimmed), trimmed})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	root := newMap(0)
+	if len(lines) > 0 {
+		p := &yamlParser{lines: lines}
+		n, err := p.block(lines[0].indent)
+		if err != nil {
+			return nil, fmt.Errorf("config %w", err)
+		}
+		if p.pos < len(lines) {
+			l := lines[p.pos]
+			return nil, fmt.Errorf("config line %d: unexpected indentation", l.num)
+		}
+		if !n.isMap {
+			return nil, fmt.Errorf("config line %d: top level must be a mapping", lines[0].num)
+		}
+		root = n
+	}
+	entries, err := flatten(root)
+	if err != nil {
+		return nil, fmt.Errorf("config %w", err)
+	}
+	return entries, nil
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+func isSeqItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// block parses the mapping or sequence starting at the current line, which
+// is at indent.
+func (p *yamlParser) block(indent int) (*node, error) {
+	if isSeqItem(p.lines[p.pos].text) {
+		return p.sequence(indent)
+	}
+	return p.mapping(indent)
+}
+
+func (p *yamlParser) sequence(indent int) (*node, error) {
+	seq := &node{line: p.lines[p.pos].num, isSeq: true}
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent != indent || !isSeqItem(l.text) {
+			break
+		}
+		content := strings.TrimLeft(strings.TrimPrefix(l.text, "-"), " ")
+		switch {
+		case content == "":
+			p.pos++
+			if p.pos >= len(p.lines) || p.lines[p.pos].indent <= indent {
+				seq.list = append(seq.list, &node{line: l.num})
+				continue
+			}
+			item, err := p.block(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			seq.list = append(seq.list, item)
+		case mappingKey(content) >= 0 || isSeqItem(content):
+			// "- key: value" starts a mapping whose further keys line up
+			// with key; re-read this line as its first entry
+			p.lines[p.pos] = yamlLine{l.num, indent + len(l.text) - len(content), content}
+			item, err := p.block(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			seq.list = append(seq.list, item)
+		default:
+			item, err := yamlValue(content, l.num)
+			if err != nil {
+				return nil, err
+			}
+			seq.list = append(seq.list, item)
+			p.pos++
+		}
+	}
+	return seq, nil
+}
+
+func (p *yamlParser) mapping(indent int) (*node, error) {
+	m := newMap(p.lines[p.pos].num)
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent < indent {
+			break
+		}
+		if l.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", l.num)
+		}
+		if isSeqItem(l.text) {
+			break // a sequence at the parent key's indent
+		}
+		colon := mappingKey(l.text)
+		if colon < 0 {
+			return nil, fmt.Errorf("line %d: expected \"key: value\", got %q", l.num, l.text)
+		}
+		key := strings.Trim(strings.TrimSpace(l.text[:colon]), "\"'")
+		if key == "<<" {
+			return nil, fmt.Errorf("line %d: merge keys are not supported", l.num)
+		}
+		rest := strings.TrimSpace(l.text[colon+1:])
+		p.pos++
+
+		var value *node
+		var err error
+		switch {
+		case rest != "":
+			value, err = yamlValue(rest, l.num)
+			if err == nil && p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				err = fmt.Errorf("line %d: multi-line values are not supported", p.lines[p.pos].num)
+			}
+		case p.pos < len(p.lines) && p.lines[p.pos].indent > indent:
+			value, err = p.block(p.lines[p.pos].indent)
+		case p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isSeqItem(p.lines[p.pos].text):
+			value, err = p.sequence(indent)
+		default:
+			value = &node{line: l.num}
+		}
+		if err == nil {
+			err = m.set(key, value)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// mappingKey returns the index of the ": " (or trailing ":") that ends the
+// key of a "key: value" line, or -1.
+func mappingKey(text string) int {
+	if text == "" || text[0] == '"' || text[0] == '\'' || text[0] == '[' {
+		if text == "" || text[0] == '[' {
+			return -1
+		}
+		end := strings.IndexByte(text[1:], text[0])
+		if end < 0 || !strings.HasPrefix(text[end+2:], ":") {
+			return -1
+		}
+		return end + 2
+	}
+	if i := strings.Index(text, ": "); i >= 0 {
+		return i
+	}
+	if strings.HasSuffix(text, ":") {
+		return len(text) - 1
+	}
+	return -1
+}
+
+// yamlValue parses an inline value: a flow sequence or a scalar, with
+// ~ and null read as empty.
+func yamlValue(s string, lineNum int) (*node, error) {
+	if strings.HasPrefix(s, "[") {
+		return parseInlineList(s, lineNum)
+	}
+	if strings.HasPrefix(s, "{") || strings.HasPrefix(s, "|") || strings.HasPrefix(s, ">") {
+		return nil, fmt.Errorf("line %d: flow mappings and block scalars are not supported", lineNum)
+	}
+	if strings.HasPrefix(s, "&") || strings.HasPrefix(s, "*") || strings.HasPrefix(s, "!") {
+		return nil, fmt.Errorf("line %d: anchors, aliases and tags are not supported", lineNum)
+	}
+	n, err := parseScalar(s, lineNum)
+	if err == nil && (s == "~" || s == "null") {
+		n.value = ""
+	}
+	return n, err
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStructuredFormats(t *testing.T) {
+	want, err := Load(fixturePath)
+	if err != nil {
+		t.Fatalf("Load(%s): %v", fixturePath, err)
+	}
+	for _, path := range []string{"testdata/inertial_config.toml", "testdata/inertial_config.yaml"} {
+		got, err := Load(path)
+		if err != nil {
+			t.Errorf("Load(%s): %v", path, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Load(%s) = %+v\nwant %+v", path, got, want)
+		}
+	}
+}
+
+// loadStructured writes data to a file named name (whose extension picks
+// the format) and loads it.
+func loadStructured(t *testing.T, name, data string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return Load(path)
+}
+
+func TestStructuredErrors(t *testing.T) {
+	tests := []struct {
+		name, data, want string
+	}{
+		{"c.toml", "mqtt.brokr = \"tcp://x:1883\"\n", "MQTT_BROKR"},
+		{"c.toml", "\n\nimu.accel_range = \"fast\"\n", "config line 3"},
+		{"c.toml", "[imu\n", "invalid table header"},
+		{"c.toml", "a = [1, [2]]\n", "nested lists"},
+		{"c.toml", "a = 1\na = 2\n", "duplicate key"},
+		{"c.toml", "imu = { accel_range = 2 }\n", "inline tables"},
+		{"c.toml", "mqtt.broker = \"\"\"\ntcp://x:1883\"\"\"\n", "multi-line strings"},
+		{"c.toml", "a = [1,\n 2]\n", "closed on the same line"},
+		{"c.toml", "[[gps.geofence]]\nname = \"home\"\nlat = 1\nlon = 2\n", "missing \"radius_m\""},
+		{"c.toml", "mqtt_broker = \"tcp://x:1883\"\n", "required"},
+		{"c.yaml", "imu:\n  accel_range: fast\n", "config line 2"},
+		{"c.yaml", "imu:\n  left:\n     x: 1\n    y: 2\n", "unexpected indentation"},
+		{"c.yaml", "gps:\n  geofence:\n    - name: home\n      alt: 3\n", "missing \"lat\""},
+		{"c.yml", "mqtt: {broker: x}\n", "flow mappings"},
+		{"c.yml", "- a\n- b\n", "top level must be a mapping"},
+		{"c.yml", "imu:\n  accel_range: |\n    2\n", "block scalars"},
+		{"c.yml", "mqtt:\n  broker: tcp://x\n    :1883\n", "multi-line values"},
+		{"c.yml", "base: &b 2\nimu:\n  accel_range: *b\n", "anchors, aliases and tags"},
+		{"c.yml", "imu:\n  accel_range: !!int 2\n", "anchors, aliases and tags"},
+		{"c.yml", "imu:\n  <<: {accel_range: 2}\n", "merge keys"},
+		{"c.yml", "? imu\n: 2\n", "complex keys"},
+		{"c.yml", "%YAML 1.2\n---\nimu:\n  accel_range: 2\n", "directives"},
+		{"c.yml", "imu:\n  accel_range: 2\n---\nimu:\n  gyro_range: 1\n", "multiple documents"},
+	}
+	for _, tt := range tests {
+		_, err := loadStructured(t, tt.name, tt.data)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Load(%s %q) = %v, want error containing %q", tt.name, tt.data, err, tt.want)
+		}
+	}
+}
+
+func TestStructuredGeofenceForms(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/inertial_config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Replace the single geofence mapping with two plain strings
+	data := strings.Replace(string(fixture), `    - name: home
+      lat: 48.137154
+      lon: 11.576124
+      radius_m: 50`, `    - home,48.137154,11.576124,50
+    - "depot,48.2,11.6,120"`, 1)
+	cfg, err := loadStructured(t, "c.yaml", data)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(cfg.GPSGeofences) != 2 || cfg.GPSGeofences[1].Name != "depot" || cfg.GPSGeofences[1].RadiusM != 120 {
+		t.Errorf("GPSGeofences = %+v, want home and depot", cfg.GPSGeofences)
+	}
+}
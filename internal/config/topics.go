@@ -0,0 +1,89 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// topicDefault is the shipped value of a topic key. Topics still at their
+// default are namespaced by TOPIC_PREFIX; any other value is an explicit
+// override and used as is.
+type topicDefault struct {
+	value string
+	field func(c *Config) *string
+	// empty falls back to value at the use site (see app), so an unset key
+	// is prefixed too
+	emptyIsDefault bool
+}
+
+var topicDefaults = []topicDefault{
+	{value: "inertial/pose/left", field: func(c *Config) *string { return &c.TopicPoseLeft }},
+	{value: "inertial/pose/right", field: func(c *Config) *string { return &c.TopicPoseRight }},
+	{value: "inertial/pose/fused", field: func(c *Config) *string { return &c.TopicPoseFused }},
+	{value: "inertial/pose/reference", field: func(c *Config) *string { return &c.TopicPoseReference }},
+	{value: "inertial/calibration", field: func(c *Config) *string { return &c.TopicCalibration }},
+	{value: "inertial/imu/left", field: func(c *Config) *string { return &c.TopicIMULeft }},
+	{value: "inertial/imu/right", field: func(c *Config) *string { return &c.TopicIMURight }},
+	{value: "inertial/mag/left", field: func(c *Config) *string { return &c.TopicMagLeft }},
+	{value: "inertial/mag/right", field: func(c *Config) *string { return &c.TopicMagRight }},
+	{value: "inertial/bmp/left", field: func(c *Config) *string { return &c.TopicBMPLeft }},
+	{value: "inertial/bmp/right", field: func(c *Config) *string { return &c.TopicBMPRight }},
+	{value: "inertial/bmp/diff", field: func(c *Config) *string { return &c.TopicBMPDiff }},
+	{value: "inertial/vario/left", field: func(c *Config) *string { return &c.TopicVarioLeft }},
+	{value: "inertial/vario/right", field: func(c *Config) *string { return &c.TopicVarioRight }},
+	{value: "inertial/motion/left", field: func(c *Config) *string { return &c.TopicMotionLeft }},
+	{value: "inertial/motion/right", field: func(c *Config) *string { return &c.TopicMotionRight }},
+	{value: "inertial/gps/position", field: func(c *Config) *string { return &c.TopicGPSPosition }},
+	{value: "inertial/gps/velocity", field: func(c *Config) *string { return &c.TopicGPSVelocity }},
+	{value: "inertial/gps/quality", field: func(c *Config) *string { return &c.TopicGPSQuality }},
+	{value: "inertial/gps/satellites", field: func(c *Config) *string { return &c.TopicGPSSatellites }},
+	{value: "inertial/glonass/satellites", field: func(c *Config) *string { return &c.TopicGLONASSSatellites }},
+	{value: "inertial/gps", field: func(c *Config) *string { return &c.TopicGPS }},
+	{value: "inertial/gps/events", field: func(c *Config) *string { return &c.TopicGPSEvents }},
+	{value: "inertial/mag/hmc", field: func(c *Config) *string { return &c.TopicMagHMC }, emptyIsDefault: true},
+	{value: "inertial/fused/state", field: func(c *Config) *string { return &c.TopicFusedState }},
+	{value: "inertial/imu/health", field: func(c *Config) *string { return &c.TopicIMUHealth }},
+	{value: "inertial/status", field: func(c *Config) *string { return &c.TopicStatusPrefix }, emptyIsDefault: true},
+	{value: "inertial/system/health", field: func(c *Config) *string { return &c.TopicSystemHealth }},
+	{value: "inertial/system/battery", field: func(c *Config) *string { return &c.TopicBattery }},
+	{value: "inertial/registers/cmd/read", field: func(c *Config) *string { return &c.TopicRegistersCmdRead }},
+	{value: "inertial/registers/cmd/write", field: func(c *Config) *string { return &c.TopicRegistersCmdWrite }},
+	{value: "inertial/registers/cmd/init", field: func(c *Config) *string { return &c.TopicRegistersCmdInit }},
+	{value: "inertial/registers/cmd/spi_speed", field: func(c *Config) *string { return &c.TopicRegistersCmdSPISpeed }},
+	{value: "inertial/registers/data/left", field: func(c *Config) *string { return &c.TopicRegistersDataLeft }},
+	{value: "inertial/registers/data/right", field: func(c *Config) *string { return &c.TopicRegistersDataRight }},
+	{value: "inertial/registers/map", field: func(c *Config) *string { return &c.TopicRegistersMap }},
+	{value: "inertial/registers/status", field: func(c *Config) *string { return &c.TopicRegistersStatus }},
+}
+
+// applyTopicPrefix namespaces every topic left at its default under
+// TOPIC_PREFIX, e.g. rig1/inertial/pose/left. Load calls it once after
+// parsing, so the rest of the code only ever sees the final topics.
+func (c *Config) applyTopicPrefix() {
+	prefix := strings.TrimSuffix(c.TopicPrefix, "/")
+	if prefix == "" {
+		return
+	}
+	for _, d := range topicDefaults {
+		topic := d.field(c)
+		if *topic == d.value || (*topic == "" && d.emptyIsDefault) {
+			*topic = prefix + "/" + d.value
+		}
+	}
+}
+
+// parseTopicPrefix checks a TOPIC_PREFIX value: a topic level path without
+// wildcards.
+func parseTopicPrefix(value string) (string, error) {
+	if strings.ContainsAny(value, "+#") {
+		return "", fmt.Errorf("TOPIC_PREFIX must not contain MQTT wildcards, got %q", value)
+	}
+	if strings.HasPrefix(value, "/") {
+		return "", fmt.Errorf("TOPIC_PREFIX must not start with '/', got %q", value)
+	}
+	return value, nil
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package env
+
+import "time"
+
+// BMPDiff is the left-minus-right difference between the two BMP sensors.
+// A pressure difference can indicate airflow across the rig, a temperature
+// difference a thermal gradient (or uneven self-heating).
+//
+// When only one sensor was read, Valid is false, the deltas are zero and
+// LeftOK/RightOK tell which one is missing.
+type BMPDiff struct {
+	Valid           bool    `json:"valid"`             // both sensors read this cycle
+	LeftOK          bool    `json:"left_ok"`           // left sample available
+	RightOK         bool    `json:"right_ok"`          // right sample available
+	DeltaTempC      float64 `json:"delta_temp_c"`      // left - right (°C)
+	DeltaPressurePa float64 `json:"delta_pressure_pa"` // left - right (Pa)
+	Time            string  `json:"time"`              // RFC3339
+}
+
+// NewBMPDiff builds the difference of left and right samples taken at t;
+// leftOK/rightOK report whether each sample was actually read.
+func NewBMPDiff(left Sample, leftOK bool, right Sample, rightOK bool, t time.Time) BMPDiff {
+	d := BMPDiff{
+		Valid:   leftOK && rightOK,
+		LeftOK:  leftOK,
+		RightOK: rightOK,
+		Time:    t.Format(time.RFC3339),
+	}
+	if d.Valid {
+		d.DeltaTempC = left.Temperature - right.Temperature
+		d.DeltaPressurePa = left.Pressure - right.Pressure
+	}
+	return d
+}
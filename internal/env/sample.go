@@ -5,6 +5,8 @@
 
 package env
 
+import "math"
+
 // Sample represents a single environmental measurement (BMP).
 type Sample struct {
 	Source string `json:"source"` // "left" or "right"`
@@ -13,8 +15,26 @@ type Sample struct {
 	Pressure     float64 `json:"pressure_pa"`   // Pa
 	PressureMbar float64 `json:"pressure_mbar"` // mbar
 	PressureHPa  float64 `json:"pressure_hpa"`  // hPa
+	Altitude     float64 `json:"altitude_m"`    // barometric altitude (m), see PressureToAltitude
+
+	Humidity float64 `json:"humidity_pct,omitempty"` // relative humidity (%), BME280 only
 }
 
 type EnvSource interface {
 	NextEnv() (Sample, error)
 }
+
+// PressureToAltitude converts pressure to altitude (meters) using the
+// international barometric formula:
+//
+//	h = 44330 * (1 - (p / p0)^(1/5.255))
+//
+// seaLevelHPa is the reference sea-level pressure (BMP_SEA_LEVEL_HPA).
+// Unless it is set to the local QNH the absolute altitude is only approximate
+// (roughly 8 m per hPa of error); relative altitude changes remain accurate.
+func PressureToAltitude(pressureHPa, seaLevelHPa float64) float64 {
+	if pressureHPa <= 0 || seaLevelHPa <= 0 {
+		return 0
+	}
+	return 44330.0 * (1.0 - math.Pow(pressureHPa/seaLevelHPa, 1.0/5.255))
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package env
+
+import "time"
+
+const (
+	// DefaultVarioTimeConstant applies when VARIO_TIME_CONSTANT is not configured.
+	DefaultVarioTimeConstant = time.Second
+
+	gasConstantDryAir = 287.05  // J/(kg·K)
+	standardGravity   = 9.80665 // m/s²
+	celsiusToKelvin   = 273.15
+)
+
+// Vario is a vertical speed estimate derived from barometric pressure.
+type Vario struct {
+	Source        string  `json:"source"`             // "left" or "right"
+	VerticalSpeed float64 `json:"vertical_speed_mps"` // m/s, positive = climbing
+	PressurePa    float64 `json:"pressure_pa"`        // low-pass filtered pressure
+	Time          string  `json:"time"`               // RFC3339
+}
+
+// VarioCalculator turns a stream of BMP samples into a vertical speed
+// (variometer). Pressure is low-pass filtered with a first-order filter of
+// the given time constant, differentiated, and the resulting rate is filtered
+// again with the same time constant. Longer time constants suppress BMP noise
+// at the cost of lag (≈ 2× the time constant to settle after a step).
+//
+// The pressure rate is converted to vertical speed with the hydrostatic
+// equation, dh = -(R·T / (g·p)) · dp, using the sample's temperature:
+//
+//   - No sea-level reference is needed: the result depends only on the local
+//     pressure and temperature, so it does not inherit BMP_SEA_LEVEL_HPA errors.
+//   - It assumes the sensor temperature equals the surrounding air
+//     temperature. A BMP warmed by nearby electronics reads high, and every
+//     3 K of error scales the vertical speed by about 1%.
+//   - Weather-driven pressure drift (≈ 1 hPa/h, about 2 mm/s) shows up as a
+//     small constant climb or sink.
+//
+// It is not safe for concurrent use.
+type VarioCalculator struct {
+	tau float64 // seconds
+
+	initialized bool
+	last        time.Time
+	pressure    float64 // filtered pressure (Pa)
+	rate        float64 // filtered vertical speed (m/s)
+}
+
+// NewVarioCalculator creates a calculator with the given filter time constant
+// (DefaultVarioTimeConstant if <= 0).
+func NewVarioCalculator(timeConstant time.Duration) *VarioCalculator {
+	if timeConstant <= 0 {
+		timeConstant = DefaultVarioTimeConstant
+	}
+	return &VarioCalculator{tau: timeConstant.Seconds()}
+}
+
+// Update adds a sample taken at t and returns the filtered vertical speed in
+// m/s. ok is false for the first sample and for samples that are not newer
+// than the previous one.
+func (v *VarioCalculator) Update(s Sample, t time.Time) (verticalSpeed float64, ok bool) {
+	if s.Pressure <= 0 {
+		return v.rate, false
+	}
+	if !v.initialized {
+		v.initialized = true
+		v.last = t
+		v.pressure = s.Pressure
+		return 0, false
+	}
+
+	dt := t.Sub(v.last).Seconds()
+	if dt <= 0 {
+		return v.rate, false
+	}
+	v.last = t
+
+	alpha := dt / (v.tau + dt)
+	prevPressure := v.pressure
+	v.pressure += alpha * (s.Pressure - v.pressure)
+	dpdt := (v.pressure - prevPressure) / dt
+
+	tempK := s.Temperature + celsiusToKelvin
+	dhdt := -gasConstantDryAir * tempK / (standardGravity * v.pressure) * dpdt
+	v.rate += alpha * (dhdt - v.rate)
+
+	return v.rate, true
+}
+
+// Pressure returns the filtered pressure in Pa.
+func (v *VarioCalculator) Pressure() float64 {
+	return v.pressure
+}
+
+// Reset discards the filter state; the next Update starts over.
+func (v *VarioCalculator) Reset() {
+	*v = VarioCalculator{tau: v.tau}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package fusion
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Magnetic declination is the angle from true north to magnetic north,
+// positive east: true heading = magnetic heading + declination. E.g. +3° in
+// central Europe, -13° in Maine.
+
+// DeclinationTable is a regular latitude/longitude grid of declinations
+// (MAG_DECLINATION_TABLE), interpolated bilinearly. The grid values come
+// from a geomagnetic model such as the WMM, e.g. exported from NOAA's
+// declination grid calculator for the area of operation.
+type DeclinationTable struct {
+	lats, lons []float64   // ascending grid coordinates (degrees)
+	decl       [][]float64 // decl[i][j] at lats[i], lons[j]
+}
+
+// LoadDeclinationTable reads a table file: one "lat,lon,declination" line
+// per grid point in decimal degrees, '#' comments and blank lines ignored.
+// The points must cover a full grid (every lat with every lon), in any order.
+func LoadDeclinationTable(path string) (*DeclinationTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	type point struct{ lat, lon float64 }
+	values := make(map[point]float64)
+	var lats, lons []float64
+
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, ",")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("%s:%d: want lat,lon,declination", path, line)
+		}
+		var v [3]float64
+		for i, s := range fields {
+			if v[i], err = strconv.ParseFloat(strings.TrimSpace(s), 64); err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+			}
+		}
+		p := point{v[0], v[1]}
+		if _, dup := values[p]; dup {
+			return nil, fmt.Errorf("%s:%d: duplicate point %g,%g", path, line, p.lat, p.lon)
+		}
+		values[p] = v[2]
+		if !slices.Contains(lats, p.lat) {
+			lats = append(lats, p.lat)
+		}
+		if !slices.Contains(lons, p.lon) {
+			lons = append(lons, p.lon)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lats) < 2 || len(lons) < 2 {
+		return nil, fmt.Errorf("%s: need at least 2 latitudes and 2 longitudes", path)
+	}
+	if len(values) != len(lats)*len(lons) {
+		return nil, fmt.Errorf("%s: %d points do not form a full %dx%d grid", path, len(values), len(lats), len(lons))
+	}
+
+	slices.Sort(lats)
+	slices.Sort(lons)
+	t := &DeclinationTable{lats: lats, lons: lons, decl: make([][]float64, len(lats))}
+	for i, lat := range lats {
+		t.decl[i] = make([]float64, len(lons))
+		for j, lon := range lons {
+			t.decl[i][j] = values[point{lat, lon}]
+		}
+	}
+	return t, nil
+}
+
+// Lookup returns the declination at lat, lon, or ok=false outside the grid.
+func (t *DeclinationTable) Lookup(lat, lon float64) (decl float64, ok bool) {
+	i, fi, ok := gridCell(t.lats, lat)
+	if !ok {
+		return 0, false
+	}
+	j, fj, ok := gridCell(t.lons, lon)
+	if !ok {
+		return 0, false
+	}
+	d := t.decl
+	return (1-fi)*((1-fj)*d[i][j]+fj*d[i][j+1]) + fi*((1-fj)*d[i+1][j]+fj*d[i+1][j+1]), true
+}
+
+// gridCell returns the index of the grid interval containing x and the
+// fraction of x within it.
+func gridCell(grid []float64, x float64) (int, float64, bool) {
+	n := len(grid)
+	if math.IsNaN(x) || x < grid[0] || x > grid[n-1] {
+		return 0, 0, false
+	}
+	i, _ := slices.BinarySearch(grid, x)
+	if i > 0 {
+		i--
+	}
+	i = min(i, n-2)
+	return i, (x - grid[i]) / (grid[i+1] - grid[i]), true
+}
@@ -0,0 +1,279 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package fusion
+
+import (
+	"math"
+	"time"
+
+	"github.com/relabs-tech/inertial_computer/internal/gps"
+	"github.com/relabs-tech/inertial_computer/internal/orientation"
+)
+
+const (
+	earthRadiusM = 6371000.0 // mean Earth radius (meters)
+	knotsToMps   = 0.514444  // 1 knot in m/s
+)
+
+// Dead-reckoning defaults of NewHeadingFusion.
+const (
+	DefaultFixTimeout    = 3 * time.Second  // no valid fix for this long = outage (GPS is 1 Hz)
+	DefaultMaxDRDuration = 30 * time.Second // stop dead reckoning after this long
+)
+
+// Position sources of State.PositionSource.
+const (
+	PositionGPS   = "gps"   // last valid fix, fix is current
+	PositionDR    = "dr"    // dead-reckoned from the last fix during an outage
+	PositionStale = "stale" // outage longer than MaxDRDuration: held at the last estimate
+	PositionNone  = "none"  // no fix received yet
+)
+
+// State is the fused navigation state published on TOPIC_FUSED_STATE.
+type State struct {
+	Latitude  float64 `json:"lat"`        // decimal degrees
+	Longitude float64 `json:"lon"`        // decimal degrees
+	Altitude  float64 `json:"altitude_m"` // meters (from GPS)
+
+	HeadingDeg    float64 `json:"heading_deg"`    // [0, 360)
+	HeadingSource string  `json:"heading_source"` // "gps", "gyro" or "none"
+	SpeedMps      float64 `json:"speed_mps"`      // ground speed (m/s)
+
+	Roll  float64 `json:"roll"`  // degrees (from IMU pose)
+	Pitch float64 `json:"pitch"` // degrees (from IMU pose)
+
+	// IMU yaw corrected to true north, [0, 360): yaw + DeclinationDeg. Only
+	// meaningful when the yaw is a magnetic heading (ORIENTATION_ALGORITHM=ekf).
+	TrueHeadingDeg    float64 `json:"true_heading_deg"`
+	DeclinationDeg    float64 `json:"declination_deg"`    // positive east
+	DeclinationSource string  `json:"declination_source"` // "table", "model" (at the GPS position) or "config"
+
+	PositionSource string `json:"source"`        // PositionGPS, PositionDR, PositionStale or PositionNone
+	HaveGPS        bool   `json:"have_gps"`      // at least one valid GPS fix received
+	DeadReckoned   bool   `json:"dead_reckoned"` // PositionSource is PositionDR
+	Time           string `json:"time"`          // RFC3339
+}
+
+// Estimator fuses IMU pose and GPS data into a navigation State.
+// The loosely-coupled HeadingFusion is the first implementation; a Kalman
+// filter can implement the same interface and be swapped in by the producer.
+type Estimator interface {
+	UpdatePose(p orientation.Pose, t time.Time)
+	UpdateGPS(pos gps.Position, vel gps.Velocity, t time.Time)
+	UpdateMotion(moving bool, t time.Time)
+	State(t time.Time) State
+}
+
+// HeadingFusion is a loosely-coupled estimator:
+//   - heading follows GPS course over ground while moving faster than MinSpeedKnots,
+//     and the offset between gyro yaw and course is remembered
+//   - when slow or without GPS, heading is gyro yaw plus the last known offset
+//   - position is the last GPS fix; during an outage (void fix, or none for
+//     FixTimeout) it is dead-reckoned from that fix along the gyro heading at
+//     the last GPS speed (0 while stationary), for at most MaxDRDuration
+type HeadingFusion struct {
+	MinSpeedKnots float64
+	FixTimeout    time.Duration
+	MaxDRDuration time.Duration
+
+	// Magnetic declination (positive east) for TrueHeadingDeg: looked up in
+	// DeclinationTable at the GPS position when possible, else computed by
+	// DeclinationModel (e.g. magmodel.Declination) at the GPS position, else
+	// DeclinationDeg. nil table/model = not used.
+	DeclinationDeg   float64
+	DeclinationTable *DeclinationTable
+	DeclinationModel func(lat, lon float64, date time.Time) float64
+
+	pose     orientation.Pose
+	havePose bool
+
+	pos     gps.Position
+	vel     gps.Velocity
+	haveFix bool
+	fixLost bool // void fix received since the last valid one
+	fixTime time.Time
+
+	moving     bool // stationarity detector (UpdateMotion)
+	haveMotion bool
+
+	// Dead-reckoned position, propagated up to drTime
+	drLat, drLon float64
+	drTime       time.Time
+	drActive     bool
+
+	yawOffset     float64 // course - gyro yaw (degrees)
+	haveYawOffset bool
+}
+
+// NewHeadingFusion creates a HeadingFusion estimator.
+// minSpeedKnots is the ground speed above which GPS course is trusted for heading.
+func NewHeadingFusion(minSpeedKnots float64) *HeadingFusion {
+	return &HeadingFusion{
+		MinSpeedKnots: minSpeedKnots,
+		FixTimeout:    DefaultFixTimeout,
+		MaxDRDuration: DefaultMaxDRDuration,
+	}
+}
+
+// UpdatePose records the latest IMU pose. During an outage the dead-reckoned
+// position is first advanced to t with the previous heading.
+func (f *HeadingFusion) UpdatePose(p orientation.Pose, t time.Time) {
+	f.propagate(t)
+	f.pose = p
+	f.havePose = true
+}
+
+// UpdateGPS records the latest GPS position/velocity. A void fix starts an
+// outage; a valid one ends it and resets the position to the fix.
+func (f *HeadingFusion) UpdateGPS(pos gps.Position, vel gps.Velocity, t time.Time) {
+	if pos.Validity != "A" {
+		f.fixLost = f.haveFix
+		return
+	}
+	f.pos = pos
+	f.vel = vel
+	f.haveFix = true
+	f.fixLost = false
+	f.fixTime = t
+	f.drActive = false
+
+	if f.havePose && vel.SpeedKnots >= f.MinSpeedKnots {
+		f.yawOffset = normalize360(vel.CourseDeg - f.pose.Yaw)
+		f.haveYawOffset = true
+	}
+}
+
+// UpdateMotion records the stationarity detector output; while stationary
+// the dead-reckoned position does not move.
+func (f *HeadingFusion) UpdateMotion(moving bool, t time.Time) {
+	f.propagate(t)
+	f.moving = moving
+	f.haveMotion = true
+}
+
+// fixCurrent reports whether the last fix is still valid at t.
+func (f *HeadingFusion) fixCurrent(t time.Time) bool {
+	return f.haveFix && !f.fixLost && t.Sub(f.fixTime) <= f.FixTimeout
+}
+
+// drSpeed returns the dead-reckoning ground speed (m/s).
+func (f *HeadingFusion) drSpeed() float64 {
+	if f.haveMotion && !f.moving {
+		return 0
+	}
+	return f.vel.SpeedKnots * knotsToMps
+}
+
+// drHeading returns the dead-reckoning heading: gyro yaw plus the learned
+// offset, or the last GPS course if no offset was learned.
+func (f *HeadingFusion) drHeading() float64 {
+	if f.havePose && f.haveYawOffset {
+		return normalize360(f.pose.Yaw + f.yawOffset)
+	}
+	return f.vel.CourseDeg
+}
+
+// propagate advances the dead-reckoned position to t (at most to
+// MaxDRDuration after the last fix). It starts from the last fix at its time,
+// so the gap before the outage was detected is covered too.
+func (f *HeadingFusion) propagate(t time.Time) {
+	if f.fixCurrent(t) {
+		f.drActive = false
+		return
+	}
+	if !f.haveFix {
+		return
+	}
+	if !f.drActive {
+		f.drLat, f.drLon = f.pos.Latitude, f.pos.Longitude
+		f.drTime = f.fixTime
+		f.drActive = true
+	}
+	end := t
+	if limit := f.fixTime.Add(f.MaxDRDuration); end.After(limit) {
+		end = limit
+	}
+	dt := end.Sub(f.drTime).Seconds()
+	if dt <= 0 {
+		return
+	}
+	f.drTime = end
+
+	dist := f.drSpeed() * dt
+	if dist == 0 {
+		return
+	}
+	hdg := f.drHeading() * math.Pi / 180.0
+	latRad := f.drLat * math.Pi / 180.0
+	f.drLat += (dist * math.Cos(hdg) / earthRadiusM) * 180.0 / math.Pi
+	f.drLon += (dist * math.Sin(hdg) / (earthRadiusM * math.Cos(latRad))) * 180.0 / math.Pi
+}
+
+// State returns the fused state at time t, advancing the dead-reckoned
+// position to t.
+func (f *HeadingFusion) State(t time.Time) State {
+	f.propagate(t)
+	s := State{
+		Roll:           f.pose.Roll,
+		Pitch:          f.pose.Pitch,
+		HeadingSource:  "none",
+		PositionSource: PositionNone,
+		HaveGPS:        f.haveFix,
+		Time:           t.Format(time.RFC3339),
+	}
+
+	s.DeclinationDeg, s.DeclinationSource = f.DeclinationDeg, "config"
+	if f.haveFix && f.DeclinationTable != nil {
+		if d, ok := f.DeclinationTable.Lookup(f.pos.Latitude, f.pos.Longitude); ok {
+			s.DeclinationDeg, s.DeclinationSource = d, "table"
+		}
+	}
+	if f.haveFix && s.DeclinationSource == "config" && f.DeclinationModel != nil {
+		s.DeclinationDeg, s.DeclinationSource = f.DeclinationModel(f.pos.Latitude, f.pos.Longitude, t), "model"
+	}
+	if f.havePose {
+		s.TrueHeadingDeg = normalize360(f.pose.Yaw + s.DeclinationDeg)
+	}
+
+	current := f.fixCurrent(t)
+	switch {
+	case current && f.vel.SpeedKnots >= f.MinSpeedKnots:
+		s.HeadingDeg = normalize360(f.vel.CourseDeg)
+		s.HeadingSource = "gps"
+	case f.havePose:
+		s.HeadingDeg = normalize360(f.pose.Yaw + f.yawOffset)
+		s.HeadingSource = "gyro"
+	}
+
+	if !f.haveFix {
+		return s
+	}
+
+	s.Altitude = f.pos.Altitude
+	switch {
+	case current:
+		s.Latitude, s.Longitude = f.pos.Latitude, f.pos.Longitude
+		s.SpeedMps = f.vel.SpeedKnots * knotsToMps
+		s.PositionSource = PositionGPS
+	case t.Sub(f.fixTime) <= f.MaxDRDuration:
+		s.Latitude, s.Longitude = f.drLat, f.drLon
+		s.SpeedMps = f.drSpeed()
+		s.PositionSource = PositionDR
+		s.DeadReckoned = true
+	default:
+		s.Latitude, s.Longitude = f.drLat, f.drLon
+		s.PositionSource = PositionStale
+	}
+	return s
+}
+
+// normalize360 wraps an angle in degrees to [0, 360).
+func normalize360(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
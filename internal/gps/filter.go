@@ -0,0 +1,57 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package gps
+
+import "strings"
+
+// SentenceFilter selects NMEA sentences by address before they are parsed.
+// Entries are either a sentence type ("RMC"), matching every talker (GP GPS,
+// GL GLONASS, GA Galileo, GN multi-constellation, ...), or a talker plus type
+// ("GNRMC") matching that talker only. An empty filter allows everything.
+type SentenceFilter struct {
+	types     map[string]bool
+	addresses map[string]bool
+}
+
+// NewSentenceFilter builds a filter from GPS_SENTENCE_FILTER entries.
+func NewSentenceFilter(entries []string) SentenceFilter {
+	f := SentenceFilter{types: map[string]bool{}, addresses: map[string]bool{}}
+	for _, e := range entries {
+		e = strings.ToUpper(e)
+		if len(e) == 3 {
+			f.types[e] = true
+		} else {
+			f.addresses[e] = true
+		}
+	}
+	return f
+}
+
+// Allows reports whether the raw sentence line should be parsed. It only
+// looks at the address field, so rejected sentences cost no parsing.
+func (f SentenceFilter) Allows(line string) bool {
+	if len(f.types) == 0 && len(f.addresses) == 0 {
+		return true
+	}
+	talker, typ := SentenceAddress(line)
+	return f.types[typ] || f.addresses[talker+typ]
+}
+
+// SentenceAddress splits the address of a "$TTSSS,..." sentence into its
+// two-letter talker ID and sentence type. Proprietary ("$P...") and
+// malformed sentences return empty strings.
+func SentenceAddress(line string) (talker, typ string) {
+	if len(line) < 6 || line[0] != '$' || line[1] == 'P' {
+		return "", ""
+	}
+	addr := line[1:]
+	if i := strings.IndexByte(addr, ','); i >= 0 {
+		addr = addr[:i]
+	}
+	if len(addr) != 5 {
+		return "", ""
+	}
+	return addr[:2], addr[2:]
+}
@@ -38,6 +38,12 @@ type Quality struct {
 	HDOP          float64 `json:"hdop"`           // horizontal dilution of precision
 	PDOP          float64 `json:"pdop"`           // position dilution of precision
 	VDOP          float64 `json:"vdop"`           // vertical dilution of precision
+
+	// Signal health summary (from GSV, GPS + GLONASS)
+	AvgSNR     float64 `json:"avg_snr"`     // mean SNR of tracked satellites (dB)
+	MaxSNR     int64   `json:"max_snr"`     // strongest satellite SNR (dB)
+	NumUsed    int     `json:"num_used"`    // satellites with SNR >= GPS_MIN_USED_SNR
+	SNRPartial bool    `json:"snr_partial"` // summary built from an incomplete GSV sequence
 }
 
 // SatellitesInView contains all visible satellites with signal strength (from GSV).
@@ -78,3 +84,28 @@ type Fix struct {
 	GPSSatellitesInView     []Satellite `json:"gps_satellites_in_view"`     // GPS satellites with signal strength
 	GLONASSSatellitesInView []Satellite `json:"glonass_satellites_in_view"` // GLONASS satellites with signal strength
 }
+
+// SummarizeSNR computes signal health over a satellite list.
+// Satellites with SNR 0 (not tracked) are excluded from the average.
+// used counts satellites whose SNR is at least minSNR.
+func SummarizeSNR(sats []Satellite, minSNR int64) (avg float64, max int64, used int) {
+	var sum int64
+	tracked := 0
+	for _, s := range sats {
+		if s.SNR <= 0 {
+			continue
+		}
+		tracked++
+		sum += s.SNR
+		if s.SNR > max {
+			max = s.SNR
+		}
+		if s.SNR >= minSNR {
+			used++
+		}
+	}
+	if tracked > 0 {
+		avg = float64(sum) / float64(tracked)
+	}
+	return avg, max, used
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package gps
+
+import "math"
+
+// EarthRadiusM is the mean Earth radius used by the spherical helpers (meters).
+const EarthRadiusM = 6371000.0
+
+// Haversine returns the great-circle distance in meters between two points
+// given in decimal degrees, on a spherical Earth (error up to ~0.5%).
+func Haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1, phi2 := deg2rad(lat1), deg2rad(lat2)
+	dPhi := phi2 - phi1
+	dLambda := deg2rad(lon2 - lon1)
+
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * EarthRadiusM * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// Bearing returns the initial great-circle bearing from point 1 to point 2 in
+// degrees clockwise from true north, in [0, 360).
+func Bearing(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1, phi2 := deg2rad(lat1), deg2rad(lat2)
+	dLambda := deg2rad(lon2 - lon1)
+
+	y := math.Sin(dLambda) * math.Cos(phi2)
+	x := math.Cos(phi1)*math.Sin(phi2) - math.Sin(phi1)*math.Cos(phi2)*math.Cos(dLambda)
+	b := math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+	if b >= 360 {
+		b = 0
+	}
+	return b
+}
+
+// Track accumulates the distance travelled over a sequence of fixes. The
+// zero value is an empty track.
+type Track struct {
+	DistanceM float64 // total distance (meters)
+	Points    int     // fixes added
+
+	lastLat, lastLon float64
+}
+
+// Add appends a fix (decimal degrees) and returns the length of the segment
+// from the previous fix (0 for the first one).
+func (t *Track) Add(lat, lon float64) float64 {
+	var d float64
+	if t.Points > 0 {
+		d = Haversine(t.lastLat, t.lastLon, lat, lon)
+		t.DistanceM += d
+	}
+	t.lastLat, t.lastLon = lat, lon
+	t.Points++
+	return d
+}
+
+// Reset empties the track.
+func (t *Track) Reset() {
+	*t = Track{}
+}
+
+func deg2rad(d float64) float64 { return d * math.Pi / 180 }
@@ -0,0 +1,87 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package gps
+
+import (
+	"math"
+	"testing"
+)
+
+// oneDegreeM is the length of one degree of a great circle on EarthRadiusM.
+const oneDegreeM = EarthRadiusM * math.Pi / 180
+
+func TestHaversine(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		want, tol              float64
+	}{
+		{"same point", 48.137154, 11.576124, 48.137154, 11.576124, 0, 1e-9},
+		{"Paris-London", 48.8566, 2.3522, 51.5074, -0.1278, 343.5e3, 1e3},
+		{"Munich-Berlin", 48.1372, 11.5756, 52.5200, 13.4050, 504.5e3, 1e3},
+		{"one degree of latitude", 10, 20, 11, 20, oneDegreeM, 1e-6},
+		{"antimeridian", 0, 179.5, 0, -179.5, oneDegreeM, 1e-6},
+		{"antipodes", 0, 0, 0, 180, math.Pi * EarthRadiusM, 1e-6},
+	}
+	for _, tt := range tests {
+		if got := Haversine(tt.lat1, tt.lon1, tt.lat2, tt.lon2); math.Abs(got-tt.want) > tt.tol {
+			t.Errorf("%s: Haversine = %.1f m, want %.1f ± %g", tt.name, got, tt.want, tt.tol)
+		}
+		if got := Haversine(tt.lat2, tt.lon2, tt.lat1, tt.lon1); math.Abs(got-tt.want) > tt.tol {
+			t.Errorf("%s reversed: Haversine = %.1f m, want %.1f ± %g", tt.name, got, tt.want, tt.tol)
+		}
+	}
+}
+
+func TestBearing(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		want                   float64
+	}{
+		{"due north", 48, 11, 49, 11, 0},
+		{"due south", 49, 11, 48, 11, 180},
+		{"due east on the equator", 0, 10, 0, 11, 90},
+		{"due west on the equator", 0, 11, 0, 10, 270},
+		{"east across the antimeridian", 0, 179.5, 0, -179.5, 90},
+		{"west across the antimeridian", 0, -179.5, 0, 179.5, 270},
+		{"north at the antimeridian", 10, 180, 11, -180, 0},
+	}
+	for _, tt := range tests {
+		got := Bearing(tt.lat1, tt.lon1, tt.lat2, tt.lon2)
+		if got < 0 || got >= 360 {
+			t.Errorf("%s: Bearing = %g, want in [0, 360)", tt.name, got)
+		}
+		// Compare on the circle so 359.9999 matches 0
+		if d := math.Abs(math.Mod(got-tt.want+540, 360) - 180); d > 1e-6 {
+			t.Errorf("%s: Bearing = %g, want %g", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTrack(t *testing.T) {
+	var tr Track
+	if d := tr.Add(0, 0); d != 0 || tr.Points != 1 || tr.DistanceM != 0 {
+		t.Fatalf("first Add = %g, track %+v, want an empty segment", d, tr)
+	}
+	if d := tr.Add(1, 0); math.Abs(d-oneDegreeM) > 1e-6 {
+		t.Errorf("second Add = %g, want %g", d, oneDegreeM)
+	}
+	tr.Add(1, 0) // standing still
+	tr.Add(1, 1)
+	want := oneDegreeM + Haversine(1, 0, 1, 1)
+	if tr.Points != 4 || math.Abs(tr.DistanceM-want) > 1e-6 {
+		t.Errorf("track = %d points, %g m, want 4 points, %g m", tr.Points, tr.DistanceM, want)
+	}
+
+	tr.Reset()
+	if tr.Points != 0 || tr.DistanceM != 0 {
+		t.Errorf("after Reset: %+v, want empty", tr)
+	}
+	// The first fix after Reset starts a new track instead of joining the old one
+	if d := tr.Add(50, 50); d != 0 || tr.DistanceM != 0 {
+		t.Errorf("Add after Reset = %g, distance %g, want 0", d, tr.DistanceM)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package gps
+
+import "time"
+
+// Geofence is a circular zone around a center point.
+type Geofence struct {
+	Name    string
+	Lat     float64 // center, decimal degrees
+	Lon     float64 // center, decimal degrees
+	RadiusM float64
+}
+
+// GeofenceEvent reports the position entering or leaving a geofence.
+type GeofenceEvent struct {
+	Name      string  `json:"name"`
+	Event     string  `json:"event"` // "enter" or "exit"
+	Time      string  `json:"time"`  // RFC 3339, UTC
+	Lat       float64 `json:"lat"`
+	Lon       float64 `json:"lon"`
+	DistanceM float64 `json:"distance_m"` // from the geofence center
+}
+
+// GeofenceMonitor tracks whether the position is inside each geofence. To
+// avoid flapping when the position jitters around a boundary, a fence is
+// only entered within RadiusM - hysteresis and only left beyond
+// RadiusM + hysteresis.
+type GeofenceMonitor struct {
+	fences      []Geofence
+	hysteresisM float64
+	inside      []bool
+	known       bool // state established by a first fix
+}
+
+// NewGeofenceMonitor creates a monitor for fences with the given boundary
+// hysteresis in meters.
+func NewGeofenceMonitor(fences []Geofence, hysteresisM float64) *GeofenceMonitor {
+	return &GeofenceMonitor{
+		fences:      fences,
+		hysteresisM: hysteresisM,
+		inside:      make([]bool, len(fences)),
+	}
+}
+
+// Update feeds a fix and returns the resulting events. The first fix sets
+// the initial state and reports "enter" for the fences it is inside of.
+func (m *GeofenceMonitor) Update(lat, lon float64, t time.Time) []GeofenceEvent {
+	var events []GeofenceEvent
+	for i, f := range m.fences {
+		d := Haversine(f.Lat, f.Lon, lat, lon)
+
+		// The band inside the fence shrinks to at most half the radius
+		h := m.hysteresisM
+		if h > f.RadiusM/2 {
+			h = f.RadiusM / 2
+		}
+
+		var event string
+		switch {
+		case !m.known:
+			if d <= f.RadiusM {
+				m.inside[i] = true
+				event = "enter"
+			}
+		case !m.inside[i] && d <= f.RadiusM-h:
+			m.inside[i] = true
+			event = "enter"
+		case m.inside[i] && d > f.RadiusM+h:
+			m.inside[i] = false
+			event = "exit"
+		}
+		if event != "" {
+			events = append(events, GeofenceEvent{
+				Name:      f.Name,
+				Event:     event,
+				Time:      t.UTC().Format(time.RFC3339),
+				Lat:       lat,
+				Lon:       lon,
+				DistanceM: d,
+			})
+		}
+	}
+	m.known = true
+	return events
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package gps
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Default point on the simulated track, used when no geofence is configured.
+const (
+	MockLat = 48.137154
+	MockLon = 11.576124
+)
+
+const (
+	mockRadiusM   = 200.0 // track radius
+	mockSpeedMS   = 5.0   // ground speed, above FUSION_MIN_GPS_SPEED_KNOTS
+	mockAltitudeM = 520.0
+	mockEpoch     = time.Second
+	metersPerDeg  = 111320.0
+)
+
+// mockSat is a simulated satellite; its azimuth drifts slowly with time.
+type mockSat struct {
+	prn, elevation, azimuth, snr int64
+}
+
+var (
+	mockGPSSats = []mockSat{
+		{3, 62, 45, 44}, {8, 35, 120, 39}, {14, 18, 200, 31},
+		{17, 74, 300, 46}, {22, 41, 250, 40}, {27, 12, 80, 24},
+	}
+	mockGLONASSSats = []mockSat{
+		{65, 55, 10, 38}, {72, 28, 160, 33}, {81, 20, 280, 29},
+	}
+)
+
+// MockReader generates NMEA sentences (RMC, GGA, GSA, GPGSV, GLGSV) once per
+// second for a receiver driving a circle of mockRadiusM through a given point
+// at mockSpeedMS, for MOCK_HARDWARE. It has the ReadString method of
+// bufio.Reader, like ReplayReader, and never returns an error.
+type MockReader struct {
+	lat, lon float64 // circle center, mockRadiusM west of the start point
+	start    time.Time
+	next     time.Time // time of the next epoch
+	pending  []string
+	sleep    func(time.Duration) // time.Sleep, replaceable for tests
+}
+
+// NewMockReader creates a simulated receiver whose track starts at, and
+// passes through once per lap, lat, lon.
+func NewMockReader(lat, lon float64) *MockReader {
+	now := time.Now().UTC()
+	centerLon := lon - mockRadiusM/(metersPerDeg*math.Cos(lat*math.Pi/180))
+	return &MockReader{lat: lat, lon: centerLon, start: now, next: now, sleep: time.Sleep}
+}
+
+// ReadString returns the next sentence with a CRLF line ending, waiting for
+// the next epoch once the current one is used up.
+func (r *MockReader) ReadString(delim byte) (string, error) {
+	if len(r.pending) == 0 {
+		if wait := time.Until(r.next); wait > 0 {
+			r.sleep(wait)
+		}
+		r.pending = r.epoch(r.next)
+		r.next = r.next.Add(mockEpoch)
+	}
+	line := r.pending[0]
+	r.pending = r.pending[1:]
+	return line + "\r\n", nil
+}
+
+// epoch returns the sentences for time t.
+func (r *MockReader) epoch(t time.Time) []string {
+	elapsed := t.Sub(r.start).Seconds()
+	angle := mockSpeedMS * elapsed / mockRadiusM
+	north := mockRadiusM * math.Sin(angle)
+	east := mockRadiusM * math.Cos(angle)
+	lat := r.lat + north/metersPerDeg
+	lon := r.lon + east/(metersPerDeg*math.Cos(r.lat*math.Pi/180))
+	alt := mockAltitudeM + 3*math.Sin(2*math.Pi*elapsed/60)
+
+	// Velocity (cos a, -sin a): course is -a
+	course := math.Mod(360-math.Mod(angle*180/math.Pi, 360), 360)
+	speedKnots := mockSpeedMS / 0.514444
+
+	hms := t.Format("150405.00")
+	latS, ns := nmeaCoord(lat, 2, "N", "S")
+	lonS, ew := nmeaCoord(lon, 3, "E", "W")
+
+	sentences := []string{
+		fmt.Sprintf("GPRMC,%s,A,%s,%s,%s,%s,%.2f,%.1f,%s,,,A", hms, latS, ns, lonS, ew, speedKnots, course, t.Format("020106")),
+		fmt.Sprintf("GPGGA,%s,%s,%s,%s,%s,1,%02d,0.9,%.1f,M,47.0,M,,", hms, latS, ns, lonS, ew, len(mockGPSSats), alt),
+		mockGSA(mockGPSSats),
+	}
+	sentences = append(sentences, mockGSV("GP", mockGPSSats, elapsed)...)
+	sentences = append(sentences, mockGSV("GL", mockGLONASSSats, elapsed)...)
+
+	for i, s := range sentences {
+		sentences[i] = "$" + s + "*" + nmeaChecksum(s)
+	}
+	return sentences
+}
+
+// nmeaCoord formats decimal degrees as NMEA (d)ddmm.mmmm plus hemisphere.
+func nmeaCoord(deg float64, degDigits int, pos, neg string) (string, string) {
+	hemi := pos
+	if deg < 0 {
+		hemi, deg = neg, -deg
+	}
+	d := math.Floor(deg)
+	return fmt.Sprintf("%0*d%07.4f", degDigits, int(d), (deg-d)*60), hemi
+}
+
+func mockGSA(sats []mockSat) string {
+	fields := []string{"GPGSA", "A", "3"}
+	for i := range 12 {
+		if i < len(sats) {
+			fields = append(fields, fmt.Sprintf("%02d", sats[i].prn))
+		} else {
+			fields = append(fields, "")
+		}
+	}
+	return strings.Join(append(fields, "1.6", "0.9", "1.3"), ",")
+}
+
+// mockGSV returns the GSV sequence (four satellites per sentence) for talker.
+func mockGSV(talker string, sats []mockSat, elapsed float64) []string {
+	total := (len(sats) + 3) / 4
+	var out []string
+	for msg := range total {
+		fields := []string{talker + "GSV", fmt.Sprint(total), fmt.Sprint(msg + 1), fmt.Sprintf("%02d", len(sats))}
+		for _, s := range sats[msg*4 : min(len(sats), msg*4+4)] {
+			az := (s.azimuth + int64(elapsed/60)) % 360
+			fields = append(fields, fmt.Sprintf("%02d", s.prn), fmt.Sprint(s.elevation), fmt.Sprintf("%03d", az), fmt.Sprint(s.snr))
+		}
+		out = append(out, strings.Join(fields, ","))
+	}
+	return out
+}
+
+// nmeaChecksum is the XOR of the bytes between '$' and '*', as two hex digits.
+func nmeaChecksum(body string) string {
+	var cs byte
+	for i := 0; i < len(body); i++ {
+		cs ^= body[i]
+	}
+	return fmt.Sprintf("%02X", cs)
+}
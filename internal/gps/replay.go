@@ -0,0 +1,100 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package gps
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// replayDefaultEpoch is the pause between fix epochs when the sentence
+	// times cannot be used (missing, going backwards or too far apart).
+	replayDefaultEpoch = time.Second
+	replayMaxEpoch     = 5 * time.Second
+	day                = 24 * time.Hour
+)
+
+// ReplayReader reads captured NMEA lines (e.g. a GPS_NMEA_LOG file) at about
+// the rate a receiver would send them: sentences are grouped into fix epochs
+// by the UTC time field of RMC/GGA sentences, and the reader sleeps for the
+// time difference whenever a new epoch starts (replayDefaultEpoch if the
+// difference is not usable). At the end of the input it returns io.EOF, or
+// starts over if loop is set.
+type ReplayReader struct {
+	src   io.ReadSeeker
+	r     *bufio.Reader
+	loop  bool
+	sleep func(time.Duration) // time.Sleep, replaceable for tests
+
+	epoch     time.Duration // time of day of the current epoch
+	haveEpoch bool
+}
+
+// NewReplayReader creates a replay reader over src.
+func NewReplayReader(src io.ReadSeeker, loop bool) *ReplayReader {
+	return &ReplayReader{src: src, r: bufio.NewReader(src), loop: loop, sleep: time.Sleep}
+}
+
+// ReadString returns the next line including the delimiter, like
+// bufio.Reader.ReadString, pausing first if it starts a new fix epoch.
+func (rr *ReplayReader) ReadString(delim byte) (string, error) {
+	line, err := rr.r.ReadString(delim)
+	if err == io.EOF && line == "" && rr.loop {
+		if _, serr := rr.src.Seek(0, io.SeekStart); serr != nil {
+			return "", serr
+		}
+		rr.r.Reset(rr.src)
+		rr.haveEpoch = false
+		line, err = rr.r.ReadString(delim)
+	}
+	if line == "" {
+		return line, err
+	}
+
+	if t, ok := sentenceTimeOfDay(line); ok {
+		if rr.haveEpoch && t != rr.epoch {
+			wait := t - rr.epoch
+			if wait < 0 {
+				wait += day // past midnight
+			}
+			if wait <= 0 || wait > replayMaxEpoch {
+				wait = replayDefaultEpoch
+			}
+			rr.sleep(wait)
+		}
+		rr.epoch, rr.haveEpoch = t, true
+	}
+	return line, err
+}
+
+// sentenceTimeOfDay returns the UTC time field (hhmmss.ss) of an RMC or GGA
+// sentence as a time of day.
+func sentenceTimeOfDay(line string) (time.Duration, bool) {
+	fields := strings.Split(strings.TrimSpace(line), ",")
+	if len(fields) < 2 || len(fields[0]) < 6 {
+		return 0, false
+	}
+	switch fields[0][3:] {
+	case "RMC", "GGA":
+	default:
+		return 0, false
+	}
+
+	f := fields[1]
+	if len(f) < 6 {
+		return 0, false
+	}
+	h, err1 := strconv.Atoi(f[0:2])
+	m, err2 := strconv.Atoi(f[2:4])
+	s, err3 := strconv.ParseFloat(f[4:], 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return 0, false
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s*float64(time.Second)), true
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package imu
+
+import "math"
+
+// AK8963ToMPU converts a magnetometer sample from the AK8963 axes to the
+// MPU9250 accel/gyro axes. The AK8963 die is rotated inside the package
+// (MPU-9250 datasheet §9.1): its X is the accel Y, its Y the accel X and its
+// Z points the other way, so MPU (x, y, z) = AK8963 (y, x, -z). The
+// transform is a rotation, so the frame stays right-handed.
+func AK8963ToMPU(mx, my, mz int16) (int16, int16, int16) {
+	return my, mx, negate16(mz)
+}
+
+// AxisMap rotates samples from the sensor frame into the vehicle frame for a
+// board that is not mounted axis-aligned (IMU_LEFT_AXIS_MAP /
+// IMU_RIGHT_AXIS_MAP). Entry i is the sensor axis feeding vehicle axis i as a
+// signed 1-based index: {+2, -1, +3} ("+y,-x,+z") means vehicle X = sensor
+// Y, vehicle Y = -sensor X, vehicle Z = sensor Z. The zero value is the
+// identity. Validity (a right-handed permutation) is checked when the config
+// is parsed.
+type AxisMap [3]int8
+
+// IsIdentity reports whether the map leaves samples unchanged.
+func (m AxisMap) IsIdentity() bool {
+	return m == AxisMap{} || m == AxisMap{1, 2, 3}
+}
+
+// Apply remaps the accel, gyro and mag axes of s. Negating -32768 saturates
+// at 32767.
+func (m AxisMap) Apply(s IMURaw) IMURaw {
+	if m.IsIdentity() {
+		return s
+	}
+	s.Ax, s.Ay, s.Az = m.remap(s.Ax, s.Ay, s.Az)
+	s.Gx, s.Gy, s.Gz = m.remap(s.Gx, s.Gy, s.Gz)
+	s.Mx, s.My, s.Mz = m.remap(s.Mx, s.My, s.Mz)
+	return s
+}
+
+func (m AxisMap) remap(x, y, z int16) (int16, int16, int16) {
+	in := [3]int16{x, y, z}
+	var out [3]int16
+	for i, a := range m {
+		if a < 0 {
+			out[i] = negate16(in[-a-1])
+		} else {
+			out[i] = in[a-1]
+		}
+	}
+	return out[0], out[1], out[2]
+}
+
+// negate16 returns -v, saturating -32768 to 32767.
+func negate16(v int16) int16 {
+	if v == math.MinInt16 {
+		return math.MaxInt16
+	}
+	return -v
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package imu
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAK8963ToMPU(t *testing.T) {
+	tests := []struct {
+		in, want [3]int16
+	}{
+		{[3]int16{1, 2, 3}, [3]int16{2, 1, -3}},
+		{[3]int16{-100, 250, -480}, [3]int16{250, -100, 480}},
+		{[3]int16{0, 0, 0}, [3]int16{0, 0, 0}},
+		// −z saturates instead of wrapping back to -32768
+		{[3]int16{math.MinInt16, math.MaxInt16, math.MinInt16}, [3]int16{math.MaxInt16, math.MinInt16, math.MaxInt16}},
+	}
+	for _, tt := range tests {
+		x, y, z := AK8963ToMPU(tt.in[0], tt.in[1], tt.in[2])
+		if got := [3]int16{x, y, z}; got != tt.want {
+			t.Errorf("AK8963ToMPU(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNegate16(t *testing.T) {
+	for _, tt := range []struct{ in, want int16 }{
+		{0, 0},
+		{1, -1},
+		{-1, 1},
+		{math.MaxInt16, -math.MaxInt16},
+		{math.MinInt16, math.MaxInt16},
+	} {
+		if got := negate16(tt.in); got != tt.want {
+			t.Errorf("negate16(%d) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
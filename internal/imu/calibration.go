@@ -0,0 +1,459 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package imu
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Calibration is the correction applied to IMURaw samples, loaded from a
+// calibration file written by cmd/calibration (schema_version 3) or the web
+// calibration UI (version 2). Corrected samples stay in counts:
+//
+//   - gyro:  raw - GyroBias
+//   - accel: (raw - AccelBias) / AccelScale * mean(AccelScale), i.e. the axis
+//     gains are equalized without changing the overall sensitivity. With
+//     AccelScaleTempCoeff, each AccelScale is first multiplied by
+//     1 + coeff * (DieTempC - AccelScaleRefTempC) for samples with TempValid.
+//     With AccelMatrix (see FitAccelMatrix) it is AccelMatrix·(raw - AccelBias)
+//     instead, the temperature factor dividing each raw axis before the matrix
+//   - mag:   MagCounts(Mag.Apply(raw)), only for samples with MagValid
+type Calibration struct {
+	IMU        string          `json:"imu"`                   // "left" or "right"
+	Source     string          `json:"source,omitempty"`      // file the calibration was loaded from
+	GyroBias   [3]float64      `json:"gyro_bias"`             // counts
+	AccelBias  [3]float64      `json:"accel_bias"`            // counts
+	AccelScale [3]float64      `json:"accel_scale,omitempty"` // counts per g; zero = no accel correction
+	Mag        *MagCalibration `json:"mag,omitempty"`
+
+	// Optional accel scale temperature compensation (zero = none), see
+	// FitAccelScaleTemp
+	AccelScaleTempCoeff [3]float64 `json:"accel_scale_temp_coeff,omitempty"` // relative scale change per °C
+	AccelScaleRefTempC  float64    `json:"accel_scale_ref_temp_c,omitempty"` // die temperature of AccelScale
+
+	// Optional scale/misalignment matrix (zero = diagonal AccelScale only),
+	// see FitAccelMatrix
+	AccelMatrix [3][3]float64 `json:"accel_matrix,omitzero"`
+
+	// Why part of the file was not applied, e.g. a mag calibration in the
+	// old AK8963 frame (see magFrameWarning)
+	Warning string `json:"warning,omitempty"`
+}
+
+// First file versions whose mag calibration is in the accel/gyro frame
+// (AK8963ToMPU); older ones recorded the raw AK8963 axes.
+const (
+	CLISchemaVersion  = 3 // cmd/calibration schema_version
+	WebCalibVersion   = 2 // web calibration UI version
+	magFrameWarning   = "mag calibration predates the accel/gyro-frame magnetometer axes and is ignored; recalibrate the magnetometer"
+	oldCLISchema      = 2
+	oldWebCalibration = 1
+)
+
+// cliCalibrationFile is the subset of the cmd/calibration output that is applied.
+type cliCalibrationFile struct {
+	SchemaVersion int            `json:"schema_version"`
+	IMU           string         `json:"imu"`
+	GyroBiasFinal vec3           `json:"gyro_bias_final"`
+	AccelBias     vec3           `json:"accel_bias"`
+	AccelScale    vec3           `json:"accel_scale"`
+	AccelMatrix   *[3][3]float64 `json:"accel_matrix"`
+	MagOffset     vec3           `json:"mag_offset"`
+	MagScale      vec3           `json:"mag_scale"`
+
+	AccelScaleTempCoeff *vec3   `json:"accel_scale_temp_coeff"`
+	AccelScaleRefTempC  float64 `json:"accel_scale_ref_temp_c"`
+}
+
+type vec3 struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
+
+func (v vec3) array() [3]float64 { return [3]float64{v.X, v.Y, v.Z} }
+
+// webCalibrationFile is the subset of the web calibration output that is
+// applied. Its accel fields are not in counts and are ignored.
+type webCalibrationFile struct {
+	Version    int     `json:"version"`
+	IMU        string  `json:"imu"`
+	GyroBiasX  float64 `json:"gyro_bias_x"`
+	GyroBiasY  float64 `json:"gyro_bias_y"`
+	GyroBiasZ  float64 `json:"gyro_bias_z"`
+	MagOffsetX float64 `json:"mag_offset_x"`
+	MagOffsetY float64 `json:"mag_offset_y"`
+	MagOffsetZ float64 `json:"mag_offset_z"`
+	MagScaleX  float64 `json:"mag_scale_x"`
+	MagScaleY  float64 `json:"mag_scale_y"`
+	MagScaleZ  float64 `json:"mag_scale_z"`
+}
+
+// ParseCalibration decodes and validates a calibration file.
+func ParseCalibration(data []byte) (Calibration, error) {
+	var probe struct {
+		SchemaVersion int `json:"schema_version"`
+		Version       int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return Calibration{}, fmt.Errorf("invalid calibration JSON: %w", err)
+	}
+
+	var c Calibration
+	switch {
+	case probe.SchemaVersion == CLISchemaVersion || probe.SchemaVersion == oldCLISchema:
+		var f cliCalibrationFile
+		if err := json.Unmarshal(data, &f); err != nil {
+			return Calibration{}, fmt.Errorf("invalid calibration JSON: %w", err)
+		}
+		c = Calibration{
+			IMU:        f.IMU,
+			GyroBias:   f.GyroBiasFinal.array(),
+			AccelBias:  f.AccelBias.array(),
+			AccelScale: f.AccelScale.array(),
+		}
+		if probe.SchemaVersion == oldCLISchema {
+			c.Warning = magFrameWarning
+		} else {
+			c.Mag = &MagCalibration{OffsetUT: f.MagOffset.array(), Scale: f.MagScale.array()}
+		}
+		if f.AccelMatrix != nil {
+			c.AccelMatrix = *f.AccelMatrix
+		}
+		if f.AccelScaleTempCoeff != nil {
+			c.AccelScaleTempCoeff = f.AccelScaleTempCoeff.array()
+			c.AccelScaleRefTempC = f.AccelScaleRefTempC
+		}
+	case probe.SchemaVersion == 0 && (probe.Version == WebCalibVersion || probe.Version == oldWebCalibration):
+		var f webCalibrationFile
+		if err := json.Unmarshal(data, &f); err != nil {
+			return Calibration{}, fmt.Errorf("invalid calibration JSON: %w", err)
+		}
+		c = Calibration{
+			IMU:      f.IMU,
+			GyroBias: [3]float64{f.GyroBiasX, f.GyroBiasY, f.GyroBiasZ},
+		}
+		if probe.Version == oldWebCalibration {
+			c.Warning = magFrameWarning
+		} else {
+			c.Mag = &MagCalibration{
+				OffsetUT: [3]float64{f.MagOffsetX, f.MagOffsetY, f.MagOffsetZ},
+				Scale:    [3]float64{f.MagScaleX, f.MagScaleY, f.MagScaleZ},
+			}
+		}
+	default:
+		return Calibration{}, fmt.Errorf("unsupported calibration schema (schema_version %d, version %d)", probe.SchemaVersion, probe.Version)
+	}
+
+	if err := c.Validate(); err != nil {
+		return Calibration{}, err
+	}
+	return c, nil
+}
+
+// Validate checks that the calibration names an IMU and that every
+// coefficient is finite, with positive scales.
+func (c Calibration) Validate() error {
+	if c.IMU != "left" && c.IMU != "right" {
+		return fmt.Errorf("invalid calibration imu %q: must be left or right", c.IMU)
+	}
+	for i := 0; i < 3; i++ {
+		if !finite(c.GyroBias[i]) || !finite(c.AccelBias[i]) {
+			return fmt.Errorf("calibration bias is not finite")
+		}
+	}
+	if c.AccelScale != [3]float64{} {
+		for _, s := range c.AccelScale {
+			if !finite(s) || s <= 0 {
+				return fmt.Errorf("invalid calibration accel scale %v: must be > 0", c.AccelScale)
+			}
+		}
+	}
+	if c.AccelMatrix != [3][3]float64{} {
+		for _, row := range c.AccelMatrix {
+			for _, v := range row {
+				if !finite(v) {
+					return fmt.Errorf("calibration accel matrix is not finite")
+				}
+			}
+		}
+		if det3(c.AccelMatrix) <= 0 {
+			return fmt.Errorf("invalid calibration accel matrix: determinant must be > 0")
+		}
+	}
+	for _, k := range c.AccelScaleTempCoeff {
+		if !finite(k) {
+			return fmt.Errorf("calibration accel scale temperature coefficient is not finite")
+		}
+	}
+	if !finite(c.AccelScaleRefTempC) {
+		return fmt.Errorf("calibration accel scale reference temperature is not finite")
+	}
+	if c.Mag != nil {
+		for i := 0; i < 3; i++ {
+			if !finite(c.Mag.OffsetUT[i]) {
+				return fmt.Errorf("calibration mag offset is not finite")
+			}
+			if s := c.Mag.Scale[i]; !finite(s) || s <= 0 {
+				return fmt.Errorf("invalid calibration mag scale %v: must be > 0", c.Mag.Scale)
+			}
+		}
+	}
+	return nil
+}
+
+// Apply returns s corrected by the calibration. Source and flags pass through.
+func (c Calibration) Apply(s IMURaw) IMURaw {
+	s.Gx = clampCounts(float64(s.Gx) - c.GyroBias[0])
+	s.Gy = clampCounts(float64(s.Gy) - c.GyroBias[1])
+	s.Gz = clampCounts(float64(s.Gz) - c.GyroBias[2])
+
+	if c.AccelMatrix != [3][3]float64{} {
+		d := [3]float64{float64(s.Ax) - c.AccelBias[0], float64(s.Ay) - c.AccelBias[1], float64(s.Az) - c.AccelBias[2]}
+		if c.AccelScaleTempCoeff != [3]float64{} && s.TempValid {
+			dt := DieTempC(s.Temp) - c.AccelScaleRefTempC
+			for i := range d {
+				d[i] /= 1 + c.AccelScaleTempCoeff[i]*dt
+			}
+		}
+		m := c.AccelMatrix
+		s.Ax = clampCounts(m[0][0]*d[0] + m[0][1]*d[1] + m[0][2]*d[2])
+		s.Ay = clampCounts(m[1][0]*d[0] + m[1][1]*d[1] + m[1][2]*d[2])
+		s.Az = clampCounts(m[2][0]*d[0] + m[2][1]*d[1] + m[2][2]*d[2])
+	} else if c.AccelScale != [3]float64{} {
+		ref := (c.AccelScale[0] + c.AccelScale[1] + c.AccelScale[2]) / 3
+		scale := c.AccelScale
+		if c.AccelScaleTempCoeff != [3]float64{} && s.TempValid {
+			dt := DieTempC(s.Temp) - c.AccelScaleRefTempC
+			for i := range scale {
+				scale[i] *= 1 + c.AccelScaleTempCoeff[i]*dt
+			}
+		}
+		s.Ax = clampCounts((float64(s.Ax) - c.AccelBias[0]) / scale[0] * ref)
+		s.Ay = clampCounts((float64(s.Ay) - c.AccelBias[1]) / scale[1] * ref)
+		s.Az = clampCounts((float64(s.Az) - c.AccelBias[2]) / scale[2] * ref)
+	}
+
+	if c.Mag != nil && s.MagValid {
+		m := c.Mag.Apply(s.Mx, s.My, s.Mz)
+		s.Mx, s.My, s.Mz = MagCounts(m[0]), MagCounts(m[1]), MagCounts(m[2])
+	}
+	return s
+}
+
+// MinAccelScaleTempSpreadC is the smallest die temperature range
+// FitAccelScaleTemp accepts; narrower captures give meaningless slopes.
+const MinAccelScaleTempSpreadC = 5.0
+
+// AccelScaleTempPoint is one accel 6-point calibration (AccelScale, counts
+// per g) taken at a die temperature.
+type AccelScaleTempPoint struct {
+	TempC float64
+	Scale [3]float64
+}
+
+// FitAccelScaleTemp fits scale(T) = scale * (1 + coeff * (T - refTempC)) per
+// axis by least squares over calibrations taken at different temperatures.
+// refTempC is the mean temperature of the points and scale the fitted scale
+// at that temperature.
+func FitAccelScaleTemp(points []AccelScaleTempPoint) (scale, coeff [3]float64, refTempC float64, err error) {
+	if len(points) < 2 {
+		return scale, coeff, 0, fmt.Errorf("need at least 2 calibrations, got %d", len(points))
+	}
+
+	minT, maxT := points[0].TempC, points[0].TempC
+	for _, p := range points {
+		refTempC += p.TempC
+		minT = math.Min(minT, p.TempC)
+		maxT = math.Max(maxT, p.TempC)
+	}
+	refTempC /= float64(len(points))
+	if maxT-minT < MinAccelScaleTempSpreadC {
+		return scale, coeff, 0, fmt.Errorf("temperature spread %.1f °C is below %.0f °C", maxT-minT, MinAccelScaleTempSpreadC)
+	}
+
+	for i := 0; i < 3; i++ {
+		var meanS, sxx, sxy float64
+		for _, p := range points {
+			meanS += p.Scale[i]
+		}
+		meanS /= float64(len(points))
+		if meanS <= 0 {
+			return scale, coeff, 0, fmt.Errorf("invalid accel scale on axis %d", i)
+		}
+		for _, p := range points {
+			dt := p.TempC - refTempC
+			sxx += dt * dt
+			sxy += dt * (p.Scale[i] - meanS)
+		}
+		scale[i] = meanS
+		coeff[i] = sxy / sxx / meanS
+	}
+	return scale, coeff, refTempC, nil
+}
+
+// AccelPose is the mean raw accel reading (counts) of one static pose and the
+// direction gravity reaction points to in the sensor frame for that pose
+// (e.g. {0, 0, 1} lying flat with +Z up, {0.707, 0.707, 0} resting on the
+// +X/+Y edge).
+type AccelPose struct {
+	Up   [3]float64
+	Mean [3]float64
+}
+
+// FitAccelMatrix fits raw = S·up + bias by least squares over the poses, S
+// being the 3x3 sensitivity (counts per g, off-diagonal terms = cross-axis
+// misalignment), and returns the correction matrix M = mean(diag S)·S⁻¹ for
+// corrected = M·(raw - bias), which keeps the result in counts at the mean
+// sensitivity. rmsErrG is the RMS distance between the corrected poses and
+// their up directions, in g.
+//
+// The up directions must span all three axes (the six ±X/±Y/±Z poses do);
+// further poses, such as repeats or edge poses, average out placement errors.
+func FitAccelMatrix(poses []AccelPose) (bias [3]float64, m [3][3]float64, rmsErrG float64, err error) {
+	if len(poses) < 6 {
+		return bias, m, 0, fmt.Errorf("need at least 6 poses, got %d", len(poses))
+	}
+
+	// Each output axis r is an independent linear fit of
+	// raw[r] = S[r][0]*up[0] + S[r][1]*up[1] + S[r][2]*up[2] + bias[r]
+	var n [4][4]float64
+	var rhs [3][4]float64
+	for _, p := range poses {
+		u := normalize3(p.Up)
+		x := [4]float64{u[0], u[1], u[2], 1}
+		for i := 0; i < 4; i++ {
+			for j := 0; j < 4; j++ {
+				n[i][j] += x[i] * x[j]
+			}
+			for r := 0; r < 3; r++ {
+				rhs[r][i] += x[i] * p.Mean[r]
+			}
+		}
+	}
+
+	var s [3][3]float64
+	for r := 0; r < 3; r++ {
+		sol, ok := solve4(n, rhs[r])
+		if !ok {
+			return bias, m, 0, fmt.Errorf("pose directions do not span all three axes")
+		}
+		s[r] = [3]float64{sol[0], sol[1], sol[2]}
+		bias[r] = sol[3]
+	}
+
+	ref := (s[0][0] + s[1][1] + s[2][2]) / 3
+	inv, ok := invert3(s)
+	if !ok || ref <= 0 {
+		return bias, m, 0, fmt.Errorf("fitted accel sensitivity is singular")
+	}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			m[i][j] = ref * inv[i][j]
+		}
+	}
+
+	var sum float64
+	for _, p := range poses {
+		u := normalize3(p.Up)
+		for i := 0; i < 3; i++ {
+			var c float64
+			for j := 0; j < 3; j++ {
+				c += m[i][j] * (p.Mean[j] - bias[j])
+			}
+			e := c/ref - u[i]
+			sum += e * e
+		}
+	}
+	return bias, m, math.Sqrt(sum / float64(len(poses))), nil
+}
+
+func normalize3(v [3]float64) [3]float64 {
+	l := math.Sqrt(v[0]*v[0] + v[1]*v[1] + v[2]*v[2])
+	if l == 0 {
+		return v
+	}
+	return [3]float64{v[0] / l, v[1] / l, v[2] / l}
+}
+
+// solve4 solves a·x = b by Gauss-Jordan elimination with partial pivoting,
+// reporting false for a (near) singular a.
+func solve4(a [4][4]float64, b [4]float64) ([4]float64, bool) {
+	scale := 0.0
+	for i := range a {
+		for j := range a[i] {
+			scale = math.Max(scale, math.Abs(a[i][j]))
+		}
+	}
+	for col := 0; col < 4; col++ {
+		p := col
+		for r := col + 1; r < 4; r++ {
+			if math.Abs(a[r][col]) > math.Abs(a[p][col]) {
+				p = r
+			}
+		}
+		if math.Abs(a[p][col]) <= 1e-9*scale {
+			return b, false
+		}
+		a[col], a[p] = a[p], a[col]
+		b[col], b[p] = b[p], b[col]
+		for r := 0; r < 4; r++ {
+			if r == col {
+				continue
+			}
+			f := a[r][col] / a[col][col]
+			for c := col; c < 4; c++ {
+				a[r][c] -= f * a[col][c]
+			}
+			b[r] -= f * b[col]
+		}
+	}
+	for i := 0; i < 4; i++ {
+		b[i] /= a[i][i]
+	}
+	return b, true
+}
+
+func det3(m [3][3]float64) float64 {
+	return m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+}
+
+// invert3 inverts m by cofactors, reporting false if it is singular.
+func invert3(m [3][3]float64) ([3][3]float64, bool) {
+	d := det3(m)
+	if d == 0 || !finite(d) {
+		return [3][3]float64{}, false
+	}
+	var inv [3][3]float64
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			// cofactor of m[j][i] (transposed), cyclic index form
+			a, b := (j+1)%3, (j+2)%3
+			c, e := (i+1)%3, (i+2)%3
+			inv[i][j] = (m[a][c]*m[b][e] - m[a][e]*m[b][c]) / d
+		}
+	}
+	return inv, true
+}
+
+// clampCounts rounds v to the nearest int16, saturating at the range limits.
+func clampCounts(v float64) int16 {
+	v = math.Round(v)
+	if v > math.MaxInt16 {
+		return math.MaxInt16
+	}
+	if v < math.MinInt16 {
+		return math.MinInt16
+	}
+	return int16(v)
+}
+
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package imu
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestParseCalibrationMagFrame(t *testing.T) {
+	const cli = `{"schema_version": %d, "imu": "left",
+		"gyro_bias_final": {"x": 1, "y": 2, "z": 3},
+		"accel_bias": {"x": 0, "y": 0, "z": 0},
+		"accel_scale": {"x": 16384, "y": 16384, "z": 16384},
+		"mag_offset": {"x": 10, "y": -5, "z": 2},
+		"mag_scale": {"x": 1, "y": 1.1, "z": 0.9}}`
+	const web = `{"version": %d, "imu": "right", "gyro_bias_x": 1,
+		"mag_offset_x": 10, "mag_scale_x": 1, "mag_scale_y": 1, "mag_scale_z": 1}`
+
+	tests := []struct {
+		name    string
+		data    string
+		wantMag bool
+	}{
+		{"cli current", fmt.Sprintf(cli, CLISchemaVersion), true},
+		{"cli AK8963 frame", fmt.Sprintf(cli, 2), false},
+		{"web current", fmt.Sprintf(web, WebCalibVersion), true},
+		{"web AK8963 frame", fmt.Sprintf(web, 1), false},
+	}
+	for _, tt := range tests {
+		c, err := ParseCalibration([]byte(tt.data))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if (c.Mag != nil) != tt.wantMag || (c.Warning == "") != tt.wantMag {
+			t.Errorf("%s: Mag = %v, Warning = %q, want mag applied = %t", tt.name, c.Mag, c.Warning, tt.wantMag)
+		}
+		if c.GyroBias[0] != 1 {
+			t.Errorf("%s: GyroBias = %v, want the gyro part applied", tt.name, c.GyroBias)
+		}
+	}
+
+	if _, err := ParseCalibration([]byte(fmt.Sprintf(cli, 4))); err == nil {
+		t.Error("schema_version 4: expected error")
+	}
+}
+
+// accelScaleAt is the synthetic sensor: s0 counts per g at 25 °C, changing
+// by k per °C.
+func accelScaleAt(s0, k [3]float64, tempC float64) [3]float64 {
+	var s [3]float64
+	for i := range s {
+		s[i] = s0[i] * (1 + k[i]*(tempC-25))
+	}
+	return s
+}
+
+func TestAccelScaleTempCompensation(t *testing.T) {
+	s0 := [3]float64{16500, 16300, 16384}
+	k := [3]float64{2e-3, -1e-3, 5e-4}
+
+	var points []AccelScaleTempPoint
+	for _, tempC := range []float64{15, 25, 35, 45} {
+		points = append(points, AccelScaleTempPoint{TempC: tempC, Scale: accelScaleAt(s0, k, tempC)})
+	}
+	scale, coeff, refTempC, err := FitAccelScaleTemp(points)
+	if err != nil {
+		t.Fatalf("FitAccelScaleTemp: %v", err)
+	}
+	if refTempC != 30 {
+		t.Errorf("refTempC = %g, want 30 (mean of the points)", refTempC)
+	}
+	wantScale := accelScaleAt(s0, k, refTempC)
+	for i := range 3 {
+		if math.Abs(scale[i]-wantScale[i]) > 1e-6 {
+			t.Errorf("scale[%d] = %g, want %g", i, scale[i], wantScale[i])
+		}
+		// Relative to the scale at refTempC rather than at 25 °C
+		if want := s0[i] * k[i] / wantScale[i]; math.Abs(coeff[i]-want) > 1e-12 {
+			t.Errorf("coeff[%d] = %g, want %g", i, coeff[i], want)
+		}
+	}
+
+	ref := (scale[0] + scale[1] + scale[2]) / 3
+	diag := [3][3]float64{{ref / scale[0]}, {0, ref / scale[1]}, {0, 0, ref / scale[2]}}
+	cals := map[string]Calibration{
+		"scale":  {IMU: "left", AccelScale: scale, AccelScaleTempCoeff: coeff, AccelScaleRefTempC: refTempC},
+		"matrix": {IMU: "left", AccelScale: scale, AccelScaleTempCoeff: coeff, AccelScaleRefTempC: refTempC, AccelMatrix: diag},
+	}
+	for name, c := range cals {
+		if err := c.Validate(); err != nil {
+			t.Fatalf("%s: Validate: %v", name, err)
+		}
+		for _, tempC := range []float64{10, 30, 50} {
+			// 1 g on every axis at the sensor's true scale for this temperature
+			temp := int16(math.Round((tempC - tempOffsetC) * tempLSBPerDegC))
+			sens := accelScaleAt(s0, k, DieTempC(temp))
+			s := IMURaw{Ax: int16(math.Round(sens[0])), Ay: int16(math.Round(sens[1])), Az: int16(math.Round(sens[2])), Temp: temp, TempValid: true}
+
+			got := c.Apply(s)
+			for i, v := range [3]int16{got.Ax, got.Ay, got.Az} {
+				if math.Abs(float64(v)-ref) > 1.5 {
+					t.Errorf("%s at %g °C: axis %d = %d counts, want %.0f (1 g at the mean scale)", name, tempC, i, v, ref)
+				}
+			}
+
+			// Without a die temperature only the reference-temperature scale applies
+			s.TempValid = false
+			if tempC != 30 {
+				if got := c.Apply(s); math.Abs(float64(got.Ax)-ref) < 10 {
+					t.Errorf("%s at %g °C without TempValid: Ax = %d, want the uncompensated drift", name, tempC, got.Ax)
+				}
+			}
+		}
+	}
+}
+
+func TestFitAccelScaleTempErrors(t *testing.T) {
+	s := [3]float64{16384, 16384, 16384}
+	tests := []struct {
+		name   string
+		points []AccelScaleTempPoint
+	}{
+		{"single point", []AccelScaleTempPoint{{TempC: 25, Scale: s}}},
+		{"narrow spread", []AccelScaleTempPoint{{TempC: 25, Scale: s}, {TempC: 28, Scale: s}}},
+		{"missing scale", []AccelScaleTempPoint{{TempC: 20}, {TempC: 40}}},
+	}
+	for _, tt := range tests {
+		if _, _, _, err := FitAccelScaleTemp(tt.points); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package imu
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Filter kinds accepted by NewFilter (IMU_FILTER).
+const (
+	FilterNone          = "none"
+	FilterMovingAverage = "moving_average"
+	FilterMedian        = "median"
+
+	// DefaultFilterWindow applies when IMU_FILTER_WINDOW is not configured.
+	DefaultFilterWindow = 5
+)
+
+// Filter smooths a stream of IMURaw samples. Accel and gyro axes are filtered
+// over a window of the last n samples; mag, Source and flags pass through
+// unchanged. Until the window has filled, the available samples are used.
+//
+// Both implementations delay the signal by about (n-1)/2 samples, e.g. 2
+// samples (80 ms at IMU_SAMPLE_INTERVAL=40) for n=5. The median rejects
+// single-sample spikes but, like the moving average, smears a step over n
+// samples.
+//
+// Call Reset after a gap in the stream (e.g. a read error) so samples from
+// before the gap are not mixed with new ones. Filters are not safe for
+// concurrent use.
+type Filter interface {
+	Apply(s IMURaw) IMURaw
+	Reset()
+}
+
+// NewFilter returns the filter for kind ("none", "moving_average" or
+// "median") with a window of n samples. "none" (or "") returns nil.
+func NewFilter(kind string, n int) (Filter, error) {
+	switch kind {
+	case "", FilterNone:
+		return nil, nil
+	case FilterMovingAverage:
+		return MovingAverage(n), nil
+	case FilterMedian:
+		return Median(n), nil
+	}
+	return nil, fmt.Errorf("unknown IMU filter %q", kind)
+}
+
+// MovingAverage returns a filter that averages each accel/gyro axis over the
+// last n samples (n < 1 is treated as 1, i.e. no smoothing).
+func MovingAverage(n int) Filter {
+	return &movingAverage{w: newAxisWindow(n)}
+}
+
+// Median returns a filter that takes the per-axis median of the last n
+// samples (n < 1 is treated as 1). Odd n avoids averaging the middle pair.
+func Median(n int) Filter {
+	w := newAxisWindow(n)
+	return &median{w: w, scratch: make([]int16, 0, len(w.buf[0]))}
+}
+
+// filteredAxes is the number of axes held in the window: ax, ay, az, gx, gy, gz.
+const filteredAxes = 6
+
+// axisWindow keeps one ring buffer per accel/gyro axis.
+type axisWindow struct {
+	buf   [filteredAxes][]int16
+	next  int
+	count int
+}
+
+func newAxisWindow(n int) axisWindow {
+	if n < 1 {
+		n = 1
+	}
+	var w axisWindow
+	for i := range w.buf {
+		w.buf[i] = make([]int16, n)
+	}
+	return w
+}
+
+func (w *axisWindow) push(s IMURaw) {
+	for i, v := range [filteredAxes]int16{s.Ax, s.Ay, s.Az, s.Gx, s.Gy, s.Gz} {
+		w.buf[i][w.next] = v
+	}
+	w.next = (w.next + 1) % len(w.buf[0])
+	if w.count < len(w.buf[0]) {
+		w.count++
+	}
+}
+
+// values returns the filled part of an axis buffer (order not preserved).
+func (w *axisWindow) values(axis int) []int16 {
+	return w.buf[axis][:w.count]
+}
+
+func (w *axisWindow) reset() {
+	w.next = 0
+	w.count = 0
+}
+
+// withAxes returns s with its accel/gyro axes replaced.
+func withAxes(s IMURaw, v [filteredAxes]int16) IMURaw {
+	s.Ax, s.Ay, s.Az = v[0], v[1], v[2]
+	s.Gx, s.Gy, s.Gz = v[3], v[4], v[5]
+	return s
+}
+
+type movingAverage struct {
+	w axisWindow
+}
+
+func (f *movingAverage) Apply(s IMURaw) IMURaw {
+	f.w.push(s)
+	var out [filteredAxes]int16
+	for i := range out {
+		var sum int64
+		vals := f.w.values(i)
+		for _, v := range vals {
+			sum += int64(v)
+		}
+		out[i] = int16(math.Round(float64(sum) / float64(len(vals))))
+	}
+	return withAxes(s, out)
+}
+
+func (f *movingAverage) Reset() {
+	f.w.reset()
+}
+
+type median struct {
+	w       axisWindow
+	scratch []int16
+}
+
+func (f *median) Apply(s IMURaw) IMURaw {
+	f.w.push(s)
+	var out [filteredAxes]int16
+	for i := range out {
+		f.scratch = append(f.scratch[:0], f.w.values(i)...)
+		sort.Slice(f.scratch, func(a, b int) bool { return f.scratch[a] < f.scratch[b] })
+		n := len(f.scratch)
+		if n%2 == 1 {
+			out[i] = f.scratch[n/2]
+		} else {
+			out[i] = int16(math.Round((float64(f.scratch[n/2-1]) + float64(f.scratch[n/2])) / 2))
+		}
+	}
+	return withAxes(s, out)
+}
+
+func (f *median) Reset() {
+	f.w.reset()
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package imu
+
+import (
+	"slices"
+	"testing"
+)
+
+// filterSample puts v on every accel/gyro axis and a fixed mag reading.
+func filterSample(v int16) IMURaw {
+	return IMURaw{Source: "left", Ax: v, Ay: v, Az: v, Gx: v, Gy: v, Gz: v, Mx: 7, My: 8, Mz: 9, MagValid: true}
+}
+
+// runFilter feeds in through f and returns the filtered Ax values, checking
+// that the other axes follow and mag passes through.
+func runFilter(t *testing.T, f Filter, in []int16) []int16 {
+	t.Helper()
+	out := make([]int16, len(in))
+	for i, v := range in {
+		s := f.Apply(filterSample(v))
+		if s.Ay != s.Ax || s.Az != s.Ax || s.Gx != s.Ax || s.Gy != s.Ax || s.Gz != s.Ax {
+			t.Errorf("sample %d: axes filtered differently: %+v", i, s)
+		}
+		if s.Mx != 7 || s.My != 8 || s.Mz != 9 || !s.MagValid || s.Source != "left" {
+			t.Errorf("sample %d: mag or source changed: %+v", i, s)
+		}
+		out[i] = s.Ax
+	}
+	return out
+}
+
+func TestFilterResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		f       Filter
+		in, out []int16
+	}{
+		{"moving average step", MovingAverage(4), []int16{0, 0, 100, 100, 100, 100}, []int16{0, 0, 33, 50, 75, 100}},
+		{"moving average spike", MovingAverage(3), []int16{10, 10, 400, 10, 10, 10}, []int16{10, 10, 140, 140, 140, 10}},
+		{"median spike", Median(3), []int16{10, 10, 500, 10, 10}, []int16{10, 10, 10, 10, 10}},
+		{"median step", Median(3), []int16{0, 0, 100, 100, 100}, []int16{0, 0, 0, 100, 100}},
+		{"median even window", Median(4), []int16{0, 100, 100, 100}, []int16{0, 50, 100, 100}},
+		{"window 1 passes through", MovingAverage(0), []int16{3, -8, 5}, []int16{3, -8, 5}},
+		// (32767 - 32768) / 2 rounds half away from zero
+		{"no overflow at full scale", MovingAverage(2), []int16{32767, 32767, -32768}, []int16{32767, 32767, -1}},
+	}
+	for _, tt := range tests {
+		if got := runFilter(t, tt.f, tt.in); !slices.Equal(got, tt.out) {
+			t.Errorf("%s: %v -> %v, want %v", tt.name, tt.in, got, tt.out)
+		}
+	}
+}
+
+func TestFilterReset(t *testing.T) {
+	for _, f := range []Filter{MovingAverage(4), Median(3)} {
+		runFilter(t, f, []int16{100, 100, 100, 100})
+		f.Reset()
+		// After Reset the old samples no longer pull the output towards 100
+		if got := runFilter(t, f, []int16{0, 0}); !slices.Equal(got, []int16{0, 0}) {
+			t.Errorf("%T after Reset: %v, want [0 0]", f, got)
+		}
+	}
+}
+
+func TestNewFilter(t *testing.T) {
+	for _, kind := range []string{"", FilterNone} {
+		if f, err := NewFilter(kind, 5); f != nil || err != nil {
+			t.Errorf("NewFilter(%q) = %v, %v, want nil, nil", kind, f, err)
+		}
+	}
+	if f, err := NewFilter(FilterMedian, 3); err != nil || f == nil {
+		t.Errorf("NewFilter(median) = %v, %v", f, err)
+	}
+	if _, err := NewFilter("kalman", 3); err == nil {
+		t.Error("NewFilter(kalman): expected error")
+	}
+}
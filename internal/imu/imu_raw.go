@@ -5,9 +5,13 @@
 
 package imu
 
+import "time"
+
 // IMURaw represents a single raw IMU+mag sample.
 type IMURaw struct {
-	Source string `json:"source"` // "left" or "right"
+	Source    string    `json:"source"`             // "left" or "right"
+	Timestamp time.Time `json:"timestamp,omitzero"` // when the sample was read (producer clock)
+	Seq       uint64    `json:"seq,omitempty"`      // per-topic message number (MQTT_SEQUENCE_NUMBERS)
 
 	Ax int16 `json:"ax"` // accel
 	Ay int16 `json:"ay"`
@@ -20,6 +24,12 @@ type IMURaw struct {
 	Mx int16 `json:"mx"` // magnetometer
 	My int16 `json:"my"`
 	Mz int16 `json:"mz"`
+
+	MagValid    bool `json:"mag_valid"`              // false if no mag, read error or overflow
+	MagOverflow bool `json:"mag_overflow,omitempty"` // AK8963 ST2 HOFL set for this sample
+
+	Temp      int16 `json:"temp"`                 // die temperature, TEMP_OUT counts (see DieTempC)
+	TempValid bool  `json:"temp_valid,omitempty"` // false if not read
 }
 
 type IMURawSource interface {
@@ -0,0 +1,37 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package imu
+
+// SeqTracker detects lost messages of one stream from the Seq numbers its
+// producer assigns (1, 2, 3, ... per topic, see MQTT_SEQUENCE_NUMBERS).
+// The zero value is ready to use; it is not safe for concurrent use.
+type SeqTracker struct {
+	Received uint64 `json:"received"` // messages with a sequence number
+	Dropped  uint64 `json:"dropped"`  // sequence numbers skipped
+	Restarts uint64 `json:"restarts"` // sequence went backwards (producer restart or reordering)
+
+	last uint64
+}
+
+// Observe records a received sequence number and returns how many messages
+// were lost right before it. Seq 0 (sequence numbers disabled) is ignored.
+func (t *SeqTracker) Observe(seq uint64) (missed uint64) {
+	if seq == 0 {
+		return 0
+	}
+	t.Received++
+
+	switch {
+	case t.last == 0:
+		// first message
+	case seq > t.last:
+		missed = seq - t.last - 1
+		t.Dropped += missed
+	default:
+		t.Restarts++
+	}
+	t.last = seq
+	return missed
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package imu
+
+import "testing"
+
+func TestSeqTrackerObserve(t *testing.T) {
+	tests := []struct {
+		name                        string
+		seqs                        []uint64
+		received, dropped, restarts uint64
+	}{
+		{"in order", []uint64{1, 2, 3}, 3, 0, 0},
+		{"gap", []uint64{1, 2, 5}, 3, 2, 0},
+		{"first message late", []uint64{7, 8}, 2, 0, 0},
+		{"producer restart", []uint64{1, 2, 3, 1, 2}, 5, 0, 1},
+		{"repeat", []uint64{4, 4}, 2, 0, 1},
+		{"gap after restart", []uint64{5, 6, 1, 3}, 4, 1, 1},
+		{"seq 0 ignored", []uint64{0, 1, 0, 2, 0}, 2, 0, 0},
+	}
+	for _, tt := range tests {
+		var s SeqTracker
+		for _, seq := range tt.seqs {
+			s.Observe(seq)
+		}
+		if s.Received != tt.received || s.Dropped != tt.dropped || s.Restarts != tt.restarts {
+			t.Errorf("%s: %v: received/dropped/restarts = %d/%d/%d, want %d/%d/%d", tt.name, tt.seqs,
+				s.Received, s.Dropped, s.Restarts, tt.received, tt.dropped, tt.restarts)
+		}
+	}
+}
+
+func TestSeqTrackerObserveMissed(t *testing.T) {
+	var s SeqTracker
+	for _, step := range []struct{ seq, missed uint64 }{{1, 0}, {2, 0}, {5, 2}, {0, 0}, {6, 0}, {2, 0}, {4, 1}} {
+		if got := s.Observe(step.seq); got != step.missed {
+			t.Errorf("Observe(%d) = %d, want %d", step.seq, got, step.missed)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package imu
+
+import "math"
+
+// Magnetometer unit convention: every mag value carried as int16 (IMURaw
+// Mx/My/Mz, the HMC5983 payload, the mag MQTT topics) is µT×10, called "mag
+// counts" below. Anything derived from them (norms, calibration offsets and
+// ranges) is in µT. Convert with MagUT and MagCounts.
+const MagCountsPerUT = 10.0
+
+// MPU9250 sensitivities indexed by full-scale code (IMU_ACCEL_RANGE /
+// IMU_GYRO_RANGE 0..3).
+var (
+	accelLSBPerG  = [4]float64{16384, 8192, 4096, 2048}
+	gyroLSBPerDPS = [4]float64{131, 65.5, 32.8, 16.4}
+)
+
+// AccelLSBPerG returns the accel sensitivity (counts per g) for an
+// ACCEL_FS_SEL code (0=±2, 1=±4, 2=±8, 3=±16 g). Out-of-range codes are
+// clamped to ±16 g.
+func AccelLSBPerG(fsSel byte) float64 {
+	if fsSel > 3 {
+		fsSel = 3
+	}
+	return accelLSBPerG[fsSel]
+}
+
+// GyroLSBPerDPS returns the gyro sensitivity (counts per °/s) for a
+// GYRO_FS_SEL code (0=±250, 1=±500, 2=±1000, 3=±2000 °/s). Out-of-range codes
+// are clamped to ±2000 °/s.
+func GyroLSBPerDPS(fsSel byte) float64 {
+	if fsSel > 3 {
+		fsSel = 3
+	}
+	return gyroLSBPerDPS[fsSel]
+}
+
+// AccelG converts a raw accel reading to g.
+func AccelG(counts int16, fsSel byte) float64 {
+	return float64(counts) / AccelLSBPerG(fsSel)
+}
+
+// GyroDPS converts a raw gyro reading to degrees/second.
+func GyroDPS(counts int16, fsSel byte) float64 {
+	return float64(counts) / GyroLSBPerDPS(fsSel)
+}
+
+// MPU9250 die temperature: TEMP_degC = TEMP_OUT / 333.87 + 21.
+const (
+	tempLSBPerDegC = 333.87
+	tempOffsetC    = 21.0
+)
+
+// DieTempC converts a raw TEMP_OUT reading to °C.
+func DieTempC(counts int16) float64 {
+	return float64(counts)/tempLSBPerDegC + tempOffsetC
+}
+
+// MagUT converts a stored magnetometer value (µT×10) to µT.
+func MagUT(counts int16) float64 {
+	return float64(counts) / MagCountsPerUT
+}
+
+// MagCounts converts µT to the stored µT×10 representation, saturating at
+// the int16 range.
+func MagCounts(ut float64) int16 {
+	v := math.Round(ut * MagCountsPerUT)
+	if v > math.MaxInt16 {
+		return math.MaxInt16
+	}
+	if v < math.MinInt16 {
+		return math.MinInt16
+	}
+	return int16(v)
+}
+
+// MagNormUT returns the field magnitude in µT of a stored mag sample.
+func MagNormUT(mx, my, mz int16) float64 {
+	x, y, z := MagUT(mx), MagUT(my), MagUT(mz)
+	return math.Sqrt(x*x + y*y + z*z)
+}
+
+// MagCalibration is a hard-iron offset plus diagonal soft-iron scale, in µT:
+//
+//	corrected (µT) = (MagUT(raw) - OffsetUT) * Scale
+type MagCalibration struct {
+	OffsetUT [3]float64 `json:"offset_ut"` // hard-iron offset (µT)
+	Scale    [3]float64 `json:"scale"`     // per-axis soft-iron scale (dimensionless, 0 = 1)
+}
+
+// MagCalibrationFromRange computes the min/max calibration from per-axis
+// extremes (µT) seen while rotating the sensor through all orientations:
+// the offset centers each axis and the scale equalizes each axis' range to
+// their mean. Axes without range get scale 1.
+func MagCalibrationFromRange(minUT, maxUT [3]float64) MagCalibration {
+	var c MagCalibration
+	var ranges [3]float64
+	var sum float64
+	for i := 0; i < 3; i++ {
+		c.OffsetUT[i] = (maxUT[i] + minUT[i]) / 2
+		ranges[i] = maxUT[i] - minUT[i]
+		sum += ranges[i]
+	}
+	avg := sum / 3
+	for i := 0; i < 3; i++ {
+		c.Scale[i] = 1
+		if ranges[i] > 0 {
+			c.Scale[i] = avg / ranges[i]
+		}
+	}
+	return c
+}
+
+// Apply returns the calibrated field (µT) for a stored mag sample.
+func (c MagCalibration) Apply(mx, my, mz int16) [3]float64 {
+	raw := [3]float64{MagUT(mx), MagUT(my), MagUT(mz)}
+	var out [3]float64
+	for i := 0; i < 3; i++ {
+		scale := c.Scale[i]
+		if scale == 0 {
+			scale = 1
+		}
+		out[i] = (raw[i] - c.OffsetUT[i]) * scale
+	}
+	return out
+}
+
+// IMUScaled is an IMURaw sample in engineering units.
+type IMUScaled struct {
+	Source string `json:"source"`
+
+	Ax float64 `json:"ax_g"` // accel (g)
+	Ay float64 `json:"ay_g"`
+	Az float64 `json:"az_g"`
+
+	Gx float64 `json:"gx_dps"` // gyro (°/s)
+	Gy float64 `json:"gy_dps"`
+	Gz float64 `json:"gz_dps"`
+
+	Mx float64 `json:"mx_ut"` // magnetometer (µT)
+	My float64 `json:"my_ut"`
+	Mz float64 `json:"mz_ut"`
+
+	MagValid bool `json:"mag_valid"`
+}
+
+// Scale converts a raw sample to engineering units for the given accel and
+// gyro full-scale codes.
+func Scale(raw IMURaw, accelFs, gyroFs byte) IMUScaled {
+	return IMUScaled{
+		Source:   raw.Source,
+		Ax:       AccelG(raw.Ax, accelFs),
+		Ay:       AccelG(raw.Ay, accelFs),
+		Az:       AccelG(raw.Az, accelFs),
+		Gx:       GyroDPS(raw.Gx, gyroFs),
+		Gy:       GyroDPS(raw.Gy, gyroFs),
+		Gz:       GyroDPS(raw.Gz, gyroFs),
+		Mx:       MagUT(raw.Mx),
+		My:       MagUT(raw.My),
+		Mz:       MagUT(raw.Mz),
+		MagValid: raw.MagValid,
+	}
+}
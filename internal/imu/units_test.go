@@ -0,0 +1,111 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package imu
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMagCounts(t *testing.T) {
+	tests := []struct {
+		ut   float64
+		want int16
+	}{
+		{0, 0},
+		{48.3, 483},
+		{-12.04, -120},
+		{5000, math.MaxInt16},
+		{-5000, math.MinInt16},
+	}
+	for _, tt := range tests {
+		if got := MagCounts(tt.ut); got != tt.want {
+			t.Errorf("MagCounts(%g) = %d, want %d", tt.ut, got, tt.want)
+		}
+	}
+	if got := MagUT(483); got != 48.3 {
+		t.Errorf("MagUT(483) = %g, want 48.3", got)
+	}
+}
+
+// TestMagCalibrationRoundTrip rotates a 50 µT field through all
+// orientations, distorts it with a hard-iron offset and per-axis soft-iron
+// gain, stores it as the producer does (µT×10 int16 fields) and checks that
+// the calibration computed from the observed extremes gives the field back
+// centred and with equal axis ranges.
+func TestMagCalibrationRoundTrip(t *testing.T) {
+	const fieldUT = 50.0
+	offset := [3]float64{30, -20, 10}
+	gain := [3]float64{1.2, 0.8, 1.0} // mean 1, so the corrected field is the true one
+
+	type sample struct {
+		field [3]float64
+		raw   [3]int16
+	}
+	var samples []sample
+	minUT := [3]float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+	maxUT := [3]float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for lat := -90; lat <= 90; lat += 15 {
+		for lon := 0; lon < 360; lon += 15 {
+			phi, lambda := float64(lat)*math.Pi/180, float64(lon)*math.Pi/180
+			s := sample{field: [3]float64{
+				fieldUT * math.Cos(phi) * math.Cos(lambda),
+				fieldUT * math.Cos(phi) * math.Sin(lambda),
+				fieldUT * math.Sin(phi),
+			}}
+			for i := range 3 {
+				s.raw[i] = MagCounts(s.field[i]*gain[i] + offset[i])
+				minUT[i] = math.Min(minUT[i], MagUT(s.raw[i]))
+				maxUT[i] = math.Max(maxUT[i], MagUT(s.raw[i]))
+			}
+			samples = append(samples, s)
+		}
+	}
+
+	c := MagCalibrationFromRange(minUT, maxUT)
+	for i := range 3 {
+		if math.Abs(c.OffsetUT[i]-offset[i]) > 0.1 {
+			t.Errorf("OffsetUT[%d] = %g µT, want %g", i, c.OffsetUT[i], offset[i])
+		}
+		if math.Abs(c.Scale[i]-1/gain[i]) > 0.01 {
+			t.Errorf("Scale[%d] = %g, want %g", i, c.Scale[i], 1/gain[i])
+		}
+	}
+
+	// Quantisation to 0.1 µT, scaled by at most 1/0.8
+	const tol = 0.1
+	var sum [3]float64
+	for _, s := range samples {
+		got := c.Apply(s.raw[0], s.raw[1], s.raw[2])
+		for i := range 3 {
+			if math.Abs(got[i]-s.field[i]) > tol {
+				t.Fatalf("Apply(%v) = %v µT, want %v", s.raw, got, s.field)
+			}
+			sum[i] += got[i]
+		}
+		if n := math.Sqrt(got[0]*got[0] + got[1]*got[1] + got[2]*got[2]); math.Abs(n-fieldUT) > tol {
+			t.Fatalf("|Apply(%v)| = %g µT, want %g", s.raw, n, fieldUT)
+		}
+	}
+	for i := range 3 {
+		if mean := sum[i] / float64(len(samples)); math.Abs(mean) > tol {
+			t.Errorf("axis %d: mean corrected field %g µT, want centred", i, mean)
+		}
+	}
+}
+
+func TestMagCalibrationDegenerate(t *testing.T) {
+	// An axis without range keeps scale 1
+	c := MagCalibrationFromRange([3]float64{-10, -20, 5}, [3]float64{30, 20, 5})
+	if c.Scale[2] != 1 || c.OffsetUT[2] != 5 {
+		t.Errorf("flat axis: offset %g, scale %g, want 5, 1", c.OffsetUT[2], c.Scale[2])
+	}
+
+	// A zero scale (e.g. omitted in a calibration file) applies as 1
+	got := MagCalibration{OffsetUT: [3]float64{1, 2, 3}}.Apply(100, 200, 300)
+	if want := [3]float64{9, 18, 27}; got != want {
+		t.Errorf("zero scale: Apply = %v, want %v", got, want)
+	}
+}
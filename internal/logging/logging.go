@@ -0,0 +1,116 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+// Package logging provides leveled, printf-style logging on top of the
+// standard log package and log/slog.
+//
+// In the default text mode messages keep the standard log format, with the
+// level after the timestamp for everything but INFO:
+//
+//	2026/01/07 10:15:02 WARN web: pose left unmarshal error: ...
+//
+// In JSON mode every message, including plain log.Printf calls, is written as
+// one slog JSON object per line on stderr.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Levels, ordered by severity.
+const (
+	LevelDebug = slog.LevelDebug
+	LevelInfo  = slog.LevelInfo
+	LevelWarn  = slog.LevelWarn
+	LevelError = slog.LevelError
+)
+
+var (
+	level      = new(slog.LevelVar) // INFO until Setup
+	jsonLogger *slog.Logger         // nil in text mode
+)
+
+// ParseLevel parses a LOG_LEVEL value: debug, info, warn or error.
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return 0, fmt.Errorf("must be debug, info, warn or error")
+}
+
+// Setup sets the minimum level ("" = info) and the output format, "text"
+// ("" too) or "json". In JSON mode the standard logger is redirected too,
+// so its messages become INFO records.
+func Setup(levelName, format string) error {
+	l := LevelInfo
+	if levelName != "" {
+		var err error
+		if l, err = ParseLevel(levelName); err != nil {
+			return fmt.Errorf("invalid log level %q: %w", levelName, err)
+		}
+	}
+	level.Set(l)
+
+	switch format {
+	case "", "text":
+		jsonLogger = nil
+	case "json":
+		jsonLogger = slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+		slog.SetDefault(jsonLogger)
+	default:
+		return fmt.Errorf("invalid log format %q: must be text or json", format)
+	}
+	return nil
+}
+
+// Enabled reports whether messages at l are written, for skipping work that
+// only feeds a log message.
+func Enabled(l slog.Level) bool {
+	return l >= level.Level()
+}
+
+// Debugf logs at DEBUG level.
+func Debugf(format string, args ...any) { logf(LevelDebug, format, args...) }
+
+// Infof logs at INFO level.
+func Infof(format string, args ...any) { logf(LevelInfo, format, args...) }
+
+// Warnf logs at WARN level.
+func Warnf(format string, args ...any) { logf(LevelWarn, format, args...) }
+
+// Errorf logs at ERROR level.
+func Errorf(format string, args ...any) { logf(LevelError, format, args...) }
+
+// Fatalf logs at ERROR level and exits with status 1.
+func Fatalf(format string, args ...any) {
+	logf(LevelError, format, args...)
+	os.Exit(1)
+}
+
+func logf(l slog.Level, format string, args ...any) {
+	if !Enabled(l) {
+		return
+	}
+	msg := fmt.Sprintf(format, args...)
+	if jsonLogger != nil {
+		jsonLogger.Log(context.Background(), l, msg)
+		return
+	}
+	if l != LevelInfo {
+		msg = l.String() + " " + msg
+	}
+	_ = log.Output(3, msg)
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+// Package magmodel evaluates the World Magnetic Model (WMM) from an embedded
+// coefficient file, for the magnetic declination at a GPS position without
+// network access.
+//
+// The embedded WMM.COF is WMM2025 (valid 2025.0 to 2030.0) in the standard
+// NOAA/BGS coefficient file format (degree 12 spherical harmonics plus
+// linear secular variation) and can be replaced by a newer release of the
+// same format. Within its 5-year validity the WMM
+// gives the declination of the core field to about ±0.5° away from the
+// magnetic poles; local anomalies (geology, steel structures, vehicle
+// fields) are not modelled. After the validity ends the secular variation is
+// extrapolated and the error grows, typically by a few tenths of a degree per
+// year.
+package magmodel
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxDegree is the degree and order of the WMM expansion.
+const maxDegree = 12
+
+// validYears is how long a WMM release is valid after its epoch.
+const validYears = 5.0
+
+// WGS84 ellipsoid and the geomagnetic reference radius (km).
+const (
+	wgs84A      = 6378.137
+	wgs84F      = 1 / 298.257223563
+	earthRadius = 6371.2
+)
+
+//go:embed WMM.COF
+var embeddedCOF []byte
+
+// Model is a set of Gauss coefficients (nT) at an epoch, with their secular
+// variation (nT/year).
+type Model struct {
+	Name  string
+	Epoch float64 // decimal year
+
+	g, h, gDot, hDot [maxDegree + 1][maxDegree + 1]float64
+}
+
+var defaultModel = sync.OnceValues(func() (*Model, error) {
+	return ParseCOF(embeddedCOF)
+})
+
+// Default returns the embedded model.
+func Default() *Model {
+	m, err := defaultModel()
+	if err != nil {
+		// The embedded file is part of the build
+		panic(fmt.Sprintf("magmodel: embedded WMM.COF: %v", err))
+	}
+	return m
+}
+
+// Declination returns the magnetic declination in degrees (positive east:
+// true heading = magnetic heading + declination) at sea level at lat, lon
+// (decimal degrees, WGS84) on date, from the embedded model.
+func Declination(lat, lon float64, date time.Time) float64 {
+	return Default().Declination(lat, lon, date)
+}
+
+// ParseCOF parses a WMM coefficient file: a header line with the epoch and
+// model name, then "n m g h gdot hdot" lines, ended by a line of 9s.
+func ParseCOF(data []byte) (*Model, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("empty coefficient file")
+	}
+	header := strings.Fields(scanner.Text())
+	if len(header) < 2 {
+		return nil, fmt.Errorf("invalid header %q", scanner.Text())
+	}
+	epoch, err := strconv.ParseFloat(header[0], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid epoch %q: %w", header[0], err)
+	}
+	m := &Model{Name: header[1], Epoch: epoch}
+
+	terms := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "9999") {
+			break
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 6 {
+			return nil, fmt.Errorf("invalid coefficient line %q", line)
+		}
+		n, errN := strconv.Atoi(fields[0])
+		mm, errM := strconv.Atoi(fields[1])
+		if errN != nil || errM != nil || n < 1 || n > maxDegree || mm < 0 || mm > n {
+			return nil, fmt.Errorf("invalid degree/order in %q", line)
+		}
+		var v [4]float64
+		for i := range v {
+			if v[i], err = strconv.ParseFloat(fields[2+i], 64); err != nil {
+				return nil, fmt.Errorf("invalid coefficient in %q: %w", line, err)
+			}
+		}
+		m.g[n][mm], m.h[n][mm], m.gDot[n][mm], m.hDot[n][mm] = v[0], v[1], v[2], v[3]
+		terms++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if want := (maxDegree+1)*(maxDegree+2)/2 - 1; terms != want {
+		return nil, fmt.Errorf("got %d coefficient lines, want %d", terms, want)
+	}
+	return m, nil
+}
+
+// ValidAt reports whether date lies within the model's validity period
+// (epoch to epoch + 5 years).
+func (m *Model) ValidAt(date time.Time) bool {
+	t := decimalYear(date)
+	return t >= m.Epoch && t < m.Epoch+validYears
+}
+
+// Declination returns the declination in degrees at sea level, see the
+// package-level Declination.
+func (m *Model) Declination(lat, lon float64, date time.Time) float64 {
+	x, y, _ := m.Field(lat, lon, 0, date)
+	return math.Atan2(y, x) * 180 / math.Pi
+}
+
+// Field returns the north, east and down components (nT) of the main field
+// at geodetic lat, lon (degrees) and height above the ellipsoid (km).
+func (m *Model) Field(lat, lon, heightKm float64, date time.Time) (x, y, z float64) {
+	const deg = math.Pi / 180
+	dt := decimalYear(date) - m.Epoch
+
+	// Geodetic to geocentric spherical coordinates
+	e2 := wgs84F * (2 - wgs84F)
+	sinLat, cosLat := math.Sincos(lat * deg)
+	rc := wgs84A / math.Sqrt(1-e2*sinLat*sinLat)
+	p := (rc + heightKm) * cosLat
+	zc := (rc*(1-e2) + heightKm) * sinLat
+	r := math.Hypot(p, zc)
+	latC := math.Asin(zc / r)
+
+	// Schmidt semi-normalized associated Legendre functions of the
+	// colatitude and their derivatives
+	sinT, cosT := math.Cos(latC), math.Sin(latC) // sin/cos of the colatitude
+	var pnm, dpnm [maxDegree + 1][maxDegree + 1]float64
+	pnm[0][0] = 1
+	for n := 1; n <= maxDegree; n++ {
+		for mm := 0; mm <= n; mm++ {
+			switch {
+			case n == mm:
+				pnm[n][mm] = sinT * pnm[n-1][mm-1]
+				dpnm[n][mm] = sinT*dpnm[n-1][mm-1] + cosT*pnm[n-1][mm-1]
+			case n == 1:
+				pnm[n][mm] = cosT * pnm[n-1][mm]
+				dpnm[n][mm] = cosT*dpnm[n-1][mm] - sinT*pnm[n-1][mm]
+			default:
+				k := float64((n-1)*(n-1)-mm*mm) / float64((2*n-1)*(2*n-3))
+				pnm[n][mm] = cosT*pnm[n-1][mm] - k*pnm[n-2][mm]
+				dpnm[n][mm] = cosT*dpnm[n-1][mm] - sinT*pnm[n-1][mm] - k*dpnm[n-2][mm]
+			}
+		}
+	}
+	var schmidt [maxDegree + 1][maxDegree + 1]float64
+	schmidt[0][0] = 1
+	for n := 1; n <= maxDegree; n++ {
+		schmidt[n][0] = schmidt[n-1][0] * float64(2*n-1) / float64(n)
+		for mm := 1; mm <= n; mm++ {
+			f := float64(n-mm+1) / float64(n+mm)
+			if mm == 1 {
+				f *= 2
+			}
+			schmidt[n][mm] = schmidt[n][mm-1] * math.Sqrt(f)
+		}
+	}
+
+	// Field in the geocentric frame: θ (south), φ (east), r (up) sums
+	var bt, bp, br float64
+	ratio := earthRadius / r
+	ar := ratio * ratio
+	for n := 1; n <= maxDegree; n++ {
+		ar *= ratio // (a/r)^(n+2)
+		for mm := 0; mm <= n; mm++ {
+			g := m.g[n][mm] + dt*m.gDot[n][mm]
+			h := m.h[n][mm] + dt*m.hDot[n][mm]
+			sinM, cosM := math.Sincos(float64(mm) * lon * deg)
+			pn := schmidt[n][mm] * pnm[n][mm]
+			dpn := schmidt[n][mm] * dpnm[n][mm]
+
+			bt -= ar * (g*cosM + h*sinM) * dpn
+			bp += ar * float64(mm) * (g*sinM - h*cosM) * pn
+			br += ar * float64(n+1) * (g*cosM + h*sinM) * pn
+		}
+	}
+	if sinT > 1e-9 {
+		bp /= sinT
+	}
+
+	// Rotate north/down from geocentric to geodetic
+	xc, zcField := -bt, -br
+	psi := latC - lat*deg
+	sinPsi, cosPsi := math.Sincos(psi)
+	return xc*cosPsi - zcField*sinPsi, bp, xc*sinPsi + zcField*cosPsi
+}
+
+// decimalYear returns t as a fractional year, e.g. 2025.5 for early July.
+func decimalYear(t time.Time) float64 {
+	t = t.UTC()
+	start := time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+	return float64(t.Year()) + t.Sub(start).Seconds()/end.Sub(start).Seconds()
+}
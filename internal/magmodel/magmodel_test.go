@@ -0,0 +1,79 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package magmodel
+
+import (
+	"math"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestFieldWMM2020 checks the field evaluation against the official test
+// values published with WMM2020 (testdata/WMM2020.COF is that release).
+func TestFieldWMM2020(t *testing.T) {
+	data, err := os.ReadFile("testdata/WMM2020.COF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := ParseCOF(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	mid := time.Date(2022, 7, 2, 12, 0, 0, 0, time.UTC) // 2022.5
+	tests := []struct {
+		date       time.Time
+		heightKm   float64
+		lat, lon   float64
+		x, z       float64 // nT
+		decl, incl float64 // degrees
+	}{
+		{epoch, 0, 80, 0, 6570.4, 54606.0, -1.28, 83.14},
+		{epoch, 0, 0, 120, 39624.3, -10932.5, 0.16, -15.42},
+		{epoch, 0, -80, 240, 5940.6, -52480.8, 69.36, -72.20},
+		{epoch, 100, 80, 0, 6261.8, 52429.1, -1.70, 83.19},
+		{epoch, 100, 0, 120, 37636.7, -10474.8, 0.16, -15.55},
+		{epoch, 100, -80, 240, 5744.9, -49969.4, 68.78, -72.37},
+		{mid, 0, 80, 0, 6529.9, 54713.4, 0.01, 83.19},
+		{mid, 0, 0, 120, 39684.7, -10809.5, -0.06, -15.24},
+		{mid, 0, -80, 240, 6016.5, -52251.6, 69.13, -72.09},
+		{mid, 100, 80, 0, 6224.0, 52527.0, -0.41, 83.24},
+		{mid, 100, 0, 120, 37694.0, -10362.0, -0.05, -15.37},
+		{mid, 100, -80, 240, 5815.0, -49755.3, 68.55, -72.27},
+	}
+	for _, tt := range tests {
+		x, y, z := m.Field(tt.lat, tt.lon, tt.heightKm, tt.date)
+		decl := math.Atan2(y, x) * 180 / math.Pi
+		incl := math.Atan2(z, math.Hypot(x, y)) * 180 / math.Pi
+		if math.Abs(x-tt.x) > 1 || math.Abs(z-tt.z) > 1 ||
+			math.Abs(decl-tt.decl) > 0.015 || math.Abs(incl-tt.incl) > 0.015 {
+			t.Errorf("%.1f %gkm (%g, %g): X=%.1f Z=%.1f D=%.2f I=%.2f, want X=%.1f Z=%.1f D=%.2f I=%.2f",
+				decimalYear(tt.date), tt.heightKm, tt.lat, tt.lon, x, z, decl, incl, tt.x, tt.z, tt.decl, tt.incl)
+		}
+	}
+}
+
+// TestDefaultValidity checks that the embedded model is the current release.
+func TestDefaultValidity(t *testing.T) {
+	m := Default()
+	if m.Epoch != 2025 {
+		t.Fatalf("embedded model %s has epoch %g, want 2025", m.Name, m.Epoch)
+	}
+	for _, tt := range []struct {
+		date time.Time
+		want bool
+	}{
+		{time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), false},
+		{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2029, 12, 31, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), false},
+	} {
+		if got := m.ValidAt(tt.date); got != tt.want {
+			t.Errorf("ValidAt(%s) = %v, want %v", tt.date.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package orientation
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultDeadbandKeepalive is the keepalive of a PoseDeadband without one.
+const DefaultDeadbandKeepalive = 2 * time.Second
+
+// PoseDeadband decides whether a pose is worth publishing
+// (POSE_PUBLISH_DEADBAND): only when roll, pitch or yaw moved more than Deg
+// from the last published pose, or Keepalive has elapsed since it, so a
+// still device stops flooding the broker while motion still shows at once.
+// Deg 0 publishes every pose. It is not safe for concurrent use.
+type PoseDeadband struct {
+	Deg       float64
+	Keepalive time.Duration
+
+	last     Pose
+	lastTime time.Time
+	have     bool
+}
+
+// Publish reports whether p, at time t, should be published, and if so
+// records it as the last published pose.
+func (d *PoseDeadband) Publish(p Pose, t time.Time) bool {
+	keepalive := d.Keepalive
+	if keepalive <= 0 {
+		keepalive = DefaultDeadbandKeepalive
+	}
+	if d.Deg > 0 && d.have && t.Sub(d.lastTime) < keepalive &&
+		math.Abs(normalizeAngle(p.Roll-d.last.Roll)) <= d.Deg &&
+		math.Abs(normalizeAngle(p.Pitch-d.last.Pitch)) <= d.Deg &&
+		math.Abs(normalizeAngle(p.Yaw-d.last.Yaw)) <= d.Deg {
+		return false
+	}
+	d.last, d.lastTime, d.have = p, t, true
+	return true
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package orientation
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPoseDeadband(t *testing.T) {
+	d := PoseDeadband{Deg: 0.5, Keepalive: 2 * time.Second}
+	t0 := time.Unix(0, 0)
+	ms := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Millisecond) }
+
+	steps := []struct {
+		pose Pose
+		at   time.Time
+		want bool
+	}{
+		{Pose{Roll: 1, Yaw: 179.9}, ms(0), true},       // first pose
+		{Pose{Roll: 1.3, Yaw: 179.9}, ms(100), false},  // inside the deadband
+		{Pose{Roll: 1.3, Yaw: -179.8}, ms(200), false}, // 0.3° across the wrap
+		{Pose{Roll: 1.6, Yaw: 179.9}, ms(300), true},   // 0.6° from the last published
+		{Pose{Roll: 1.6, Yaw: 179.9}, ms(2200), false}, // keepalive not yet due
+		{Pose{Roll: 1.6, Yaw: 179.9}, ms(2300), true},  // keepalive
+	}
+	for i, s := range steps {
+		if got := d.Publish(s.pose, s.at); got != s.want {
+			t.Errorf("step %d: Publish = %v, want %v", i, got, s.want)
+		}
+	}
+
+	var off PoseDeadband
+	if !off.Publish(Pose{}, t0) || !off.Publish(Pose{}, t0) {
+		t.Error("zero deadband must publish every pose")
+	}
+}
@@ -0,0 +1,370 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package orientation
+
+import (
+	"math"
+
+	imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
+)
+
+// Default EKF noise parameters (EKF_* config keys).
+const (
+	// DefaultEKFGyroNoise is the gyro rate noise density in °/s/√Hz; the
+	// MPU9250 datasheet gives 0.01, the default leaves room for vibration.
+	DefaultEKFGyroNoise = 0.05
+	// DefaultEKFGyroBiasNoise is the gyro bias random walk in °/s/√s.
+	DefaultEKFGyroBiasNoise = 0.002
+	// DefaultEKFAccelNoise is the accel direction noise (1σ, g), including
+	// small linear accelerations.
+	DefaultEKFAccelNoise = 0.05
+	// DefaultEKFMagNoise is the magnetometer heading noise (1σ, degrees).
+	DefaultEKFMagNoise = 5.0
+)
+
+const (
+	// Accel samples whose magnitude is further than this from 1 g are
+	// dominated by linear acceleration and skip the gravity update; closer
+	// ones have the deviation added to the accel noise.
+	ekfAccelGateG = 0.1
+	// Mag samples whose horizontal component is below this fraction of the
+	// field give no usable heading (sensor axis near the field line).
+	ekfMagMinHorizontal = 0.1
+	// Initial 1σ uncertainties: tilt from the first accel sample, heading from
+	// the first mag sample (or unknown), gyro bias of an uncalibrated MPU9250.
+	ekfInitTiltDeg    = 5.0
+	ekfInitHeadingDeg = 20.0
+	ekfInitBiasDPS    = 2.0
+	// One-sigma attitude error at which Pose.Confidence is 0.5.
+	ekfConfidenceDeg = 5.0
+)
+
+// EKFConfig configures an AttitudeEKF. Zero noise values select the defaults.
+type EKFConfig struct {
+	AccelRange byte // ACCEL_FS_SEL code of the samples (IMU_ACCEL_RANGE)
+	GyroRange  byte // GYRO_FS_SEL code of the samples (IMU_GYRO_RANGE)
+
+	GyroNoise     float64 // °/s/√Hz, see DefaultEKFGyroNoise
+	GyroBiasNoise float64 // °/s/√s, see DefaultEKFGyroBiasNoise
+	AccelNoise    float64 // g, see DefaultEKFAccelNoise
+	MagNoise      float64 // degrees, see DefaultEKFMagNoise
+}
+
+// AttitudeEKF is an error-state extended Kalman filter estimating attitude
+// (a quaternion, sensor to earth frame) and the gyro bias from accel, gyro
+// and magnetometer samples.
+//
+// The nominal state is propagated with the bias-corrected gyro rates; the
+// 6-element error state (attitude error in the sensor frame, bias error)
+// carries the covariance. Accel corrects tilt towards gravity, the mag
+// corrects heading only (its horizontal component, so the field inclination
+// and local disturbances never tilt roll/pitch), and both errors feed back
+// into the bias estimate through the covariance. Yaw 0 is magnetic north;
+// without valid mag samples yaw is gyro-integrated and its uncertainty grows.
+//
+// Unlike Filter it takes raw samples, as it needs the magnetometer and the
+// accel magnitude. It is not safe for concurrent use.
+type AttitudeEKF struct {
+	cfg EKFConfig
+
+	q    quaternion
+	bias [3]float64    // rad/s
+	p    [6][6]float64 // error covariance: attitude (rad), bias (rad/s)
+
+	initialized bool
+}
+
+// NewAttitudeEKF creates an EKF; the state is initialized from the first
+// sample passed to Update.
+func NewAttitudeEKF(cfg EKFConfig) *AttitudeEKF {
+	if cfg.GyroNoise <= 0 {
+		cfg.GyroNoise = DefaultEKFGyroNoise
+	}
+	if cfg.GyroBiasNoise <= 0 {
+		cfg.GyroBiasNoise = DefaultEKFGyroBiasNoise
+	}
+	if cfg.AccelNoise <= 0 {
+		cfg.AccelNoise = DefaultEKFAccelNoise
+	}
+	if cfg.MagNoise <= 0 {
+		cfg.MagNoise = DefaultEKFMagNoise
+	}
+	return &AttitudeEKF{cfg: cfg, q: quaternion{w: 1}}
+}
+
+// Update advances the filter by dt seconds with sample s and returns the
+// pose, with Confidence derived from the attitude covariance.
+func (f *AttitudeEKF) Update(s imu_raw.IMURaw, dt float64) Pose {
+	const deg = math.Pi / 180.0
+	accel := [3]float64{
+		imu_raw.AccelG(s.Ax, f.cfg.AccelRange),
+		imu_raw.AccelG(s.Ay, f.cfg.AccelRange),
+		imu_raw.AccelG(s.Az, f.cfg.AccelRange),
+	}
+	mag, magOK := ekfMag(s)
+
+	if !f.initialized {
+		f.initialize(accel, mag, magOK)
+	} else if dt > 0 {
+		gyro := [3]float64{
+			imu_raw.GyroDPS(s.Gx, f.cfg.GyroRange) * deg,
+			imu_raw.GyroDPS(s.Gy, f.cfg.GyroRange) * deg,
+			imu_raw.GyroDPS(s.Gz, f.cfg.GyroRange) * deg,
+		}
+		f.predict(gyro, dt)
+	}
+
+	var dx [6]float64
+	f.updateAccel(accel, &dx)
+	if magOK {
+		f.updateMag(mag, &dx)
+	}
+	f.inject(dx)
+
+	p := f.q.pose()
+	p.RollStd, p.PitchStd, p.YawStd = f.eulerStd(p)
+	tilt, heading := f.Sigma()
+	sigma := math.Max(tilt, heading)
+	p.Confidence = 1 / (1 + (sigma/ekfConfidenceDeg)*(sigma/ekfConfidenceDeg))
+	return p
+}
+
+// Sigma returns the one-sigma tilt (roll/pitch combined) and heading errors
+// in degrees.
+func (f *AttitudeEKF) Sigma() (tilt, heading float64) {
+	// Attitude covariance rotated into the earth frame: R P Rᵀ
+	r := f.q.rotationMatrix()
+	var pe [3][3]float64
+	for i := range 3 {
+		for j := range 3 {
+			for k := range 3 {
+				for l := range 3 {
+					pe[i][j] += r[i][k] * f.p[k][l] * r[j][l]
+				}
+			}
+		}
+	}
+	const rad = 180.0 / math.Pi
+	return math.Sqrt(math.Max(0, pe[0][0]+pe[1][1])) * rad, math.Sqrt(math.Max(0, pe[2][2])) * rad
+}
+
+// eulerStd returns the one-sigma roll, pitch and yaw errors (degrees) of
+// pose p, mapping the attitude covariance through the Euler-rate Jacobian E
+// (dEuler = E·δθ): E P Eᵀ.
+func (f *AttitudeEKF) eulerStd(p Pose) (roll, pitch, yaw float64) {
+	const deg = math.Pi / 180.0
+	sr, cr := math.Sincos(p.Roll * deg)
+	sp, cp := math.Sincos(p.Pitch * deg)
+	if math.Abs(cp) < 1e-3 {
+		cp = math.Copysign(1e-3, cp)
+	}
+	e := [3][3]float64{
+		{1, sr * sp / cp, cr * sp / cp},
+		{0, cr, -sr},
+		{0, sr / cp, cr / cp},
+	}
+	var v [3]float64
+	for i := range 3 {
+		for k := range 3 {
+			for l := range 3 {
+				v[i] += e[i][k] * f.p[k][l] * e[i][l]
+			}
+		}
+		v[i] = math.Sqrt(math.Max(0, v[i])) / deg
+	}
+	return v[0], v[1], v[2]
+}
+
+// GyroBias returns the estimated gyro bias in °/s.
+func (f *AttitudeEKF) GyroBias() [3]float64 {
+	const rad = 180.0 / math.Pi
+	return [3]float64{f.bias[0] * rad, f.bias[1] * rad, f.bias[2] * rad}
+}
+
+// initialize sets the attitude from the accel tilt and, if available, the
+// mag heading, and the covariance to the initial uncertainties.
+func (f *AttitudeEKF) initialize(accel, mag [3]float64, magOK bool) {
+	const deg = math.Pi / 180.0
+	pose := ComputePoseFromAccel(accel[0], accel[1], accel[2])
+	headingSigma := math.Pi
+	if magOK {
+		f.q = quaternionFromPose(pose)
+		m := f.q.rotate(mag)
+		pose.Yaw = -math.Atan2(m[1], m[0]) / deg
+		headingSigma = ekfInitHeadingDeg * deg
+	}
+	f.q = quaternionFromPose(pose)
+	f.bias = [3]float64{}
+
+	// Initial covariance is diagonal in the earth frame (tilt, heading);
+	// rotate it into the sensor-frame error state: Rᵀ Pe R.
+	pe := [3]float64{ekfInitTiltDeg * deg, ekfInitTiltDeg * deg, headingSigma}
+	r := f.q.rotationMatrix()
+	f.p = [6][6]float64{}
+	for i := range 3 {
+		for j := range 3 {
+			for k := range 3 {
+				f.p[i][j] += r[k][i] * pe[k] * pe[k] * r[k][j]
+			}
+		}
+		f.p[3+i][3+i] = (ekfInitBiasDPS * deg) * (ekfInitBiasDPS * deg)
+	}
+	f.initialized = true
+}
+
+// predict propagates the state with the bias-corrected rates (rad/s) and the
+// covariance with P = F P Fᵀ + Q, where F = [[Rωᵀ, -I dt], [0, I]] and Rω is
+// the rotation of this step (the exact form keeps an unobserved heading
+// variance from leaking into tilt).
+func (f *AttitudeEKF) predict(gyro [3]float64, dt float64) {
+	w := [3]float64{gyro[0] - f.bias[0], gyro[1] - f.bias[1], gyro[2] - f.bias[2]}
+	step := quaternionFromRotationVector(w[0]*dt, w[1]*dt, w[2]*dt)
+	f.q = f.q.multiply(step).normalized()
+
+	var fm [6][6]float64
+	rw := step.rotationMatrix()
+	for i := range 3 {
+		for j := range 3 {
+			fm[i][j] = rw[j][i]
+		}
+		fm[i][3+i] = -dt
+		fm[3+i][3+i] = 1
+	}
+
+	var fp [6][6]float64
+	for i := range 6 {
+		for j := range 6 {
+			for k := range 6 {
+				fp[i][j] += fm[i][k] * f.p[k][j]
+			}
+		}
+	}
+	var p [6][6]float64
+	for i := range 6 {
+		for j := range 6 {
+			for k := range 6 {
+				p[i][j] += fp[i][k] * fm[j][k]
+			}
+		}
+	}
+
+	const deg = math.Pi / 180.0
+	gyroVar := (f.cfg.GyroNoise * deg) * (f.cfg.GyroNoise * deg) * dt
+	biasVar := (f.cfg.GyroBiasNoise * deg) * (f.cfg.GyroBiasNoise * deg) * dt
+	for i := range 3 {
+		p[i][i] += gyroVar
+		p[3+i][3+i] += biasVar
+	}
+	f.p = p
+}
+
+// updateAccel corrects tilt with the measured gravity direction. The
+// predicted measurement is v = Rᵀ·down, with Jacobian [v×] on the attitude
+// error.
+func (f *AttitudeEKF) updateAccel(accel [3]float64, dx *[6]float64) {
+	n := math.Sqrt(accel[0]*accel[0] + accel[1]*accel[1] + accel[2]*accel[2])
+	if n == 0 || math.Abs(n-1) > ekfAccelGateG {
+		return
+	}
+	r := f.q.rotationMatrix()
+	v := [3]float64{r[2][0], r[2][1], r[2][2]}
+	h := [3][6]float64{
+		{0, -v[2], v[1]},
+		{v[2], 0, -v[0]},
+		{-v[1], v[0], 0},
+	}
+	sigma := f.cfg.AccelNoise + math.Abs(n-1)
+	variance := sigma * sigma
+	for i := range 3 {
+		f.scalarUpdate(h[i], accel[i]/n-v[i], variance, dx)
+	}
+}
+
+// updateMag corrects heading with the horizontal direction of the field.
+// The innovation is the negated heading of the field rotated into the earth
+// frame, and the Jacobian the earth Z row of R.
+func (f *AttitudeEKF) updateMag(mag [3]float64, dx *[6]float64) {
+	m := f.q.rotate(mag)
+	horizontal := math.Hypot(m[0], m[1])
+	if horizontal < ekfMagMinHorizontal*math.Sqrt(m[0]*m[0]+m[1]*m[1]+m[2]*m[2]) {
+		return
+	}
+	r := f.q.rotationMatrix()
+	h := [6]float64{r[2][0], r[2][1], r[2][2]}
+	sigma := f.cfg.MagNoise * math.Pi / 180.0
+	f.scalarUpdate(h, -math.Atan2(m[1], m[0]), sigma*sigma, dx)
+}
+
+// scalarUpdate applies one scalar measurement with innovation y (relative to
+// the state before any update of this step) and noise variance r, updating
+// the error state estimate dx and the covariance (Joseph form).
+func (f *AttitudeEKF) scalarUpdate(h [6]float64, y, r float64, dx *[6]float64) {
+	var ph [6]float64 // P Hᵀ
+	for i := range 6 {
+		for j := range 6 {
+			ph[i] += f.p[i][j] * h[j]
+		}
+	}
+	s := r
+	for i := range 6 {
+		s += h[i] * ph[i]
+		y -= h[i] * dx[i]
+	}
+	if s <= 0 {
+		return
+	}
+	var k [6]float64
+	for i := range 6 {
+		k[i] = ph[i] / s
+		dx[i] += k[i] * y
+	}
+
+	// P = (I - K H) P (I - K H)ᵀ + K r Kᵀ
+	var a [6][6]float64
+	for i := range 6 {
+		for j := range 6 {
+			a[i][j] = -k[i] * h[j]
+		}
+		a[i][i]++
+	}
+	var ap [6][6]float64
+	for i := range 6 {
+		for j := range 6 {
+			for l := range 6 {
+				ap[i][j] += a[i][l] * f.p[l][j]
+			}
+		}
+	}
+	for i := range 6 {
+		for j := range 6 {
+			var v float64
+			for l := range 6 {
+				v += ap[i][l] * a[j][l]
+			}
+			f.p[i][j] = v + k[i]*r*k[j]
+		}
+	}
+}
+
+// inject folds the error state into the nominal state; the covariance is
+// kept as is (the reset Jacobian is ≈ I for small corrections).
+func (f *AttitudeEKF) inject(dx [6]float64) {
+	f.q = f.q.multiply(quaternionFromRotationVector(dx[0], dx[1], dx[2])).normalized()
+	for i := range 3 {
+		f.bias[i] += dx[3+i]
+	}
+}
+
+// ekfMag returns the mag sample of s in µT, if valid.
+func ekfMag(s imu_raw.IMURaw) ([3]float64, bool) {
+	if !s.MagValid || s.MagOverflow {
+		return [3]float64{}, false
+	}
+	m := [3]float64{imu_raw.MagUT(s.Mx), imu_raw.MagUT(s.My), imu_raw.MagUT(s.Mz)}
+	if m == [3]float64{} {
+		return m, false
+	}
+	return m, true
+}
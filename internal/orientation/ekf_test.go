@@ -0,0 +1,202 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package orientation
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
+)
+
+const (
+	testDt        = 0.02 // 50 Hz
+	testMagNorth  = 20.0 // µT
+	testMagDown   = 44.0
+	testGyroNoise = 0.05 // °/s per sample
+	testAccNoise  = 0.005
+	testMagNoise  = 0.3
+)
+
+// trajectory simulates an IMU with a constant gyro bias: the true attitude is
+// integrated from the body rates returned by rates(t), and each step
+// produces the noisy raw sample the sensor would report.
+type trajectory struct {
+	rates  func(t float64) [3]float64 // °/s
+	bias   [3]float64                 // °/s
+	linAcc func(t float64) [3]float64 // extra specific force (g, earth frame), may be nil
+	noMag  bool
+
+	rng *rand.Rand
+	q   quaternion
+	t   float64
+}
+
+func newTrajectory(start Pose, rates func(float64) [3]float64, bias [3]float64) *trajectory {
+	return &trajectory{rates: rates, bias: bias, rng: rand.New(rand.NewPCG(1, 2)), q: quaternionFromPose(start)}
+}
+
+func (tr *trajectory) next() imu_raw.IMURaw {
+	const deg = math.Pi / 180.0
+	w := tr.rates(tr.t)
+	tr.q = tr.q.multiply(quaternionFromRotationVector(w[0]*deg*testDt, w[1]*deg*testDt, w[2]*deg*testDt)).normalized()
+	tr.t += testDt
+
+	r := tr.q.rotationMatrix()
+	down := [3]float64{0, 0, 1}
+	if tr.linAcc != nil {
+		a := tr.linAcc(tr.t)
+		down = [3]float64{-a[0], -a[1], 1 - a[2]}
+	}
+	field := [3]float64{testMagNorth, 0, testMagDown}
+	var s imu_raw.IMURaw
+	var acc, gyro, mag [3]float64
+	for i := range 3 {
+		for k := range 3 {
+			// Rᵀ maps earth vectors into the sensor frame
+			acc[i] += r[k][i] * down[k]
+			mag[i] += r[k][i] * field[k]
+		}
+		acc[i] += tr.rng.NormFloat64() * testAccNoise
+		gyro[i] = w[i] + tr.bias[i] + tr.rng.NormFloat64()*testGyroNoise
+		mag[i] += tr.rng.NormFloat64() * testMagNoise
+	}
+	s.Ax, s.Ay, s.Az = testCounts(acc[0]*16384), testCounts(acc[1]*16384), testCounts(acc[2]*16384)
+	s.Gx, s.Gy, s.Gz = testCounts(gyro[0]*131), testCounts(gyro[1]*131), testCounts(gyro[2]*131)
+	if !tr.noMag {
+		s.Mx, s.My, s.Mz = imu_raw.MagCounts(mag[0]), imu_raw.MagCounts(mag[1]), imu_raw.MagCounts(mag[2])
+		s.MagValid = true
+	}
+	return s
+}
+
+func testCounts(v float64) int16 {
+	return int16(math.Max(math.MinInt16, math.Min(math.MaxInt16, math.Round(v))))
+}
+
+// attitudeError returns the tilt and heading errors (degrees) of est
+// against the true attitude q.
+func attitudeError(est Pose, q quaternion) (tilt, heading float64) {
+	qe := quaternionFromPose(est)
+	// Earth-frame error rotation q ⊗ qe⁻¹
+	d := q.multiply(quaternion{w: qe.w, x: -qe.x, y: -qe.y, z: -qe.z}).normalized()
+	if d.w < 0 {
+		d = quaternion{-d.w, -d.x, -d.y, -d.z}
+	}
+	const rad = 180.0 / math.Pi
+	return 2 * math.Hypot(d.x, d.y) * rad, math.Abs(2*d.z) * rad
+}
+
+func runEKF(t *testing.T, ekf *AttitudeEKF, tr *trajectory, seconds float64, check func(p Pose)) Pose {
+	t.Helper()
+	var p Pose
+	for range int(seconds / testDt) {
+		p = ekf.Update(tr.next(), testDt)
+		if check != nil {
+			check(p)
+		}
+	}
+	return p
+}
+
+func TestEKFInitialPose(t *testing.T) {
+	start := Pose{Roll: 20, Pitch: -10, Yaw: 135}
+	tr := newTrajectory(start, func(float64) [3]float64 { return [3]float64{} }, [3]float64{})
+	ekf := NewAttitudeEKF(EKFConfig{})
+	p := ekf.Update(tr.next(), testDt)
+	tilt, heading := attitudeError(p, tr.q)
+	if tilt > 1 || heading > 2 {
+		t.Errorf("first pose %+v: tilt error %.2f°, heading error %.2f°", p, tilt, heading)
+	}
+}
+
+func TestEKFLearnsGyroBias(t *testing.T) {
+	bias := [3]float64{1.5, -0.8, 0.6}
+	tr := newTrajectory(Pose{Roll: 5, Pitch: 3, Yaw: 40}, func(float64) [3]float64 { return [3]float64{} }, bias)
+	ekf := NewAttitudeEKF(EKFConfig{})
+	p := runEKF(t, ekf, tr, 120, nil)
+
+	got := ekf.GyroBias()
+	for i := range got {
+		if math.Abs(got[i]-bias[i]) > 0.1 {
+			t.Errorf("bias[%d] = %.3f °/s, want %.3f", i, got[i], bias[i])
+		}
+	}
+	tilt, heading := attitudeError(p, tr.q)
+	if tilt > 1 || heading > 2 {
+		t.Errorf("tilt error %.2f°, heading error %.2f°", tilt, heading)
+	}
+	if p.Confidence < 0.8 {
+		t.Errorf("confidence %.2f after convergence, want >= 0.8", p.Confidence)
+	}
+	for name, std := range map[string]float64{"roll": p.RollStd, "pitch": p.PitchStd, "yaw": p.YawStd} {
+		if std <= 0 || std > 2 {
+			t.Errorf("%s std %.3f° after convergence, want (0, 2]", name, std)
+		}
+	}
+}
+
+func TestEKFTracksMotion(t *testing.T) {
+	// Rocking in roll and pitch while turning, with an unknown bias
+	rates := func(t float64) [3]float64 {
+		return [3]float64{
+			40 * math.Cos(2*math.Pi*0.3*t),
+			25 * math.Sin(2*math.Pi*0.2*t),
+			30,
+		}
+	}
+	tr := newTrajectory(Pose{Yaw: -60}, rates, [3]float64{0.7, 0.5, -0.9})
+	ekf := NewAttitudeEKF(EKFConfig{})
+
+	runEKF(t, ekf, tr, 60, nil) // converge
+	var worstTilt, worstHeading float64
+	runEKF(t, ekf, tr, 60, func(p Pose) {
+		tilt, heading := attitudeError(p, tr.q)
+		worstTilt = math.Max(worstTilt, tilt)
+		worstHeading = math.Max(worstHeading, heading)
+	})
+	if worstTilt > 2 || worstHeading > 3 {
+		t.Errorf("worst tilt error %.2f°, heading error %.2f°", worstTilt, worstHeading)
+	}
+}
+
+func TestEKFWithoutMag(t *testing.T) {
+	tr := newTrajectory(Pose{Roll: -15}, func(t float64) [3]float64 {
+		return [3]float64{0, 0, 20 * math.Sin(t)}
+	}, [3]float64{0.5, 0.5, 0})
+	tr.noMag = true
+	ekf := NewAttitudeEKF(EKFConfig{})
+	p := runEKF(t, ekf, tr, 60, nil)
+
+	if tilt, _ := attitudeError(p, tr.q); tilt > 1 {
+		t.Errorf("tilt error %.2f° without mag", tilt)
+	}
+	if _, heading := ekf.Sigma(); heading < 30 {
+		t.Errorf("heading sigma %.1f° without mag, want it to stay unknown", heading)
+	}
+	if p.Confidence > 0.1 {
+		t.Errorf("confidence %.2f with unknown heading, want near 0", p.Confidence)
+	}
+}
+
+func TestEKFRejectsLinearAcceleration(t *testing.T) {
+	tr := newTrajectory(Pose{}, func(float64) [3]float64 { return [3]float64{} }, [3]float64{})
+	ekf := NewAttitudeEKF(EKFConfig{})
+	runEKF(t, ekf, tr, 20, nil)
+
+	// 0.5 g forward for two seconds would tilt an accel-only estimate by ~27°
+	tr.linAcc = func(float64) [3]float64 { return [3]float64{0.5, 0, 0} }
+	p := runEKF(t, ekf, tr, 2, nil)
+	if tilt, _ := attitudeError(p, tr.q); tilt > 1 {
+		t.Errorf("tilt error %.2f° during linear acceleration", tilt)
+	}
+}
+
+func TestNewFilterRejectsEKF(t *testing.T) {
+	if _, err := NewFilter(AlgorithmEKF, FilterOptions{}); err == nil {
+		t.Error("NewFilter(ekf) succeeded, want an error pointing to NewAttitudeEKF")
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package orientation
+
+import (
+	"fmt"
+	"math"
+)
+
+// Orientation algorithms accepted by NewFilter (ORIENTATION_ALGORITHM).
+const (
+	AlgorithmTilt          = "tilt"          // accel roll/pitch, gyro-integrated yaw
+	AlgorithmComplementary = "complementary" // three-axis gyro + accel complementary filter
+	AlgorithmMadgwick      = "madgwick"      // quaternion gradient-descent filter
+	AlgorithmMahony        = "mahony"        // quaternion PI filter
+	AlgorithmEKF           = "ekf"           // error-state Kalman filter with mag, see AttitudeEKF
+)
+
+// Filter estimates orientation from a stream of accel/gyro samples. Each IMU
+// needs its own Filter; implementations are not safe for concurrent use.
+type Filter interface {
+	// Update advances the estimate by dt seconds. Accel may be in any unit
+	// (counts or g), gyro rates are in degrees/second.
+	Update(ax, ay, az, gx, gy, gz, dt float64) Pose
+}
+
+// FilterOptions holds the tuning parameters of all algorithms; only the ones
+// of the selected algorithm are used. Zero values select the defaults.
+type FilterOptions struct {
+	ComplementaryAlpha float64 // gyro weight, see DefaultComplementaryAlpha
+	MadgwickBeta       float64 // see DefaultMadgwickBeta
+	MahonyKp           float64 // see DefaultMahonyKp
+	MahonyKi           float64 // integral gain (0 = no gyro bias integration)
+}
+
+// NewFilter returns a Filter for the given algorithm. AlgorithmEKF is not a
+// Filter, as it also needs the magnetometer; see NewAttitudeEKF.
+func NewFilter(algorithm string, opts FilterOptions) (Filter, error) {
+	switch algorithm {
+	case AlgorithmTilt:
+		return &tiltFilter{}, nil
+	case AlgorithmComplementary:
+		return &complementaryFilter{alpha: opts.ComplementaryAlpha}, nil
+	case AlgorithmMadgwick:
+		return NewMadgwick(opts.MadgwickBeta), nil
+	case AlgorithmMahony:
+		return NewMahony(opts.MahonyKp, opts.MahonyKi), nil
+	case AlgorithmEKF:
+		return nil, fmt.Errorf("orientation algorithm %q needs raw samples, use NewAttitudeEKF", algorithm)
+	}
+	return nil, fmt.Errorf("unknown orientation algorithm %q", algorithm)
+}
+
+// tiltFilter is IntegrateGyro: roll/pitch from the accelerometer only, yaw
+// integrated from gyro Z.
+type tiltFilter struct {
+	pose Pose
+}
+
+func (f *tiltFilter) Update(ax, ay, az, gx, gy, gz, dt float64) Pose {
+	f.pose = IntegrateGyro(ax, ay, az, gx, gy, gz, f.pose, dt)
+	return f.pose
+}
+
+// complementaryStdTau is the time constant (seconds) of the residual average
+// behind the complementary filter's RollStd/PitchStd.
+const complementaryStdTau = 1.0
+
+// complementaryFilter is IntegrateGyroFull. As a heuristic uncertainty,
+// RollStd/PitchStd are the RMS disagreement between the accelerometer tilt
+// and the estimate over about complementaryStdTau: low while the accel
+// measures gravity alone, high under vibration or linear acceleration. Yaw
+// has no reference, so YawStd is not estimated.
+type complementaryFilter struct {
+	alpha       float64
+	pose        Pose
+	residualVar [2]float64 // roll, pitch (degrees²)
+	initialized bool
+}
+
+func (f *complementaryFilter) Update(ax, ay, az, gx, gy, gz, dt float64) Pose {
+	if !f.initialized {
+		// Start from the accelerometer tilt instead of converging from level.
+		f.pose = ComputePoseFromAccel(ax, ay, az)
+		f.initialized = true
+	}
+	f.pose = IntegrateGyroFull(ax, ay, az, gx, gy, gz, f.pose, dt, f.alpha)
+
+	accel := ComputePoseFromAccel(ax, ay, az)
+	k := dt / (complementaryStdTau + dt)
+	for i, d := range [2]float64{normalizeAngle(accel.Roll - f.pose.Roll), normalizeAngle(accel.Pitch - f.pose.Pitch)} {
+		f.residualVar[i] += k * (d*d - f.residualVar[i])
+	}
+	f.pose.RollStd = math.Sqrt(f.residualVar[0])
+	f.pose.PitchStd = math.Sqrt(f.residualVar[1])
+	return f.pose
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package orientation
+
+import "testing"
+
+func TestComplementaryStd(t *testing.T) {
+	f, err := NewFilter(AlgorithmComplementary, FilterOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// At rest the accel agrees with the estimate
+	var p Pose
+	for range 200 {
+		p = f.Update(0, 0, 1, 0, 0, 0, 0.02)
+	}
+	if p.RollStd > 0.1 || p.PitchStd > 0.1 || p.YawStd != 0 {
+		t.Errorf("at rest: std %.3f/%.3f/%.3f, want ~0 and no yaw std", p.RollStd, p.PitchStd, p.YawStd)
+	}
+
+	// A sustained sideways acceleration disagrees with the gyro-held roll
+	for range 25 {
+		p = f.Update(0, 0.3, 1, 0, 0, 0, 0.02)
+	}
+	if p.RollStd < 3 {
+		t.Errorf("under lateral acceleration: roll std %.2f°, want >= 3°", p.RollStd)
+	}
+}
+
+func TestTiltHasNoStd(t *testing.T) {
+	f, _ := NewFilter(AlgorithmTilt, FilterOptions{})
+	if p := f.Update(0.1, 0.2, 1, 1, 1, 1, 0.02); p.RollStd != 0 || p.PitchStd != 0 || p.YawStd != 0 {
+		t.Errorf("tilt pose %+v has uncertainty", p)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package orientation
+
+import (
+	imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
+)
+
+// DefaultGyroBiasLearningRate is the fraction of the remaining error removed
+// per stationary sample: 0.01 settles (≈95%) in about 300 samples, 12 s at
+// the default 40 ms IMU_SAMPLE_INTERVAL.
+const DefaultGyroBiasLearningRate = 0.01
+
+// GyroBiasStatus is a snapshot of a GyroBiasEstimator for health reporting.
+type GyroBiasStatus struct {
+	Bias       [3]float64 `json:"bias"`       // x, y, z (counts)
+	Stationary bool       `json:"stationary"` // latest ZUPT decision
+	Updates    uint64     `json:"updates"`    // stationary samples learned from
+}
+
+// GyroBiasEstimator tracks gyro bias at runtime, e.g. as the sensor warms up.
+// While the ZUPT detector reports the IMU still, the bias estimate moves
+// towards the measured rate by learningRate per sample (an exponential
+// average, i.e. a high-pass on the corrected gyro). During motion it is frozen.
+// It is not safe for concurrent use.
+type GyroBiasEstimator struct {
+	zupt         *ZUPTDetector
+	learningRate float64
+	gyroThresh   float64 // counts; single-sample motion guard
+	bias         [3]float64
+	updates      uint64
+}
+
+// NewGyroBiasEstimator creates an estimator using zupt for stationarity and
+// gyroThresh (counts, as passed to the detector) as an extra per-sample guard.
+// learningRate outside (0, 1] falls back to DefaultGyroBiasLearningRate.
+func NewGyroBiasEstimator(zupt *ZUPTDetector, gyroThresh, learningRate float64) *GyroBiasEstimator {
+	if learningRate <= 0 || learningRate > 1 {
+		learningRate = DefaultGyroBiasLearningRate
+	}
+	return &GyroBiasEstimator{zupt: zupt, learningRate: learningRate, gyroThresh: gyroThresh}
+}
+
+// Update feeds a raw sample and learns from it if the IMU is stationary and
+// the sample itself shows no rotation above the threshold. It returns whether
+// the estimate was updated.
+func (e *GyroBiasEstimator) Update(s imu_raw.IMURaw) bool {
+	if !e.zupt.Update(s) {
+		return false
+	}
+	g := [3]float64{float64(s.Gx), float64(s.Gy), float64(s.Gz)}
+	if magnitude(g[0], g[1], g[2]) >= e.gyroThresh {
+		return false
+	}
+	for i := range e.bias {
+		e.bias[i] += e.learningRate * (g[i] - e.bias[i])
+	}
+	e.updates++
+	return true
+}
+
+// Correct returns the bias-corrected gyro rates of s in counts.
+func (e *GyroBiasEstimator) Correct(s imu_raw.IMURaw) (gx, gy, gz float64) {
+	return float64(s.Gx) - e.bias[0], float64(s.Gy) - e.bias[1], float64(s.Gz) - e.bias[2]
+}
+
+// Status returns the current estimate.
+func (e *GyroBiasEstimator) Status() GyroBiasStatus {
+	return GyroBiasStatus{Bias: e.bias, Stationary: e.zupt.Stationary(), Updates: e.updates}
+}
@@ -21,11 +21,17 @@ func NewMockSource() Source {
 }
 
 func (m *mockSource) Next() (Pose, error) {
-	elapsed := time.Since(m.start).Seconds()
+	return MockPose(time.Since(m.start).Seconds()), nil
+}
 
+// MockPose is the smooth synthetic motion of the mock source, elapsed
+// seconds after its start: roll ±20° and pitch ±15° oscillations while
+// yawing at 30°/s. The simulated IMUs (MOCK_HARDWARE) derive their samples
+// from it.
+func MockPose(elapsed float64) Pose {
 	return Pose{
 		Roll:  20 * math.Sin(elapsed),
 		Pitch: 15 * math.Cos(elapsed*0.7),
 		Yaw:   math.Mod(elapsed*30, 360),
-	}, nil
+	}
 }
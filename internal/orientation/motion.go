@@ -0,0 +1,55 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package orientation
+
+import (
+	"time"
+
+	imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
+)
+
+// Motion is derived per-IMU telemetry published next to the pose
+// (TOPIC_MOTION_LEFT/TOPIC_MOTION_RIGHT), for dashboards.
+type Motion struct {
+	Source      string  `json:"source"`
+	TurnRateDeg float64 `json:"turn_rate_deg"` // gyro Z (°/s), bias-corrected when tracked
+	AccelMagG   float64 `json:"accel_mag_g"`   // net accel magnitude (g), 1 at rest
+	Moving      bool    `json:"moving"`        // not stationary per the ZUPT detector
+
+	// Time of the IMU sample it was computed from (producer clock)
+	Timestamp time.Time `json:"timestamp,omitzero"`
+}
+
+// MotionTracker computes Motion from the raw samples of one IMU, with its own
+// ZUPT detector (default window and thresholds). Until the detector window
+// has filled, Moving is true. It is not safe for concurrent use.
+type MotionTracker struct {
+	zupt       *ZUPTDetector
+	accelRange byte
+	gyroRange  byte
+}
+
+// NewMotionTracker creates a tracker for samples at the given ACCEL_FS_SEL
+// and GYRO_FS_SEL codes.
+func NewMotionTracker(accelRange, gyroRange byte) *MotionTracker {
+	accelThresh, gyroThresh := DefaultZUPTThresholds(accelRange, gyroRange)
+	return &MotionTracker{
+		zupt:       NewZUPTDetector(DefaultZUPTWindow, accelThresh, gyroThresh),
+		accelRange: accelRange,
+		gyroRange:  gyroRange,
+	}
+}
+
+// Update feeds sample s and returns its Motion. gz is the gyro Z rate in
+// counts, e.g. bias-corrected by a GyroBiasEstimator (float64(s.Gz) if not).
+func (m *MotionTracker) Update(s imu_raw.IMURaw, gz float64) Motion {
+	return Motion{
+		Source:      s.Source,
+		TurnRateDeg: GyroCountsToDegPerSec(gz, m.gyroRange),
+		AccelMagG:   AccelCountsToG(magnitude(float64(s.Ax), float64(s.Ay), float64(s.Az)), m.accelRange),
+		Moving:      !m.zupt.Update(s),
+		Timestamp:   s.Timestamp,
+	}
+}
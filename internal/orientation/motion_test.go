@@ -0,0 +1,38 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package orientation
+
+import (
+	"math"
+	"testing"
+
+	imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
+)
+
+func TestMotionTracker(t *testing.T) {
+	// ±4 g and ±500 °/s: 8192 LSB/g, 65.5 LSB/(°/s)
+	m := NewMotionTracker(1, 1)
+	still := imu_raw.IMURaw{Source: "left", Az: 8192}
+
+	var got Motion
+	for i := range DefaultZUPTWindow {
+		got = m.Update(still, 0)
+		if i < DefaultZUPTWindow-1 && !got.Moving {
+			t.Fatalf("sample %d: stationary before the window filled", i)
+		}
+	}
+	if got.Moving || math.Abs(got.AccelMagG-1) > 1e-9 || got.Source != "left" {
+		t.Errorf("at rest: %+v, want not moving at 1 g", got)
+	}
+
+	turning := still
+	turning.Gz = 655
+	for range DefaultZUPTWindow {
+		got = m.Update(turning, float64(turning.Gz))
+	}
+	if !got.Moving || math.Abs(got.TurnRateDeg-10) > 1e-9 {
+		t.Errorf("turning: %+v, want moving at 10 °/s", got)
+	}
+}
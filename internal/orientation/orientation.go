@@ -7,6 +7,7 @@ package orientation
 
 import (
 	"math"
+	"time"
 )
 
 // Pose is the canonical representation of orientation for your app.
@@ -14,6 +15,19 @@ type Pose struct {
 	Roll  float64 `json:"roll"`
 	Pitch float64 `json:"pitch"`
 	Yaw   float64 `json:"yaw"`
+
+	// Time of the IMU sample the pose was computed from (producer clock)
+	Timestamp time.Time `json:"timestamp,omitzero"`
+	// Per-topic message number (MQTT_SEQUENCE_NUMBERS)
+	Seq uint64 `json:"seq,omitempty"`
+	// Estimate confidence 0-1 from the filter covariance (ekf only, see
+	// AttitudeEKF.Update)
+	Confidence float64 `json:"confidence,omitempty"`
+	// One-sigma uncertainty of Roll/Pitch/Yaw in degrees, from filters that
+	// can estimate it (0 = not estimated, e.g. tilt)
+	RollStd  float64 `json:"roll_std,omitempty"`
+	PitchStd float64 `json:"pitch_std,omitempty"`
+	YawStd   float64 `json:"yaw_std,omitempty"`
 }
 
 // Source is anything that can provide poses over time.
@@ -85,6 +99,7 @@ func IntegrateGyro(ax, ay, az, gx, gy, gz float64, prevPose Pose, deltaTime floa
 
 // ComputePoseFromIMURaw computes pose from raw IMU data including gyro integration.
 // This is a convenience function that combines accelerometer and gyroscope data.
+// Raw gyro counts must be converted first, see GyroCountsToDegPerSec.
 //
 // Parameters:
 //   - ax, ay, az: accelerometer values
@@ -96,3 +111,70 @@ func IntegrateGyro(ax, ay, az, gx, gy, gz float64, prevPose Pose, deltaTime floa
 func ComputePoseFromIMURaw(ax, ay, az, gx, gy, gz float64, prevPose Pose, deltaTime float64) Pose {
 	return IntegrateGyro(ax, ay, az, gx, gy, gz, prevPose, deltaTime)
 }
+
+// DefaultComplementaryAlpha is the gyro weight used by IntegrateGyroFull when
+// alpha is outside (0, 1].
+const DefaultComplementaryAlpha = 0.98
+
+// IntegrateGyroFull propagates roll, pitch and yaw with all three gyro axes
+// (Euler-rate equations) and blends accelerometer roll/pitch in with a
+// complementary filter, so fast rotations are tracked by the gyro while the
+// accelerometer removes long-term drift. Yaw is gyro-only.
+//
+// Parameters:
+//   - ax, ay, az: accelerometer values (any unit, for roll/pitch reference)
+//   - gx, gy, gz: body angular rates (degrees/second)
+//   - prevPose: previous pose of the same IMU
+//   - deltaTime: elapsed time in seconds since last update
+//   - alpha: gyro weight in (0, 1]; 1 = gyro only, e.g. 0.98
+//
+// Near ±90° pitch the Euler rates are singular; cos(pitch) is clamped there.
+func IntegrateGyroFull(ax, ay, az, gx, gy, gz float64, prevPose Pose, deltaTime, alpha float64) Pose {
+	if alpha <= 0 || alpha > 1 {
+		alpha = DefaultComplementaryAlpha
+	}
+	accel := ComputePoseFromAccel(ax, ay, az)
+
+	const deg = math.Pi / 180.0
+	roll := prevPose.Roll * deg
+	pitch := prevPose.Pitch * deg
+	p, q, r := gx*deg, gy*deg, gz*deg
+
+	cosPitch := math.Cos(pitch)
+	if math.Abs(cosPitch) < 1e-3 {
+		cosPitch = math.Copysign(1e-3, cosPitch)
+	}
+	sinRoll, cosRoll := math.Sin(roll), math.Cos(roll)
+	tanPitch := math.Sin(pitch) / cosPitch
+
+	rollRate := p + sinRoll*tanPitch*q + cosRoll*tanPitch*r
+	pitchRate := cosRoll*q - sinRoll*r
+	yawRate := (sinRoll*q + cosRoll*r) / cosPitch
+
+	gyroRoll := prevPose.Roll + rollRate/deg*deltaTime
+	gyroPitch := prevPose.Pitch + pitchRate/deg*deltaTime
+
+	return Pose{
+		Roll:  blendAngle(gyroRoll, accel.Roll, alpha),
+		Pitch: blendAngle(gyroPitch, accel.Pitch, alpha),
+		Yaw:   normalizeAngle(prevPose.Yaw + yawRate/deg*deltaTime),
+	}
+}
+
+// blendAngle returns alpha*gyro + (1-alpha)*accel along the shorter arc,
+// normalized to [-180, 180].
+func blendAngle(gyro, accel, alpha float64) float64 {
+	diff := normalizeAngle(accel - gyro)
+	return normalizeAngle(gyro + (1-alpha)*diff)
+}
+
+// normalizeAngle wraps degrees to [-180, 180].
+func normalizeAngle(a float64) float64 {
+	for a > 180 {
+		a -= 360
+	}
+	for a < -180 {
+		a += 360
+	}
+	return a
+}
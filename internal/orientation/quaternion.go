@@ -0,0 +1,245 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package orientation
+
+import "math"
+
+const (
+	// DefaultMadgwickBeta is the Madgwick gradient-descent gain: higher trusts
+	// the accelerometer more (faster drift correction, more vibration noise).
+	DefaultMadgwickBeta = 0.1
+	// DefaultMahonyKp is the Mahony proportional gain on the accel error.
+	DefaultMahonyKp = 0.5
+)
+
+// quaternion is a unit rotation from the sensor frame to the earth frame,
+// w + xi + yj + zk.
+type quaternion struct {
+	w, x, y, z float64
+}
+
+// quaternionFromPose converts roll/pitch/yaw (degrees, ZYX order) to a
+// quaternion.
+func quaternionFromPose(p Pose) quaternion {
+	const deg = math.Pi / 180.0
+	cr, sr := math.Cos(p.Roll*deg/2), math.Sin(p.Roll*deg/2)
+	cp, sp := math.Cos(p.Pitch*deg/2), math.Sin(p.Pitch*deg/2)
+	cy, sy := math.Cos(p.Yaw*deg/2), math.Sin(p.Yaw*deg/2)
+	return quaternion{
+		w: cr*cp*cy + sr*sp*sy,
+		x: sr*cp*cy - cr*sp*sy,
+		y: cr*sp*cy + sr*cp*sy,
+		z: cr*cp*sy - sr*sp*cy,
+	}
+}
+
+// pose converts the quaternion to roll/pitch/yaw in degrees (ZYX order), using
+// the same sign conventions as ComputePoseFromAccel.
+func (q quaternion) pose() Pose {
+	const rad = 180.0 / math.Pi
+	sinPitch := 2 * (q.w*q.y - q.z*q.x)
+	sinPitch = math.Max(-1, math.Min(1, sinPitch))
+	return Pose{
+		Roll:  math.Atan2(2*(q.w*q.x+q.y*q.z), 1-2*(q.x*q.x+q.y*q.y)) * rad,
+		Pitch: math.Asin(sinPitch) * rad,
+		Yaw:   math.Atan2(2*(q.w*q.z+q.x*q.y), 1-2*(q.y*q.y+q.z*q.z)) * rad,
+	}
+}
+
+func (q quaternion) normalized() quaternion {
+	n := math.Sqrt(q.w*q.w + q.x*q.x + q.y*q.y + q.z*q.z)
+	if n == 0 {
+		return quaternion{w: 1}
+	}
+	return quaternion{q.w / n, q.x / n, q.y / n, q.z / n}
+}
+
+// multiply returns the Hamilton product q ⊗ r.
+func (q quaternion) multiply(r quaternion) quaternion {
+	return quaternion{
+		w: q.w*r.w - q.x*r.x - q.y*r.y - q.z*r.z,
+		x: q.w*r.x + q.x*r.w + q.y*r.z - q.z*r.y,
+		y: q.w*r.y - q.x*r.z + q.y*r.w + q.z*r.x,
+		z: q.w*r.z + q.x*r.y - q.y*r.x + q.z*r.w,
+	}
+}
+
+// quaternionFromRotationVector returns the rotation by |v| radians about v.
+func quaternionFromRotationVector(vx, vy, vz float64) quaternion {
+	angle := math.Sqrt(vx*vx + vy*vy + vz*vz)
+	if angle < 1e-12 {
+		return quaternion{w: 1, x: vx / 2, y: vy / 2, z: vz / 2}.normalized()
+	}
+	s := math.Sin(angle/2) / angle
+	return quaternion{w: math.Cos(angle / 2), x: vx * s, y: vy * s, z: vz * s}
+}
+
+// rotationMatrix returns the sensor-to-earth rotation matrix of a unit
+// quaternion; its transpose maps earth vectors into the sensor frame.
+func (q quaternion) rotationMatrix() [3][3]float64 {
+	return [3][3]float64{
+		{1 - 2*(q.y*q.y+q.z*q.z), 2 * (q.x*q.y - q.w*q.z), 2 * (q.x*q.z + q.w*q.y)},
+		{2 * (q.x*q.y + q.w*q.z), 1 - 2*(q.x*q.x+q.z*q.z), 2 * (q.y*q.z - q.w*q.x)},
+		{2 * (q.x*q.z - q.w*q.y), 2 * (q.y*q.z + q.w*q.x), 1 - 2*(q.x*q.x+q.y*q.y)},
+	}
+}
+
+// rotate maps a sensor-frame vector into the earth frame.
+func (q quaternion) rotate(v [3]float64) [3]float64 {
+	r := q.rotationMatrix()
+	return [3]float64{
+		r[0][0]*v[0] + r[0][1]*v[1] + r[0][2]*v[2],
+		r[1][0]*v[0] + r[1][1]*v[1] + r[1][2]*v[2],
+		r[2][0]*v[0] + r[2][1]*v[1] + r[2][2]*v[2],
+	}
+}
+
+// rateOfChange returns dq/dt = ½ q ⊗ (0, gx, gy, gz) for body rates in rad/s.
+func (q quaternion) rateOfChange(gx, gy, gz float64) quaternion {
+	return quaternion{
+		w: 0.5 * (-q.x*gx - q.y*gy - q.z*gz),
+		x: 0.5 * (q.w*gx + q.y*gz - q.z*gy),
+		y: 0.5 * (q.w*gy - q.x*gz + q.z*gx),
+		z: 0.5 * (q.w*gz + q.x*gy - q.y*gx),
+	}
+}
+
+// normalizeAccel returns the unit accel vector, or ok=false for a zero vector
+// (e.g. free fall or no reading).
+func normalizeAccel(ax, ay, az float64) (x, y, z float64, ok bool) {
+	n := math.Sqrt(ax*ax + ay*ay + az*az)
+	if n == 0 {
+		return 0, 0, 0, false
+	}
+	return ax / n, ay / n, az / n, true
+}
+
+// Madgwick is the 6-axis (IMU) variant of Madgwick's gradient-descent
+// orientation filter. Yaw is gyro-only; roll and pitch are corrected towards
+// the accelerometer at a rate set by beta.
+type Madgwick struct {
+	beta        float64
+	q           quaternion
+	initialized bool
+}
+
+// NewMadgwick creates a Madgwick filter with gain beta (DefaultMadgwickBeta
+// if <= 0).
+func NewMadgwick(beta float64) *Madgwick {
+	if beta <= 0 {
+		beta = DefaultMadgwickBeta
+	}
+	return &Madgwick{beta: beta, q: quaternion{w: 1}}
+}
+
+// Update implements Filter.
+func (f *Madgwick) Update(ax, ay, az, gx, gy, gz, dt float64) Pose {
+	if !f.initialized {
+		f.q = quaternionFromPose(ComputePoseFromAccel(ax, ay, az))
+		f.initialized = true
+	}
+
+	const deg = math.Pi / 180.0
+	q := f.q
+	qDot := q.rateOfChange(gx*deg, gy*deg, gz*deg)
+
+	if ax, ay, az, ok := normalizeAccel(ax, ay, az); ok {
+		// Gradient of the error between measured and estimated gravity
+		_2w, _2x, _2y, _2z := 2*q.w, 2*q.x, 2*q.y, 2*q.z
+		_4w, _4x, _4y := 4*q.w, 4*q.x, 4*q.y
+		_8x, _8y := 8*q.x, 8*q.y
+		ww, xx, yy, zz := q.w*q.w, q.x*q.x, q.y*q.y, q.z*q.z
+
+		s := quaternion{
+			w: _4w*yy + _2y*ax + _4w*xx - _2x*ay,
+			x: _4x*zz - _2z*ax + 4*ww*q.x - _2w*ay - _4x + _8x*xx + _8x*yy + _4x*az,
+			y: 4*ww*q.y + _2w*ax + _4y*zz - _2z*ay - _4y + _8y*xx + _8y*yy + _4y*az,
+			z: 4*xx*q.z - _2x*ax + 4*yy*q.z - _2y*ay,
+		}
+		if n := math.Sqrt(s.w*s.w + s.x*s.x + s.y*s.y + s.z*s.z); n > 0 {
+			qDot.w -= f.beta * s.w / n
+			qDot.x -= f.beta * s.x / n
+			qDot.y -= f.beta * s.y / n
+			qDot.z -= f.beta * s.z / n
+		}
+	}
+
+	f.q = quaternion{
+		w: q.w + qDot.w*dt,
+		x: q.x + qDot.x*dt,
+		y: q.y + qDot.y*dt,
+		z: q.z + qDot.z*dt,
+	}.normalized()
+	return f.q.pose()
+}
+
+// Mahony is the 6-axis (IMU) variant of Mahony's complementary filter on
+// SO(3): the cross product of measured and estimated gravity is fed back to
+// the gyro rates through a PI controller. Kp sets how fast roll/pitch follow
+// the accelerometer; Ki > 0 additionally learns a constant gyro bias (roll
+// and pitch axes only, yaw is unobservable without a magnetometer).
+type Mahony struct {
+	kp, ki      float64
+	integral    [3]float64 // rad/s
+	q           quaternion
+	initialized bool
+}
+
+// NewMahony creates a Mahony filter with gains kp (DefaultMahonyKp if <= 0)
+// and ki (0 disables the integral term).
+func NewMahony(kp, ki float64) *Mahony {
+	if kp <= 0 {
+		kp = DefaultMahonyKp
+	}
+	if ki < 0 {
+		ki = 0
+	}
+	return &Mahony{kp: kp, ki: ki, q: quaternion{w: 1}}
+}
+
+// Update implements Filter.
+func (f *Mahony) Update(ax, ay, az, gx, gy, gz, dt float64) Pose {
+	if !f.initialized {
+		f.q = quaternionFromPose(ComputePoseFromAccel(ax, ay, az))
+		f.initialized = true
+	}
+
+	const deg = math.Pi / 180.0
+	gx, gy, gz = gx*deg, gy*deg, gz*deg
+	q := f.q
+
+	if ax, ay, az, ok := normalizeAccel(ax, ay, az); ok {
+		// Estimated gravity direction in the sensor frame
+		vx := 2 * (q.x*q.z - q.w*q.y)
+		vy := 2 * (q.w*q.x + q.y*q.z)
+		vz := q.w*q.w - q.x*q.x - q.y*q.y + q.z*q.z
+
+		// Error: measured × estimated
+		ex := ay*vz - az*vy
+		ey := az*vx - ax*vz
+		ez := ax*vy - ay*vx
+
+		if f.ki > 0 {
+			f.integral[0] += f.ki * ex * dt
+			f.integral[1] += f.ki * ey * dt
+			f.integral[2] += f.ki * ez * dt
+			gx += f.integral[0]
+			gy += f.integral[1]
+			gz += f.integral[2]
+		}
+		gx += f.kp * ex
+		gy += f.kp * ey
+		gz += f.kp * ez
+	}
+
+	qDot := q.rateOfChange(gx, gy, gz)
+	f.q = quaternion{
+		w: q.w + qDot.w*dt,
+		x: q.x + qDot.x*dt,
+		y: q.y + qDot.y*dt,
+		z: q.z + qDot.z*dt,
+	}.normalized()
+	return f.q.pose()
+}
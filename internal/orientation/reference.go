@@ -0,0 +1,84 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package orientation
+
+import "sync"
+
+// Subtract returns p relative to ref: the rotation ref is removed on the
+// sensor side (q_p ⊗ q_ref⁻¹), in quaternion space so there is no Euler
+// wraparound. With ref the pose of a tilted mount while the vehicle is level
+// and its yaw set to 0, the result is the vehicle attitude: level reads 0/0
+// and the heading is kept. p.Subtract(p) is 0/0/0. Non-angle fields of p
+// are kept.
+func (p Pose) Subtract(ref Pose) Pose {
+	qr := quaternionFromPose(ref)
+	qr.x, qr.y, qr.z = -qr.x, -qr.y, -qr.z
+	rel := quaternionFromPose(p).multiply(qr).normalized().pose()
+
+	out := p
+	out.Roll, out.Pitch, out.Yaw = rel.Roll, rel.Pitch, rel.Yaw
+	return out
+}
+
+// Reference is a zero orientation ("level") subtracted from the poses of one
+// IMU, e.g. to remove the installed tilt of the device. Capture takes the
+// next pose as the reference, keeping its roll and pitch only, so level
+// reads 0/0 afterwards and the heading is unaffected. It is safe for
+// concurrent use (commands arrive on MQTT callbacks).
+type Reference struct {
+	mu      sync.Mutex
+	ref     Pose
+	set     bool
+	capture bool
+}
+
+// Capture makes the next pose passed to Apply the reference.
+func (r *Reference) Capture() {
+	r.mu.Lock()
+	r.capture = true
+	r.mu.Unlock()
+}
+
+// Set makes ref the reference (e.g. restored from a previous capture).
+func (r *Reference) Set(ref Pose) {
+	r.mu.Lock()
+	r.ref = Pose{Roll: ref.Roll, Pitch: ref.Pitch, Yaw: ref.Yaw}
+	r.set = true
+	r.mu.Unlock()
+}
+
+// Clear removes the reference and any pending capture.
+func (r *Reference) Clear() {
+	r.mu.Lock()
+	r.ref = Pose{}
+	r.set = false
+	r.capture = false
+	r.mu.Unlock()
+}
+
+// Get returns the reference, if set.
+func (r *Reference) Get() (Pose, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ref, r.set
+}
+
+// Apply returns p relative to the reference (p unchanged if none is set).
+// captured reports that p was taken as the new reference by a pending
+// Capture.
+func (r *Reference) Apply(p Pose) (out Pose, captured bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.capture {
+		r.ref = Pose{Roll: p.Roll, Pitch: p.Pitch}
+		r.set = true
+		r.capture = false
+		captured = true
+	}
+	if !r.set {
+		return p, captured
+	}
+	return p.Subtract(r.ref), captured
+}
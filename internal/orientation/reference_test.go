@@ -0,0 +1,54 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package orientation
+
+import (
+	"math"
+	"testing"
+)
+
+// devicePose returns the pose of a device mounted with mount (roll/pitch) on
+// a vehicle with attitude vehicle.
+func devicePose(vehicle, mount Pose) Pose {
+	return quaternionFromPose(vehicle).multiply(quaternionFromPose(mount)).pose()
+}
+
+func posesClose(a, b Pose) bool {
+	return math.Abs(normalizeAngle(a.Roll-b.Roll)) < 1e-6 &&
+		math.Abs(normalizeAngle(a.Pitch-b.Pitch)) < 1e-6 &&
+		math.Abs(normalizeAngle(a.Yaw-b.Yaw)) < 1e-6
+}
+
+func TestSubtractSelf(t *testing.T) {
+	p := Pose{Roll: 170, Pitch: -40, Yaw: -179}
+	if got := p.Subtract(p); !posesClose(got, Pose{}) {
+		t.Errorf("p.Subtract(p) = %+v, want 0/0/0", got)
+	}
+}
+
+func TestReferenceLevelsTiltedMount(t *testing.T) {
+	mount := Pose{Roll: 12, Pitch: -7}
+	var ref Reference
+	ref.Capture()
+
+	// Captured while the vehicle is level, heading 170°
+	got, captured := ref.Apply(devicePose(Pose{Yaw: 170}, mount))
+	if !captured || !posesClose(got, Pose{Yaw: 170}) {
+		t.Fatalf("at capture: %+v (captured %v), want level with yaw 170", got, captured)
+	}
+
+	// The vehicle then rolls and turns across ±180°
+	vehicle := Pose{Roll: 20, Pitch: 5, Yaw: -175}
+	got, captured = ref.Apply(devicePose(vehicle, mount))
+	if captured || !posesClose(got, vehicle) {
+		t.Errorf("after capture: %+v, want the vehicle attitude %+v", got, vehicle)
+	}
+
+	ref.Clear()
+	p := devicePose(vehicle, mount)
+	if got, _ := ref.Apply(p); got != p {
+		t.Errorf("after Clear: %+v, want the pose unchanged", got)
+	}
+}
@@ -0,0 +1,20 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package orientation
+
+import imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
+
+// GyroCountsToDegPerSec converts a raw MPU9250 gyro reading to degrees/second
+// for the given GYRO_FS_SEL code (0=±250, 1=±500, 2=±1000, 3=±2000 °/s).
+func GyroCountsToDegPerSec(counts float64, fsSel byte) float64 {
+	return counts / imu_raw.GyroLSBPerDPS(fsSel)
+}
+
+// AccelCountsToG converts a raw MPU9250 accel reading to g for the given
+// ACCEL_FS_SEL code (0=±2, 1=±4, 2=±8, 3=±16 g). Tilt (roll/pitch) is
+// scale-invariant, so this is only needed where magnitudes matter.
+func AccelCountsToG(counts float64, fsSel byte) float64 {
+	return counts / imu_raw.AccelLSBPerG(fsSel)
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package orientation
+
+import (
+	"math"
+
+	imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
+)
+
+// Stationarity (zero-velocity update, ZUPT) detection works on raw sensor
+// counts so it can run before any unit conversion:
+//
+//   - accelThresh bounds the variance of the accel magnitude over the window,
+//     in counts². At rest the magnitude stays at 1 g plus sensor noise.
+//   - gyroThresh bounds the mean gyro magnitude over the window, in counts.
+//     It must stay above the uncalibrated gyro bias.
+const (
+	// DefaultZUPTWindow is the default number of samples per window
+	// (1 s at the default 40 ms IMU_SAMPLE_INTERVAL).
+	DefaultZUPTWindow = 25
+
+	// defaultZUPTAccelStdG is the accel magnitude standard deviation (g)
+	// below which the IMU is considered still.
+	defaultZUPTAccelStdG = 0.02
+	// defaultZUPTGyroDegPerSec is the mean rotation rate (°/s) below which
+	// the IMU is considered still; covers typical MPU9250 bias.
+	defaultZUPTGyroDegPerSec = 3.0
+)
+
+// DefaultZUPTThresholds returns IsStationary thresholds (in counts) for the
+// given MPU9250 accel and gyro full-scale codes. Out-of-range codes are
+// clamped to the widest range.
+//
+//	accel FS   accelThresh (counts²)   gyro FS     gyroThresh (counts)
+//	±2g        ≈107374                 ±250°/s     393
+//	±4g        ≈26844                  ±500°/s     196.5
+//	±8g        ≈6711                   ±1000°/s    98.4
+//	±16g       ≈1678                   ±2000°/s    49.2
+func DefaultZUPTThresholds(accelFS, gyroFS byte) (accelThresh, gyroThresh float64) {
+	accelStd := defaultZUPTAccelStdG * imu_raw.AccelLSBPerG(accelFS)
+	return accelStd * accelStd, defaultZUPTGyroDegPerSec * imu_raw.GyroLSBPerDPS(gyroFS)
+}
+
+// IsStationary reports whether the IMU was still over the window: the
+// variance of the accel magnitude is below accelThresh (counts²) and the mean
+// gyro magnitude is below gyroThresh (counts). Windows shorter than two
+// samples are never stationary.
+func IsStationary(window []imu_raw.IMURaw, accelThresh, gyroThresh float64) bool {
+	n := len(window)
+	if n < 2 {
+		return false
+	}
+
+	var sum, sumSq, gyroSum float64
+	for _, s := range window {
+		a := magnitude(float64(s.Ax), float64(s.Ay), float64(s.Az))
+		sum += a
+		sumSq += a * a
+		gyroSum += magnitude(float64(s.Gx), float64(s.Gy), float64(s.Gz))
+	}
+	mean := sum / float64(n)
+	variance := sumSq/float64(n) - mean*mean
+
+	return variance < accelThresh && gyroSum/float64(n) < gyroThresh
+}
+
+// ZUPTDetector tracks stationarity over a sliding window of samples, e.g. to
+// reset velocity or re-estimate gyro bias while the IMU is still.
+// It is not safe for concurrent use.
+type ZUPTDetector struct {
+	window      []imu_raw.IMURaw // ring buffer
+	next        int
+	full        bool
+	accelThresh float64
+	gyroThresh  float64
+	stationary  bool
+}
+
+// NewZUPTDetector creates a detector over size samples (DefaultZUPTWindow if
+// size < 2) with thresholds in counts, see DefaultZUPTThresholds.
+func NewZUPTDetector(size int, accelThresh, gyroThresh float64) *ZUPTDetector {
+	if size < 2 {
+		size = DefaultZUPTWindow
+	}
+	return &ZUPTDetector{
+		window:      make([]imu_raw.IMURaw, size),
+		accelThresh: accelThresh,
+		gyroThresh:  gyroThresh,
+	}
+}
+
+// Update adds a sample and returns whether the IMU is stationary. It returns
+// false until the window has filled.
+func (d *ZUPTDetector) Update(s imu_raw.IMURaw) bool {
+	d.window[d.next] = s
+	d.next = (d.next + 1) % len(d.window)
+	if d.next == 0 {
+		d.full = true
+	}
+	d.stationary = d.full && IsStationary(d.window, d.accelThresh, d.gyroThresh)
+	return d.stationary
+}
+
+// Stationary returns the result of the latest Update.
+func (d *ZUPTDetector) Stationary() bool {
+	return d.stationary
+}
+
+// Reset clears the window, e.g. after a sensor range change.
+func (d *ZUPTDetector) Reset() {
+	d.next = 0
+	d.full = false
+	d.stationary = false
+}
+
+func magnitude(x, y, z float64) float64 {
+	return math.Sqrt(x*x + y*y + z*z)
+}
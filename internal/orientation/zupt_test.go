@@ -0,0 +1,114 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package orientation
+
+import (
+	"testing"
+
+	imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
+)
+
+// ±4 g and ±500 °/s: 8192 LSB/g, 65.5 LSB/(°/s)
+const zuptAccelFS, zuptGyroFS = 1, 1
+
+// zuptWindow builds n samples from gen.
+func zuptWindow(n int, gen func(i int) imu_raw.IMURaw) []imu_raw.IMURaw {
+	w := make([]imu_raw.IMURaw, n)
+	for i := range w {
+		w[i] = gen(i)
+	}
+	return w
+}
+
+// stillSample is 1 g on z with a few counts of noise and a typical gyro bias.
+func stillSample(i int) imu_raw.IMURaw {
+	noise := int16(i%5-2) * 10
+	return imu_raw.IMURaw{Ax: noise, Ay: -noise, Az: 8192 + noise, Gx: 40, Gy: -30, Gz: 20 + noise/10}
+}
+
+func TestIsStationary(t *testing.T) {
+	accelThresh, gyroThresh := DefaultZUPTThresholds(zuptAccelFS, zuptGyroFS)
+
+	tests := []struct {
+		name   string
+		window []imu_raw.IMURaw
+		want   bool
+	}{
+		{"constant gravity", zuptWindow(DefaultZUPTWindow, func(int) imu_raw.IMURaw {
+			return imu_raw.IMURaw{Az: 8192}
+		}), true},
+		{"gravity with noise and gyro bias", zuptWindow(DefaultZUPTWindow, stillSample), true},
+		{"tilted but still", zuptWindow(DefaultZUPTWindow, func(int) imu_raw.IMURaw {
+			return imu_raw.IMURaw{Ax: 4096, Az: 7094}
+		}), true},
+		{"shaking", zuptWindow(DefaultZUPTWindow, func(i int) imu_raw.IMURaw {
+			s := stillSample(i)
+			s.Az += int16(i%2*2-1) * 2000 // ±0.25 g
+			return s
+		}), false},
+		{"rotating", zuptWindow(DefaultZUPTWindow, func(i int) imu_raw.IMURaw {
+			s := stillSample(i)
+			s.Gz = 655 // 10 °/s
+			return s
+		}), false},
+		{"single sample", zuptWindow(1, stillSample), false},
+		{"empty", nil, false},
+	}
+	for _, tt := range tests {
+		if got := IsStationary(tt.window, accelThresh, gyroThresh); got != tt.want {
+			t.Errorf("%s: IsStationary = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestZUPTDetector(t *testing.T) {
+	accelThresh, gyroThresh := DefaultZUPTThresholds(zuptAccelFS, zuptGyroFS)
+	const size = 10
+	d := NewZUPTDetector(size, accelThresh, gyroThresh)
+
+	// A partially filled window is never stationary
+	for i := range size {
+		got := d.Update(stillSample(i))
+		if want := i == size-1; got != want || d.Stationary() != want {
+			t.Fatalf("sample %d: Update = %t, Stationary = %t, want %t", i, got, d.Stationary(), want)
+		}
+	}
+
+	// One jolt keeps the window moving until it has slid out
+	jolt := stillSample(0)
+	jolt.Az += 8192
+	if d.Update(jolt) {
+		t.Error("jolt: stationary, want moving")
+	}
+	for i := range size - 1 {
+		if d.Update(stillSample(i)) {
+			t.Fatalf("sample %d after the jolt: stationary while the jolt is in the window", i)
+		}
+	}
+	if !d.Update(stillSample(0)) {
+		t.Error("jolt slid out of the window: moving, want stationary")
+	}
+
+	d.Reset()
+	if d.Stationary() {
+		t.Error("after Reset: Stationary = true")
+	}
+	for i := range size - 1 {
+		if d.Update(stillSample(i)) {
+			t.Fatalf("sample %d after Reset: stationary before the window refilled", i)
+		}
+	}
+	if !d.Update(stillSample(0)) {
+		t.Error("refilled after Reset: moving, want stationary")
+	}
+}
+
+func TestNewZUPTDetectorDefaultSize(t *testing.T) {
+	for _, size := range []int{0, 1} {
+		if got := len(NewZUPTDetector(size, 1, 1).window); got != DefaultZUPTWindow {
+			t.Errorf("NewZUPTDetector(%d) window = %d, want %d", size, got, DefaultZUPTWindow)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package recording
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/relabs-tech/inertial_computer/internal/gps"
+	imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
+	"github.com/relabs-tech/inertial_computer/internal/orientation"
+)
+
+// Record kinds exported by ExportCSV, one CSV per kind.
+const (
+	KindIMU  = "imu"
+	KindPose = "pose"
+	KindGPS  = "gps"
+)
+
+var csvHeaders = map[string][]string{
+	KindIMU:  {"time", "unix_s", "topic", "source", "ax", "ay", "az", "gx", "gy", "gz", "mx", "my", "mz", "mag_valid"},
+	KindPose: {"time", "unix_s", "topic", "roll", "pitch", "yaw"},
+	KindGPS:  {"time", "unix_s", "topic", "lat", "lon", "altitude_m", "speed_knots", "course_deg", "validity", "fix_type", "num_satellites", "hdop"},
+}
+
+// ExportStats counts the rows written per kind and the records skipped
+// because their payload is not an IMU sample, pose or GPS fix.
+type ExportStats struct {
+	Rows    map[string]int
+	Skipped int
+}
+
+// ExportCSV converts a JSONL session into one CSV per record kind (KindIMU,
+// KindPose, KindGPS). open is called once per kind, on its first record, and
+// the header row is written before the first data row.
+func ExportCSV(in io.Reader, open func(kind string) (io.Writer, error)) (ExportStats, error) {
+	stats := ExportStats{Rows: make(map[string]int)}
+	writers := make(map[string]*csv.Writer)
+
+	flushAll := func() error {
+		for kind, w := range writers {
+			w.Flush()
+			if err := w.Error(); err != nil {
+				return fmt.Errorf("%s csv: %w", kind, err)
+			}
+		}
+		return nil
+	}
+
+	r := NewReader(in)
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			flushAll()
+			return stats, err
+		}
+
+		kind, row, ok := csvRow(rec)
+		if !ok {
+			stats.Skipped++
+			continue
+		}
+
+		w, exists := writers[kind]
+		if !exists {
+			out, err := open(kind)
+			if err != nil {
+				flushAll()
+				return stats, err
+			}
+			w = csv.NewWriter(out)
+			writers[kind] = w
+			if err := w.Write(csvHeaders[kind]); err != nil {
+				flushAll()
+				return stats, fmt.Errorf("%s csv: %w", kind, err)
+			}
+		}
+		if err := w.Write(row); err != nil {
+			flushAll()
+			return stats, fmt.Errorf("%s csv: %w", kind, err)
+		}
+		stats.Rows[kind]++
+	}
+
+	return stats, flushAll()
+}
+
+// csvRow classifies a record by the fields present in its payload and
+// formats it as a CSV row.
+func csvRow(rec Record) (kind string, row []string, ok bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Payload, &fields); err != nil {
+		return "", nil, false
+	}
+	has := func(name string) bool {
+		_, ok := fields[name]
+		return ok
+	}
+
+	prefix := []string{
+		rec.Time.Format(time.RFC3339Nano),
+		fmt.Sprintf("%d.%06d", rec.Time.Unix(), rec.Time.Nanosecond()/1000),
+		rec.Topic,
+	}
+
+	switch {
+	case has("ax") && has("gx"):
+		var s imu_raw.IMURaw
+		if err := json.Unmarshal(rec.Payload, &s); err != nil {
+			return "", nil, false
+		}
+		return KindIMU, append(prefix,
+			s.Source,
+			formatInt(s.Ax), formatInt(s.Ay), formatInt(s.Az),
+			formatInt(s.Gx), formatInt(s.Gy), formatInt(s.Gz),
+			formatInt(s.Mx), formatInt(s.My), formatInt(s.Mz),
+			strconv.FormatBool(s.MagValid),
+		), true
+
+	case has("roll") && has("pitch") && has("yaw"):
+		var p orientation.Pose
+		if err := json.Unmarshal(rec.Payload, &p); err != nil {
+			return "", nil, false
+		}
+		return KindPose, append(prefix,
+			formatFloat(p.Roll), formatFloat(p.Pitch), formatFloat(p.Yaw),
+		), true
+
+	case has("lat") && has("lon"):
+		var f gps.Fix
+		if err := json.Unmarshal(rec.Payload, &f); err != nil {
+			return "", nil, false
+		}
+		return KindGPS, append(prefix,
+			formatFloat(f.Latitude), formatFloat(f.Longitude), formatFloat(f.Altitude),
+			formatFloat(f.SpeedKnots), formatFloat(f.CourseDeg), f.Validity,
+			f.FixType, strconv.FormatInt(f.NumSatellites, 10), formatFloat(f.HDOP),
+		), true
+	}
+
+	return "", nil, false
+}
+
+// formatFloat uses plain decimal notation (never exponents) with the fewest
+// digits that round-trip, which spreadsheets and pandas both parse.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func formatInt(v int16) string {
+	return strconv.FormatInt(int64(v), 10)
+}
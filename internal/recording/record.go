@@ -0,0 +1,121 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxLineSize bounds a single JSONL record (satellite lists make GPS records
+// the largest).
+const maxLineSize = 1 << 20
+
+// Record is one line of a recorded session: an MQTT message payload with the
+// time it was captured and the topic it was published on.
+type Record struct {
+	Time    time.Time       `json:"time"` // RFC3339 with sub-second precision
+	Topic   string          `json:"topic"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Reader reads Records from a JSONL session, one per line.
+type Reader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewReader returns a Reader for a JSONL session.
+func NewReader(r io.Reader) *Reader {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), maxLineSize)
+	return &Reader{scanner: s}
+}
+
+// Next returns the next record, skipping blank lines. It returns io.EOF at
+// the end of the session.
+func (r *Reader) Next() (Record, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := strings.TrimSpace(r.scanner.Text())
+		if line == "" {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			return Record{}, fmt.Errorf("line %d: %w", r.line, err)
+		}
+		return rec, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return Record{}, fmt.Errorf("line %d: %w", r.line+1, err)
+	}
+	return Record{}, io.EOF
+}
+
+// Writer writes Records to a JSONL session, one per line. Output is buffered;
+// call Flush before closing the underlying writer.
+type Writer struct {
+	w *bufio.Writer
+}
+
+// NewWriter returns a Writer for a JSONL session.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Write appends one record.
+func (w *Writer) Write(rec Record) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if _, err := w.w.Write(line); err != nil {
+		return err
+	}
+	return w.w.WriteByte('\n')
+}
+
+// Flush writes any buffered records to the underlying writer.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// Summary describes a recorded session.
+type Summary struct {
+	Start   time.Time // time of the first record
+	End     time.Time // time of the last record
+	Records int
+}
+
+// Duration is the time between the first and last record.
+func (s Summary) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Summarize reads a JSONL session to the end and returns its time span and
+// record count.
+func Summarize(r io.Reader) (Summary, error) {
+	var s Summary
+	rr := NewReader(r)
+	for {
+		rec, err := rr.Next()
+		if err == io.EOF {
+			return s, nil
+		}
+		if err != nil {
+			return s, err
+		}
+		if s.Records == 0 {
+			s.Start = rec.Time
+		}
+		s.End = rec.Time
+		s.Records++
+	}
+}
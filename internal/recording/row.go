@@ -0,0 +1,115 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package recording
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/relabs-tech/inertial_computer/internal/env"
+	"github.com/relabs-tech/inertial_computer/internal/gps"
+	imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
+)
+
+// Row is one time-aligned snapshot of every sensor, as written by the
+// logger. A nil field means that sensor had no sample for this row.
+type Row struct {
+	Time     time.Time
+	IMULeft  *imu_raw.IMURaw
+	IMURight *imu_raw.IMURaw
+	BMPLeft  *env.Sample
+	BMPRight *env.Sample
+	GPS      *gps.Fix      // latest fix, repeated until a new one arrives
+	GPSAge   time.Duration // time since GPS was received
+	GPSNew   bool          // GPS arrived since the previous row
+}
+
+// RowCSVWriter writes Rows as a wide CSV, one timestamp per row. Columns of
+// missing sensors are left empty.
+type RowCSVWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+// NewRowCSVWriter returns a RowCSVWriter; the header row is written with the
+// first Row.
+func NewRowCSVWriter(w io.Writer) *RowCSVWriter {
+	return &RowCSVWriter{w: csv.NewWriter(w)}
+}
+
+func rowCSVHeader() []string {
+	h := []string{"time", "unix_s"}
+	for _, side := range []string{"imu_left", "imu_right"} {
+		for _, f := range []string{"ax", "ay", "az", "gx", "gy", "gz", "mx", "my", "mz", "mag_valid"} {
+			h = append(h, side+"_"+f)
+		}
+	}
+	for _, side := range []string{"bmp_left", "bmp_right"} {
+		for _, f := range []string{"temp_c", "pressure_pa", "altitude_m", "humidity_pct"} {
+			h = append(h, side+"_"+f)
+		}
+	}
+	return append(h,
+		"gps_lat", "gps_lon", "gps_altitude_m", "gps_speed_knots", "gps_course_deg",
+		"gps_validity", "gps_fix_type", "gps_num_satellites", "gps_hdop", "gps_age_ms",
+	)
+}
+
+// Write appends one row.
+func (w *RowCSVWriter) Write(r Row) error {
+	if !w.wroteHeader {
+		if err := w.w.Write(rowCSVHeader()); err != nil {
+			return err
+		}
+		w.wroteHeader = true
+	}
+
+	row := []string{
+		r.Time.Format(time.RFC3339Nano),
+		fmt.Sprintf("%d.%06d", r.Time.Unix(), r.Time.Nanosecond()/1000),
+	}
+	for _, s := range []*imu_raw.IMURaw{r.IMULeft, r.IMURight} {
+		if s == nil {
+			row = append(row, make([]string, 10)...)
+			continue
+		}
+		row = append(row,
+			formatInt(s.Ax), formatInt(s.Ay), formatInt(s.Az),
+			formatInt(s.Gx), formatInt(s.Gy), formatInt(s.Gz),
+			formatInt(s.Mx), formatInt(s.My), formatInt(s.Mz),
+			strconv.FormatBool(s.MagValid),
+		)
+	}
+	for _, s := range []*env.Sample{r.BMPLeft, r.BMPRight} {
+		if s == nil {
+			row = append(row, make([]string, 4)...)
+			continue
+		}
+		row = append(row,
+			formatFloat(s.Temperature), formatFloat(s.Pressure),
+			formatFloat(s.Altitude), formatFloat(s.Humidity),
+		)
+	}
+	if f := r.GPS; f == nil {
+		row = append(row, make([]string, 10)...)
+	} else {
+		row = append(row,
+			formatFloat(f.Latitude), formatFloat(f.Longitude), formatFloat(f.Altitude),
+			formatFloat(f.SpeedKnots), formatFloat(f.CourseDeg), f.Validity,
+			f.FixType, strconv.FormatInt(f.NumSatellites, 10), formatFloat(f.HDOP),
+			strconv.FormatInt(r.GPSAge.Milliseconds(), 10),
+		)
+	}
+	return w.w.Write(row)
+}
+
+// Flush writes any buffered rows to the underlying writer.
+func (w *RowCSVWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package sensors
+
+// getAK8963RegisterMap returns metadata for the AK8963 magnetometer registers
+// (accessed through the MPU9250 I2C master at address 0x0C).
+func getAK8963RegisterMap() []RegisterInfo {
+	return []RegisterInfo{
+		// Device Identification
+		{Address: "0x00", Name: "WIA", Description: "Device ID (should be 0x48)", Access: "R", Default: "0x48"},
+		{Address: "0x01", Name: "INFO", Description: "Device Information", Access: "R"},
+
+		// Status and Measurement Data
+		{Address: "0x02", Name: "ST1", Description: "Status 1", Access: "R", Default: "0x00",
+			BitFields: []BitField{
+				{Bits: "1", Name: "DOR", Description: "Data overrun", Values: "0=Normal, 1=Data overrun"},
+				{Bits: "0", Name: "DRDY", Description: "Data ready", Values: "0=Normal, 1=Data ready"},
+			}},
+		{Address: "0x03", Name: "HXL", Description: "Magnetometer X-Axis Low Byte", Access: "R"},
+		{Address: "0x04", Name: "HXH", Description: "Magnetometer X-Axis High Byte", Access: "R"},
+		{Address: "0x05", Name: "HYL", Description: "Magnetometer Y-Axis Low Byte", Access: "R"},
+		{Address: "0x06", Name: "HYH", Description: "Magnetometer Y-Axis High Byte", Access: "R"},
+		{Address: "0x07", Name: "HZL", Description: "Magnetometer Z-Axis Low Byte", Access: "R"},
+		{Address: "0x08", Name: "HZH", Description: "Magnetometer Z-Axis High Byte", Access: "R"},
+		{Address: "0x09", Name: "ST2", Description: "Status 2", Access: "R", Default: "0x00",
+			BitFields: []BitField{
+				{Bits: "4", Name: "BITM", Description: "Output bit setting (mirror)", Values: "0=14-bit, 1=16-bit"},
+				{Bits: "3", Name: "HOFL", Description: "Magnetic sensor overflow", Values: "0=Normal, 1=Overflow"},
+			}},
+
+		// Control Registers
+		{Address: "0x0A", Name: "CNTL1", Description: "Control 1", Access: "RW", Default: "0x00",
+			BitFields: []BitField{
+				{Bits: "4", Name: "BIT", Description: "Output bit setting", Values: "0=14-bit, 1=16-bit"},
+				{Bits: "3:0", Name: "MODE", Description: "Operation mode", Values: "0=Power-down, 1=Single measurement, 2=Continuous 8Hz, 4=External trigger, 6=Continuous 100Hz, 8=Self-test, 15=Fuse ROM access"},
+			}},
+		{Address: "0x0B", Name: "CNTL2", Description: "Control 2", Access: "RW", Default: "0x00",
+			BitFields: []BitField{
+				{Bits: "0", Name: "SRST", Description: "Soft reset", Values: "0=Normal, 1=Reset"},
+			}},
+		{Address: "0x0C", Name: "ASTC", Description: "Self-Test Control", Access: "RW", Default: "0x00",
+			BitFields: []BitField{
+				{Bits: "6", Name: "SELF", Description: "Self-test magnetic field", Values: "0=Normal, 1=Generate field"},
+			}},
+		{Address: "0x0F", Name: "I2CDIS", Description: "I2C Disable (write 0x1B to disable I2C)", Access: "RW", Default: "0x00"},
+
+		// Sensitivity Adjustment (Fuse ROM)
+		{Address: "0x10", Name: "ASAX", Description: "X-Axis Sensitivity Adjustment", Access: "R"},
+		{Address: "0x11", Name: "ASAY", Description: "Y-Axis Sensitivity Adjustment", Access: "R"},
+		{Address: "0x12", Name: "ASAZ", Description: "Z-Axis Sensitivity Adjustment", Access: "R"},
+	}
+}
@@ -7,6 +7,7 @@ package sensors
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,13 +19,34 @@ import (
 	"periph.io/x/host/v3"
 )
 
+// Each BMP is initialized independently: a missing or failing sensor leaves
+// its device nil and records its error, and the other one keeps working.
 var (
-	bmpLeftDev  *bmxx80.Dev
-	bmpRightDev *bmxx80.Dev
-	bmpOnce     sync.Once
-	bmpInitErr  error
+	bmpLeftDev   *bmxx80.Dev
+	bmpRightDev  *bmxx80.Dev
+	bmpOnce      sync.Once
+	bmpLeftErr   error
+	bmpRightErr  error
+	bmpLeftChip  string
+	bmpRightChip string
+	bmpMock      bool // MOCK_HARDWARE: both BMPs are simulated
 )
 
+// Chip names reported by EnvStatus. A BME280 also measures humidity;
+// ChipMock is a simulated sensor (MOCK_HARDWARE).
+const (
+	ChipBMP280 = "BMP280"
+	ChipBME280 = "BME280"
+	ChipMock   = "mock"
+)
+
+// EnvInfo describes one BMP sensor as detected at init.
+type EnvInfo struct {
+	Available bool   `json:"available"`
+	Chip      string `json:"chip,omitempty"`  // ChipBMP280, ChipBME280 or ChipMock
+	Error     string `json:"error,omitempty"` // init error when not available
+}
+
 // standbyTimeToDuration converts standby time config values to time.Duration
 // Based on BMP280 datasheet standby times
 func standbyTimeToDuration(val byte) time.Duration {
@@ -50,101 +72,168 @@ func standbyTimeToDuration(val byte) time.Duration {
 	}
 }
 
-// initBMP initializes both BMP sensors once
+// seaLevelHPa returns the configured sea-level reference pressure for
+// barometric altitude, defaulting to the standard atmosphere (1013.25 hPa).
+func seaLevelHPa() float64 {
+	if p := config.Get().BMPSeaLevelHPa; p > 0 {
+		return p
+	}
+	return 1013.25
+}
+
+// initBMP initializes both BMP sensors once. Failures are per sensor, see
+// IsLeftEnvAvailable/IsRightEnvAvailable.
 func initBMP() {
 	bmpOnce.Do(func() {
 		cfg := config.Get()
 
-		// Initialize periph host
-		if _, err := host.Init(); err != nil {
-			bmpInitErr = fmt.Errorf("periph host init: %w", err)
+		if cfg.MockHardware {
+			bmpMock = true
+			bmpLeftChip, bmpRightChip = ChipMock, ChipMock
+			fmt.Println("MOCK_HARDWARE: using simulated left and right BMPs")
 			return
 		}
 
-		// Initialize left BMP
-		busLeft, err := spireg.Open(cfg.BMPLeftSPIDevice)
-		if err != nil {
-			bmpInitErr = fmt.Errorf("left BMP SPI open: %w", err)
+		// Initialize periph host
+		if _, err := host.Init(); err != nil {
+			bmpLeftErr = fmt.Errorf("periph host init: %w", err)
+			bmpRightErr = bmpLeftErr
+			fmt.Printf("Warning: BMP initialization failed: %v\n", bmpLeftErr)
 			return
 		}
 
-		leftOpts := bmxx80.Opts{
+		// Initialize left BMP
+		bmpLeftDev, bmpLeftErr = openBMP("left", cfg.BMPLeftSPIDevice, bmxx80.Opts{
 			Temperature: bmxx80.Oversampling(cfg.BMPLeftTempOSR),
 			Pressure:    bmxx80.Oversampling(cfg.BMPLeftPressureOSR),
+			Humidity:    bmxx80.O1x, // BME280 only; ignored on a BMP280
 			Filter:      bmxx80.Filter(cfg.BMPLeftIIRFilter),
 			Standby:     standbyTimeToDuration(cfg.BMPLeftStandbyTime),
-		}
-
-		bmpLeftDev, err = bmxx80.NewSPI(busLeft, &leftOpts)
-		if err != nil {
-			bmpInitErr = fmt.Errorf("left BMP init: %w", err)
-			return
+		})
+		if bmpLeftErr != nil {
+			fmt.Printf("Warning: Left BMP initialization failed: %v\n", bmpLeftErr)
+		} else {
+			bmpLeftChip = chipName(bmpLeftDev)
+			fmt.Printf("Left BMP initialized successfully (%s)\n", bmpLeftChip)
 		}
 
 		// Initialize right BMP
-		busRight, err := spireg.Open(cfg.BMPRightSPIDevice)
-		if err != nil {
-			bmpInitErr = fmt.Errorf("right BMP SPI open: %w", err)
-			return
-		}
-
-		rightOpts := bmxx80.Opts{
+		bmpRightDev, bmpRightErr = openBMP("right", cfg.BMPRightSPIDevice, bmxx80.Opts{
 			Temperature: bmxx80.Oversampling(cfg.BMPRightTempOSR),
 			Pressure:    bmxx80.Oversampling(cfg.BMPRightPressureOSR),
+			Humidity:    bmxx80.O1x, // BME280 only; ignored on a BMP280
 			Filter:      bmxx80.Filter(cfg.BMPRightIIRFilter),
 			Standby:     standbyTimeToDuration(cfg.BMPRightStandbyTime),
+		})
+		if bmpRightErr != nil {
+			fmt.Printf("Warning: Right BMP initialization failed: %v\n", bmpRightErr)
+		} else {
+			bmpRightChip = chipName(bmpRightDev)
+			fmt.Printf("Right BMP initialized successfully (%s)\n", bmpRightChip)
 		}
+	})
+}
+
+// openBMP opens the SPI device and initializes one BMP sensor.
+func openBMP(side, device string, opts bmxx80.Opts) (*bmxx80.Dev, error) {
+	bus, err := spireg.Open(device)
+	if err != nil {
+		return nil, fmt.Errorf("%s BMP SPI open: %w", side, err)
+	}
+	dev, err := bmxx80.NewSPI(bus, &opts)
+	if err != nil {
+		bus.Close()
+		return nil, fmt.Errorf("%s BMP init: %w", side, err)
+	}
+	return dev, nil
+}
 
-		bmpRightDev, err = bmxx80.NewSPI(busRight, &rightOpts)
+// chipName returns the chip detected by bmxx80 from the chip id register.
+// The driver reports it as the prefix of its String(), e.g. "BME280{...}".
+func chipName(dev *bmxx80.Dev) string {
+	if strings.HasPrefix(dev.String(), ChipBME280) {
+		return ChipBME280
+	}
+	return ChipBMP280
+}
+
+// EnvStatus returns availability and detected chip for both BMP sensors.
+func EnvStatus() (left, right EnvInfo) {
+	initBMP()
+	if bmpMock {
+		return EnvInfo{Available: true, Chip: ChipMock}, EnvInfo{Available: true, Chip: ChipMock}
+	}
+	return envInfo(bmpLeftDev, bmpLeftChip, bmpLeftErr), envInfo(bmpRightDev, bmpRightChip, bmpRightErr)
+}
+
+func envInfo(dev *bmxx80.Dev, chip string, err error) EnvInfo {
+	if dev == nil {
+		info := EnvInfo{}
 		if err != nil {
-			bmpInitErr = fmt.Errorf("right BMP init: %w", err)
-			return
+			info.Error = err.Error()
 		}
+		return info
+	}
+	return EnvInfo{Available: true, Chip: chip}
+}
 
-		fmt.Println("BMP sensors initialized successfully")
-	})
+// IsLeftEnvAvailable returns true if the left BMP initialized successfully.
+func IsLeftEnvAvailable() bool {
+	initBMP()
+	return bmpLeftDev != nil || bmpMock
+}
+
+// IsRightEnvAvailable returns true if the right BMP initialized successfully.
+func IsRightEnvAvailable() bool {
+	initBMP()
+	return bmpRightDev != nil || bmpMock
 }
 
 // ReadLeftEnv reads the LEFT BMP sensor (temp + pressure).
+// Returns error if the left BMP is not available.
 func ReadLeftEnv() (env.Sample, error) {
 	initBMP()
-	if bmpInitErr != nil {
-		return env.Sample{}, bmpInitErr
+	if bmpMock {
+		return mockEnv("left"), nil
 	}
-
-	var e physic.Env
-	if err := bmpLeftDev.Sense(&e); err != nil {
-		return env.Sample{}, fmt.Errorf("left BMP sense: %w", err)
+	if bmpLeftDev == nil {
+		return env.Sample{}, fmt.Errorf("left BMP not available: %w", bmpLeftErr)
 	}
-
-	pressurePa := float64(e.Pressure) / float64(physic.Pascal)
-	return env.Sample{
-		Source:       "left",
-		Temperature:  e.Temperature.Celsius(),
-		Pressure:     pressurePa,
-		PressureMbar: pressurePa / 100.0, // 1 mbar = 100 Pa
-		PressureHPa:  pressurePa / 100.0, // 1 hPa = 100 Pa (same as mbar)
-	}, nil
+	return readEnv(bmpLeftDev, "left", bmpLeftChip == ChipBME280)
 }
 
 // ReadRightEnv reads the RIGHT BMP sensor (temp + pressure).
+// Returns error if the right BMP is not available.
 func ReadRightEnv() (env.Sample, error) {
 	initBMP()
-	if bmpInitErr != nil {
-		return env.Sample{}, bmpInitErr
+	if bmpMock {
+		return mockEnv("right"), nil
+	}
+	if bmpRightDev == nil {
+		return env.Sample{}, fmt.Errorf("right BMP not available: %w", bmpRightErr)
 	}
+	return readEnv(bmpRightDev, "right", bmpRightChip == ChipBME280)
+}
 
+// readEnv reads one BMP sensor; source is "left" or "right". Humidity is
+// only filled in for a BME280.
+func readEnv(dev *bmxx80.Dev, source string, hasHumidity bool) (env.Sample, error) {
 	var e physic.Env
-	if err := bmpRightDev.Sense(&e); err != nil {
-		return env.Sample{}, fmt.Errorf("right BMP sense: %w", err)
+	if err := dev.Sense(&e); err != nil {
+		return env.Sample{}, fmt.Errorf("%s BMP sense: %w", source, err)
 	}
 
 	pressurePa := float64(e.Pressure) / float64(physic.Pascal)
-	return env.Sample{
-		Source:       "right",
+	s := env.Sample{
+		Source:       source,
 		Temperature:  e.Temperature.Celsius(),
 		Pressure:     pressurePa,
 		PressureMbar: pressurePa / 100.0, // 1 mbar = 100 Pa
 		PressureHPa:  pressurePa / 100.0, // 1 hPa = 100 Pa (same as mbar)
-	}, nil
+		Altitude:     env.PressureToAltitude(pressurePa/100.0, seaLevelHPa()),
+	}
+	if hasHumidity {
+		s.Humidity = float64(e.Humidity) / float64(physic.PercentRH)
+	}
+	return s, nil
 }
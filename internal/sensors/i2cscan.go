@@ -0,0 +1,68 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package sensors
+
+import (
+	"fmt"
+
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/host/v3"
+)
+
+// I2C address range probed by ScanI2C (7-bit, excluding reserved addresses)
+const (
+	i2cScanFirstAddr = 0x03
+	i2cScanLastAddr  = 0x77
+)
+
+// I2CDevice is an address that acknowledged during a bus scan.
+type I2CDevice struct {
+	Addr  uint16 // 7-bit I2C address
+	Guess string // likely device type based on well-known addresses ("" if unknown)
+}
+
+// GuessI2CDevice returns the likely device type for a well-known I2C address.
+func GuessI2CDevice(addr uint16) string {
+	switch addr {
+	case 0x3C, 0x3D:
+		return "SSD1306 OLED"
+	case 0x0C:
+		return "AK8963 magnetometer"
+	case 0x1E:
+		return "HMC5983 magnetometer"
+	case 0x68, 0x69:
+		return "MPU9250 IMU"
+	case 0x76, 0x77:
+		return "BMP280/BME280"
+	default:
+		return ""
+	}
+}
+
+// ScanI2C opens the given I2C bus ("" for the first available bus) and probes
+// every address from 0x03 to 0x77 with a 1-byte read, returning the addresses
+// that acknowledged.
+func ScanI2C(busName string) ([]I2CDevice, error) {
+	if _, err := host.Init(); err != nil {
+		return nil, fmt.Errorf("periph host init failed: %w", err)
+	}
+
+	bus, err := i2creg.Open(busName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open I2C bus %q: %w", busName, err)
+	}
+	defer bus.Close()
+
+	var found []I2CDevice
+	buf := make([]byte, 1)
+	for addr := uint16(i2cScanFirstAddr); addr <= i2cScanLastAddr; addr++ {
+		if err := bus.Tx(addr, nil, buf); err != nil {
+			continue // no ACK
+		}
+		found = append(found, I2CDevice{Addr: addr, Guess: GuessI2CDevice(addr)})
+	}
+
+	return found, nil
+}
@@ -5,19 +5,45 @@
 package sensors
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"sync"
+	"time"
 
+	"github.com/relabs-tech/inertial_computer/internal/config"
 	imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
 )
 
 // IMUManager manages persistent left and right IMU sensor instances.
 // It initializes hardware once and provides thread-safe access to both IMUs.
+//
+// mu guards the manager state (initialization and the IMU instances), while
+// leftDevMu/rightDevMu serialize SPI transactions per device so that ReadRaw
+// (including the magnetometer read) and register access from the producer,
+// web and calibration handlers never interleave on the same bus/CS. Reads of
+// the left and right IMU may still run in parallel since they use different
+// chip selects (and typically different SPI buses).
 type IMUManager struct {
 	leftIMU     IMURawReader
 	rightIMU    IMURawReader
 	mu          sync.RWMutex
 	initialized bool
+
+	leftDevMu  sync.Mutex
+	rightDevMu sync.Mutex
+
+	// Read/error counters and rates, see Metrics()
+	metrics imuMetricsState
+
+	// Shared reader goroutines, see StreamLeft()/StreamRight()
+	leftStream  imuStream
+	rightStream imuStream
+
+	// Active calibration per IMU (nil = raw), applied by ReadLeftIMU and
+	// ReadRightIMU; guarded by mu, see SetCalibration()
+	leftCal  *imu_raw.Calibration
+	rightCal *imu_raw.Calibration
 }
 
 var (
@@ -33,9 +59,10 @@ func GetIMUManager() *IMUManager {
 	return defaultManager
 }
 
-// Init initializes both left and right IMU sensors.
-// This should be called once at application startup.
-// Returns error if both IMUs fail to initialize, but allows partial success.
+// Init initializes the left and right IMU sensors enabled in the config
+// (IMU_LEFT_ENABLED/IMU_RIGHT_ENABLED); a disabled IMU is left nil, as if
+// unavailable. This should be called once at application startup.
+// Returns error if all enabled IMUs fail to initialize, but allows partial success.
 func (m *IMUManager) Init() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -44,61 +71,174 @@ func (m *IMUManager) Init() error {
 		return nil
 	}
 
+	cfg := config.Get()
+	if cfg.MockHardware {
+		if cfg.IMULeftEnabled {
+			m.leftIMU = newMockIMU("left")
+		}
+		if cfg.IMURightEnabled {
+			m.rightIMU = newMockIMU("right")
+		}
+		fmt.Println("MOCK_HARDWARE: using simulated IMUs")
+		m.initialized = true
+		m.metrics.start = time.Now()
+		return nil
+	}
+
 	var leftErr, rightErr error
 
 	// Initialize left IMU
-	m.leftIMU, leftErr = NewIMUSourceLeft()
-	if leftErr != nil {
-		fmt.Printf("Warning: Left IMU initialization failed: %v\n", leftErr)
-	} else {
-		fmt.Println("Left IMU initialized successfully")
+	if cfg.IMULeftEnabled {
+		m.leftIMU, leftErr = NewIMUSourceLeft()
+		if leftErr != nil {
+			fmt.Printf("Warning: Left IMU initialization failed: %v\n", leftErr)
+		} else {
+			fmt.Println("Left IMU initialized successfully")
+		}
 	}
 
 	// Initialize right IMU
-	m.rightIMU, rightErr = NewIMUSourceRight()
-	if rightErr != nil {
-		fmt.Printf("Warning: Right IMU initialization failed: %v\n", rightErr)
-	} else {
-		fmt.Println("Right IMU initialized successfully")
+	if cfg.IMURightEnabled {
+		m.rightIMU, rightErr = NewIMUSourceRight()
+		if rightErr != nil {
+			fmt.Printf("Warning: Right IMU initialization failed: %v\n", rightErr)
+		} else {
+			fmt.Println("Right IMU initialized successfully")
+		}
 	}
 
-	// Fail only if both IMUs failed
-	if leftErr != nil && rightErr != nil {
-		return fmt.Errorf("both IMUs failed to initialize: left=%v, right=%v", leftErr, rightErr)
+	// Fail only if every enabled IMU failed
+	switch {
+	case cfg.IMULeftEnabled && cfg.IMURightEnabled:
+		if leftErr != nil && rightErr != nil {
+			return fmt.Errorf("both IMUs failed to initialize: left=%v, right=%v", leftErr, rightErr)
+		}
+	case cfg.IMULeftEnabled && leftErr != nil:
+		return fmt.Errorf("left IMU failed to initialize (right IMU disabled): %w", leftErr)
+	case cfg.IMURightEnabled && rightErr != nil:
+		return fmt.Errorf("right IMU failed to initialize (left IMU disabled): %w", rightErr)
 	}
 
 	m.initialized = true
+	m.metrics.start = time.Now()
 	return nil
 }
 
-// ReadLeftIMU reads raw data from the left IMU sensor.
+// ReadLeftIMU reads data from the left IMU sensor, corrected by the active
+// calibration if one is set.
 // Returns error if left IMU is not available.
 func (m *IMUManager) ReadLeftIMU() (imu_raw.IMURaw, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	raw, err := m.readLeftLocked()
+	if err == nil && m.leftCal != nil {
+		raw = m.leftCal.Apply(raw)
+	}
+	return raw, err
+}
+
+// readLeftLocked reads the left IMU without calibration. The caller must
+// hold mu (read or write).
+func (m *IMUManager) readLeftLocked() (imu_raw.IMURaw, error) {
 	if !m.initialized {
 		return imu_raw.IMURaw{}, fmt.Errorf("IMU manager not initialized")
 	}
 	if m.leftIMU == nil {
 		return imu_raw.IMURaw{}, fmt.Errorf("left IMU not available")
 	}
-	return m.leftIMU.ReadRaw()
+	m.leftDevMu.Lock()
+	raw, err := m.leftIMU.ReadRaw()
+	m.leftDevMu.Unlock()
+	m.metrics.left.record(err)
+	return raw, err
 }
 
-// ReadRightIMU reads raw data from the right IMU sensor.
+// ReadRightIMU reads data from the right IMU sensor, corrected by the active
+// calibration if one is set.
 // Returns error if right IMU is not available.
 func (m *IMUManager) ReadRightIMU() (imu_raw.IMURaw, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	raw, err := m.readRightLocked()
+	if err == nil && m.rightCal != nil {
+		raw = m.rightCal.Apply(raw)
+	}
+	return raw, err
+}
+
+// readRightLocked reads the right IMU without calibration. The caller must
+// hold mu (read or write).
+func (m *IMUManager) readRightLocked() (imu_raw.IMURaw, error) {
 	if !m.initialized {
 		return imu_raw.IMURaw{}, fmt.Errorf("IMU manager not initialized")
 	}
 	if m.rightIMU == nil {
 		return imu_raw.IMURaw{}, fmt.Errorf("right IMU not available")
 	}
-	return m.rightIMU.ReadRaw()
+	m.rightDevMu.Lock()
+	raw, err := m.rightIMU.ReadRaw()
+	m.rightDevMu.Unlock()
+	m.metrics.right.record(err)
+	return raw, err
+}
+
+// ReadIMUUncalibrated reads the specified IMU ("left" or "right") ignoring
+// the active calibration, e.g. to compute a new one.
+func (m *IMUManager) ReadIMUUncalibrated(imuID string) (imu_raw.IMURaw, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	switch imuID {
+	case "left":
+		return m.readLeftLocked()
+	case "right":
+		return m.readRightLocked()
+	}
+	return imu_raw.IMURaw{}, fmt.Errorf("invalid IMU ID: %s (must be 'left' or 'right')", imuID)
+}
+
+// SetCalibration makes c the active calibration of the specified IMU ("left"
+// or "right"); nil reverts to raw readings. c must be valid and for the same
+// IMU. Reads in progress finish with the previous calibration.
+func (m *IMUManager) SetCalibration(imuID string, c *imu_raw.Calibration) error {
+	if c != nil {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+		if c.IMU != imuID {
+			return fmt.Errorf("calibration is for the %s IMU, not %s", c.IMU, imuID)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch imuID {
+	case "left":
+		m.leftCal = c
+	case "right":
+		m.rightCal = c
+	default:
+		return fmt.Errorf("invalid IMU ID: %s (must be 'left' or 'right')", imuID)
+	}
+	return nil
+}
+
+// Calibration returns the active calibration of the specified IMU, or nil if
+// readings are raw.
+func (m *IMUManager) Calibration(imuID string) *imu_raw.Calibration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	switch imuID {
+	case "left":
+		return m.leftCal
+	case "right":
+		return m.rightCal
+	}
+	return nil
 }
 
 // IsLeftIMUAvailable returns true if the left IMU is initialized and available.
@@ -115,33 +255,103 @@ func (m *IMUManager) IsRightIMUAvailable() bool {
 	return m.initialized && m.rightIMU != nil
 }
 
+// registerDevice is register-level access to one IMU: the MPU9250 driver of
+// a hardware IMU, or a test fake.
+type registerDevice interface {
+	ReadRegister(regAddr byte) (byte, error)
+	WriteRegister(regAddr byte, value byte) error
+}
+
+// registerDeviceLocked returns the register access and device mutex of the
+// specified IMU ("left" or "right"). The caller must hold mu (read or write).
+func (m *IMUManager) registerDeviceLocked(imuID string) (registerDevice, *sync.Mutex, error) {
+	if !m.initialized {
+		return nil, nil, fmt.Errorf("IMU manager not initialized")
+	}
+
+	var reader IMURawReader
+	var devMu *sync.Mutex
+	switch imuID {
+	case "left":
+		reader, devMu = m.leftIMU, &m.leftDevMu
+	case "right":
+		reader, devMu = m.rightIMU, &m.rightDevMu
+	default:
+		return nil, nil, fmt.Errorf("invalid IMU ID: %s (must be 'left' or 'right')", imuID)
+	}
+	if reader == nil {
+		return nil, nil, fmt.Errorf("%s IMU not available", imuID)
+	}
+
+	switch dev := reader.(type) {
+	case *imuSource:
+		return dev.imu, devMu, nil
+	case registerDevice:
+		return dev, devMu, nil
+	}
+	return nil, nil, errSimulatedIMU(imuID)
+}
+
 // ReadRegister reads a single register from the specified IMU.
 // imuID should be "left" or "right".
 func (m *IMUManager) ReadRegister(imuID string, regAddr byte) (byte, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	dev, devMu, err := m.registerDeviceLocked(imuID)
+	if err != nil {
+		return 0, err
+	}
+
+	devMu.Lock()
+	defer devMu.Unlock()
+	return dev.ReadRegister(regAddr)
+}
+
+// ReadMagRegister reads a single AK8963 register from the specified IMU
+// through the MPU9250 I2C master, the same path as the per-sample ST1 read.
+// imuID should be "left" or "right".
+func (m *IMUManager) ReadMagRegister(imuID string, regAddr byte) (byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	if !m.initialized {
 		return 0, fmt.Errorf("IMU manager not initialized")
 	}
 
 	var imuSrc *imuSource
+	var devMu *sync.Mutex
 	switch imuID {
 	case "left":
 		if m.leftIMU == nil {
 			return 0, fmt.Errorf("left IMU not available")
 		}
-		imuSrc = m.leftIMU.(*imuSource)
+		src, ok := m.leftIMU.(*imuSource)
+		if !ok {
+			return 0, errSimulatedIMU("left")
+		}
+		imuSrc = src
+		devMu = &m.leftDevMu
 	case "right":
 		if m.rightIMU == nil {
 			return 0, fmt.Errorf("right IMU not available")
 		}
-		imuSrc = m.rightIMU.(*imuSource)
+		src, ok := m.rightIMU.(*imuSource)
+		if !ok {
+			return 0, errSimulatedIMU("right")
+		}
+		imuSrc = src
+		devMu = &m.rightDevMu
 	default:
 		return 0, fmt.Errorf("invalid IMU ID: %s (must be 'left' or 'right')", imuID)
 	}
+	if !imuSrc.magReady {
+		return 0, fmt.Errorf("%s IMU magnetometer not initialized", imuID)
+	}
 
-	return imuSrc.imu.ReadRegister(regAddr)
+	devMu.Lock()
+	defer devMu.Unlock()
+	return imuSrc.readMagRegister(regAddr)
 }
 
 // WriteRegister writes a single register to the specified IMU.
@@ -150,95 +360,215 @@ func (m *IMUManager) WriteRegister(imuID string, regAddr byte, value byte) error
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	dev, devMu, err := m.registerDeviceLocked(imuID)
+	if err != nil {
+		return err
+	}
+
+	devMu.Lock()
+	defer devMu.Unlock()
+	return dev.WriteRegister(regAddr, value)
+}
+
+// WriteRegisterField performs a read-modify-write of one bitfield in a
+// register of the specified IMU, preserving all other bits. The read and write
+// happen under the device lock so no other transaction can interleave.
+// Returns the register value before and after the write.
+func (m *IMUManager) WriteRegisterField(imuID string, regAddr byte, field string, fieldValue uint64) (oldValue, newValue byte, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dev, devMu, err := m.registerDeviceLocked(imuID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	devMu.Lock()
+	defer devMu.Unlock()
+
+	oldValue, err = dev.ReadRegister(regAddr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("read register 0x%02X: %w", regAddr, err)
+	}
+	newValue, err = SetBitField(DeviceMPU9250, regAddr, field, oldValue, fieldValue)
+	if err != nil {
+		return oldValue, 0, err
+	}
+	if err := dev.WriteRegister(regAddr, newValue); err != nil {
+		return oldValue, 0, fmt.Errorf("write register 0x%02X: %w", regAddr, err)
+	}
+	return oldValue, newValue, nil
+}
+
+// ReadAllRegisters reads all MPU9250 registers (0x00-0x7F) from the specified
+// IMU, except FIFO_R_W: reading it would pop a byte off the FIFO.
+func (m *IMUManager) ReadAllRegisters(imuID string) (map[byte]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	dev, devMu, err := m.registerDeviceLocked(imuID)
+	if err != nil {
+		return nil, err
+	}
+
+	devMu.Lock()
+	defer devMu.Unlock()
+
+	registers := make(map[byte]byte)
+	for addr := byte(0x00); addr <= 0x7F; addr++ {
+		if addr == regFIFORW {
+			continue
+		}
+		value, err := dev.ReadRegister(addr)
+		if err != nil {
+			return nil, fmt.Errorf("error reading register 0x%02X: %w", addr, err)
+		}
+		registers[addr] = value
+	}
+
+	return registers, nil
+}
+
+// DeviceIDs returns the MPU9250 WHO_AM_I and AK8963 WIA values read when the
+// specified IMU was initialized, and whether the magnetometer is in use.
+func (m *IMUManager) DeviceIDs(imuID string) (whoAmI, magWIA byte, magReady bool, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	if !m.initialized {
-		return fmt.Errorf("IMU manager not initialized")
+		return 0, 0, false, fmt.Errorf("IMU manager not initialized")
 	}
 
 	var imuSrc *imuSource
 	switch imuID {
 	case "left":
 		if m.leftIMU == nil {
-			return fmt.Errorf("left IMU not available")
+			return 0, 0, false, fmt.Errorf("left IMU not available")
+		}
+		src, ok := m.leftIMU.(*imuSource)
+		if !ok {
+			return 0, 0, false, errSimulatedIMU("left")
 		}
-		imuSrc = m.leftIMU.(*imuSource)
+		imuSrc = src
 	case "right":
 		if m.rightIMU == nil {
-			return fmt.Errorf("right IMU not available")
+			return 0, 0, false, fmt.Errorf("right IMU not available")
+		}
+		src, ok := m.rightIMU.(*imuSource)
+		if !ok {
+			return 0, 0, false, errSimulatedIMU("right")
 		}
-		imuSrc = m.rightIMU.(*imuSource)
+		imuSrc = src
 	default:
-		return fmt.Errorf("invalid IMU ID: %s (must be 'left' or 'right')", imuID)
+		return 0, 0, false, fmt.Errorf("invalid IMU ID: %s (must be 'left' or 'right')", imuID)
 	}
 
-	return imuSrc.imu.WriteRegister(regAddr, value)
+	return imuSrc.whoAmI, imuSrc.magWIA, imuSrc.magReady, nil
 }
 
-// ReadAllRegisters reads all MPU9250 registers (0x00-0x7F) from the specified IMU.
-func (m *IMUManager) ReadAllRegisters(imuID string) (map[byte]byte, error) {
+// MagSettings returns the AK8963 CNTL1 mode and resolution (0=14-bit,
+// 1=16-bit) applied when the specified IMU was initialized.
+func (m *IMUManager) MagSettings(imuID string) (mode, scale byte, err error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
 	if !m.initialized {
-		return nil, fmt.Errorf("IMU manager not initialized")
+		return 0, 0, fmt.Errorf("IMU manager not initialized")
 	}
 
 	var imuSrc *imuSource
 	switch imuID {
 	case "left":
 		if m.leftIMU == nil {
-			return nil, fmt.Errorf("left IMU not available")
+			return 0, 0, fmt.Errorf("left IMU not available")
+		}
+		src, ok := m.leftIMU.(*imuSource)
+		if !ok {
+			return 0, 0, errSimulatedIMU("left")
 		}
-		imuSrc = m.leftIMU.(*imuSource)
+		imuSrc = src
 	case "right":
 		if m.rightIMU == nil {
-			return nil, fmt.Errorf("right IMU not available")
+			return 0, 0, fmt.Errorf("right IMU not available")
 		}
-		imuSrc = m.rightIMU.(*imuSource)
-	default:
-		return nil, fmt.Errorf("invalid IMU ID: %s (must be 'left' or 'right')", imuID)
-	}
-
-	registers := make(map[byte]byte)
-	for addr := byte(0x00); addr <= 0x7F; addr++ {
-		value, err := imuSrc.imu.ReadRegister(addr)
-		if err != nil {
-			return nil, fmt.Errorf("error reading register 0x%02X: %w", addr, err)
+		src, ok := m.rightIMU.(*imuSource)
+		if !ok {
+			return 0, 0, errSimulatedIMU("right")
 		}
-		registers[addr] = value
+		imuSrc = src
+	default:
+		return 0, 0, fmt.Errorf("invalid IMU ID: %s (must be 'left' or 'right')", imuID)
 	}
 
-	return registers, nil
+	return imuSrc.magMode, imuSrc.magScale, nil
 }
 
-// SetSPISpeed sets the SPI read and write speeds for the specified IMU.
+// MPU9250 SPI clock limits (datasheet §6.5): every register may be accessed
+// at up to 1 MHz, but only the sensor and interrupt registers may be read at
+// up to 20 MHz. Faster clocks on other registers return corrupted data.
+const (
+	MPU9250SPIRegisterMaxHz   = 1000000
+	MPU9250SPISensorReadMaxHz = 20000000
+)
+
+// spiTransportHz is the clock mpu9250.NewSpiTransport connects at, for both
+// reads and writes.
+const spiTransportHz = 1000000
+
+// ErrSPISpeedUnsupported is returned by SetSPISpeed: the mpu9250 driver
+// cannot change the SPI clock of an open transport.
+var ErrSPISpeedUnsupported = errors.New("SPI speed control not yet implemented in the mpu9250 driver")
+
+// SetSPISpeed sets the SPI read and write speeds for the specified IMU and
+// returns the effective (clamped) speeds.
+//
+// The read speed is used for every read, including register reads (device
+// identification, configuration, the AK8963 via the I2C master), so it is
+// clamped to MPU9250SPIRegisterMaxHz like the write speed; the 20 MHz sensor
+// data limit would need a separate clock for burst sensor reads. Clamping is
+// logged as a warning. The effective speeds are returned even if applying
+// them fails; until the driver supports it, applying always fails with
+// ErrSPISpeedUnsupported and GetSPISpeed keeps reporting the transport clock.
 // TODO: Implement SPI speed control in mpu9250 driver
-func (m *IMUManager) SetSPISpeed(imuID string, readSpeed, writeSpeed int64) error {
+func (m *IMUManager) SetSPISpeed(imuID string, readSpeed, writeSpeed int64) (effectiveRead, effectiveWrite int64, err error) {
+	effectiveRead, effectiveWrite = readSpeed, writeSpeed
+	if effectiveRead > MPU9250SPIRegisterMaxHz {
+		log.Printf("Warning: %s IMU: SPI read speed %d Hz exceeds the MPU9250 register limit of %d Hz (only sensor data tolerates up to %d Hz), clamping",
+			imuID, readSpeed, MPU9250SPIRegisterMaxHz, MPU9250SPISensorReadMaxHz)
+		effectiveRead = MPU9250SPIRegisterMaxHz
+	}
+	if effectiveWrite > MPU9250SPIRegisterMaxHz {
+		log.Printf("Warning: %s IMU: SPI write speed %d Hz exceeds the MPU9250 register limit of %d Hz, clamping",
+			imuID, writeSpeed, MPU9250SPIRegisterMaxHz)
+		effectiveWrite = MPU9250SPIRegisterMaxHz
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	if !m.initialized {
-		return fmt.Errorf("IMU manager not initialized")
+		return effectiveRead, effectiveWrite, fmt.Errorf("IMU manager not initialized")
 	}
 
 	switch imuID {
 	case "left":
 		if m.leftIMU == nil {
-			return fmt.Errorf("left IMU not available")
+			return effectiveRead, effectiveWrite, fmt.Errorf("left IMU not available")
 		}
 	case "right":
 		if m.rightIMU == nil {
-			return fmt.Errorf("right IMU not available")
+			return effectiveRead, effectiveWrite, fmt.Errorf("right IMU not available")
 		}
 	default:
-		return fmt.Errorf("invalid IMU ID: %s (must be 'left' or 'right')", imuID)
+		return effectiveRead, effectiveWrite, fmt.Errorf("invalid IMU ID: %s (must be 'left' or 'right')", imuID)
 	}
 
 	// TODO: Call driver method when implemented
-	return fmt.Errorf("SPI speed control not yet implemented")
+	return effectiveRead, effectiveWrite, ErrSPISpeedUnsupported
 }
 
-// GetSPISpeed gets the current SPI read and write speeds for the specified IMU.
-// TODO: Implement SPI speed query in mpu9250 driver
+// GetSPISpeed gets the current SPI read and write speeds for the specified
+// IMU: the fixed clock of the mpu9250 SPI transport.
 func (m *IMUManager) GetSPISpeed(imuID string) (readSpeed, writeSpeed int64, err error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -260,8 +590,7 @@ func (m *IMUManager) GetSPISpeed(imuID string) (readSpeed, writeSpeed int64, err
 		return 0, 0, fmt.Errorf("invalid IMU ID: %s (must be 'left' or 'right')", imuID)
 	}
 
-	// TODO: Call driver method when implemented
-	return 0, 0, fmt.Errorf("SPI speed query not yet implemented")
+	return spiTransportHz, spiTransportHz, nil
 }
 
 // GetRegisterMap returns metadata for all MPU9250 registers.
@@ -269,7 +598,10 @@ func (m *IMUManager) GetRegisterMap() []RegisterInfo {
 	return getMPU9250RegisterMap()
 }
 
-// ReinitializeIMU closes and reopens the SPI connection for the specified IMU.
+// ReinitializeIMU reopens and reconfigures the specified IMU; with
+// MOCK_HARDWARE it is replaced by a fresh simulated IMU. The mpu9250 SPI
+// transport keeps no handle to close, so the old SPI connection of a real
+// IMU is only dropped, not closed.
 func (m *IMUManager) ReinitializeIMU(imuID string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -278,13 +610,19 @@ func (m *IMUManager) ReinitializeIMU(imuID string) error {
 		return fmt.Errorf("IMU manager not initialized")
 	}
 
+	newLeft, newRight := NewIMUSourceLeft, NewIMUSourceRight
+	if config.Get().MockHardware {
+		newLeft = func() (IMURawReader, error) { return newMockIMU("left"), nil }
+		newRight = func() (IMURawReader, error) { return newMockIMU("right"), nil }
+	}
+
 	switch imuID {
 	case "left":
 		if m.leftIMU == nil {
 			return fmt.Errorf("left IMU not available")
 		}
 		// Reinitialize left IMU
-		newIMU, err := NewIMUSourceLeft()
+		newIMU, err := newLeft()
 		if err != nil {
 			return fmt.Errorf("failed to reinitialize left IMU: %w", err)
 		}
@@ -297,7 +635,7 @@ func (m *IMUManager) ReinitializeIMU(imuID string) error {
 			return fmt.Errorf("right IMU not available")
 		}
 		// Reinitialize right IMU
-		newIMU, err := NewIMUSourceRight()
+		newIMU, err := newRight()
 		if err != nil {
 			return fmt.Errorf("failed to reinitialize right IMU: %w", err)
 		}
@@ -326,12 +664,20 @@ func (m *IMUManager) ApplyRegisterConfig(imuID, configFile string) error {
 		if m.leftIMU == nil {
 			return fmt.Errorf("left IMU not available")
 		}
-		imuSrc = m.leftIMU.(*imuSource)
+		src, ok := m.leftIMU.(*imuSource)
+		if !ok {
+			return errSimulatedIMU("left")
+		}
+		imuSrc = src
 	case "right":
 		if m.rightIMU == nil {
 			return fmt.Errorf("right IMU not available")
 		}
-		imuSrc = m.rightIMU.(*imuSource)
+		src, ok := m.rightIMU.(*imuSource)
+		if !ok {
+			return errSimulatedIMU("right")
+		}
+		imuSrc = src
 	default:
 		return fmt.Errorf("invalid IMU ID: %s (must be 'left' or 'right')", imuID)
 	}
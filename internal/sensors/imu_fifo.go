@@ -0,0 +1,307 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package sensors
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
+)
+
+// MPU9250 FIFO registers. The chip buffers up to fifoSize bytes of samples at
+// the sample rate; each sample is the enabled outputs in register order
+// (accel, temperature, gyro X/Y/Z, then external I2C slave data).
+const (
+	regSmplrtDiv  = 0x19 // SMPLRT_DIV
+	regConfig     = 0x1A // CONFIG (DLPF_CFG bits 2:0)
+	regGyroConfig = 0x1B // GYRO_CONFIG (FCHOICE_B bits 1:0)
+	regFIFOEn     = 0x23 // FIFO_EN
+	regI2CSlv0Ctl = 0x27 // I2C_SLV0_CTRL; SLV1/SLV2 follow every 3 registers
+	regIntStatus  = 0x3A // INT_STATUS
+	regUserCtrl   = 0x6A // USER_CTRL
+	regFIFOCountH = 0x72 // FIFO_COUNTH (bits 4:0)
+	regFIFOCountL = 0x73 // FIFO_COUNTL
+	regFIFORW     = 0x74 // FIFO_R_W
+
+	fifoEnTemp  = 0x80
+	fifoEnGyroX = 0x40
+	fifoEnGyroY = 0x20
+	fifoEnGyroZ = 0x10
+	fifoEnAccel = 0x08
+
+	// FIFOSampleOutputs is what EnableFIFO buffers: accel, temperature and gyro
+	// (14 bytes per sample). The magnetometer is read by the driver, not the
+	// I2C master slaves, so it is not in the FIFO.
+	FIFOSampleOutputs = fifoEnTemp | fifoEnGyroX | fifoEnGyroY | fifoEnGyroZ | fifoEnAccel
+
+	userCtrlFIFOEn  = 0x40
+	userCtrlFIFORst = 0x04
+
+	intStatusFIFOOverflow = 0x10 // FIFO_OVERFLOW_INT
+
+	fifoSize = 512
+)
+
+// ErrFIFOOverflow is returned by ReadFIFO when the FIFO filled up and
+// overwrote samples. The FIFO is reset, since its sample boundaries are lost.
+var ErrFIFOOverflow = errors.New("FIFO overflow: samples were lost and the FIFO was reset")
+
+// fifoLayout describes one FIFO sample as configured by FIFO_EN.
+type fifoLayout struct {
+	accel, temp, gyroX, gyroY, gyroZ bool
+	slaveBytes                       int // external sensor data, skipped
+}
+
+func newFIFOLayout(fifoEn byte, slaveLens [3]byte) fifoLayout {
+	l := fifoLayout{
+		accel: fifoEn&fifoEnAccel != 0,
+		temp:  fifoEn&fifoEnTemp != 0,
+		gyroX: fifoEn&fifoEnGyroX != 0,
+		gyroY: fifoEn&fifoEnGyroY != 0,
+		gyroZ: fifoEn&fifoEnGyroZ != 0,
+	}
+	for i, n := range slaveLens {
+		if fifoEn&(1<<i) != 0 {
+			l.slaveBytes += int(n & 0x0F)
+		}
+	}
+	return l
+}
+
+// size is the number of bytes per sample.
+func (l fifoLayout) size() int {
+	n := l.slaveBytes
+	for _, on := range []bool{l.gyroX, l.gyroY, l.gyroZ} {
+		if on {
+			n += 2
+		}
+	}
+	if l.accel {
+		n += 6
+	}
+	if l.temp {
+		n += 2
+	}
+	return n
+}
+
+// decode splits data into samples (a trailing partial sample is ignored).
+// Outputs not in the layout stay zero; the magnetometer is never valid.
+func (l fifoLayout) decode(data []byte) []imu_raw.IMURaw {
+	size := l.size()
+	if size == 0 {
+		return nil
+	}
+	word := func(b []byte, i int) int16 { return int16(uint16(b[i])<<8 | uint16(b[i+1])) }
+
+	samples := make([]imu_raw.IMURaw, 0, len(data)/size)
+	for off := 0; off+size <= len(data); off += size {
+		b := data[off : off+size]
+		var r imu_raw.IMURaw
+		i := 0
+		if l.accel {
+			r.Ax, r.Ay, r.Az = word(b, 0), word(b, 2), word(b, 4)
+			i += 6
+		}
+		if l.temp {
+			r.Temp, r.TempValid = word(b, i), true
+			i += 2
+		}
+		if l.gyroX {
+			r.Gx = word(b, i)
+			i += 2
+		}
+		if l.gyroY {
+			r.Gy = word(b, i)
+			i += 2
+		}
+		if l.gyroZ {
+			r.Gz = word(b, i)
+		}
+		samples = append(samples, r)
+	}
+	return samples
+}
+
+// fifoSampleRate returns the rate samples enter the FIFO: the internal rate
+// (32 kHz with the gyro DLPF bypassed, 8 kHz with DLPF_CFG 0 or 7, else 1 kHz)
+// divided by 1+SMPLRT_DIV, which only applies at 1 kHz.
+func fifoSampleRate(smplrtDiv, config, gyroConfig byte) float64 {
+	switch dlpf := config & 0x07; {
+	case gyroConfig&0x03 != 0:
+		return 32000
+	case dlpf == 0 || dlpf == 7:
+		return 8000
+	default:
+		return 1000 / (1 + float64(smplrtDiv))
+	}
+}
+
+// hardwareIMU returns the hardware source of imuID and its device mutex.
+// m.mu must be held.
+func (m *IMUManager) hardwareIMU(imuID string) (*imuSource, *sync.Mutex, error) {
+	if !m.initialized {
+		return nil, nil, fmt.Errorf("IMU manager not initialized")
+	}
+	var reader IMURawReader
+	var devMu *sync.Mutex
+	switch imuID {
+	case "left":
+		reader, devMu = m.leftIMU, &m.leftDevMu
+	case "right":
+		reader, devMu = m.rightIMU, &m.rightDevMu
+	default:
+		return nil, nil, fmt.Errorf("invalid IMU ID: %s (must be 'left' or 'right')", imuID)
+	}
+	if reader == nil {
+		return nil, nil, fmt.Errorf("%s IMU not available", imuID)
+	}
+	src, ok := reader.(*imuSource)
+	if !ok {
+		return nil, nil, errSimulatedIMU(imuID)
+	}
+	return src, devMu, nil
+}
+
+// EnableFIFO starts buffering FIFOSampleOutputs in the FIFO of the specified
+// IMU (after a reset), or stops it. Other USER_CTRL bits, such as the I2C
+// master used for the magnetometer, are preserved.
+func (m *IMUManager) EnableFIFO(imuID string, enable bool) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	src, devMu, err := m.hardwareIMU(imuID)
+	if err != nil {
+		return err
+	}
+	devMu.Lock()
+	defer devMu.Unlock()
+
+	userCtrl, err := src.imu.ReadRegister(regUserCtrl)
+	if err != nil {
+		return fmt.Errorf("%s IMU: read USER_CTRL: %w", imuID, err)
+	}
+	if !enable {
+		if err := src.imu.WriteRegister(regUserCtrl, userCtrl&^userCtrlFIFOEn); err != nil {
+			return fmt.Errorf("%s IMU: write USER_CTRL: %w", imuID, err)
+		}
+		return src.imu.WriteRegister(regFIFOEn, 0)
+	}
+	if err := src.imu.WriteRegister(regFIFOEn, FIFOSampleOutputs); err != nil {
+		return fmt.Errorf("%s IMU: write FIFO_EN: %w", imuID, err)
+	}
+	if err := src.imu.WriteRegister(regUserCtrl, userCtrl|userCtrlFIFOEn|userCtrlFIFORst); err != nil {
+		return fmt.Errorf("%s IMU: write USER_CTRL: %w", imuID, err)
+	}
+	return nil
+}
+
+// ResetFIFO discards the contents of the specified IMU's FIFO.
+func (m *IMUManager) ResetFIFO(imuID string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	src, devMu, err := m.hardwareIMU(imuID)
+	if err != nil {
+		return err
+	}
+	devMu.Lock()
+	defer devMu.Unlock()
+	return src.resetFIFO()
+}
+
+func (s *imuSource) resetFIFO() error {
+	userCtrl, err := s.imu.ReadRegister(regUserCtrl)
+	if err != nil {
+		return fmt.Errorf("%s IMU: read USER_CTRL: %w", s.name, err)
+	}
+	if err := s.imu.WriteRegister(regUserCtrl, userCtrl|userCtrlFIFORst); err != nil {
+		return fmt.Errorf("%s IMU: write USER_CTRL: %w", s.name, err)
+	}
+	return nil
+}
+
+// ReadFIFO drains the complete samples buffered in the FIFO of the specified
+// IMU, decoded with the layout configured in FIFO_EN and remapped into the
+// vehicle frame. Timestamps are spread back from now at the configured sample
+// rate; the magnetometer is not valid in FIFO samples. If the FIFO
+// overflowed it is reset and ErrFIFOOverflow is returned. The FIFO must have
+// been enabled (EnableFIFO); otherwise no samples are returned.
+//
+// The driver reads registers one at a time, so each byte is still one SPI
+// transfer, but samples are no longer lost between polls.
+func (m *IMUManager) ReadFIFO(imuID string) ([]imu_raw.IMURaw, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	src, devMu, err := m.hardwareIMU(imuID)
+	if err != nil {
+		return nil, err
+	}
+	devMu.Lock()
+	defer devMu.Unlock()
+
+	read := func(reg byte, name string) byte {
+		if err != nil {
+			return 0
+		}
+		var v byte
+		if v, err = src.imu.ReadRegister(reg); err != nil {
+			err = fmt.Errorf("%s IMU: read %s: %w", imuID, name, err)
+		}
+		return v
+	}
+
+	// Reading INT_STATUS clears it, including the overflow flag
+	status := read(regIntStatus, "INT_STATUS")
+	fifoEn := read(regFIFOEn, "FIFO_EN")
+	var slaveLens [3]byte
+	for i := range slaveLens {
+		if fifoEn&(1<<i) != 0 {
+			slaveLens[i] = read(regI2CSlv0Ctl+3*byte(i), "I2C_SLV_CTRL")
+		}
+	}
+	rate := fifoSampleRate(read(regSmplrtDiv, "SMPLRT_DIV"), read(regConfig, "CONFIG"), read(regGyroConfig, "GYRO_CONFIG"))
+	countH := read(regFIFOCountH, "FIFO_COUNTH")
+	countL := read(regFIFOCountL, "FIFO_COUNTL")
+	if err != nil {
+		return nil, err
+	}
+	if status&intStatusFIFOOverflow != 0 {
+		if err := src.resetFIFO(); err != nil {
+			return nil, err
+		}
+		return nil, ErrFIFOOverflow
+	}
+
+	layout := newFIFOLayout(fifoEn, slaveLens)
+	size := layout.size()
+	count := int(countH&0x1F)<<8 | int(countL)
+	if size == 0 || count < size {
+		return nil, nil
+	}
+	count -= count % size // leave a sample being written for the next read
+	if count > fifoSize {
+		count = fifoSize - fifoSize%size
+	}
+
+	data := make([]byte, count)
+	for i := range data {
+		data[i] = read(regFIFORW, "FIFO_R_W")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	samples := layout.decode(data)
+	now := time.Now()
+	period := time.Duration(float64(time.Second) / rate)
+	for i := range samples {
+		samples[i].Source = src.name
+		samples[i].Timestamp = now.Add(-time.Duration(len(samples)-1-i) * period)
+		samples[i] = src.axisMap.Apply(samples[i])
+	}
+	return samples, nil
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package sensors
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/relabs-tech/inertial_computer/internal/config"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpioreg"
+)
+
+// MPU9250 interrupt registers used for data-ready sampling.
+const (
+	regIntPinCfg = 0x37 // INT_PIN_CFG
+	regIntEnable = 0x38 // INT_ENABLE
+
+	intPinCfgActiveLow  = 0x80 // ACTL: INT active low
+	intPinCfgOpenDrain  = 0x40 // OPEN: INT open drain
+	intPinCfgLatch      = 0x20 // LATCH_INT_EN: hold INT until cleared
+	intPinCfgAnyRdClear = 0x10 // INT_ANYRD_2CLEAR: any read clears INT_STATUS
+	intEnableRawRdy     = 0x01 // RAW_RDY_EN: raw sensor data ready
+)
+
+// enableDataReady configures the IMU to pulse INT (active high, push-pull,
+// 50 µs) whenever a new sample is ready (INT_STATUS RAW_DATA_RDY_INT) and
+// opens pinName for rising-edge detection. Other INT_PIN_CFG/INT_ENABLE bits,
+// such as the I2C bypass, are preserved.
+func enableDataReady(src *imuSource, pinName string) (gpio.PinIn, error) {
+	pin := gpioreg.ByName(pinName)
+	if pin == nil {
+		return nil, fmt.Errorf("%s IMU: INT pin %q not found", src.name, pinName)
+	}
+
+	pinCfg, err := src.imu.ReadRegister(regIntPinCfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s IMU: read INT_PIN_CFG: %w", src.name, err)
+	}
+	pinCfg &^= intPinCfgActiveLow | intPinCfgOpenDrain | intPinCfgLatch
+	pinCfg |= intPinCfgAnyRdClear
+	if err := src.imu.WriteRegister(regIntPinCfg, pinCfg); err != nil {
+		return nil, fmt.Errorf("%s IMU: write INT_PIN_CFG: %w", src.name, err)
+	}
+
+	intEnable, err := src.imu.ReadRegister(regIntEnable)
+	if err != nil {
+		return nil, fmt.Errorf("%s IMU: read INT_ENABLE: %w", src.name, err)
+	}
+	if err := src.imu.WriteRegister(regIntEnable, intEnable|intEnableRawRdy); err != nil {
+		return nil, fmt.Errorf("%s IMU: write INT_ENABLE: %w", src.name, err)
+	}
+
+	if err := pin.In(gpio.PullDown, gpio.RisingEdge); err != nil {
+		return nil, fmt.Errorf("%s IMU: INT pin %s edge detection: %w", src.name, pinName, err)
+	}
+	return pin, nil
+}
+
+// setupDataReady enables data-ready sampling on src when
+// IMU_INTERRUPT_SAMPLING is set and an INT pin is configured. Failures are
+// logged and leave the IMU in polling mode.
+func setupDataReady(src *imuSource, pinName string) {
+	if !config.Get().IMUInterruptSampling {
+		return
+	}
+	if pinName == "" {
+		log.Printf("%s IMU: no INT pin configured, polling", src.name)
+		return
+	}
+	pin, err := enableDataReady(src, pinName)
+	if err != nil {
+		log.Printf("Warning: %v (polling instead)", err)
+		return
+	}
+	src.intPin = pin
+	log.Printf("%s IMU: data-ready interrupt sampling on %s (%d Hz)", src.name, pinName, outputRateHz())
+}
+
+// outputRateHz returns the configured IMU output data rate: 1 kHz (8 kHz
+// with IMU_DLPF_CFG=7) divided by 1 + IMU_SMPLRT_DIV.
+func outputRateHz() int {
+	cfg := config.Get()
+	internalRate := 1000 // 1kHz for DLPF modes 0-6
+	if cfg.IMUDLPFConfig == 7 {
+		internalRate = 8000 // 8kHz when DLPF disabled
+	}
+	return internalRate / (1 + int(cfg.IMUSampleRateDiv))
+}
+
+// dataReadyPin returns the INT pin of the specified IMU, or nil if it polls.
+func (m *IMUManager) dataReadyPin(imuID string) gpio.PinIn {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var r IMURawReader
+	switch imuID {
+	case "left":
+		r = m.leftIMU
+	case "right":
+		r = m.rightIMU
+	}
+	if src, ok := r.(*imuSource); ok && src.intPin != nil {
+		return src.intPin
+	}
+	return nil
+}
+
+// SampleClock returns a channel delivering the times at which to read the
+// IMUs, about one per interval, until ctx is done. With data-ready interrupt
+// sampling on the left IMU (or else the right one) the ticks are every Nth
+// INT edge, N being the number of IMU samples closest to interval, so reads
+// follow the sensor's own sample clock; otherwise a time.Ticker is used.
+// Like a time.Ticker, ticks are dropped for a slow receiver.
+func (m *IMUManager) SampleClock(ctx context.Context, interval time.Duration) <-chan time.Time {
+	pin := m.dataReadyPin("left")
+	if pin == nil {
+		pin = m.dataReadyPin("right")
+	}
+	return sampleClock(ctx.Done(), interval, pin)
+}
+
+// sampleClock ticks every interval, on INT edges of pin if it is not nil.
+// If no edge arrives within two tick periods (e.g. a miswired INT), it keeps
+// ticking by timeout and logs until edges resume.
+func sampleClock(done <-chan struct{}, interval time.Duration, pin gpio.PinIn) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	send := func(t time.Time) {
+		select {
+		case ch <- t:
+		default:
+		}
+	}
+
+	if pin == nil {
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case t := <-ticker.C:
+					send(t)
+				}
+			}
+		}()
+		return ch
+	}
+
+	period := time.Second / time.Duration(outputRateHz())
+	every := int(math.Round(float64(interval) / float64(period)))
+	if every < 1 {
+		every = 1
+	}
+	timeout := 2 * time.Duration(every) * period
+
+	go func() {
+		edges := 0
+		stalled := false
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			if !pin.WaitForEdge(timeout) {
+				if !stalled {
+					log.Printf("Warning: no IMU data-ready edge on %s within %v, ticking by timeout", pin.Name(), timeout)
+					stalled = true
+				}
+				edges = 0
+				send(time.Now())
+				continue
+			}
+			if stalled {
+				log.Printf("IMU data-ready edges on %s resumed", pin.Name())
+				stalled = false
+			}
+
+			edges++
+			if edges >= every {
+				edges = 0
+				send(time.Now())
+			}
+		}
+	}()
+	return ch
+}
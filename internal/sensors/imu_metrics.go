@@ -0,0 +1,129 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package sensors
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/relabs-tech/inertial_computer/internal/orientation"
+)
+
+// imuCounters tracks read activity for one IMU.
+// Counters are atomic so recording is cheap on the read hot path.
+type imuCounters struct {
+	reads       atomic.Uint64
+	errors      atomic.Uint64
+	lastErr     atomic.Value // string
+	lastErrTime atomic.Int64 // unix nanoseconds, 0 if no error yet
+}
+
+// record counts a read attempt and remembers the error, if any.
+func (c *imuCounters) record(err error) {
+	c.reads.Add(1)
+	if err != nil {
+		c.errors.Add(1)
+		c.lastErr.Store(err.Error())
+		c.lastErrTime.Store(time.Now().UnixNano())
+	}
+}
+
+// IMUHealth summarizes read activity for one IMU.
+type IMUHealth struct {
+	Available     bool    `json:"available"`
+	Reads         uint64  `json:"reads"`
+	Errors        uint64  `json:"errors"`
+	ReadsPerSec   float64 `json:"reads_per_sec"`
+	LastError     string  `json:"last_error,omitempty"`
+	LastErrorTime string  `json:"last_error_time,omitempty"` // RFC3339
+}
+
+// IMUMetrics is a snapshot of health metrics for both IMUs.
+type IMUMetrics struct {
+	Left          IMUHealth `json:"left"`
+	Right         IMUHealth `json:"right"`
+	UptimeSeconds float64   `json:"uptime_s"`
+	Time          string    `json:"time"` // RFC3339
+
+	// BMP sensors on the same producer, filled in by imu_producer (see EnvStatus)
+	BMPLeft  *EnvInfo `json:"bmp_left,omitempty"`
+	BMPRight *EnvInfo `json:"bmp_right,omitempty"`
+
+	// Runtime gyro bias estimates, filled in by imu_producer when GYRO_BIAS_TRACKING is on
+	GyroBiasLeft  *orientation.GyroBiasStatus `json:"gyro_bias_left,omitempty"`
+	GyroBiasRight *orientation.GyroBiasStatus `json:"gyro_bias_right,omitempty"`
+}
+
+// imuMetricsState holds the counters plus the previous snapshot used to
+// compute read rates.
+type imuMetricsState struct {
+	left  imuCounters
+	right imuCounters
+	start time.Time // set by Init (guarded by IMUManager.mu)
+
+	rateMu         sync.Mutex
+	lastSample     time.Time
+	lastLeftReads  uint64
+	lastRightReads uint64
+	leftRate       float64
+	rightRate      float64
+}
+
+// Metrics returns a snapshot of read counts, error counts and read rates for
+// both IMUs. ReadsPerSec is measured over the interval since the previous call
+// to Metrics (or since Init for the first call).
+func (m *IMUManager) Metrics() IMUMetrics {
+	m.mu.RLock()
+	start := m.metrics.start
+	m.mu.RUnlock()
+
+	now := time.Now()
+	leftReads := m.metrics.left.reads.Load()
+	rightReads := m.metrics.right.reads.Load()
+
+	m.metrics.rateMu.Lock()
+	since := m.metrics.lastSample
+	if since.IsZero() {
+		since = start
+	}
+	if elapsed := now.Sub(since).Seconds(); !since.IsZero() && elapsed > 0 {
+		m.metrics.leftRate = float64(leftReads-m.metrics.lastLeftReads) / elapsed
+		m.metrics.rightRate = float64(rightReads-m.metrics.lastRightReads) / elapsed
+	}
+	m.metrics.lastSample = now
+	m.metrics.lastLeftReads = leftReads
+	m.metrics.lastRightReads = rightReads
+	leftRate, rightRate := m.metrics.leftRate, m.metrics.rightRate
+	m.metrics.rateMu.Unlock()
+
+	var uptime float64
+	if !start.IsZero() {
+		uptime = now.Sub(start).Seconds()
+	}
+
+	return IMUMetrics{
+		Left:          m.metrics.left.health(m.IsLeftIMUAvailable(), leftReads, leftRate),
+		Right:         m.metrics.right.health(m.IsRightIMUAvailable(), rightReads, rightRate),
+		UptimeSeconds: uptime,
+		Time:          now.Format(time.RFC3339),
+	}
+}
+
+func (c *imuCounters) health(available bool, reads uint64, rate float64) IMUHealth {
+	h := IMUHealth{
+		Available:   available,
+		Reads:       reads,
+		Errors:      c.errors.Load(),
+		ReadsPerSec: rate,
+	}
+	if msg, ok := c.lastErr.Load().(string); ok {
+		h.LastError = msg
+	}
+	if ns := c.lastErrTime.Load(); ns != 0 {
+		h.LastErrorTime = time.Unix(0, ns).Format(time.RFC3339)
+	}
+	return h
+}
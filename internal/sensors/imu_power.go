@@ -0,0 +1,219 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package sensors
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// MPU9250 power management registers.
+const (
+	regLPAccelODR = 0x1E // LP_ACCEL_ODR (lposc_clksel bits 3:0)
+	regPwrMgmt1   = 0x6B // PWR_MGMT_1
+	regPwrMgmt2   = 0x6C // PWR_MGMT_2
+
+	pwrMgmt1Sleep       = 0x40 // SLEEP
+	pwrMgmt1Cycle       = 0x20 // CYCLE
+	pwrMgmt1GyroStandby = 0x10 // GYRO_STANDBY
+
+	// PWR_MGMT_2 axis disable bits, for SetDisabledAxes
+	DisableXA = 0x20
+	DisableYA = 0x10
+	DisableZA = 0x08
+	DisableXG = 0x04
+	DisableYG = 0x02
+	DisableZG = 0x01
+
+	pwrMgmt2DisableGyro = DisableXG | DisableYG | DisableZG
+	pwrMgmt2AxesMask    = 0x3F
+
+	// Gyro start-up time after it is re-enabled (datasheet: 35 ms)
+	gyroStartupDelay = 50 * time.Millisecond
+)
+
+// Power modes reported by PowerState.
+const (
+	PowerAwake = "awake" // normal sampling
+	PowerSleep = "sleep" // PWR_MGMT_1 SLEEP: no sampling, reads fail with ErrIMUAsleep
+	PowerCycle = "cycle" // low-power accel only, one sample per CycleODRHz
+)
+
+// ErrIMUAsleep is returned by reads of an IMU put to sleep with Sleep.
+var ErrIMUAsleep = errors.New("IMU is asleep (wake it first)")
+
+// LPAccelODRs are the low-power accelerometer sample rates (Hz) selected by
+// LP_ACCEL_ODR lposc_clksel 0-11.
+var LPAccelODRs = []float64{0.24, 0.49, 0.98, 1.95, 3.91, 7.81, 15.63, 31.25, 62.50, 125, 250, 500}
+
+// PowerState is the power mode of an IMU as set through the manager.
+type PowerState struct {
+	Mode         string  `json:"mode"`                   // PowerAwake, PowerSleep or PowerCycle
+	CycleODRHz   float64 `json:"cycle_odr_hz,omitempty"` // low-power accel rate in cycle mode
+	DisabledAxes byte    `json:"disabled_axes"`          // PWR_MGMT_2 bits set with SetDisabledAxes
+}
+
+// lpAccelODRIndex returns the lposc_clksel of the slowest rate that is at
+// least hz, so the accel is sampled no less often than requested.
+func lpAccelODRIndex(hz float64) (byte, error) {
+	for i, odr := range LPAccelODRs {
+		if hz > 0 && odr >= hz-0.005 {
+			return byte(i), nil
+		}
+	}
+	return 0, fmt.Errorf("low-power accel ODR %.2f Hz out of range (%.2f-%.0f Hz)", hz, LPAccelODRs[0], LPAccelODRs[len(LPAccelODRs)-1])
+}
+
+// Sleep puts the specified IMU to sleep (PWR_MGMT_1 SLEEP): sampling stops
+// and reads return ErrIMUAsleep until Wake.
+func (m *IMUManager) Sleep(imuID string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	src, devMu, err := m.hardwareIMU(imuID)
+	if err != nil {
+		return err
+	}
+	devMu.Lock()
+	defer devMu.Unlock()
+
+	pwr1, err := src.imu.ReadRegister(regPwrMgmt1)
+	if err != nil {
+		return fmt.Errorf("%s IMU: read PWR_MGMT_1: %w", imuID, err)
+	}
+	if err := src.imu.WriteRegister(regPwrMgmt1, pwr1&^pwrMgmt1Cycle|pwrMgmt1Sleep); err != nil {
+		return fmt.Errorf("%s IMU: write PWR_MGMT_1: %w", imuID, err)
+	}
+	src.power.Mode, src.power.CycleODRHz = PowerSleep, 0
+	log.Printf("%s IMU: sleeping", imuID)
+	return nil
+}
+
+// Wake returns the specified IMU to normal sampling from sleep or cycle mode,
+// keeping the axes disabled with SetDisabledAxes.
+func (m *IMUManager) Wake(imuID string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	src, devMu, err := m.hardwareIMU(imuID)
+	if err != nil {
+		return err
+	}
+	devMu.Lock()
+	defer devMu.Unlock()
+	if err := src.wake(); err != nil {
+		return err
+	}
+	log.Printf("%s IMU: awake", imuID)
+	return nil
+}
+
+// wake clears SLEEP/CYCLE/GYRO_STANDBY and restores PWR_MGMT_2, waiting for
+// the gyro to start if it was off. The device mutex must be held.
+func (s *imuSource) wake() error {
+	pwr1, err := s.imu.ReadRegister(regPwrMgmt1)
+	if err != nil {
+		return fmt.Errorf("%s IMU: read PWR_MGMT_1: %w", s.name, err)
+	}
+	if err := s.imu.WriteRegister(regPwrMgmt1, pwr1&^(pwrMgmt1Sleep|pwrMgmt1Cycle|pwrMgmt1GyroStandby)); err != nil {
+		return fmt.Errorf("%s IMU: write PWR_MGMT_1: %w", s.name, err)
+	}
+	if err := s.imu.WriteRegister(regPwrMgmt2, s.power.DisabledAxes); err != nil {
+		return fmt.Errorf("%s IMU: write PWR_MGMT_2: %w", s.name, err)
+	}
+	if s.power.Mode != PowerAwake && s.power.DisabledAxes&pwrMgmt2DisableGyro != pwrMgmt2DisableGyro {
+		time.Sleep(gyroStartupDelay)
+	}
+	s.power.Mode, s.power.CycleODRHz = PowerAwake, 0
+	return nil
+}
+
+// SetCycleMode puts the specified IMU into low-power accelerometer mode: the
+// gyro is disabled and the chip wakes at odrHz (rounded up to an
+// LP_ACCEL_ODR rate) to take one accel sample, sleeping in between. Reads
+// return that latest accel sample and no gyro rate. Wake leaves the mode.
+func (m *IMUManager) SetCycleMode(imuID string, odrHz float64) error {
+	odr, err := lpAccelODRIndex(odrHz)
+	if err != nil {
+		return err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	src, devMu, err := m.hardwareIMU(imuID)
+	if err != nil {
+		return err
+	}
+	devMu.Lock()
+	defer devMu.Unlock()
+
+	pwr1, err := src.imu.ReadRegister(regPwrMgmt1)
+	if err != nil {
+		return fmt.Errorf("%s IMU: read PWR_MGMT_1: %w", imuID, err)
+	}
+	if err := src.imu.WriteRegister(regPwrMgmt2, src.power.DisabledAxes|pwrMgmt2DisableGyro); err != nil {
+		return fmt.Errorf("%s IMU: write PWR_MGMT_2: %w", imuID, err)
+	}
+	if err := src.imu.WriteRegister(regLPAccelODR, odr); err != nil {
+		return fmt.Errorf("%s IMU: write LP_ACCEL_ODR: %w", imuID, err)
+	}
+	pwr1 = pwr1&^(pwrMgmt1Sleep|pwrMgmt1GyroStandby) | pwrMgmt1Cycle
+	if err := src.imu.WriteRegister(regPwrMgmt1, pwr1); err != nil {
+		return fmt.Errorf("%s IMU: write PWR_MGMT_1: %w", imuID, err)
+	}
+	src.power.Mode, src.power.CycleODRHz = PowerCycle, LPAccelODRs[odr]
+	log.Printf("%s IMU: cycle mode at %.2f Hz", imuID, LPAccelODRs[odr])
+	return nil
+}
+
+// SetDisabledAxes disables the accel and gyro axes in mask (DisableXA ...
+// DisableZG, PWR_MGMT_2 bits 5:0) and enables the others. A disabled axis
+// reads as its last value. While asleep the mask is applied on Wake; in
+// cycle mode the gyro stays disabled regardless.
+func (m *IMUManager) SetDisabledAxes(imuID string, mask byte) error {
+	if mask&^pwrMgmt2AxesMask != 0 {
+		return fmt.Errorf("invalid axis mask 0x%02X (PWR_MGMT_2 bits 5:0)", mask)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	src, devMu, err := m.hardwareIMU(imuID)
+	if err != nil {
+		return err
+	}
+	devMu.Lock()
+	defer devMu.Unlock()
+
+	value := mask
+	switch src.power.Mode {
+	case PowerCycle:
+		value |= pwrMgmt2DisableGyro
+	case PowerSleep:
+		src.power.DisabledAxes = mask
+		return nil
+	}
+	if err := src.imu.WriteRegister(regPwrMgmt2, value); err != nil {
+		return fmt.Errorf("%s IMU: write PWR_MGMT_2: %w", imuID, err)
+	}
+	gyroEnabled := src.power.DisabledAxes &^ mask & pwrMgmt2DisableGyro
+	src.power.DisabledAxes = mask
+	if src.power.Mode == PowerAwake && gyroEnabled != 0 {
+		time.Sleep(gyroStartupDelay)
+	}
+	return nil
+}
+
+// PowerState returns the power mode of the specified IMU.
+func (m *IMUManager) PowerState(imuID string) (PowerState, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	src, devMu, err := m.hardwareIMU(imuID)
+	if err != nil {
+		return PowerState{}, err
+	}
+	devMu.Lock()
+	defer devMu.Unlock()
+	return src.power, nil
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package sensors
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/relabs-tech/inertial_computer/internal/config"
+	"periph.io/x/devices/v3/mpu9250"
+)
+
+// defaultSelfTestLimitPercent is the allowed deviation from factory trim when
+// IMU_SELFTEST_MAX_DEVIATION is not configured (±14%, InvenSense self-test limit).
+const defaultSelfTestLimitPercent = 14.0
+
+// SelfTestAxis is the factory self-test outcome for one sensor axis.
+type SelfTestAxis struct {
+	Axis      string  `json:"axis"`          // "X", "Y" or "Z"
+	Deviation float64 `json:"deviation_pct"` // deviation from factory trim (%)
+	Pass      bool    `json:"pass"`
+}
+
+// SelfTestResult is the structured MPU9250 factory self-test result.
+type SelfTestResult struct {
+	IMU          string         `json:"imu"`
+	Accel        []SelfTestAxis `json:"accel"`
+	Gyro         []SelfTestAxis `json:"gyro"`
+	LimitPercent float64        `json:"limit_pct"`
+	Pass         bool           `json:"pass"`
+	Time         string         `json:"time"` // RFC3339
+}
+
+// newSelfTestResult checks each axis deviation against the configured limit.
+func newSelfTestResult(imuID string, accel, gyro [3]float64) SelfTestResult {
+	limit := config.Get().IMUSelfTestMaxDeviation
+	if limit <= 0 {
+		limit = defaultSelfTestLimitPercent
+	}
+
+	r := SelfTestResult{
+		IMU:          imuID,
+		LimitPercent: limit,
+		Pass:         true,
+		Time:         time.Now().Format(time.RFC3339),
+	}
+	for i, axis := range []string{"X", "Y", "Z"} {
+		a := SelfTestAxis{Axis: axis, Deviation: accel[i], Pass: math.Abs(accel[i]) <= limit}
+		g := SelfTestAxis{Axis: axis, Deviation: gyro[i], Pass: math.Abs(gyro[i]) <= limit}
+		r.Accel = append(r.Accel, a)
+		r.Gyro = append(r.Gyro, g)
+		r.Pass = r.Pass && a.Pass && g.Pass
+	}
+	return r
+}
+
+// runSelfTest runs the driver factory self-test on an MPU9250.
+func runSelfTest(name string, dev *mpu9250.MPU9250) (SelfTestResult, error) {
+	res, err := dev.SelfTest()
+	if err != nil {
+		return SelfTestResult{}, fmt.Errorf("%s IMU self-test: %w", name, err)
+	}
+	return newSelfTestResult(name,
+		[3]float64{float64(res.AccelDeviation.X), float64(res.AccelDeviation.Y), float64(res.AccelDeviation.Z)},
+		[3]float64{float64(res.GyroDeviation.X), float64(res.GyroDeviation.Y), float64(res.GyroDeviation.Z)},
+	), nil
+}
+
+// RunSelfTest runs the MPU9250 factory self-test on the specified IMU and
+// returns the per-axis result. The self-test switches the sensor to ±2 g /
+// ±250 dps and its own rate; the configured ranges, DLPF and sample rate
+// divider are applied again afterwards, also when the self-test fails.
+func (m *IMUManager) RunSelfTest(imuID string) (SelfTestResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.initialized {
+		return SelfTestResult{}, fmt.Errorf("IMU manager not initialized")
+	}
+
+	var imuSrc *imuSource
+	var devMu *sync.Mutex
+	switch imuID {
+	case "left":
+		if m.leftIMU == nil {
+			return SelfTestResult{}, fmt.Errorf("left IMU not available")
+		}
+		src, ok := m.leftIMU.(*imuSource)
+		if !ok {
+			return SelfTestResult{}, errSimulatedIMU("left")
+		}
+		imuSrc = src
+		devMu = &m.leftDevMu
+	case "right":
+		if m.rightIMU == nil {
+			return SelfTestResult{}, fmt.Errorf("right IMU not available")
+		}
+		src, ok := m.rightIMU.(*imuSource)
+		if !ok {
+			return SelfTestResult{}, errSimulatedIMU("right")
+		}
+		imuSrc = src
+		devMu = &m.rightDevMu
+	default:
+		return SelfTestResult{}, fmt.Errorf("invalid IMU ID: %s (must be 'left' or 'right')", imuID)
+	}
+
+	devMu.Lock()
+	defer devMu.Unlock()
+	result, testErr := runSelfTest(imuSrc.name, imuSrc.imu)
+	if err := applyIMUConfig(imuSrc.name, imuSrc.imu); err != nil {
+		return result, fmt.Errorf("restore configuration after self-test: %w", err)
+	}
+	return result, testErr
+}
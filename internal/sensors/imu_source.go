@@ -11,6 +11,7 @@ import (
 
 	"github.com/relabs-tech/inertial_computer/internal/config"
 	imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
+	"periph.io/x/conn/v3/gpio"
 	"periph.io/x/conn/v3/gpio/gpioreg"
 	"periph.io/x/devices/v3/mpu9250"
 	"periph.io/x/host/v3"
@@ -21,27 +22,124 @@ type IMURawReader interface {
 	ReadRaw() (imu_raw.IMURaw, error)
 }
 
+// Expected device identifiers, checked during initialization to catch
+// miswired buses that would otherwise "succeed" and return garbage.
+const (
+	mpu9250WhoAmIReg = 0x75 // WHO_AM_I register
+	regTempOutH      = 0x41 // TEMP_OUT_H
+	regTempOutL      = 0x42 // TEMP_OUT_L
+	mpu9250WhoAmI    = 0x71 // expected MPU9250 WHO_AM_I value
+	ak8963WIA        = 0x48 // expected AK8963 WIA (device ID) value
+)
+
 type imuSource struct {
 	name     string // "left" or "right" for logging
 	imu      *mpu9250.MPU9250
 	magCal   *mpu9250.MagCal
 	magReady bool
+	whoAmI   byte // MPU9250 WHO_AM_I read at init
+	magWIA   byte // AK8963 WIA read at init (0 if the read failed)
+	magMode  byte // AK8963 CNTL1 MODE applied at init
+	magScale byte // AK8963 CNTL1 BIT applied at init (0=14-bit, 1=16-bit)
+
+	// INT pin for data-ready sampling (nil = polling), see setupDataReady
+	intPin gpio.PinIn
+
+	// Sensor-to-vehicle axis remap applied to every sample
+	axisMap imu_raw.AxisMap
+
+	// Power mode set through the manager (see imu_power.go); guarded by the
+	// device mutex like ReadRaw
+	power PowerState
+
+	// Last magnetometer sample, reused when ST1 reports no new data.
+	// Only accessed from ReadRaw, which the manager serializes per device.
+	lastMx, lastMy, lastMz int16
+	lastMagValid           bool
 }
 
+// AK8963 register access through the MPU9250 I2C master slave-4 channel, which
+// performs single-byte transactions independently of the driver's mag reads.
+const (
+	ak8963I2CAddr = 0x0C
+	ak8963ST1Reg  = 0x02
+	ak8963ST1DRDY = 0x01 // ST1 bit 0: data ready
+
+	regI2CSlv4Addr  = 0x31
+	regI2CSlv4Reg   = 0x32
+	regI2CSlv4Ctrl  = 0x34
+	regI2CSlv4DI    = 0x35
+	regI2CMstStatus = 0x36
+	i2cSlv4Done     = 0x40 // I2C_MST_STATUS bit 6
+	i2cSlv4Enable   = 0x80
+	i2cReadFlag     = 0x80
+
+	slv4PollAttempts = 10
+	slv4PollInterval = 100 * time.Microsecond
+)
+
 // NewIMUSourceLeft initializes the left MPU9250 over SPI.
 func NewIMUSourceLeft() (IMURawReader, error) {
 	cfg := config.Get()
-	return newIMUSource("left", cfg.IMULeftSPIDevice, cfg.IMULeftCSPin)
+	src, err := newIMUSource("left", cfg.IMULeftSPIDevice, cfg.IMULeftCSPin)
+	if err != nil {
+		return nil, err
+	}
+	src.setAxisMap(imu_raw.AxisMap(cfg.IMULeftAxisMap))
+	src.power.Mode = PowerAwake
+	setupDataReady(src, cfg.IMULeftIntPin)
+	return src, nil
 }
 
 // NewIMUSourceRight initializes the right MPU9250 over SPI.
 func NewIMUSourceRight() (IMURawReader, error) {
 	cfg := config.Get()
-	return newIMUSource("right", cfg.IMURightSPIDevice, cfg.IMURightCSPin)
+	src, err := newIMUSource("right", cfg.IMURightSPIDevice, cfg.IMURightCSPin)
+	if err != nil {
+		return nil, err
+	}
+	src.setAxisMap(imu_raw.AxisMap(cfg.IMURightAxisMap))
+	src.power.Mode = PowerAwake
+	setupDataReady(src, cfg.IMURightIntPin)
+	return src, nil
+}
+
+// applyIMUConfig sets the configured accel/gyro range, DLPF, sample rate
+// divider and accel DLPF. ReadRaw scales with the configured ranges, so this
+// must also run after anything that changes them, such as the self-test.
+func applyIMUConfig(name string, imu *mpu9250.MPU9250) error {
+	// Apply configured sensor ranges
+	cfg := config.Get()
+	if err := imu.SetAccelRange(cfg.IMUAccelRange); err != nil {
+		return fmt.Errorf("%s IMU: set accel range: %w", name, err)
+	}
+	log.Printf("%s IMU: accelerometer range set to %d (±%dg)", name, cfg.IMUAccelRange, []int{2, 4, 8, 16}[cfg.IMUAccelRange])
+
+	if err := imu.SetGyroRange(cfg.IMUGyroRange); err != nil {
+		return fmt.Errorf("%s IMU: set gyro range: %w", name, err)
+	}
+	log.Printf("%s IMU: gyroscope range set to %d (±%d°/s)", name, cfg.IMUGyroRange, []int{250, 500, 1000, 2000}[cfg.IMUGyroRange])
+
+	// Configure sample rate
+	if err := imu.SetDLPFMode(cfg.IMUDLPFConfig); err != nil {
+		return fmt.Errorf("%s IMU: set DLPF config: %w", name, err)
+	}
+	log.Printf("%s IMU: DLPF config set to %d", name, cfg.IMUDLPFConfig)
+
+	if err := imu.SetSampleRateDivider(cfg.IMUSampleRateDiv); err != nil {
+		return fmt.Errorf("%s IMU: set sample rate divider: %w", name, err)
+	}
+	log.Printf("%s IMU: sample rate divider set to %d (output rate: %d Hz)", name, cfg.IMUSampleRateDiv, outputRateHz())
+
+	if err := imu.SetAccelDLPF(cfg.IMUAccelDLPF); err != nil {
+		return fmt.Errorf("%s IMU: set accel DLPF: %w", name, err)
+	}
+	log.Printf("%s IMU: accelerometer DLPF set to %d", name, cfg.IMUAccelDLPF)
+	return nil
 }
 
 // newIMUSource is a unified initialization function for both left and right IMUs.
-func newIMUSource(name, spiDev, csPin string) (IMURawReader, error) {
+func newIMUSource(name, spiDev, csPin string) (*imuSource, error) {
 	if _, err := host.Init(); err != nil {
 		return nil, fmt.Errorf("%s IMU: periph host init: %w", name, err)
 	}
@@ -61,53 +159,43 @@ func newIMUSource(name, spiDev, csPin string) (IMURawReader, error) {
 		return nil, fmt.Errorf("%s IMU: device creation: %w", name, err)
 	}
 
-	if err := imu.Init(); err != nil {
-		return nil, fmt.Errorf("%s IMU: initialization: %w", name, err)
-	}
-
-	// Apply configured sensor ranges
-	cfg := config.Get()
-	if err := imu.SetAccelRange(cfg.IMUAccelRange); err != nil {
-		return nil, fmt.Errorf("%s IMU: set accel range: %w", name, err)
-	}
-	log.Printf("%s IMU: accelerometer range set to %d (±%dg)", name, cfg.IMUAccelRange, []int{2, 4, 8, 16}[cfg.IMUAccelRange])
-
-	if err := imu.SetGyroRange(cfg.IMUGyroRange); err != nil {
-		return nil, fmt.Errorf("%s IMU: set gyro range: %w", name, err)
+	// Verify the device identity before configuring anything
+	whoAmI, err := imu.ReadRegister(mpu9250WhoAmIReg)
+	if err != nil {
+		return nil, fmt.Errorf("%s IMU: read WHO_AM_I: %w", name, err)
 	}
-	log.Printf("%s IMU: gyroscope range set to %d (±%d°/s)", name, cfg.IMUGyroRange, []int{250, 500, 1000, 2000}[cfg.IMUGyroRange])
-
-	// Configure sample rate
-	if err := imu.SetDLPFMode(cfg.IMUDLPFConfig); err != nil {
-		return nil, fmt.Errorf("%s IMU: set DLPF config: %w", name, err)
+	if whoAmI != mpu9250WhoAmI {
+		return nil, fmt.Errorf("%s IMU: WHO_AM_I = 0x%02X, expected 0x%02X (check wiring of %s / CS %s)",
+			name, whoAmI, mpu9250WhoAmI, spiDev, csPin)
 	}
-	log.Printf("%s IMU: DLPF config set to %d", name, cfg.IMUDLPFConfig)
+	log.Printf("%s IMU: WHO_AM_I = 0x%02X", name, whoAmI)
 
-	if err := imu.SetSampleRateDivider(cfg.IMUSampleRateDiv); err != nil {
-		return nil, fmt.Errorf("%s IMU: set sample rate divider: %w", name, err)
-	}
-	internalRate := 1000 // 1kHz for DLPF modes 0-6
-	if cfg.IMUDLPFConfig == 7 {
-		internalRate = 8000 // 8kHz when DLPF disabled
+	if err := imu.Init(); err != nil {
+		return nil, fmt.Errorf("%s IMU: initialization: %w", name, err)
 	}
-	outputRate := internalRate / (1 + int(cfg.IMUSampleRateDiv))
-	log.Printf("%s IMU: sample rate divider set to %d (output rate: %d Hz)", name, cfg.IMUSampleRateDiv, outputRate)
 
-	if err := imu.SetAccelDLPF(cfg.IMUAccelDLPF); err != nil {
-		return nil, fmt.Errorf("%s IMU: set accel DLPF: %w", name, err)
+	if err := applyIMUConfig(name, imu); err != nil {
+		return nil, err
 	}
-	log.Printf("%s IMU: accelerometer DLPF set to %d", name, cfg.IMUAccelDLPF)
 
 	// Self-test
-	testResult, err := imu.SelfTest()
+	selfTest, err := runSelfTest(name, imu)
 	if err != nil {
-		log.Printf("Warning: %s IMU self-test failed: %v", name, err)
+		log.Printf("Warning: %v", err)
 	} else {
-		log.Printf("%s IMU self-test passed:", name)
+		status := "passed"
+		if !selfTest.Pass {
+			status = fmt.Sprintf("FAILED (limit ±%.0f%%)", selfTest.LimitPercent)
+		}
+		log.Printf("%s IMU self-test %s:", name, status)
 		log.Printf("  Accelerometer deviation: X: %.2f%%, Y: %.2f%%, Z: %.2f%%",
-			testResult.AccelDeviation.X, testResult.AccelDeviation.Y, testResult.AccelDeviation.Z)
+			selfTest.Accel[0].Deviation, selfTest.Accel[1].Deviation, selfTest.Accel[2].Deviation)
 		log.Printf("  Gyroscope deviation: X: %.2f%%, Y: %.2f%%, Z: %.2f%%",
-			testResult.GyroDeviation.X, testResult.GyroDeviation.Y, testResult.GyroDeviation.Z)
+			selfTest.Gyro[0].Deviation, selfTest.Gyro[1].Deviation, selfTest.Gyro[2].Deviation)
+	}
+	// The self-test leaves the sensor at its own ranges and rate
+	if err := applyIMUConfig(name, imu); err != nil {
+		return nil, err
 	}
 
 	// Calibration
@@ -118,17 +206,27 @@ func newIMUSource(name, spiDev, csPin string) (IMURawReader, error) {
 	}
 
 	// Magnetometer initialization (non-fatal) with configurable timing
-	if magID, err := imu.ReadMagID(); err != nil {
+	magID, err := imu.ReadMagID()
+	if err != nil {
 		log.Printf("%s IMU: WARNING: failed to read magnetometer ID: %v", name, err)
+	} else if magID != ak8963WIA {
+		log.Printf("%s IMU: magnetometer WIA = 0x%02X, expected 0x%02X (continuing without mag)", name, magID, ak8963WIA)
+		return &imuSource{
+			name:     name,
+			imu:      imu,
+			magReady: false,
+			whoAmI:   whoAmI,
+			magWIA:   byte(magID),
+		}, nil
 	} else {
-		log.Printf("%s IMU: magnetometer WHO_AM_I = 0x%02X", name, magID)
+		log.Printf("%s IMU: magnetometer WIA = 0x%02X", name, magID)
 	}
 
 	// Load magnetometer configuration parameters
+	cfg := config.Get()
 	writeDelay := time.Duration(cfg.MagWriteDelayMS) * time.Millisecond
 	readDelay := time.Duration(cfg.MagReadDelayMS) * time.Millisecond
-	magScale := cfg.MagScale
-	magMode := cfg.MagMode
+	magScale, magMode := continuousMagSettings(name, cfg.MagScale, cfg.MagMode)
 
 	log.Printf("%s IMU: initializing magnetometer (writeDelay=%dms, readDelay=%dms, scale=%d, mode=0x%02X)",
 		name, cfg.MagWriteDelayMS, cfg.MagReadDelayMS, magScale, magMode)
@@ -140,6 +238,10 @@ func newIMUSource(name, spiDev, csPin string) (IMURawReader, error) {
 			name:     name,
 			imu:      imu,
 			magReady: false,
+			whoAmI:   whoAmI,
+			magWIA:   byte(magID),
+			magMode:  magMode,
+			magScale: magScale,
 		}, nil
 	}
 
@@ -150,11 +252,48 @@ func newIMUSource(name, spiDev, csPin string) (IMURawReader, error) {
 		imu:      imu,
 		magCal:   magCal,
 		magReady: true,
+		whoAmI:   whoAmI,
+		magWIA:   byte(magID),
+		magMode:  magMode,
+		magScale: magScale,
 	}, nil
 }
 
-// ReadRaw reads accelerometer, gyroscope, and magnetometer data from this IMU.
+// continuousMagSettings validates the configured AK8963 CNTL1 settings for
+// continuous streaming. Only the two continuous measurement modes (0x02 at
+// 8Hz, 0x06 at 100Hz) produce a data stream; the others (power-down, single,
+// external trigger, which only measures on a TRG pin pulse, self-test and
+// fuse ROM) or an out-of-range resolution fall back to 100Hz continuous /
+// 16-bit, with a log message.
+func continuousMagSettings(name string, scale, mode byte) (byte, byte) {
+	switch mode {
+	case 0x02, 0x06: // continuous 8Hz, continuous 100Hz
+	default:
+		log.Printf("%s IMU: MAG_MODE=0x%02X is not a continuous mode, falling back to 0x06 (100Hz) / 16-bit", name, mode)
+		return 1, 0x06
+	}
+	if scale > 1 {
+		log.Printf("%s IMU: magnetometer resolution %d invalid, falling back to 0x06 (100Hz) / 16-bit", name, scale)
+		return 1, 0x06
+	}
+	return scale, mode
+}
+
+func (s *imuSource) setAxisMap(m imu_raw.AxisMap) {
+	s.axisMap = m
+	if !m.IsIdentity() {
+		log.Printf("%s IMU: remapping axes to the vehicle frame (%+d,%+d,%+d)", s.name, m[0], m[1], m[2])
+	}
+}
+
+// ReadRaw reads accelerometer, gyroscope, and magnetometer data from this IMU,
+// remapped into the vehicle frame.
 func (s *imuSource) ReadRaw() (imu_raw.IMURaw, error) {
+	if s.power.Mode == PowerSleep {
+		return imu_raw.IMURaw{}, fmt.Errorf("%s IMU: %w", s.name, ErrIMUAsleep)
+	}
+	sampled := time.Now()
+
 	// Read accelerometer
 	ax, err := s.imu.GetAccelerationX()
 	if err != nil {
@@ -183,32 +322,104 @@ func (s *imuSource) ReadRaw() (imu_raw.IMURaw, error) {
 		return imu_raw.IMURaw{}, fmt.Errorf("%s IMU gyro Z: %w", s.name, err)
 	}
 
+	// Read die temperature (non-fatal)
+	temp, tempErr := s.readTemp()
+
 	// Read magnetometer (if available)
 	var mx, my, mz int16
+	var magValid, magOverflow bool
 	if s.magReady {
-		mag, err := s.imu.ReadMag(s.magCal)
+		mx, my, mz, magValid, magOverflow = s.readMag()
+	}
+
+	return s.axisMap.Apply(imu_raw.IMURaw{
+		Source:    s.name,
+		Timestamp: sampled,
+		Ax:        ax,
+		Ay:        ay,
+		Az:        az,
+		Gx:        gx,
+		Gy:        gy,
+		Gz:        gz,
+		Mx:        mx,
+		My:        my,
+		Mz:        mz,
+
+		MagValid:    magValid,
+		MagOverflow: magOverflow,
+
+		Temp:      temp,
+		TempValid: tempErr == nil,
+	}), nil
+}
+
+// readTemp reads the die temperature (TEMP_OUT_H/L) in counts.
+func (s *imuSource) readTemp() (int16, error) {
+	hi, err := s.imu.ReadRegister(regTempOutH)
+	if err != nil {
+		return 0, err
+	}
+	lo, err := s.imu.ReadRegister(regTempOutL)
+	if err != nil {
+		return 0, err
+	}
+	return int16(uint16(hi)<<8 | uint16(lo)), nil
+}
+
+// readMag returns the magnetometer sample in µT×10, converted to the
+// accel/gyro axes (imu.AK8963ToMPU). When ST1 reports no new
+// data (DRDY clear) the previous sample is returned unchanged. A magnetic
+// sensor overflow (ST2 HOFL) is reported via overflow and marks the sample
+// invalid instead of zeroing it.
+func (s *imuSource) readMag() (mx, my, mz int16, valid, overflow bool) {
+	st1, err := s.readMagRegister(ak8963ST1Reg)
+	if err != nil {
+		// Status unavailable: fall through to a normal read
+		log.Printf("%s IMU: magnetometer ST1 read error: %v", s.name, err)
+	} else if st1&ak8963ST1DRDY == 0 {
+		return s.lastMx, s.lastMy, s.lastMz, s.lastMagValid, false
+	}
+
+	mag, err := s.imu.ReadMag(s.magCal)
+	if err != nil {
+		log.Printf("%s IMU: magnetometer read error: %v", s.name, err)
+		return s.lastMx, s.lastMy, s.lastMz, false, false
+	}
+
+	// Store scaled µT values as int16 (multiply by 10 for precision), in the
+	// accel/gyro frame
+	mx, my, mz = imu_raw.AK8963ToMPU(int16(mag.X*10), int16(mag.Y*10), int16(mag.Z*10))
+	valid = !mag.Overflow
+	if mag.Overflow {
+		log.Printf("%s IMU: magnetometer overflow (HOFL), sample marked invalid", s.name)
+	}
+
+	s.lastMx, s.lastMy, s.lastMz, s.lastMagValid = mx, my, mz, valid
+	return mx, my, mz, valid, mag.Overflow
+}
+
+// readMagRegister reads one AK8963 register via the I2C master slave-4
+// channel.
+func (s *imuSource) readMagRegister(regAddr byte) (byte, error) {
+	if err := s.imu.WriteRegister(regI2CSlv4Addr, ak8963I2CAddr|i2cReadFlag); err != nil {
+		return 0, err
+	}
+	if err := s.imu.WriteRegister(regI2CSlv4Reg, regAddr); err != nil {
+		return 0, err
+	}
+	if err := s.imu.WriteRegister(regI2CSlv4Ctrl, i2cSlv4Enable); err != nil {
+		return 0, err
+	}
+
+	for i := 0; i < slv4PollAttempts; i++ {
+		status, err := s.imu.ReadRegister(regI2CMstStatus)
 		if err != nil {
-			log.Printf("%s IMU: magnetometer read error: %v", s.name, err)
-		} else if mag.Overflow {
-			log.Printf("%s IMU: magnetometer overflow detected", s.name)
-		} else {
-			// Store scaled µT values as int16 (multiply by 10 for precision)
-			mx = int16(mag.X * 10)
-			my = int16(mag.Y * 10)
-			mz = int16(mag.Z * 10)
+			return 0, err
 		}
+		if status&i2cSlv4Done != 0 {
+			return s.imu.ReadRegister(regI2CSlv4DI)
+		}
+		time.Sleep(slv4PollInterval)
 	}
-
-	return imu_raw.IMURaw{
-		Source: s.name,
-		Ax:     ax,
-		Ay:     ay,
-		Az:     az,
-		Gx:     gx,
-		Gy:     gy,
-		Gz:     gz,
-		Mx:     mx,
-		My:     my,
-		Mz:     mz,
-	}, nil
+	return 0, fmt.Errorf("I2C slave 4 transaction timed out")
 }
@@ -0,0 +1,30 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package sensors
+
+import "testing"
+
+func TestContinuousMagSettings(t *testing.T) {
+	tests := []struct {
+		scale, mode         byte
+		wantScale, wantMode byte
+	}{
+		{0, 0x02, 0, 0x02}, // continuous 8Hz, 14-bit
+		{1, 0x06, 1, 0x06}, // continuous 100Hz, 16-bit
+		{0, 0x00, 1, 0x06}, // power-down
+		{0, 0x01, 1, 0x06}, // single measurement
+		{0, 0x04, 1, 0x06}, // external trigger: no data without a TRG pulse
+		{1, 0x08, 1, 0x06}, // self-test
+		{1, 0x0F, 1, 0x06}, // fuse ROM
+		{2, 0x02, 1, 0x06}, // invalid resolution
+	}
+	for _, tt := range tests {
+		scale, mode := continuousMagSettings("test", tt.scale, tt.mode)
+		if scale != tt.wantScale || mode != tt.wantMode {
+			t.Errorf("continuousMagSettings(%d, 0x%02X) = %d, 0x%02X, want %d, 0x%02X",
+				tt.scale, tt.mode, scale, mode, tt.wantScale, tt.wantMode)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package sensors
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/relabs-tech/inertial_computer/internal/config"
+	imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
+	"periph.io/x/conn/v3/gpio"
+)
+
+// streamBufferSize is the per-subscriber channel buffer. Slow subscribers
+// miss samples rather than stalling the reader.
+const streamBufferSize = 4
+
+// imuStream fans out samples from a single reader goroutine to any number of
+// subscribers. The reader runs only while there is at least one subscriber.
+type imuStream struct {
+	mu   sync.Mutex
+	subs map[chan imu_raw.IMURaw]struct{}
+	stop chan struct{} // closed to stop the reader; nil when not running
+}
+
+// StreamLeft returns a channel of left IMU samples read at IMU_STREAM_INTERVAL
+// by a single shared reader goroutine. The channel is closed when ctx is done.
+// With data-ready interrupt sampling the reads follow the IMU's INT edges.
+func (m *IMUManager) StreamLeft(ctx context.Context) <-chan imu_raw.IMURaw {
+	return m.leftStream.subscribe(ctx, "left", m.ReadLeftIMU, m.dataReadyPin("left"))
+}
+
+// StreamRight returns a channel of right IMU samples read at IMU_STREAM_INTERVAL
+// by a single shared reader goroutine. The channel is closed when ctx is done.
+// With data-ready interrupt sampling the reads follow the IMU's INT edges.
+func (m *IMUManager) StreamRight(ctx context.Context) <-chan imu_raw.IMURaw {
+	return m.rightStream.subscribe(ctx, "right", m.ReadRightIMU, m.dataReadyPin("right"))
+}
+
+func (s *imuStream) subscribe(ctx context.Context, name string, read func() (imu_raw.IMURaw, error), pin gpio.PinIn) <-chan imu_raw.IMURaw {
+	ch := make(chan imu_raw.IMURaw, streamBufferSize)
+
+	s.mu.Lock()
+	if s.subs == nil {
+		s.subs = make(map[chan imu_raw.IMURaw]struct{})
+	}
+	s.subs[ch] = struct{}{}
+	if s.stop == nil {
+		s.stop = make(chan struct{})
+		go s.run(name, read, pin, s.stop)
+	}
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.unsubscribe(ch)
+	}()
+
+	return ch
+}
+
+// unsubscribe removes and closes a subscriber channel, stopping the reader
+// when the last subscriber leaves.
+func (s *imuStream) unsubscribe(ch chan imu_raw.IMURaw) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.subs, ch)
+	close(ch)
+
+	if len(s.subs) == 0 && s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+}
+
+// run reads the IMU on a sample clock (see sampleClock) and delivers each
+// sample to all subscribers until stop is closed.
+func (s *imuStream) run(name string, read func() (imu_raw.IMURaw, error), pin gpio.PinIn, stop chan struct{}) {
+	ticks := sampleClock(stop, StreamInterval(), pin)
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticks:
+		}
+
+		sample, err := read()
+		if err != nil {
+			log.Printf("%s IMU stream: read error: %v", name, err)
+			continue
+		}
+
+		s.mu.Lock()
+		for ch := range s.subs {
+			select {
+			case ch <- sample:
+			default: // subscriber is behind; drop this sample for it
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// StreamInterval returns the configured stream read interval, falling back to
+// IMU_SAMPLE_INTERVAL and then 40ms.
+func StreamInterval() time.Duration {
+	cfg := config.Get()
+	ms := cfg.IMUStreamInterval
+	if ms <= 0 {
+		ms = cfg.IMUSampleInterval
+	}
+	if ms <= 0 {
+		ms = 40
+	}
+	return time.Duration(ms) * time.Millisecond
+}
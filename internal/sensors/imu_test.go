@@ -0,0 +1,184 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package sensors
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
+)
+
+// fakeIMU stands in for one SPI device. Its state is plain memory, so
+// `go test -race` reports any access the manager fails to serialize; without
+// the race detector, overlapping transactions are still counted.
+type fakeIMU struct {
+	name     string
+	regs     [0x80]byte
+	reads    int
+	inFlight int
+	overlaps int
+}
+
+// begin starts a transaction and yields, so that an unserialized caller
+// gets the chance to interleave.
+func (f *fakeIMU) begin() {
+	if f.inFlight > 0 {
+		f.overlaps++
+	}
+	f.inFlight++
+	runtime.Gosched()
+}
+
+func (f *fakeIMU) end() {
+	f.inFlight--
+}
+
+func (f *fakeIMU) ReadRaw() (imu_raw.IMURaw, error) {
+	f.begin()
+	defer f.end()
+	f.reads++
+	return imu_raw.IMURaw{Source: f.name, Ax: int16(f.regs[regGyroConfig]), Az: 16384}, nil
+}
+
+func (f *fakeIMU) ReadRegister(regAddr byte) (byte, error) {
+	f.begin()
+	defer f.end()
+	return f.regs[regAddr&0x7F], nil
+}
+
+func (f *fakeIMU) WriteRegister(regAddr byte, value byte) error {
+	f.begin()
+	defer f.end()
+	f.regs[regAddr&0x7F] = value
+	return nil
+}
+
+// TestIMUManagerConcurrentAccess runs sample reads, register access and
+// calibration changes on both IMUs at once. Run with -race.
+func TestIMUManagerConcurrentAccess(t *testing.T) {
+	left, right := &fakeIMU{name: "left"}, &fakeIMU{name: "right"}
+	m := &IMUManager{leftIMU: left, rightIMU: right, initialized: true}
+
+	const rounds = 200
+	var wg sync.WaitGroup
+	var failed atomic.Int64
+	check := func(err error) {
+		if err != nil {
+			failed.Add(1)
+			t.Error(err)
+		}
+	}
+	for _, side := range []string{"left", "right"} {
+		read := m.ReadLeftIMU
+		if side == "right" {
+			read = m.ReadRightIMU
+		}
+		wg.Add(6)
+		go func() {
+			defer wg.Done()
+			for range rounds {
+				_, err := read()
+				check(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range rounds {
+				_, err := m.ReadIMUUncalibrated(side)
+				check(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := range rounds {
+				check(m.WriteRegister(side, regSmplrtDiv, byte(i)))
+				_, err := m.ReadRegister(side, regSmplrtDiv)
+				check(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := range rounds {
+				_, _, err := m.WriteRegisterField(side, regGyroConfig, "GYRO_FS_SEL", uint64(i%4))
+				check(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range rounds / 20 {
+				_, err := m.ReadAllRegisters(side)
+				check(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			cal := &imu_raw.Calibration{IMU: side, GyroBias: [3]float64{1, 2, 3}}
+			for i := range rounds {
+				if i%2 == 0 {
+					check(m.SetCalibration(side, cal))
+				} else {
+					check(m.SetCalibration(side, nil))
+				}
+				m.Calibration(side)
+				m.Metrics()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if failed.Load() > 0 {
+		t.Fatalf("%d operations failed", failed.Load())
+	}
+	for _, f := range []*fakeIMU{left, right} {
+		if f.overlaps > 0 {
+			t.Errorf("%s IMU: %d overlapping SPI transactions", f.name, f.overlaps)
+		}
+		if want := 2 * rounds; f.reads != want {
+			t.Errorf("%s IMU: %d sample reads, want %d", f.name, f.reads, want)
+		}
+	}
+}
+
+func TestIMUManagerRegisterAccessErrors(t *testing.T) {
+	m := &IMUManager{leftIMU: newMockIMU("left"), initialized: true}
+
+	tests := []struct {
+		imuID, want string
+	}{
+		{"left", "simulated"},
+		{"right", "right IMU not available"},
+		{"middle", "invalid IMU ID"},
+	}
+	for _, tt := range tests {
+		if _, err := m.ReadRegister(tt.imuID, 0x75); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ReadRegister(%s) = %v, want error containing %q", tt.imuID, err, tt.want)
+		}
+	}
+
+	if _, err := (&IMUManager{}).ReadRegister("left", 0x75); err == nil || !strings.Contains(err.Error(), "not initialized") {
+		t.Errorf("uninitialized ReadRegister = %v, want not initialized", err)
+	}
+}
+
+// TestReadAllRegistersSkipsFIFO checks that a register dump does not read
+// FIFO_R_W, which would pop a byte off the FIFO.
+func TestReadAllRegistersSkipsFIFO(t *testing.T) {
+	m := &IMUManager{leftIMU: &fakeIMU{name: "left"}, initialized: true}
+
+	regs, err := m.ReadAllRegisters("left")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := regs[regFIFORW]; ok {
+		t.Errorf("ReadAllRegisters read FIFO_R_W (0x%02X)", regFIFORW)
+	}
+	if len(regs) != 0x7F {
+		t.Errorf("ReadAllRegisters returned %d registers, want %d", len(regs), 0x7F)
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package sensors
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/relabs-tech/inertial_computer/internal/config"
+	"periph.io/x/conn/v3/gpio"
+)
+
+// MPU9250 wake-on-motion registers (datasheet §7 / register map
+// "Wake-on-Motion Interrupt").
+const (
+	regAccelConfig2  = 0x1D // ACCEL_CONFIG2
+	regWOMThr        = 0x1F // WOM_THR (4 mg/LSB)
+	regMotDetectCtrl = 0x69 // MOT_DETECT_CTRL
+
+	intEnableWOM       = 0x40 // WOM_EN
+	intStatusWOM       = 0x40 // WOM_INT
+	motDetectIntelEn   = 0x80 // ACCEL_INTEL_EN
+	motDetectIntelMode = 0x40 // ACCEL_INTEL_MODE: compare with the previous sample
+
+	// accelConfig2WOM is ACCEL_FCHOICE_B = 0, A_DLPFCFG = 1 (184 Hz), as the
+	// WOM sequence requires
+	accelConfig2WOM = 0x01
+
+	// WOMThresholdStepMg is the WOM_THR resolution; the threshold is
+	// 1-255 steps (4-1020 mg)
+	WOMThresholdStepMg = 4
+
+	// Defaults for IMU_WOM_THRESHOLD_MG / IMU_WOM_ODR_HZ
+	defaultWOMThresholdMg = 100
+	defaultWOMODRHz       = 31.25
+
+	// How often SleepUntilMotion checks WOM_INT without an INT pin
+	womPollInterval = 100 * time.Millisecond
+)
+
+// ConfigureWakeOnMotion puts the specified IMU into low-power accelerometer
+// mode with the wake-on-motion interrupt: the gyro is disabled, the accel
+// wakes at odrHz (rounded up to an LP_ACCEL_ODR rate) and INT_STATUS WOM_INT
+// is set (and INT pulsed) when the acceleration changes by more than
+// thresholdMg on any axis since the previous sample. Other interrupts are
+// disabled, and the IMU is in the "cycle" power mode until
+// DisableWakeOnMotion (or Wake) restores normal sampling.
+//
+// The sequence follows the MPU9250 datasheet: wake (PWR_MGMT_1), disable the
+// gyro (PWR_MGMT_2), accel DLPF at 184 Hz (ACCEL_CONFIG2), INT_ENABLE =
+// WOM_EN, enable the accel intelligence in compare mode (MOT_DETECT_CTRL),
+// set WOM_THR and LP_ACCEL_ODR, then enter cycle mode (PWR_MGMT_1 CYCLE).
+func (m *IMUManager) ConfigureWakeOnMotion(imuID string, thresholdMg int, odrHz float64) error {
+	steps := int(math.Round(float64(thresholdMg) / WOMThresholdStepMg))
+	if steps < 1 || steps > 255 {
+		return fmt.Errorf("wake-on-motion threshold %d mg out of range (%d-%d mg)", thresholdMg, WOMThresholdStepMg, 255*WOMThresholdStepMg)
+	}
+	odr, err := lpAccelODRIndex(odrHz)
+	if err != nil {
+		return err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	src, devMu, err := m.hardwareIMU(imuID)
+	if err != nil {
+		return err
+	}
+	devMu.Lock()
+	defer devMu.Unlock()
+
+	pwr1, err := src.imu.ReadRegister(regPwrMgmt1)
+	if err != nil {
+		return fmt.Errorf("%s IMU: read PWR_MGMT_1: %w", imuID, err)
+	}
+	pwr1 &^= pwrMgmt1Sleep | pwrMgmt1Cycle | pwrMgmt1GyroStandby
+
+	writes := []struct {
+		reg   byte
+		name  string
+		value byte
+	}{
+		{regPwrMgmt1, "PWR_MGMT_1", pwr1},
+		{regPwrMgmt2, "PWR_MGMT_2", src.power.DisabledAxes | pwrMgmt2DisableGyro},
+		{regAccelConfig2, "ACCEL_CONFIG2", accelConfig2WOM},
+		{regIntEnable, "INT_ENABLE", intEnableWOM},
+		{regMotDetectCtrl, "MOT_DETECT_CTRL", motDetectIntelEn | motDetectIntelMode},
+		{regWOMThr, "WOM_THR", byte(steps)},
+		{regLPAccelODR, "LP_ACCEL_ODR", odr},
+		{regPwrMgmt1, "PWR_MGMT_1", pwr1 | pwrMgmt1Cycle},
+	}
+	for _, w := range writes {
+		if err := src.imu.WriteRegister(w.reg, w.value); err != nil {
+			return fmt.Errorf("%s IMU: write %s: %w", imuID, w.name, err)
+		}
+	}
+	src.power.Mode, src.power.CycleODRHz = PowerCycle, LPAccelODRs[odr]
+	log.Printf("%s IMU: wake-on-motion armed (threshold %d mg, %.2f Hz)", imuID, steps*WOMThresholdStepMg, LPAccelODRs[odr])
+	return nil
+}
+
+// DisableWakeOnMotion leaves wake-on-motion mode: the IMU is woken (see
+// Wake), the accel DLPF set back to IMU_ACCEL_DLPF and, with data-ready
+// sampling, the RAW_RDY interrupt enabled again.
+func (m *IMUManager) DisableWakeOnMotion(imuID string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	src, devMu, err := m.hardwareIMU(imuID)
+	if err != nil {
+		return err
+	}
+	devMu.Lock()
+	defer devMu.Unlock()
+
+	var intEnable byte
+	if src.intPin != nil {
+		intEnable = intEnableRawRdy
+	}
+	if err := src.imu.WriteRegister(regMotDetectCtrl, 0); err != nil {
+		return fmt.Errorf("%s IMU: write MOT_DETECT_CTRL: %w", imuID, err)
+	}
+	if err := src.imu.WriteRegister(regIntEnable, intEnable); err != nil {
+		return fmt.Errorf("%s IMU: write INT_ENABLE: %w", imuID, err)
+	}
+	if err := src.imu.SetAccelDLPF(config.Get().IMUAccelDLPF); err != nil {
+		return fmt.Errorf("%s IMU: set accel DLPF: %w", imuID, err)
+	}
+	if err := src.wake(); err != nil {
+		return err
+	}
+	log.Printf("%s IMU: wake-on-motion disabled", imuID)
+	return nil
+}
+
+// WakeOnMotionStatus reports whether the specified IMU detected motion
+// (INT_STATUS WOM_INT) since the last call. Reading INT_STATUS clears all
+// its flags, including the FIFO overflow that ReadFIFO checks.
+func (m *IMUManager) WakeOnMotionStatus(imuID string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	src, devMu, err := m.hardwareIMU(imuID)
+	if err != nil {
+		return false, err
+	}
+	devMu.Lock()
+	defer devMu.Unlock()
+
+	status, err := src.imu.ReadRegister(regIntStatus)
+	if err != nil {
+		return false, fmt.Errorf("%s IMU: read INT_STATUS: %w", imuID, err)
+	}
+	return status&intStatusWOM != 0, nil
+}
+
+// SleepUntilMotion arms wake-on-motion on every available hardware IMU and
+// blocks until one of them detects motion or ctx is done, then returns them
+// to normal sampling. It waits on the INT pin edge of an IMU with data-ready
+// sampling configured, and polls INT_STATUS otherwise. Zero thresholdMg or
+// odrHz use the defaults (100 mg, 31.25 Hz). It returns the IMU that woke.
+func (m *IMUManager) SleepUntilMotion(ctx context.Context, thresholdMg int, odrHz float64) (string, error) {
+	if thresholdMg <= 0 {
+		thresholdMg = defaultWOMThresholdMg
+	}
+	if odrHz <= 0 {
+		odrHz = defaultWOMODRHz
+	}
+
+	var armed []string
+	var pin gpio.PinIn
+	for _, id := range []string{"left", "right"} {
+		if err := m.ConfigureWakeOnMotion(id, thresholdMg, odrHz); err != nil {
+			log.Printf("Warning: %v", err)
+			continue
+		}
+		armed = append(armed, id)
+		if pin == nil {
+			pin = m.dataReadyPin(id)
+		}
+	}
+	if len(armed) == 0 {
+		return "", fmt.Errorf("no IMU could enter wake-on-motion mode")
+	}
+	defer func() {
+		for _, id := range armed {
+			if err := m.DisableWakeOnMotion(id); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+		}
+	}()
+
+	// Clear a stale WOM_INT from before arming
+	for _, id := range armed {
+		m.WakeOnMotionStatus(id)
+	}
+
+	for {
+		if pin != nil {
+			pin.WaitForEdge(time.Second)
+		} else {
+			select {
+			case <-ctx.Done():
+			case <-time.After(womPollInterval):
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		for _, id := range armed {
+			moved, err := m.WakeOnMotionStatus(id)
+			if err != nil {
+				return "", err
+			}
+			if moved {
+				return id, nil
+			}
+		}
+	}
+}
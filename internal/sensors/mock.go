@@ -0,0 +1,138 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package sensors
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/relabs-tech/inertial_computer/internal/config"
+	"github.com/relabs-tech/inertial_computer/internal/env"
+	imu_raw "github.com/relabs-tech/inertial_computer/internal/imu"
+	"github.com/relabs-tech/inertial_computer/internal/orientation"
+)
+
+// Simulated hardware (MOCK_HARDWARE=true): the IMU manager and the BMP
+// functions serve synthetic samples instead of opening SPI devices, so every
+// entry point runs without the Pi.
+
+// Synthetic earth field (µT, north/east/down) and sensor noise (1σ).
+const (
+	mockMagNorthUT = 20.0
+	mockMagDownUT  = 44.0
+
+	mockAccelNoiseG   = 0.005
+	mockGyroNoiseDPS  = 0.05
+	mockMagNoiseUT    = 0.3
+	mockPressureNoise = 2.0 // Pa
+
+	mockDieTempCounts = 3000 // ≈30 °C, see imu_raw.DieTempC
+	mockBaseAltitudeM = 520.0
+)
+
+// mockStart is the shared time origin, so both IMUs and the BMPs describe
+// the same motion.
+var mockStart = time.Now()
+
+// errSimulatedIMU is returned by register-level access to a simulated IMU.
+func errSimulatedIMU(side string) error {
+	return fmt.Errorf("%s IMU is simulated (MOCK_HARDWARE), no register access", side)
+}
+
+// mockIMU produces IMU samples for orientation.MockPose: gravity and a fixed
+// earth field rotated into the body frame, and the body rates of the pose,
+// with Gaussian noise and converted to counts for the configured ranges.
+type mockIMU struct {
+	name string
+}
+
+func newMockIMU(name string) *mockIMU {
+	return &mockIMU{name: name}
+}
+
+func (s *mockIMU) ReadRaw() (imu_raw.IMURaw, error) {
+	cfg := config.Get()
+	now := time.Now()
+	t := now.Sub(mockStart).Seconds()
+
+	// Euler angles and their rates (central difference, yaw unwrapped)
+	const h = 1e-3
+	p := orientation.MockPose(t)
+	p0, p1 := orientation.MockPose(t-h), orientation.MockPose(t+h)
+	dYaw := math.Remainder(p1.Yaw-p0.Yaw, 360)
+	rollRate, pitchRate, yawRate := (p1.Roll-p0.Roll)/(2*h), (p1.Pitch-p0.Pitch)/(2*h), dYaw/(2*h)
+
+	rad := math.Pi / 180
+	sr, cr := math.Sincos(p.Roll * rad)
+	sp, cp := math.Sincos(p.Pitch * rad)
+	sy, cy := math.Sincos(p.Yaw * rad)
+
+	// Direction cosine matrix world (north/east/down) -> body, ZYX order
+	c := [3][3]float64{
+		{cp * cy, cp * sy, -sp},
+		{sr*sp*cy - cr*sy, sr*sp*sy + cr*cy, sr * cp},
+		{cr*sp*cy + sr*sy, cr*sp*sy - sr*cy, cr * cp},
+	}
+
+	// Accelerometer at rest reads the reaction to gravity, +1 g "down"
+	accel := [3]float64{c[0][2], c[1][2], c[2][2]}
+	gyro := [3]float64{
+		rollRate - yawRate*sp,
+		pitchRate*cr + yawRate*sr*cp,
+		-pitchRate*sr + yawRate*cr*cp,
+	}
+	var mag [3]float64
+	for i := range mag {
+		mag[i] = c[i][0]*mockMagNorthUT + c[i][2]*mockMagDownUT
+	}
+
+	accelLSB := imu_raw.AccelLSBPerG(cfg.IMUAccelRange)
+	gyroLSB := imu_raw.GyroLSBPerDPS(cfg.IMUGyroRange)
+	var a, g, m [3]int16
+	for i := range 3 {
+		a[i] = clampInt16((accel[i] + rand.NormFloat64()*mockAccelNoiseG) * accelLSB)
+		g[i] = clampInt16((gyro[i] + rand.NormFloat64()*mockGyroNoiseDPS) * gyroLSB)
+		m[i] = imu_raw.MagCounts(mag[i] + rand.NormFloat64()*mockMagNoiseUT)
+	}
+
+	return imu_raw.IMURaw{
+		Source:    s.name,
+		Timestamp: now,
+		Ax:        a[0],
+		Ay:        a[1],
+		Az:        a[2],
+		Gx:        g[0],
+		Gy:        g[1],
+		Gz:        g[2],
+		Mx:        m[0],
+		My:        m[1],
+		Mz:        m[2],
+		MagValid:  true,
+		Temp:      mockDieTempCounts,
+		TempValid: true,
+	}, nil
+}
+
+func clampInt16(v float64) int16 {
+	return int16(math.Max(math.MinInt16, math.Min(math.MaxInt16, math.Round(v))))
+}
+
+// mockEnv returns a BMP sample for a slow ±3 m altitude oscillation around
+// mockBaseAltitudeM, with a small temperature drift.
+func mockEnv(source string) env.Sample {
+	t := time.Since(mockStart).Seconds()
+	alt := mockBaseAltitudeM + 3*math.Sin(2*math.Pi*t/60)
+	pressurePa := 100*seaLevelHPa()*math.Pow(1-alt/44330, 5.255) + rand.NormFloat64()*mockPressureNoise
+	return env.Sample{
+		Source:       source,
+		Temperature:  22 + 0.5*math.Sin(2*math.Pi*t/600),
+		Pressure:     pressurePa,
+		PressureMbar: pressurePa / 100.0,
+		PressureHPa:  pressurePa / 100.0,
+		Altitude:     env.PressureToAltitude(pressurePa/100.0, seaLevelHPa()),
+	}
+}
@@ -22,6 +22,27 @@ type RegisterInfo struct {
 	BitFields   []BitField // optional bitfield definitions
 }
 
+// mpu9250DataPorts are the registers that move data rather than hold
+// configuration: FIFO_R_W pops (read) or pushes (write) a FIFO byte, and the
+// I2C slave DO/DI registers carry the bytes of slave transactions.
+var mpu9250DataPorts = map[byte]string{
+	0x33: "I2C_SLV4_DO",
+	0x35: "I2C_SLV4_DI",
+	0x63: "I2C_SLV0_DO",
+	0x64: "I2C_SLV1_DO",
+	0x65: "I2C_SLV2_DO",
+	0x66: "I2C_SLV3_DO",
+	0x74: "FIFO_R_W",
+}
+
+// MPU9250DataPort returns the name of addr if it is a data port (see
+// mpu9250DataPorts), which register dumps and config imports must not treat
+// as configuration.
+func MPU9250DataPort(addr byte) (name string, ok bool) {
+	name, ok = mpu9250DataPorts[addr]
+	return name, ok
+}
+
 // GetRegisterMap returns metadata for all MPU9250 registers.
 func GetRegisterMap() []RegisterInfo {
 	return getMPU9250RegisterMap()
@@ -66,6 +87,7 @@ func getMPU9250RegisterMap() []RegisterInfo {
 			BitFields: []BitField{
 				{Bits: "3:0", Name: "Lposc_clksel", Description: "Low Power Accel Output Data Rate", Values: "0=0.24Hz ... 11=500Hz"},
 			}},
+		{Address: "0x1F", Name: "WOM_THR", Description: "Wake-on-Motion Threshold (4mg/LSB)", Access: "RW", Default: "0x00"},
 
 		// Interrupt Configuration
 		{Address: "0x37", Name: "INT_PIN_CFG", Description: "INT Pin / Bypass Enable Configuration", Access: "RW", Default: "0x00",
@@ -92,6 +114,11 @@ func getMPU9250RegisterMap() []RegisterInfo {
 				{Bits: "3", Name: "FSYNC_INT", Description: "FSYNC interrupt status", Values: ""},
 				{Bits: "0", Name: "RAW_DATA_RDY_INT", Description: "Raw data ready interrupt status", Values: ""},
 			}},
+		{Address: "0x69", Name: "MOT_DETECT_CTRL", Description: "Accel Intelligence Control (wake-on-motion)", Access: "RW", Default: "0x00",
+			BitFields: []BitField{
+				{Bits: "7", Name: "ACCEL_INTEL_EN", Description: "Enable wake-on-motion logic", Values: "0=Disabled, 1=Enabled"},
+				{Bits: "6", Name: "ACCEL_INTEL_MODE", Description: "Motion compare mode", Values: "1=Compare current sample with previous"},
+			}},
 
 		// Sensor Data Registers (Read-Only)
 		{Address: "0x3B", Name: "ACCEL_XOUT_H", Description: "Accelerometer X-Axis High Byte", Access: "R"},
@@ -0,0 +1,187 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package sensors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Devices with register metadata, for DecodeRegister.
+const (
+	DeviceMPU9250 = "mpu9250"
+	DeviceAK8963  = "ak8963"
+)
+
+// DecodedField is one bitfield extracted from a register value.
+type DecodedField struct {
+	Name        string `json:"name"`
+	Bits        string `json:"bits"`
+	Value       byte   `json:"value"`
+	Meaning     string `json:"meaning,omitempty"` // matching entry from BitField.Values, if any
+	Description string `json:"description,omitempty"`
+}
+
+// DecodedRegister is a register value broken down by its bitfield metadata.
+// Fields is empty for registers without bitfield definitions.
+type DecodedRegister struct {
+	Device  string         `json:"device"`
+	Address string         `json:"address"`
+	Name    string         `json:"name,omitempty"`
+	Value   string         `json:"value"`
+	Fields  []DecodedField `json:"fields,omitempty"`
+}
+
+// String formats the decoded fields, e.g. "GYRO_FS_SEL=1 (±500°/s)".
+func (d DecodedRegister) String() string {
+	if len(d.Fields) == 0 {
+		return fmt.Sprintf("%s=%s", d.Name, d.Value)
+	}
+	parts := make([]string, 0, len(d.Fields))
+	for _, f := range d.Fields {
+		if f.Meaning != "" {
+			parts = append(parts, fmt.Sprintf("%s=%d (%s)", f.Name, f.Value, f.Meaning))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s=%d", f.Name, f.Value))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// registerMapFor returns the register metadata for the given device.
+func registerMapFor(device string) ([]RegisterInfo, error) {
+	switch device {
+	case DeviceMPU9250, "":
+		return getMPU9250RegisterMap(), nil
+	case DeviceAK8963:
+		return getAK8963RegisterMap(), nil
+	default:
+		return nil, fmt.Errorf("unknown device: %s (must be '%s' or '%s')", device, DeviceMPU9250, DeviceAK8963)
+	}
+}
+
+// lookupRegister finds the metadata for addr on the given device.
+func lookupRegister(device string, addr byte) (RegisterInfo, bool, error) {
+	regs, err := registerMapFor(device)
+	if err != nil {
+		return RegisterInfo{}, false, err
+	}
+	for _, r := range regs {
+		a, err := strconv.ParseUint(r.Address, 0, 8)
+		if err == nil && byte(a) == addr {
+			return r, true, nil
+		}
+	}
+	return RegisterInfo{}, false, nil
+}
+
+// DecodeRegister breaks a register value down into its bitfields using the
+// register map metadata. Registers without metadata (or without bitfields)
+// decode to just the raw value.
+func DecodeRegister(device string, addr, value byte) (DecodedRegister, error) {
+	if device == "" {
+		device = DeviceMPU9250
+	}
+	info, found, err := lookupRegister(device, addr)
+	if err != nil {
+		return DecodedRegister{}, err
+	}
+
+	decoded := DecodedRegister{
+		Device:  device,
+		Address: fmt.Sprintf("0x%02X", addr),
+		Value:   fmt.Sprintf("0x%02X", value),
+	}
+	if !found {
+		return decoded, nil
+	}
+	decoded.Name = info.Name
+
+	for _, bf := range info.BitFields {
+		mask, shift, err := bitFieldMask(bf.Bits)
+		if err != nil {
+			return DecodedRegister{}, fmt.Errorf("%s.%s: %w", info.Name, bf.Name, err)
+		}
+		v := (value & mask) >> shift
+		decoded.Fields = append(decoded.Fields, DecodedField{
+			Name:        bf.Name,
+			Bits:        bf.Bits,
+			Value:       v,
+			Meaning:     bitFieldMeaning(bf.Values, v),
+			Description: bf.Description,
+		})
+	}
+	return decoded, nil
+}
+
+// SetBitField returns current with the named bitfield of register addr
+// replaced by fieldValue, preserving all other bits. It fails if the register
+// or field is unknown or the value does not fit the field width.
+func SetBitField(device string, addr byte, field string, current byte, fieldValue uint64) (byte, error) {
+	info, found, err := lookupRegister(device, addr)
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, fmt.Errorf("no metadata for register 0x%02X", addr)
+	}
+
+	for _, bf := range info.BitFields {
+		if !strings.EqualFold(bf.Name, field) {
+			continue
+		}
+		mask, shift, err := bitFieldMask(bf.Bits)
+		if err != nil {
+			return 0, fmt.Errorf("%s.%s: %w", info.Name, bf.Name, err)
+		}
+		maxValue := uint64(mask >> shift)
+		if fieldValue > maxValue {
+			return 0, fmt.Errorf("value %d does not fit %s.%s (bits %s, max %d)", fieldValue, info.Name, bf.Name, bf.Bits, maxValue)
+		}
+		return (current &^ mask) | (byte(fieldValue)<<shift)&mask, nil
+	}
+
+	return 0, fmt.Errorf("register %s (0x%02X) has no field %q", info.Name, addr, field)
+}
+
+// bitFieldMask converts a bit range ("4:3" or "7") into a mask and shift.
+func bitFieldMask(bits string) (mask byte, shift uint, err error) {
+	hiStr, loStr, isRange := strings.Cut(bits, ":")
+	hi, err := strconv.ParseUint(strings.TrimSpace(hiStr), 10, 8)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid bit range %q", bits)
+	}
+	lo := hi
+	if isRange {
+		lo, err = strconv.ParseUint(strings.TrimSpace(loStr), 10, 8)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid bit range %q", bits)
+		}
+	}
+	if hi > 7 || lo > hi {
+		return 0, 0, fmt.Errorf("invalid bit range %q", bits)
+	}
+
+	width := uint(hi - lo + 1)
+	return byte((uint(1)<<width - 1) << uint(lo)), uint(lo), nil
+}
+
+// bitFieldMeaning returns the description for v from a Values string such as
+// "0=±250°/s, 1=±500°/s" (ranges like "0=0.24Hz ... 11=500Hz" are supported).
+func bitFieldMeaning(values string, v byte) string {
+	values = strings.ReplaceAll(values, "...", ",")
+	for _, entry := range strings.Split(values, ",") {
+		key, meaning, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseUint(strings.TrimSpace(key), 10, 8)
+		if err == nil && byte(n) == v {
+			return strings.TrimSpace(meaning)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2026 Daniel Alarcon Rubio / Relabs Tech
+// SPDX-License-Identifier: MIT
+// See LICENSE file for full license text
+
+package sensors
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/relabs-tech/inertial_computer/internal/config"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/host/v3"
+)
+
+// VoltageSource reads a supply or battery voltage. The Pi has no ADC, so
+// this is the integration point for whatever the rig is powered from; wire a
+// new implementation into NewVoltageSource.
+type VoltageSource interface {
+	// ReadVoltage returns the current voltage in volts.
+	ReadVoltage() (float64, error)
+	// Name describes the source for logs, e.g. "ina219@0x42".
+	Name() string
+}
+
+const (
+	// INA219 bus voltage register: bits 15:3, 4 mV per LSB
+	ina219RegBusVoltage = 0x02
+	ina219BusVoltageLSB = 0.004
+	defaultINA219Addr   = 0x40
+	defaultBatteryBus   = 1
+)
+
+// NewVoltageSource returns the source configured by BATTERY_SOURCE, or nil
+// if none is configured:
+//   - "file": a text file holding a number, e.g. a sysfs
+//     /sys/class/power_supply/<name>/voltage_now (µV, BATTERY_VOLTAGE_SCALE=0.000001)
+//     or a value written by a UPS HAT daemon (BATTERY_FILE_PATH)
+//   - "ina219": the bus voltage of an INA219 current monitor, as on common
+//     UPS HATs (BATTERY_I2C_BUS, BATTERY_I2C_ADDR, default 0x40)
+//
+// Readings are multiplied by BATTERY_VOLTAGE_SCALE (e.g. a divider ratio).
+func NewVoltageSource() (VoltageSource, error) {
+	cfg := config.Get()
+	scale := cfg.BatteryVoltageScale
+	if scale == 0 {
+		scale = 1
+	}
+
+	switch cfg.BatterySource {
+	case "":
+		return nil, nil
+	case "file":
+		if cfg.BatteryFilePath == "" {
+			return nil, fmt.Errorf("BATTERY_SOURCE=file needs BATTERY_FILE_PATH")
+		}
+		return &fileVoltageSource{path: cfg.BatteryFilePath, scale: scale}, nil
+	case "ina219":
+		return newINA219VoltageSource(cfg.BatteryI2CBus, cfg.BatteryI2CAddr, scale)
+	default:
+		return nil, fmt.Errorf("unknown BATTERY_SOURCE %q", cfg.BatterySource)
+	}
+}
+
+// fileVoltageSource reads a number from a file on every read.
+type fileVoltageSource struct {
+	path  string
+	scale float64
+}
+
+func (s *fileVoltageSource) ReadVoltage() (float64, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", s.path, err)
+	}
+	return v * s.scale, nil
+}
+
+func (s *fileVoltageSource) Name() string { return "file:" + s.path }
+
+// ina219VoltageSource reads the INA219 bus voltage register.
+type ina219VoltageSource struct {
+	bus   i2c.BusCloser
+	dev   *i2c.Dev
+	scale float64
+}
+
+func newINA219VoltageSource(busNum int, addr uint16, scale float64) (*ina219VoltageSource, error) {
+	if _, err := host.Init(); err != nil {
+		return nil, fmt.Errorf("periph host init failed: %w", err)
+	}
+	if busNum == 0 {
+		busNum = defaultBatteryBus
+	}
+	if addr == 0 {
+		addr = defaultINA219Addr
+	}
+	bus, err := i2creg.Open(strconv.Itoa(busNum))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open I2C bus %d: %w", busNum, err)
+	}
+	s := &ina219VoltageSource{bus: bus, dev: &i2c.Dev{Bus: bus, Addr: addr}, scale: scale}
+	if _, err := s.ReadVoltage(); err != nil {
+		bus.Close()
+		return nil, fmt.Errorf("INA219 at 0x%02X on bus %d: %w", addr, busNum, err)
+	}
+	return s, nil
+}
+
+func (s *ina219VoltageSource) ReadVoltage() (float64, error) {
+	buf := make([]byte, 2)
+	if err := s.dev.Tx([]byte{ina219RegBusVoltage}, buf); err != nil {
+		return 0, err
+	}
+	raw := uint16(buf[0])<<8 | uint16(buf[1])
+	return float64(raw>>3) * ina219BusVoltageLSB * s.scale, nil
+}
+
+func (s *ina219VoltageSource) Name() string { return fmt.Sprintf("ina219@0x%02X", s.dev.Addr) }
+
+// Close releases the I2C bus.
+func (s *ina219VoltageSource) Close() error { return s.bus.Close() }